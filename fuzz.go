@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/config"
+	"dazzle/internal/infrastructure/httpclient"
+	"dazzle/internal/infrastructure/openapi"
+)
+
+// runFuzz implements `dazzle fuzz <spec> [operationId...]`: it sends
+// generated requests for the named operations, or all of them, and reports
+// where the server breaks the spec. Any finding makes it return an error.
+func runFuzz(ctx context.Context, out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("dazzle fuzz", flag.ContinueOnError)
+	flags.Usage = printUsage
+	envName := flags.String("env", "", "environment to run against")
+	server := flags.String("server", "", "base URL to send requests to (default: the environment's baseUrl)")
+	seed := flags.Uint64("seed", 0, "seed for generated requests (default: random)")
+	cases := flags.Int("cases", 20, "valid requests per operation, on top of the invalid ones")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() < 1 {
+		printUsage()
+		return fmt.Errorf("fuzz expects a spec")
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	envSvc := application.NewEnvironmentService(config.NewEnvironmentStore(config.DefaultEnvironmentPaths()...))
+	envs, err := envSvc.LoadEnvironments()
+	if err != nil {
+		return err
+	}
+	active, err := chooseEnvironment(envs, *envName)
+	if err != nil {
+		return err
+	}
+	env, _ := envs.Get(active)
+
+	spec, err := application.NewSpecService(openapi.NewRepository()).LoadSpec(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	ops, err := fuzzTargets(spec.Operations, flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+	if *server == "" {
+		if *server, err = defaultServer(env); err != nil {
+			return err
+		}
+	}
+
+	client := httpclient.NewClient(30 * time.Second)
+	authSvc, signSvc := newAuthorization(envSvc, client)
+	fuzzer := application.NewFuzzService(application.NewRequestService(client), envSvc, application.NewValidationService(),
+		domain.Authorizer{Auth: authSvc, Signer: signSvc})
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	fmt.Fprintf(out, "Fuzzing %s with seed %d\n\n", domain.Plural(len(ops), "operation"), *seed)
+	report, err := fuzzer.Fuzz(ctx, spec, ops, domain.FuzzOptions{Seed: *seed, Cases: *cases, Server: *server, Env: env})
+	if err != nil {
+		return err
+	}
+	if err := printFuzzReport(out, report); err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("interrupted")
+	}
+	if n := len(report.Findings); n > 0 {
+		return fmt.Errorf("%s (reproduce with --seed %d --cases %d)", domain.Plural(n, "finding"), report.Seed, *cases)
+	}
+	return nil
+}
+
+// fuzzTargets picks the operations with the given IDs, or all of them.
+func fuzzTargets(ops []domain.Operation, ids []string) ([]domain.Operation, error) {
+	if len(ids) == 0 {
+		return ops, nil
+	}
+	var out []domain.Operation
+	for _, id := range ids {
+		i := slices.IndexFunc(ops, func(op domain.Operation) bool { return op.ID == id })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown operation %q", id)
+		}
+		out = append(out, ops[i])
+	}
+	return out, nil
+}
+
+// defaultServer is the environment's baseUrl. Fuzzing sends requests that
+// may change or break data, so the spec's servers, often production, are
+// never used unless named with --server.
+func defaultServer(env domain.Environment) (string, error) {
+	if _, ok := env.Variables["baseUrl"]; ok {
+		return "{{baseUrl}}", nil
+	}
+	return "", fmt.Errorf("no server to fuzz: pass --server or set baseUrl in the environment")
+}
+
+// maxReportLine cuts the lines of long reproductions, such as those with
+// oversized values, short.
+const maxReportLine = 200
+
+func printFuzzReport(out io.Writer, report domain.FuzzReport) error {
+	curl := func(req *domain.HTTPRequest) string {
+		for _, s := range application.NewSnippetService().Snippets(req) {
+			if s.Language == domain.SnippetCurl {
+				return s.Code
+			}
+		}
+		return string(req.Method) + " " + req.URL
+	}
+	for _, f := range report.Findings {
+		name := string(f.Method) + " " + f.Path
+		if f.OperationID != "" {
+			name += " (" + f.OperationID + ")"
+		}
+		fmt.Fprintf(out, "✗ %s: %s → %d", f.Kind, name, f.Status)
+		if f.Count > 1 {
+			fmt.Fprintf(out, ", %d cases", f.Count)
+		}
+		fmt.Fprintln(out)
+		input := "valid"
+		if !f.Case.Valid() {
+			input = f.Case.Mutation
+		}
+		fmt.Fprintf(out, "    input: %s\n", input)
+		for _, v := range f.Violations {
+			fmt.Fprintf(out, "    response %s\n", v)
+		}
+		if f.Shrinks > 0 {
+			fmt.Fprintf(out, "    shrunk in %s to:\n", domain.Plural(f.Shrinks, "step"))
+		}
+		for _, line := range strings.Split(curl(f.Request), "\n") {
+			if r := []rune(line); len(r) > maxReportLine {
+				line = string(r[:maxReportLine]) + "…"
+			}
+			fmt.Fprintf(out, "    %s\n", line)
+		}
+		fmt.Fprintln(out)
+	}
+	summary := fmt.Sprintf("%s sent, %s", domain.Plural(report.Sent, "request"), domain.Plural(len(report.Findings), "finding"))
+	if report.Failed > 0 {
+		summary += fmt.Sprintf("; %d got no response (last: %s)", report.Failed, report.LastError)
+	}
+	_, err := fmt.Fprintln(out, summary)
+	return err
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/openapi"
+	"dazzle/internal/infrastructure/proxy"
+)
+
+// runInfer implements `dazzle infer <spec> <recording>...`: it compares
+// recorded traffic with the spec, reporting what the spec leaves out, and
+// can write the additions as an overlay.
+func runInfer(ctx context.Context, out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("dazzle infer", flag.ContinueOnError)
+	flags.Usage = printUsage
+	overlayFile := flags.String("overlay", "", "write an OpenAPI overlay adding what the spec lacks to this file")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() < 2 {
+		printUsage()
+		return fmt.Errorf("infer expects a spec and at least one recording")
+	}
+
+	spec, err := application.NewSpecService(openapi.NewRepository()).LoadSpec(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	var exchanges []domain.RecordedExchange
+	for _, path := range flags.Args()[1:] {
+		recorded, err := proxy.LoadRecording(path)
+		if err != nil {
+			return err
+		}
+		exchanges = append(exchanges, recorded...)
+	}
+
+	traffic := application.NewTrafficService(spec, application.NewValidationService())
+	report := traffic.Compare(exchanges)
+	if err := printReport(out, spec, report); err != nil {
+		return err
+	}
+
+	if *overlayFile == "" {
+		return nil
+	}
+	overlay := traffic.Overlay(report)
+	f, err := os.Create(*overlayFile)
+	if err != nil {
+		return fmt.Errorf("writing overlay: %w", err)
+	}
+	if err := openapi.WriteOverlay(f, overlay); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing overlay: %w", err)
+	}
+	_, err = fmt.Fprintf(out, "\nWrote %d overlay actions to %s\n", len(overlay.Actions), *overlayFile)
+	return err
+}
+
+func printReport(out io.Writer, spec *domain.Spec, report domain.TrafficReport) error {
+	fmt.Fprintf(out, "Compared %d exchanges with %s %s\n", report.Exchanges, spec.Info.Title, spec.Info.Version)
+	if len(report.Endpoints)+len(report.UndeclaredFields)+len(report.UnobservedFields) == 0 {
+		_, err := fmt.Fprintln(out, "\nThe traffic matches the spec.")
+		return err
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	if len(report.Endpoints) > 0 {
+		fmt.Fprintf(w, "\nEndpoints not in the spec (%d):\n", len(report.Endpoints))
+		for _, e := range report.Endpoints {
+			var statuses []string
+			for status := range e.Responses {
+				statuses = append(statuses, status)
+			}
+			note := ""
+			if e.Known {
+				note = "path in the spec"
+			}
+			slices.Sort(statuses)
+			fmt.Fprintf(w, "  %s\t%s\t%d×\t%s\t%s\n", e.Method, e.Path, e.Count, strings.Join(statuses, " "), note)
+		}
+	}
+	if len(report.UndeclaredFields) > 0 {
+		fmt.Fprintf(w, "\nUndeclared response fields (%d):\n", len(report.UndeclaredFields))
+		for _, f := range report.UndeclaredFields {
+			fmt.Fprintf(w, "  %s\t%s %s\t%s\t%s\t%d×\n", operationName(f), f.Status, f.MediaType, f.Field, schemaSummary(f.Schema), f.Count)
+		}
+	}
+	if len(report.UnobservedFields) > 0 {
+		fmt.Fprintf(w, "\nDeclared fields never seen (%d):\n", len(report.UnobservedFields))
+		for _, f := range report.UnobservedFields {
+			fmt.Fprintf(w, "  %s\t%s %s\t%s\n", operationName(f), f.Status, f.MediaType, f.Field)
+		}
+	}
+	return w.Flush()
+}
+
+func operationName(f domain.FieldFinding) string {
+	if f.OperationID != "" {
+		return f.OperationID
+	}
+	return string(f.Method) + " " + f.Path
+}
+
+// schemaSummary describes an inferred schema in a word or two, such as
+// "string (date-time)" or "integer, nullable".
+func schemaSummary(s *domain.Schema) string {
+	if s == nil {
+		return ""
+	}
+	summary := string(s.Type)
+	switch {
+	case s.Type == "" && s.Nullable:
+		return "null"
+	case s.Type == "":
+		summary = "mixed"
+	case s.Format != "":
+		summary += " (" + s.Format + ")"
+	}
+	if s.Nullable {
+		summary += ", nullable"
+	}
+	return summary
+}
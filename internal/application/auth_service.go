@@ -0,0 +1,252 @@
+package application
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// AuthService implements domain.AuthService. Credentials are loaded once
+// from the repository; any field left empty there falls back to the
+// process environment variable DAZZLE_<SCHEME>_<FIELD>, e.g.
+// DAZZLE_PETSTORE_AUTH_CLIENT_SECRET. OAuth2 tokens are cached per
+// environment and scheme until shortly before they expire, and persisted
+// when a token store is set.
+type AuthService struct {
+	repo        domain.CredentialRepository
+	envSvc      domain.EnvironmentService
+	client      domain.HTTPClient
+	store       domain.TokenRepository
+	openBrowser func(string) error
+	lookupEnv   func(string) (string, bool)
+	now         func() time.Time
+
+	loadOnce sync.Once
+	creds    map[string]domain.Credentials
+	loadErr  error
+
+	mu     sync.Mutex
+	tokens map[string]*domain.Token
+
+	discoveryMu sync.Mutex
+	discovery   map[string]*oidcConfiguration
+}
+
+// NewAuthService creates an AuthService. envSvc resolves {{var}} references
+// in credentials and may be nil; client is used for token requests.
+func NewAuthService(repo domain.CredentialRepository, envSvc domain.EnvironmentService, client domain.HTTPClient) *AuthService {
+	return &AuthService{
+		repo:      repo,
+		envSvc:    envSvc,
+		client:    client,
+		lookupEnv: os.LookupEnv,
+		now:       time.Now,
+		tokens:    make(map[string]*domain.Token),
+		discovery: make(map[string]*oidcConfiguration),
+	}
+}
+
+// SetTokenStore persists tokens, so logins survive restarts.
+func (s *AuthService) SetTokenStore(store domain.TokenRepository) {
+	s.store = store
+}
+
+// SetBrowser sets how BeginLogin opens the provider's login page. Without
+// one the user follows the session's AuthURL themselves.
+func (s *AuthService) SetBrowser(open func(url string) error) {
+	s.openBrowser = open
+}
+
+func (s *AuthService) Authenticate(ctx context.Context, schemes map[string]domain.SecurityScheme, op domain.Operation, env domain.Environment, req *domain.HTTPRequest) error {
+	for _, requirement := range op.Security {
+		if len(requirement) == 0 {
+			// Anonymous access is allowed; nothing more specific applied.
+			return nil
+		}
+
+		creds := make(map[string]domain.Credentials, len(requirement))
+		satisfied := true
+		for _, name := range sortedKeys(requirement) {
+			scheme, ok := schemes[name]
+			if !ok {
+				satisfied = false
+				break
+			}
+			c, err := s.credentials(env, name)
+			if err != nil {
+				return err
+			}
+			if !s.canAuthenticate(env, name, scheme, c) {
+				satisfied = false
+				break
+			}
+			creds[name] = c
+		}
+		if !satisfied {
+			continue
+		}
+
+		for _, name := range sortedKeys(requirement) {
+			if err := s.apply(ctx, env, name, schemes[name], creds[name], requirement[name], req); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return nil
+	}
+	return nil
+}
+
+// credentials returns the resolved credentials for a scheme: configured
+// values with their variables substituted, then environment fallbacks.
+func (s *AuthService) credentials(env domain.Environment, name string) (domain.Credentials, error) {
+	s.loadOnce.Do(func() {
+		if s.repo != nil {
+			s.creds, s.loadErr = s.repo.Load()
+		}
+	})
+	if s.loadErr != nil {
+		return domain.Credentials{}, s.loadErr
+	}
+
+	c := s.creds[name]
+	prefix := "DAZZLE_" + envVarName(name) + "_"
+	for _, f := range []struct {
+		suffix string
+		value  *string
+	}{
+		{"API_KEY", &c.APIKey},
+		{"USERNAME", &c.Username},
+		{"PASSWORD", &c.Password},
+		{"TOKEN", &c.Token},
+		{"CLIENT_ID", &c.ClientID},
+		{"CLIENT_SECRET", &c.ClientSecret},
+		{"REFRESH_TOKEN", &c.RefreshToken},
+		{"TOKEN_URL", &c.TokenURL},
+		{"REDIRECT_URL", &c.RedirectURL},
+	} {
+		if *f.value == "" {
+			*f.value, _ = s.lookupEnv(prefix + f.suffix)
+			continue
+		}
+		if s.envSvc == nil {
+			continue
+		}
+		resolved, err := s.envSvc.Substitute(env, *f.value)
+		if err != nil {
+			return domain.Credentials{}, fmt.Errorf("credentials for %s: %w", name, err)
+		}
+		*f.value = resolved
+	}
+	if len(c.Scopes) == 0 {
+		if scopes, ok := s.lookupEnv(prefix + "SCOPES"); ok {
+			c.Scopes = strings.Fields(strings.ReplaceAll(scopes, ",", " "))
+		}
+	}
+	return c, nil
+}
+
+var nonAlnum = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// envVarName turns a scheme name such as "petstore-auth" into PETSTORE_AUTH.
+func envVarName(name string) string {
+	return strings.ToUpper(strings.Trim(nonAlnum.ReplaceAllString(name, "_"), "_"))
+}
+
+// canAuthenticate reports whether c, or a token from an earlier login,
+// is enough to satisfy the scheme.
+func (s *AuthService) canAuthenticate(env domain.Environment, name string, scheme domain.SecurityScheme, c domain.Credentials) bool {
+	switch scheme.Type {
+	case domain.SecuritySchemeAPIKey:
+		return c.APIKey != ""
+	case domain.SecuritySchemeHTTP:
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			return c.Username != ""
+		}
+		return c.Token != ""
+	case domain.SecuritySchemeOAuth2, domain.SecuritySchemeOpenIDConnect:
+		if c.Token != "" || c.RefreshToken != "" || (c.ClientID != "" && hasClientCredentialsFlow(scheme, c)) {
+			return true
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.storedToken(env, name) != nil
+	}
+	return false
+}
+
+// apply adds one scheme's credentials to the request.
+func (s *AuthService) apply(ctx context.Context, env domain.Environment, name string, scheme domain.SecurityScheme, c domain.Credentials, scopes []string, req *domain.HTTPRequest) error {
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	switch scheme.Type {
+	case domain.SecuritySchemeAPIKey:
+		return applyAPIKey(scheme, c.APIKey, req)
+	case domain.SecuritySchemeHTTP:
+		if strings.EqualFold(scheme.Scheme, "basic") {
+			req.Header.Set("Authorization", "Basic "+basicCredentials(c.Username, c.Password))
+			return nil
+		}
+		req.Header.Set("Authorization", httpAuthScheme(scheme.Scheme)+" "+c.Token)
+		return nil
+	case domain.SecuritySchemeOAuth2, domain.SecuritySchemeOpenIDConnect:
+		if len(c.Scopes) > 0 {
+			scopes = c.Scopes
+		}
+		token, err := s.accessToken(ctx, env, name, scheme, c, scopes)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+	return fmt.Errorf("%s security schemes are not supported", scheme.Type)
+}
+
+func applyAPIKey(scheme domain.SecurityScheme, key string, req *domain.HTTPRequest) error {
+	switch scheme.In {
+	case domain.ParameterInHeader:
+		req.Header.Set(scheme.Name, key)
+	case domain.ParameterInQuery:
+		u, err := url.Parse(req.URL)
+		if err != nil {
+			return fmt.Errorf("parsing URL: %w", err)
+		}
+		query := u.Query()
+		query.Set(scheme.Name, key)
+		u.RawQuery = query.Encode()
+		req.URL = u.String()
+	case domain.ParameterInCookie:
+		cookie := (&http.Cookie{Name: scheme.Name, Value: key}).String()
+		if existing := req.Header.Get("Cookie"); existing != "" {
+			cookie = existing + "; " + cookie
+		}
+		req.Header.Set("Cookie", cookie)
+	default:
+		return fmt.Errorf("unsupported API key location %q", scheme.In)
+	}
+	return nil
+}
+
+func basicCredentials(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
+
+// httpAuthScheme capitalises the scheme name for the Authorization header,
+// e.g. "bearer" becomes "Bearer".
+func httpAuthScheme(scheme string) string {
+	if scheme == "" {
+		return "Bearer"
+	}
+	return strings.ToUpper(scheme[:1]) + strings.ToLower(scheme[1:])
+}
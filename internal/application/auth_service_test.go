@@ -0,0 +1,272 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/httpclient"
+)
+
+// credentialMap is an in-memory CredentialRepository.
+type credentialMap map[string]domain.Credentials
+
+func (m credentialMap) Load() (map[string]domain.Credentials, error) { return m, nil }
+
+// tokenEndpoint is a stand-in OAuth2 token endpoint that records the grants
+// it receives.
+type tokenEndpoint struct {
+	mu        sync.Mutex
+	grants    []string
+	forms     []map[string]string
+	basicUser string
+	expiresIn int
+	refresh   string
+	fail      bool
+}
+
+func (e *tokenEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	form := map[string]string{}
+	for k := range r.PostForm {
+		form[k] = r.PostForm.Get(k)
+	}
+	e.grants = append(e.grants, r.PostForm.Get("grant_type"))
+	e.forms = append(e.forms, form)
+	e.basicUser, _, _ = r.BasicAuth()
+
+	w.Header().Set("Content-Type", "application/json")
+	if e.fail {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"bad secret"}`))
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"access_token":  "token-" + string(rune('0'+len(e.grants))),
+		"token_type":    "Bearer",
+		"expires_in":    e.expiresIn,
+		"refresh_token": e.refresh,
+	})
+}
+
+func securedOperation(requirements ...domain.SecurityRequirement) domain.Operation {
+	return domain.Operation{ID: "listPets", Path: "/pets", Method: domain.GET, Security: requirements}
+}
+
+func newRequest() *domain.HTTPRequest {
+	return &domain.HTTPRequest{Method: domain.GET, URL: "https://api.example.com/pets?limit=1", Header: http.Header{}}
+}
+
+func authenticate(t *testing.T, svc *application.AuthService, schemes map[string]domain.SecurityScheme, op domain.Operation) *domain.HTTPRequest {
+	t.Helper()
+	req := newRequest()
+	if err := svc.Authenticate(context.Background(), schemes, op, domain.Environment{Name: "test"}, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return req
+}
+
+func TestAuthService_APIKey(t *testing.T) {
+	for _, tc := range []struct {
+		in    domain.ParameterIn
+		check func(*domain.HTTPRequest) bool
+	}{
+		{domain.ParameterInHeader, func(r *domain.HTTPRequest) bool { return r.Header.Get("X-API-Key") == "k1" }},
+		{domain.ParameterInQuery, func(r *domain.HTTPRequest) bool { return r.URL == "https://api.example.com/pets?X-API-Key=k1&limit=1" }},
+		{domain.ParameterInCookie, func(r *domain.HTTPRequest) bool { return r.Header.Get("Cookie") == "X-API-Key=k1" }},
+	} {
+		schemes := map[string]domain.SecurityScheme{
+			"api_key": {Type: domain.SecuritySchemeAPIKey, Name: "X-API-Key", In: tc.in},
+		}
+		svc := application.NewAuthService(credentialMap{"api_key": {APIKey: "k1"}}, nil, nil)
+
+		req := authenticate(t, svc, schemes, securedOperation(domain.SecurityRequirement{"api_key": nil}))
+		if !tc.check(req) {
+			t.Errorf("api key in %s not applied: %s %v", tc.in, req.URL, req.Header)
+		}
+	}
+}
+
+func TestAuthService_HTTPSchemes(t *testing.T) {
+	schemes := map[string]domain.SecurityScheme{
+		"basic":  {Type: domain.SecuritySchemeHTTP, Scheme: "basic"},
+		"bearer": {Type: domain.SecuritySchemeHTTP, Scheme: "bearer"},
+	}
+	svc := application.NewAuthService(credentialMap{
+		"basic":  {Username: "aladdin", Password: "opensesame"},
+		"bearer": {Token: "abc"},
+	}, nil, nil)
+
+	req := authenticate(t, svc, schemes, securedOperation(domain.SecurityRequirement{"basic": nil}))
+	if got := req.Header.Get("Authorization"); got != "Basic YWxhZGRpbjpvcGVuc2VzYW1l" {
+		t.Errorf("unexpected basic header %q", got)
+	}
+
+	req = authenticate(t, svc, schemes, securedOperation(domain.SecurityRequirement{"bearer": nil}))
+	if got := req.Header.Get("Authorization"); got != "Bearer abc" {
+		t.Errorf("unexpected bearer header %q", got)
+	}
+}
+
+func TestAuthService_CredentialsFromEnvironment(t *testing.T) {
+	t.Setenv("DAZZLE_BEARER_AUTH_TOKEN", "from-env")
+	t.Setenv("SECRET_KEY", "from-config")
+	schemes := map[string]domain.SecurityScheme{
+		"bearer-auth": {Type: domain.SecuritySchemeHTTP, Scheme: "bearer"},
+		"api_key":     {Type: domain.SecuritySchemeAPIKey, Name: "X-Key", In: domain.ParameterInHeader},
+	}
+	svc := application.NewAuthService(credentialMap{
+		"api_key": {APIKey: "{{env:SECRET_KEY}}"},
+	}, application.NewEnvironmentService(nil), nil)
+
+	req := authenticate(t, svc, schemes, securedOperation(domain.SecurityRequirement{"bearer-auth": nil, "api_key": nil}))
+	if got := req.Header.Get("Authorization"); got != "Bearer from-env" {
+		t.Errorf("expected token from DAZZLE_BEARER_AUTH_TOKEN, got %q", got)
+	}
+	if got := req.Header.Get("X-Key"); got != "from-config" {
+		t.Errorf("expected substituted api key, got %q", got)
+	}
+}
+
+func TestAuthService_PicksSatisfiableRequirement(t *testing.T) {
+	schemes := map[string]domain.SecurityScheme{
+		"oauth":   {Type: domain.SecuritySchemeOAuth2, Flows: &domain.OAuthFlows{ClientCredentials: &domain.OAuthFlow{TokenURL: "http://unused"}}},
+		"api_key": {Type: domain.SecuritySchemeAPIKey, Name: "X-Key", In: domain.ParameterInHeader},
+	}
+	op := securedOperation(domain.SecurityRequirement{"oauth": nil}, domain.SecurityRequirement{"api_key": nil})
+
+	svc := application.NewAuthService(credentialMap{"api_key": {APIKey: "k"}}, nil, nil)
+	req := authenticate(t, svc, schemes, op)
+	if req.Header.Get("X-Key") != "k" || req.Header.Get("Authorization") != "" {
+		t.Errorf("expected the api key alternative, got %v", req.Header)
+	}
+
+	svc = application.NewAuthService(credentialMap{}, nil, nil)
+	req = authenticate(t, svc, schemes, op)
+	if len(req.Header) != 0 {
+		t.Errorf("expected an unauthenticated request without credentials, got %v", req.Header)
+	}
+}
+
+func TestAuthService_UndefinedVariable(t *testing.T) {
+	schemes := map[string]domain.SecurityScheme{"bearer": {Type: domain.SecuritySchemeHTTP, Scheme: "bearer"}}
+	svc := application.NewAuthService(credentialMap{"bearer": {Token: "{{token}}"}}, application.NewEnvironmentService(nil), nil)
+
+	err := svc.Authenticate(context.Background(), schemes, securedOperation(domain.SecurityRequirement{"bearer": nil}), domain.Environment{Name: "dev"}, newRequest())
+	if err == nil || err.Error() != "credentials for bearer: undefined variable token (dev)" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func oauthSchemes(tokenURL string) map[string]domain.SecurityScheme {
+	return map[string]domain.SecurityScheme{
+		"oauth": {Type: domain.SecuritySchemeOAuth2, Flows: &domain.OAuthFlows{
+			ClientCredentials: &domain.OAuthFlow{TokenURL: tokenURL, Scopes: map[string]string{"read:pets": ""}},
+		}},
+	}
+}
+
+func TestAuthService_ClientCredentialsCachesToken(t *testing.T) {
+	endpoint := &tokenEndpoint{expiresIn: 3600}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	svc := application.NewAuthService(credentialMap{
+		"oauth": {ClientID: "dazzle", ClientSecret: "s3cret"},
+	}, nil, httpclient.NewClient(5*time.Second))
+	op := securedOperation(domain.SecurityRequirement{"oauth": {"read:pets"}})
+
+	for range 2 {
+		req := authenticate(t, svc, oauthSchemes(srv.URL), op)
+		if got := req.Header.Get("Authorization"); got != "Bearer token-1" {
+			t.Errorf("unexpected Authorization %q", got)
+		}
+	}
+
+	if len(endpoint.grants) != 1 || endpoint.grants[0] != "client_credentials" {
+		t.Fatalf("expected one client_credentials grant, got %v", endpoint.grants)
+	}
+	if endpoint.basicUser != "dazzle" {
+		t.Errorf("expected client authentication with HTTP Basic, got user %q", endpoint.basicUser)
+	}
+	if endpoint.forms[0]["scope"] != "read:pets" {
+		t.Errorf("expected the requirement's scopes, got %v", endpoint.forms[0])
+	}
+}
+
+func TestAuthService_RefreshesExpiringToken(t *testing.T) {
+	// A lifetime inside the refresh margin makes every token stale at once.
+	endpoint := &tokenEndpoint{expiresIn: 5, refresh: "r1"}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	svc := application.NewAuthService(credentialMap{
+		"oauth": {ClientID: "dazzle", ClientSecret: "s3cret"},
+	}, nil, httpclient.NewClient(5*time.Second))
+	op := securedOperation(domain.SecurityRequirement{"oauth": nil})
+
+	authenticate(t, svc, oauthSchemes(srv.URL), op)
+	req := authenticate(t, svc, oauthSchemes(srv.URL), op)
+
+	if strings.Join(endpoint.grants, ",") != "client_credentials,refresh_token" {
+		t.Fatalf("expected the token to be refreshed, got grants %v", endpoint.grants)
+	}
+	if endpoint.forms[1]["refresh_token"] != "r1" {
+		t.Errorf("expected the cached refresh token, got %v", endpoint.forms[1])
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer token-2" {
+		t.Errorf("expected the refreshed token, got %q", got)
+	}
+}
+
+func TestAuthService_ConfiguredRefreshToken(t *testing.T) {
+	endpoint := &tokenEndpoint{expiresIn: 3600}
+	srv := httptest.NewServer(endpoint)
+	defer srv.Close()
+
+	schemes := map[string]domain.SecurityScheme{
+		"oauth": {Type: domain.SecuritySchemeOAuth2, Flows: &domain.OAuthFlows{
+			AuthorizationCode: &domain.OAuthFlow{AuthorizationURL: "http://unused", TokenURL: srv.URL},
+		}},
+	}
+	svc := application.NewAuthService(credentialMap{
+		"oauth": {ClientID: "public-client", RefreshToken: "long-lived"},
+	}, nil, httpclient.NewClient(5*time.Second))
+
+	req := authenticate(t, svc, schemes, securedOperation(domain.SecurityRequirement{"oauth": nil}))
+	if got := req.Header.Get("Authorization"); got != "Bearer token-1" {
+		t.Errorf("unexpected Authorization %q", got)
+	}
+	form := endpoint.forms[0]
+	if form["grant_type"] != "refresh_token" || form["refresh_token"] != "long-lived" || form["client_id"] != "public-client" {
+		t.Errorf("unexpected refresh grant %v", form)
+	}
+}
+
+func TestAuthService_TokenEndpointError(t *testing.T) {
+	srv := httptest.NewServer(&tokenEndpoint{fail: true})
+	defer srv.Close()
+
+	svc := application.NewAuthService(credentialMap{
+		"oauth": {ClientID: "dazzle", ClientSecret: "wrong"},
+	}, nil, httpclient.NewClient(5*time.Second))
+
+	err := svc.Authenticate(context.Background(), oauthSchemes(srv.URL), securedOperation(domain.SecurityRequirement{"oauth": nil}), domain.Environment{}, newRequest())
+	want := "oauth: client_credentials grant: token endpoint returned 401: invalid_client: bad secret"
+	if err == nil || err.Error() != want {
+		t.Errorf("expected %q, got %v", want, err)
+	}
+}
@@ -0,0 +1,347 @@
+package application
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+// ChainService implements domain.ChainService.
+type ChainService struct{}
+
+func NewChainService() *ChainService { return &ChainService{} }
+
+func (s *ChainService) Capture(captures []domain.Capture, resp *domain.HTTPResponse) (map[string]string, error) {
+	body := lazyJSON(resp.Body)
+	out := map[string]string{}
+	var errs []error
+	for _, cp := range captures {
+		value, err := extractCapture(cp, resp, body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("capturing %s: %w", cp.Variable, err))
+			continue
+		}
+		out[cp.Variable] = value
+	}
+	return out, errors.Join(errs...)
+}
+
+// lazyJSON decodes a body the first time it is asked for.
+func lazyJSON(data []byte) func() (any, error) {
+	var (
+		decoded bool
+		doc     any
+		err     error
+	)
+	return func() (any, error) {
+		if !decoded {
+			decoded = true
+			if doc, err = decodeJSON(data); err != nil {
+				err = fmt.Errorf("body is not JSON: %w", err)
+			}
+		}
+		return doc, err
+	}
+}
+
+// extractCapture finds a capture's value in resp, taking the decoded JSON
+// body from body.
+func extractCapture(cp domain.Capture, resp *domain.HTTPResponse, body func() (any, error)) (string, error) {
+	var text string
+	switch {
+	case cp.Header != "":
+		if len(resp.Header.Values(cp.Header)) == 0 {
+			return "", fmt.Errorf("header %s is missing", cp.Header)
+		}
+		text = resp.Header.Get(cp.Header)
+	case cp.Path != "":
+		doc, err := body()
+		if err != nil {
+			return "", err
+		}
+		found, err := jsonpath.Eval(cp.Path, doc)
+		if err != nil {
+			return "", err
+		}
+		if len(found) == 0 {
+			return "", fmt.Errorf("%s matched nothing", cp.Path)
+		}
+		text = textValue(found[0])
+	default:
+		text = string(resp.Body)
+	}
+	if cp.Regex == "" {
+		return text, nil
+	}
+	re, err := regexp.Compile(cp.Regex)
+	if err != nil {
+		return "", fmt.Errorf("invalid regex: %w", err)
+	}
+	m := re.FindStringSubmatch(text)
+	switch {
+	case m == nil:
+		return "", fmt.Errorf("/%s/ matched nothing", cp.Regex)
+	case len(m) > 1:
+		return m[1], nil
+	default:
+		return m[0], nil
+	}
+}
+
+// textValue is a string as it is, and any other value as JSON.
+func textValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return displayValue(v)
+}
+
+// FollowLink fills in the parameters and body the link names. Server is
+// the link's server, if it has one; the rest is left for the caller. The
+// values are escaped so that what the exchange held is sent as it is.
+func (s *ChainService) FollowLink(link domain.Link, ops []domain.Operation, values domain.RequestValues, req *domain.HTTPRequest, resp *domain.HTTPResponse) (domain.Operation, domain.RequestValues, error) {
+	op, err := linkTarget(link, ops)
+	if err != nil {
+		return domain.Operation{}, domain.RequestValues{}, err
+	}
+	x := &exchange{values: values, req: req, resp: resp, reqBody: lazyJSON(req.Body), respBody: lazyJSON(resp.Body)}
+
+	out := domain.RequestValues{Server: link.Server, Path: map[string]string{}, Query: map[string]string{},
+		Header: map[string]string{}, Cookie: map[string]string{}}
+	for _, name := range sortedKeys(link.Parameters) {
+		p, ok := linkParameter(op, name)
+		if !ok {
+			return op, out, fmt.Errorf("link parameter %q is not a parameter of %s", name, operationName(op))
+		}
+		v, err := x.resolve(link.Parameters[name])
+		if err != nil {
+			return op, out, fmt.Errorf("link parameter %s: %w", name, err)
+		}
+		setParameter(&out, p, parameterString(v))
+	}
+	if link.RequestBody != nil {
+		v, err := x.resolve(link.RequestBody)
+		if err != nil {
+			return op, out, fmt.Errorf("link request body: %w", err)
+		}
+		if text, ok := v.(string); ok {
+			out.Body = text
+		} else {
+			data, err := json.MarshalIndent(v, "", "  ")
+			if err != nil {
+				return op, out, fmt.Errorf("link request body: %w", err)
+			}
+			out.Body = string(data)
+			out.ContentType = "application/json"
+			if op.RequestBody != nil {
+				for _, ct := range sortedKeys(op.RequestBody.Content) {
+					if isJSONMediaType(ct) {
+						out.ContentType = ct
+						break
+					}
+				}
+			}
+		}
+	}
+	return op, out.EscapeVariables(), nil
+}
+
+func operationName(op domain.Operation) string {
+	if op.ID != "" {
+		return op.ID
+	}
+	return string(op.Method) + " " + op.Path
+}
+
+// linkTarget finds the operation a link points to, by operationId or by a
+// reference such as "#/paths/~1pets~1{petId}/get".
+func linkTarget(link domain.Link, ops []domain.Operation) (domain.Operation, error) {
+	if link.OperationID != "" {
+		for _, op := range ops {
+			if op.ID == link.OperationID {
+				return op, nil
+			}
+		}
+		return domain.Operation{}, fmt.Errorf("link to unknown operation %q", link.OperationID)
+	}
+	_, ref, _ := strings.Cut(link.OperationRef, "#")
+	parts := strings.Split(ref, "/")
+	if len(parts) != 4 || parts[0] != "" || parts[1] != "paths" {
+		return domain.Operation{}, fmt.Errorf("unsupported operationRef %q", link.OperationRef)
+	}
+	path := unescapePointer(parts[2])
+	for _, op := range ops {
+		if op.Path == path && strings.EqualFold(string(op.Method), parts[3]) {
+			return op, nil
+		}
+	}
+	return domain.Operation{}, fmt.Errorf("link to unknown operation %s", link.OperationRef)
+}
+
+// linkParameter finds a parameter by name, or by "path.name" and the like
+// when the name alone is ambiguous.
+func linkParameter(op domain.Operation, name string) (domain.Parameter, bool) {
+	for _, p := range op.Parameters {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	if in, rest, ok := strings.Cut(name, "."); ok {
+		for _, p := range op.Parameters {
+			if string(p.In) == in && p.Name == rest {
+				return p, true
+			}
+		}
+	}
+	return domain.Parameter{}, false
+}
+
+func unescapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~1", "/"), "~0", "~")
+}
+
+// exchange is a request and its response, which runtime expressions read.
+type exchange struct {
+	values   domain.RequestValues
+	req      *domain.HTTPRequest
+	resp     *domain.HTTPResponse
+	reqBody  func() (any, error)
+	respBody func() (any, error)
+}
+
+// expressionPattern finds the expressions embedded in a string, as in
+// "pets/{$response.body#/id}".
+var expressionPattern = regexp.MustCompile(`\{(\$[^}]+)\}`)
+
+// resolve evaluates the runtime expressions in a link's value: a string
+// that is one, those embedded in a string, and those within objects and
+// arrays. Anything else is a constant.
+func (x *exchange) resolve(v any) (any, error) {
+	switch t := v.(type) {
+	case string:
+		if strings.HasPrefix(t, "$") {
+			return x.eval(t)
+		}
+		var err error
+		out := expressionPattern.ReplaceAllStringFunc(t, func(m string) string {
+			v, e := x.eval(m[1 : len(m)-1])
+			if e != nil {
+				err = errors.Join(err, e)
+				return m
+			}
+			return textValue(v)
+		})
+		return out, err
+	case map[string]any:
+		out := make(map[string]any, len(t))
+		for k, val := range t {
+			r, err := x.resolve(val)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = r
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(t))
+		for i, val := range t {
+			r, err := x.resolve(val)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+// eval evaluates a runtime expression such as "$statusCode",
+// "$request.path.petId", "$response.header.Location" or
+// "$response.body#/items/0/id".
+func (x *exchange) eval(expr string) (any, error) {
+	switch expr {
+	case "$url":
+		return x.req.URL, nil
+	case "$method":
+		return string(x.req.Method), nil
+	case "$statusCode":
+		return x.resp.StatusCode, nil
+	}
+	source, ok := strings.CutPrefix(expr, "$request.")
+	header, body := x.req.Header, x.reqBody
+	params := map[string]map[string]string{"path": x.values.Path, "query": x.values.Query}
+	if !ok {
+		if source, ok = strings.CutPrefix(expr, "$response."); !ok {
+			return nil, fmt.Errorf("unsupported expression %s", expr)
+		}
+		header, body, params = x.resp.Header, x.respBody, nil
+	}
+
+	if ptr, ok := strings.CutPrefix(source, "body"); ok && (ptr == "" || strings.HasPrefix(ptr, "#")) {
+		doc, err := body()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", expr, err)
+		}
+		v, err := pointerValue(doc, strings.TrimPrefix(ptr, "#"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", expr, err)
+		}
+		return v, nil
+	}
+	in, name, ok := strings.Cut(source, ".")
+	if !ok || name == "" {
+		return nil, fmt.Errorf("unsupported expression %s", expr)
+	}
+	if in == "header" {
+		if vs := header.Values(name); len(vs) > 0 {
+			return vs[0], nil
+		}
+		return nil, fmt.Errorf("%s: header is missing", expr)
+	}
+	values, ok := params[in]
+	if !ok {
+		return nil, fmt.Errorf("unsupported expression %s", expr)
+	}
+	if v, ok := values[name]; ok {
+		return v, nil
+	}
+	return nil, fmt.Errorf("%s: parameter is missing", expr)
+}
+
+// pointerValue follows a JSON pointer, such as "/items/0/id", into doc.
+func pointerValue(doc any, ptr string) (any, error) {
+	if ptr == "" {
+		return doc, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", ptr)
+	}
+	v := doc
+	for _, part := range strings.Split(ptr[1:], "/") {
+		part = unescapePointer(part)
+		switch t := v.(type) {
+		case map[string]any:
+			next, ok := t[part]
+			if !ok {
+				return nil, fmt.Errorf("no %q member", part)
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(part)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, fmt.Errorf("no item %s", part)
+			}
+			v = t[i]
+		default:
+			return nil, fmt.Errorf("no %q in %s", part, displayValue(v))
+		}
+	}
+	return v, nil
+}
@@ -0,0 +1,132 @@
+package application_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func TestChainService_Capture(t *testing.T) {
+	resp := &domain.HTTPResponse{
+		StatusCode: 201,
+		Header:     http.Header{"Location": {"/v1/pets/7"}},
+		Body:       []byte(`{"id": 7, "name": "Rex", "tags": ["a", "b"], "token": "Bearer abc.def"}`),
+	}
+	got, err := application.NewChainService().Capture([]domain.Capture{
+		{Variable: "petId", Path: "$.id"},
+		{Variable: "name", Path: "$.name"},
+		{Variable: "tags", Path: "$.tags"},
+		{Variable: "location", Header: "Location"},
+		{Variable: "fromHeader", Header: "location", Regex: `/pets/(\d+)$`},
+		{Variable: "token", Path: "$.token", Regex: `[^ ]+$`},
+		{Variable: "fromBody", Regex: `"name": "(\w+)"`},
+		{Variable: "missing", Path: "$.owner"},
+		{Variable: "unmatched", Header: "Location", Regex: `^https:`},
+	}, resp)
+
+	want := map[string]string{
+		"petId": "7", "name": "Rex", "tags": `["a","b"]`, "location": "/v1/pets/7",
+		"fromHeader": "7", "token": "abc.def", "fromBody": "Rex",
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got[k])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected only %d values, got %v", len(want), got)
+	}
+	if err == nil || !strings.Contains(err.Error(), "capturing missing: $.owner matched nothing") ||
+		!strings.Contains(err.Error(), "capturing unmatched: /^https:/ matched nothing") {
+		t.Errorf("expected both failures reported, got %v", err)
+	}
+}
+
+func TestChainService_FollowLink(t *testing.T) {
+	ops := mockSpec().Operations
+	values := domain.RequestValues{Server: "https://api.example.com/v1", Query: map[string]string{"limit": "5"}}
+	req := &domain.HTTPRequest{Method: domain.POST, URL: "https://api.example.com/v1/pets",
+		Header: http.Header{"X-Request-Id": {"r1"}}, Body: []byte(`{"name": "Rex"}`)}
+	resp := &domain.HTTPResponse{StatusCode: 201, Header: http.Header{"Location": {"/v1/pets/7"}},
+		Body: []byte(`{"id": 7, "owner": {"name": "Ann"}, "tags": ["a/b"]}`)}
+	chain := application.NewChainService()
+
+	t.Run("parameters and body", func(t *testing.T) {
+		link := domain.Link{
+			OperationID: "updatePet",
+			Parameters:  map[string]any{"path.petId": "$response.body#/id"},
+			RequestBody: map[string]any{
+				"name":  "$request.body#/name",
+				"note":  "made by {$method} {$url} ({$statusCode}, {$request.header.x-request-id})",
+				"owner": "$response.body#/owner",
+				"limit": "$request.query.limit",
+				"kind":  "dog",
+			},
+		}
+		op, got, err := chain.FollowLink(link, ops, values, req, resp)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if op.ID != "updatePet" || got.Path["petId"] != "7" || got.Server != "" || got.ContentType != "application/json" {
+			t.Errorf("unexpected %s %+v", op.ID, got)
+		}
+		for _, want := range []string{
+			`"name": "Rex"`,
+			`"note": "made by POST https://api.example.com/v1/pets (201, r1)"`,
+			`"owner": {`,
+			`"limit": "5"`,
+			`"kind": "dog"`,
+		} {
+			if !strings.Contains(got.Body, want) {
+				t.Errorf("expected %s in the body, got %s", want, got.Body)
+			}
+		}
+	})
+
+	t.Run("operationRef", func(t *testing.T) {
+		link := domain.Link{
+			OperationRef: "#/paths/~1pets~1{petId}/get",
+			Parameters:   map[string]any{"petId": "{$response.header.Location}"},
+			Server:       "https://other.example.com",
+		}
+		op, got, err := chain.FollowLink(link, ops, values, req, resp)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if op.ID != "getPet" || got.Path["petId"] != "/v1/pets/7" || got.Server != "https://other.example.com" {
+			t.Errorf("unexpected %s %+v", op.ID, got)
+		}
+	})
+
+	t.Run("values stay literal", func(t *testing.T) {
+		resp := &domain.HTTPResponse{StatusCode: 201, Body: []byte(`{"id": "{{env:AWS_SECRET_ACCESS_KEY}}"}`)}
+		link := domain.Link{OperationID: "getPet", Parameters: map[string]any{"petId": "$response.body#/id"}}
+		_, got, err := chain.FollowLink(link, ops, values, req, resp)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Path["petId"] != `\{{env:AWS_SECRET_ACCESS_KEY}}` {
+			t.Errorf("expected the value escaped, got %q", got.Path["petId"])
+		}
+	})
+
+	for name, tc := range map[string]struct {
+		link domain.Link
+		want string
+	}{
+		"unknown operation": {domain.Link{OperationID: "nope"}, `unknown operation "nope"`},
+		"unknown ref":       {domain.Link{OperationRef: "#/paths/~1owners/get"}, "unknown operation #/paths/~1owners/get"},
+		"unknown parameter": {domain.Link{OperationID: "getPet", Parameters: map[string]any{"id": 1}}, `"id" is not a parameter of getPet`},
+		"missing value":     {domain.Link{OperationID: "getPet", Parameters: map[string]any{"petId": "$response.body#/tags/3"}}, "no item 3"},
+		"unsupported":       {domain.Link{OperationID: "getPet", Parameters: map[string]any{"petId": "$request.cookie.x"}}, "unsupported expression"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := chain.FollowLink(tc.link, ops, values, req, resp); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("expected an error containing %q, got %v", tc.want, err)
+			}
+		})
+	}
+}
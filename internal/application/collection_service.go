@@ -0,0 +1,49 @@
+package application
+
+import (
+	"errors"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// DefaultCollection holds saved requests when no collection is named.
+const DefaultCollection = "default"
+
+// CollectionService implements domain.CollectionService.
+type CollectionService struct {
+	repo domain.CollectionRepository
+}
+
+func NewCollectionService(repo domain.CollectionRepository) *CollectionService {
+	return &CollectionService{repo: repo}
+}
+
+func (s *CollectionService) LoadCollections() ([]domain.Collection, error) {
+	return s.repo.LoadCollections()
+}
+
+func (s *CollectionService) SaveRequest(req domain.SavedRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Collection = strings.TrimSpace(req.Collection)
+	if req.Name == "" {
+		return errors.New("a saved request needs a name")
+	}
+	if req.OperationID == "" {
+		return errors.New("a saved request needs an operation")
+	}
+	if req.Collection == "" {
+		req.Collection = DefaultCollection
+	}
+	return s.repo.SaveRequest(req)
+}
+
+func (s *CollectionService) ByOperation(collections []domain.Collection) map[string][]domain.SavedRequest {
+	byOp := make(map[string][]domain.SavedRequest)
+	for _, c := range collections {
+		for _, r := range c.Requests {
+			byOp[r.OperationID] = append(byOp[r.OperationID], r)
+		}
+	}
+	return byOp
+}
@@ -0,0 +1,55 @@
+package application_test
+
+import (
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+// collectionList is an in-memory CollectionRepository.
+type collectionList struct {
+	saved []domain.SavedRequest
+}
+
+func (l *collectionList) LoadCollections() ([]domain.Collection, error) {
+	var out []domain.Collection
+	for _, r := range l.saved {
+		out = append(out, domain.Collection{Name: r.Collection, Requests: []domain.SavedRequest{r}})
+	}
+	return out, nil
+}
+
+func (l *collectionList) SaveRequest(r domain.SavedRequest) error {
+	l.saved = append(l.saved, r)
+	return nil
+}
+
+func TestCollectionService_SaveRequest(t *testing.T) {
+	repo := &collectionList{}
+	svc := application.NewCollectionService(repo)
+
+	if err := svc.SaveRequest(domain.SavedRequest{Name: "  Rex ", OperationID: "getPet"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := repo.saved[0]; got.Name != "Rex" || got.Collection != application.DefaultCollection {
+		t.Errorf("expected a trimmed name in the default collection, got %+v", got)
+	}
+	if err := svc.SaveRequest(domain.SavedRequest{Name: " ", OperationID: "getPet"}); err == nil {
+		t.Error("expected a blank name to be refused")
+	}
+}
+
+func TestCollectionService_ByOperation(t *testing.T) {
+	svc := application.NewCollectionService(&collectionList{})
+	byOp := svc.ByOperation([]domain.Collection{
+		{Name: "pets", Requests: []domain.SavedRequest{{Name: "Rex", OperationID: "getPet"}, {Name: "New", OperationID: "createPet"}}},
+		{Name: "smoke", Requests: []domain.SavedRequest{{Name: "Tom", OperationID: "getPet"}}},
+	})
+	if got := byOp["getPet"]; len(got) != 2 || got[0].Name != "Rex" || got[1].Name != "Tom" {
+		t.Errorf("unexpected getPet requests %+v", got)
+	}
+	if len(byOp["createPet"]) != 1 {
+		t.Errorf("unexpected createPet requests %+v", byOp["createPet"])
+	}
+}
@@ -0,0 +1,389 @@
+package application
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// curlCommand is what a curl command line asks for.
+type curlCommand struct {
+	method      string // empty unless set with -X
+	url         string
+	header      http.Header
+	data        []string // -d and friends, joined with & as curl does
+	hasData     bool
+	json        bool // --json
+	get         bool // -G: data goes in the query
+	head        bool // -I
+	cookie      string
+	unsupported []string // options that affect the request but cannot be imported
+}
+
+// curlArgOptions take a value that does not change the request.
+var curlArgOptions = map[string]bool{
+	"-o": true, "--output": true, "-w": true, "--write-out": true,
+	"-m": true, "--max-time": true, "--connect-timeout": true,
+	"-x": true, "--proxy": true, "-U": true, "--proxy-user": true,
+	"--retry": true, "--retry-delay": true, "--retry-max-time": true,
+	"--cacert": true, "--capath": true, "-E": true, "--cert": true,
+	"--key": true, "--cert-type": true, "--key-type": true,
+	"-c": true, "--cookie-jar": true, "--resolve": true, "--connect-to": true,
+	"--limit-rate": true, "--max-redirs": true, "--interface": true,
+	"-K": true, "--config": true, "--trace": true, "--trace-ascii": true,
+	"-D": true, "--dump-header": true, "--stderr": true,
+}
+
+// curlShortFlags are single-letter options without a value that do not
+// change the request. They may be combined, as in -sSL, and with -G, -I and
+// an option that takes a value, as in -sX POST.
+const curlShortFlags = "sSLkvifgqNZ#0123456"
+
+// parseCurl parses a curl command line as a POSIX shell would split it.
+func parseCurl(command string) (curlCommand, error) {
+	words, err := shellWords(command)
+	if err != nil {
+		return curlCommand{}, err
+	}
+	if len(words) > 0 && words[0] == "$" {
+		words = words[1:]
+	}
+	if len(words) == 0 || words[0] != "curl" {
+		return curlCommand{}, errors.New("not a curl command")
+	}
+
+	c := curlCommand{header: make(http.Header)}
+	args := words[1:]
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if c.url == "" {
+				c.url = arg
+			}
+			continue
+		}
+
+		name, value, attached := arg, "", false
+		if !strings.HasPrefix(arg, "--") && len(arg) > 2 {
+			// In a cluster such as -sSX, the first option that takes a
+			// value takes the rest of the cluster, or the next argument
+			// when nothing is left.
+			j := 1
+			for ; j < len(arg) && strings.IndexByte(curlShortFlags+"GI", arg[j]) >= 0; j++ {
+				switch arg[j] {
+				case 'G':
+					c.get = true
+				case 'I':
+					c.head = true
+				}
+			}
+			if j == len(arg) {
+				continue
+			}
+			name, value = "-"+arg[j:j+1], arg[j+1:]
+			attached = value != ""
+		}
+		next := func() (string, error) {
+			if attached {
+				return value, nil
+			}
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("curl option %s needs a value", name)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch name {
+		case "-X", "--request":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.method = strings.ToUpper(v)
+		case "-H", "--header":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			if k, hv, ok := strings.Cut(v, ":"); ok {
+				c.header.Add(strings.TrimSpace(k), strings.TrimSpace(hv))
+			}
+		case "-d", "--data", "--data-ascii", "--data-binary", "--data-raw", "--json":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			if strings.HasPrefix(v, "@") && name != "--data-raw" {
+				c.unsupported = append(c.unsupported, name+" "+v)
+				continue
+			}
+			if name == "-d" || name == "--data" || name == "--data-ascii" {
+				v = strings.NewReplacer("\r", "", "\n", "").Replace(v)
+			}
+			c.data = append(c.data, v)
+			c.hasData = true
+			c.json = c.json || name == "--json"
+		case "--data-urlencode":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.data = append(c.data, urlencodeData(v))
+			c.hasData = true
+		case "-F", "--form", "--form-string", "-T", "--upload-file":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.unsupported = append(c.unsupported, name+" "+v)
+		case "-u", "--user":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(v)))
+		case "-b", "--cookie":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			if strings.Contains(v, "=") {
+				c.cookie = joinNonEmpty(c.cookie, v, "; ")
+			} else {
+				c.unsupported = append(c.unsupported, name+" "+v)
+			}
+		case "-A", "--user-agent":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.header.Set("User-Agent", v)
+		case "-e", "--referer":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.header.Set("Referer", v)
+		case "-r", "--range":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.header.Set("Range", "bytes="+v)
+		case "--url":
+			v, err := next()
+			if err != nil {
+				return c, err
+			}
+			c.url = v
+		case "-G", "--get":
+			c.get = true
+		case "-I", "--head":
+			c.head = true
+		default:
+			// Other options only change how curl behaves.
+			if curlArgOptions[name] {
+				if _, err := next(); err != nil {
+					return c, err
+				}
+			}
+		}
+	}
+	if c.url == "" {
+		return c, errors.New("curl command has no URL")
+	}
+	if !strings.Contains(c.url, "://") {
+		c.url = "http://" + c.url
+	}
+	return c, nil
+}
+
+// requestMethod is the method curl would use.
+func (c curlCommand) requestMethod() string {
+	switch {
+	case c.method != "":
+		return c.method
+	case c.head:
+		return http.MethodHead
+	case c.hasData && !c.get:
+		return http.MethodPost
+	}
+	return http.MethodGet
+}
+
+// body is the request body; with -G the data goes in the query instead.
+func (c curlCommand) body() string {
+	if c.get {
+		return ""
+	}
+	return strings.Join(c.data, "&")
+}
+
+// contentType is the body's media type: the Content-Type header, or what
+// curl sends by default for the data options used.
+func (c curlCommand) contentType() string {
+	if ct := c.header.Get("Content-Type"); ct != "" {
+		return ct
+	}
+	if c.json {
+		return "application/json"
+	}
+	return "application/x-www-form-urlencoded"
+}
+
+// urlencodeData encodes a --data-urlencode value: "name=value" encodes
+// only the value, "=value" and a bare value encode all of it.
+func urlencodeData(v string) string {
+	name, value, ok := strings.Cut(v, "=")
+	switch {
+	case !ok:
+		return url.QueryEscape(v)
+	case name == "":
+		return url.QueryEscape(value)
+	}
+	return name + "=" + url.QueryEscape(value)
+}
+
+func joinNonEmpty(a, b, sep string) string {
+	if a == "" {
+		return b
+	}
+	return a + sep + b
+}
+
+// shellWords splits a command line like a POSIX shell: single and double
+// quotes, $'…' strings, backslash escapes and line continuations.
+func shellWords(s string) ([]string, error) {
+	var words []string
+	var cur strings.Builder
+	inWord := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				i++
+				continue
+			}
+			if i+2 < len(s) && s[i+1] == '\r' && s[i+2] == '\n' {
+				i += 2
+				continue
+			}
+			if i+1 < len(s) {
+				i++
+				cur.WriteByte(s[i])
+				inWord = true
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, errors.New("unterminated single quote")
+			}
+			cur.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '$' && i+1 < len(s) && s[i+1] == '\'':
+			n, err := ansiCString(s[i+2:], &cur)
+			if err != nil {
+				return nil, err
+			}
+			i += n + 1
+			inWord = true
+		case c == '"':
+			n, err := doubleQuoted(s[i+1:], &cur)
+			if err != nil {
+				return nil, err
+			}
+			i += n
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		default:
+			cur.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, cur.String())
+	}
+	return words, nil
+}
+
+// doubleQuoted copies a double-quoted string, whose opening quote has been
+// consumed, and returns the bytes read including the closing quote.
+func doubleQuoted(s string, out *strings.Builder) (int, error) {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"':
+			return i + 1, nil
+		case '\\':
+			if i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+				i++
+				if s[i] != '\n' {
+					out.WriteByte(s[i])
+				}
+				continue
+			}
+			out.WriteByte(c)
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return 0, errors.New("unterminated double quote")
+}
+
+// ansiCString copies a $'…' string, whose opening has been consumed, and
+// returns the bytes read including the closing quote.
+func ansiCString(s string, out *strings.Builder) (int, error) {
+	escapes := map[byte]byte{'n': '\n', 't': '\t', 'r': '\r', '\\': '\\', '\'': '\'', '"': '"', 'a': '\a', 'b': '\b', 'e': 0x1b, 'f': '\f', 'v': '\v'}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\'' {
+			return i + 1, nil
+		}
+		if c != '\\' || i+1 >= len(s) {
+			out.WriteByte(c)
+			continue
+		}
+		i++
+		if e, ok := escapes[s[i]]; ok {
+			out.WriteByte(e)
+			continue
+		}
+		if s[i] == 'x' || s[i] == 'u' {
+			digits := 2
+			if s[i] == 'u' {
+				digits = 4
+			}
+			end := i + 1
+			for end < len(s) && end < i+1+digits && isHex(s[end]) {
+				end++
+			}
+			if n, err := strconv.ParseUint(s[i+1:end], 16, 32); err == nil {
+				if s[i] == 'x' {
+					out.WriteByte(byte(n))
+				} else {
+					out.WriteRune(rune(n))
+				}
+				i = end - 1
+				continue
+			}
+		}
+		out.WriteByte('\\')
+		out.WriteByte(s[i])
+	}
+	return 0, errors.New("unterminated $' quote")
+}
+
+func isHex(c byte) bool {
+	return '0' <= c && c <= '9' || 'a' <= c && c <= 'f' || 'A' <= c && c <= 'F'
+}
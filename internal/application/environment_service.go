@@ -0,0 +1,126 @@
+package application
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// maxVariableDepth bounds how deeply variables may refer to one another,
+// which also stops reference cycles.
+const maxVariableDepth = 10
+
+// variablePattern matches {{name}} and {{env:NAME}}, allowing spaces inside
+// the braces, and the escaped \{{ of domain.EscapeVariables, which has no
+// name.
+var variablePattern = regexp.MustCompile(`\\\{\{|\{\{\s*((?:env:)?[A-Za-z_][\w.-]*)\s*\}\}`)
+
+// EnvironmentService implements domain.EnvironmentService.
+type EnvironmentService struct {
+	repo      domain.EnvironmentRepository
+	lookupEnv func(string) (string, bool)
+}
+
+func NewEnvironmentService(repo domain.EnvironmentRepository) *EnvironmentService {
+	return &EnvironmentService{repo: repo, lookupEnv: os.LookupEnv}
+}
+
+func (s *EnvironmentService) LoadEnvironments() (*domain.EnvironmentSet, error) {
+	return s.repo.Load()
+}
+
+// Substitute replaces {{name}} with the environment's variable and
+// {{env:NAME}} with the process environment variable, so secrets can stay
+// out of config files. Variable values may themselves contain references,
+// except where escaped: \{{ stands for a literal {{.
+func (s *EnvironmentService) Substitute(env domain.Environment, text string) (string, error) {
+	return s.substitute(env, text, 0)
+}
+
+func (s *EnvironmentService) substitute(env domain.Environment, text string, depth int) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	if depth > maxVariableDepth {
+		return "", fmt.Errorf("variables nested too deeply (is there a cycle?) in %q", text)
+	}
+
+	var undefined []string
+	var nestedErr error
+	out := variablePattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := variablePattern.FindStringSubmatch(match)[1]
+		if name == "" {
+			return "{{"
+		}
+		value, ok := s.lookup(env, name)
+		if !ok {
+			undefined = append(undefined, name)
+			return match
+		}
+		resolved, err := s.substitute(env, value, depth+1)
+		if err != nil && nestedErr == nil {
+			nestedErr = err
+		}
+		return resolved
+	})
+
+	if nestedErr != nil {
+		return "", nestedErr
+	}
+	if len(undefined) > 0 {
+		envName := env.Name
+		if envName == "" {
+			envName = "no environment"
+		}
+		return "", fmt.Errorf("undefined variable %s (%s)", strings.Join(undefined, ", "), envName)
+	}
+	return out, nil
+}
+
+func (s *EnvironmentService) lookup(env domain.Environment, name string) (string, bool) {
+	if osName, ok := strings.CutPrefix(name, "env:"); ok {
+		return s.lookupEnv(osName)
+	}
+	v, ok := env.Variables[name]
+	return v, ok
+}
+
+// SubstituteValues substitutes variables throughout a request's values.
+func (s *EnvironmentService) SubstituteValues(env domain.Environment, values domain.RequestValues) (domain.RequestValues, error) {
+	out := values
+	var err error
+	if out.Server, err = s.Substitute(env, values.Server); err != nil {
+		return values, fmt.Errorf("server: %w", err)
+	}
+	if out.Body, err = s.Substitute(env, values.Body); err != nil {
+		return values, fmt.Errorf("body: %w", err)
+	}
+
+	for _, m := range []struct {
+		in  domain.ParameterIn
+		src map[string]string
+		dst *map[string]string
+	}{
+		{domain.ParameterInPath, values.Path, &out.Path},
+		{domain.ParameterInQuery, values.Query, &out.Query},
+		{domain.ParameterInHeader, values.Header, &out.Header},
+		{domain.ParameterInCookie, values.Cookie, &out.Cookie},
+	} {
+		if m.src == nil {
+			continue
+		}
+		resolved := make(map[string]string, len(m.src))
+		for _, name := range sortedKeys(m.src) {
+			v, err := s.Substitute(env, m.src[name])
+			if err != nil {
+				return values, fmt.Errorf("%s: %w", domain.ParameterPath(m.in, name), err)
+			}
+			resolved[name] = v
+		}
+		*m.dst = resolved
+	}
+	return out, nil
+}
@@ -0,0 +1,105 @@
+package application_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func stagingEnv() domain.Environment {
+	return domain.Environment{Name: "staging", Variables: map[string]string{
+		"host":     "staging.example.com",
+		"baseUrl":  "https://{{host}}/v1",
+		"tenantId": "42",
+		"token":    "{{env:DAZZLE_TEST_TOKEN}}",
+		"loop":     "{{loop}}",
+	}}
+}
+
+func TestEnvironmentService_Substitute(t *testing.T) {
+	t.Setenv("DAZZLE_TEST_TOKEN", "s3cret")
+	svc := application.NewEnvironmentService(nil)
+
+	for _, tc := range []struct{ in, want string }{
+		{"plain", "plain"},
+		{"{{baseUrl}}/tenants/{{ tenantId }}", "https://staging.example.com/v1/tenants/42"},
+		{"Bearer {{token}}", "Bearer s3cret"},
+		{"{{env:DAZZLE_TEST_TOKEN}}", "s3cret"},
+		{"{ not a variable }", "{ not a variable }"},
+		{`\{{token}} is {{token}}`, "{{token}} is s3cret"},
+	} {
+		got, err := svc.Substitute(stagingEnv(), tc.in)
+		if err != nil {
+			t.Errorf("Substitute(%q): unexpected error: %v", tc.in, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("Substitute(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestEnvironmentService_SubstituteErrors(t *testing.T) {
+	svc := application.NewEnvironmentService(nil)
+
+	_, err := svc.Substitute(stagingEnv(), "{{missing}} and {{other}}")
+	if err == nil || err.Error() != "undefined variable missing, other (staging)" {
+		t.Errorf("unexpected error for undefined variables: %v", err)
+	}
+
+	_, err = svc.Substitute(domain.Environment{}, "{{baseUrl}}")
+	if err == nil || !strings.Contains(err.Error(), "(no environment)") {
+		t.Errorf("expected error to mention the missing environment, got %v", err)
+	}
+
+	_, err = svc.Substitute(stagingEnv(), "{{loop}}")
+	if err == nil || !strings.Contains(err.Error(), "cycle") {
+		t.Errorf("expected a cycle error, got %v", err)
+	}
+}
+
+func TestEnvironmentService_RuntimeVariablesStayLiteral(t *testing.T) {
+	t.Setenv("DAZZLE_TEST_TOKEN", "s3cret")
+	svc := application.NewEnvironmentService(nil)
+	set := &domain.EnvironmentSet{Environments: map[string]domain.Environment{"staging": stagingEnv()}}
+	set.SetVariable("staging", "petId", "{{env:DAZZLE_TEST_TOKEN}}")
+	env, _ := set.Get("staging")
+
+	got, err := svc.Substitute(env, "/pets/{{petId}} with {{token}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "/pets/{{env:DAZZLE_TEST_TOKEN}} with s3cret" {
+		t.Errorf("expected the runtime value used as it is, got %q", got)
+	}
+}
+
+func TestEnvironmentService_SubstituteValues(t *testing.T) {
+	svc := application.NewEnvironmentService(nil)
+
+	values, err := svc.SubstituteValues(stagingEnv(), domain.RequestValues{
+		Server: "{{baseUrl}}",
+		Path:   map[string]string{"tenantId": "{{tenantId}}"},
+		Header: map[string]string{"X-Host": "{{host}}"},
+		Body:   `{"tenant": {{tenantId}}}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if values.Server != "https://staging.example.com/v1" {
+		t.Errorf("unexpected server %q", values.Server)
+	}
+	if values.Path["tenantId"] != "42" || values.Header["X-Host"] != "staging.example.com" {
+		t.Errorf("unexpected parameters %v %v", values.Path, values.Header)
+	}
+	if values.Body != `{"tenant": 42}` {
+		t.Errorf("unexpected body %q", values.Body)
+	}
+
+	_, err = svc.SubstituteValues(stagingEnv(), domain.RequestValues{Query: map[string]string{"q": "{{nope}}"}})
+	if err == nil || !strings.HasPrefix(err.Error(), "query q: undefined variable nope") {
+		t.Errorf("expected error located at the parameter, got %v", err)
+	}
+}
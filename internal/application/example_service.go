@@ -0,0 +1,407 @@
+package application
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"dazzle/internal/domain"
+)
+
+// ExampleService implements domain.ExampleService. A schema's own example
+// or default is used when it has one; otherwise a value is generated from
+// its type, format, enum and constraints. Strings without a format are
+// chosen by property name, so an "email" or "createdAt" property looks
+// like one. Every call starts from the seed, so output does not depend on
+// what was generated before.
+type ExampleService struct {
+	seed uint64
+}
+
+func NewExampleService(seed uint64) *ExampleService {
+	return &ExampleService{seed: seed}
+}
+
+func (s *ExampleService) Generate(schema *domain.Schema) any {
+	g := &exampleGen{r: rand.New(rand.NewPCG(s.seed, s.seed^0x9e3779b97f4a7c15))}
+	return g.value(schema, "")
+}
+
+// exampleEpoch anchors generated dates so they are stable and plausible.
+var exampleEpoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+var (
+	exampleFirstNames = []string{"Ada", "Grace", "Alan", "Edsger", "Barbara", "Ken", "Margaret", "Linus"}
+	exampleLastNames  = []string{"Lovelace", "Hopper", "Turing", "Dijkstra", "Liskov", "Thompson", "Hamilton", "Torvalds"}
+	exampleWords      = []string{"alpha", "bravo", "delta", "echo", "falcon", "harbor", "juniper", "meadow", "nova", "orbit", "pixel", "quartz", "river", "summit", "tundra", "willow"}
+	exampleCities     = []string{"Amsterdam", "Lisbon", "Oslo", "Kyoto", "Austin", "Nairobi", "Montreal", "Auckland"}
+	exampleCountries  = []string{"NL", "PT", "NO", "JP", "US", "KE", "CA", "NZ"}
+	exampleCurrencies = []string{"EUR", "USD", "GBP", "JPY", "CAD"}
+)
+
+// exampleGen generates values from one random stream.
+type exampleGen struct {
+	r *rand.Rand
+}
+
+// value generates a value for schema; name is the property it belongs to,
+// used to pick realistic strings and numbers.
+func (g *exampleGen) value(s *domain.Schema, name string) any {
+	if s == nil {
+		return g.word()
+	}
+	switch {
+	case s.Example != nil:
+		return s.Example
+	case s.Default != nil:
+		return s.Default
+	case len(s.Enum) > 0:
+		return s.Enum[g.r.IntN(len(s.Enum))]
+	}
+
+	switch schemaKind(s) {
+	case domain.SchemaTypeObject:
+		return g.object(s)
+	case domain.SchemaTypeArray:
+		return g.array(s, name)
+	case domain.SchemaTypeInteger:
+		return g.integer(s, name)
+	case domain.SchemaTypeNumber:
+		return g.number(s, name)
+	case domain.SchemaTypeBoolean:
+		return g.r.IntN(2) == 0
+	}
+	return g.str(s, name)
+}
+
+// schemaKind is the schema's type, inferred from its keywords when unset.
+func schemaKind(s *domain.Schema) domain.SchemaType {
+	switch {
+	case s.Type != "":
+		return s.Type
+	case len(s.Properties) > 0:
+		return domain.SchemaTypeObject
+	case s.Items != nil:
+		return domain.SchemaTypeArray
+	}
+	return domain.SchemaTypeString
+}
+
+// object fills every property, in name order, plus any required property
+// the schema does not describe.
+func (g *exampleGen) object(s *domain.Schema) map[string]any {
+	out := make(map[string]any, len(s.Properties))
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		out[name] = g.value(s.Properties[name], name)
+	}
+	for _, name := range s.Required {
+		if _, ok := out[name]; !ok {
+			out[name] = g.value(nil, name)
+		}
+	}
+	return out
+}
+
+func (g *exampleGen) array(s *domain.Schema, name string) []any {
+	n := max(s.MinItems, 1+g.r.IntN(2))
+	if s.MaxItems != nil {
+		n = min(n, *s.MaxItems)
+	}
+	item := singular(name)
+	out := make([]any, n)
+	for i := range out {
+		out[i] = g.value(s.Items, item)
+	}
+	return out
+}
+
+// singular turns a plural property name into the name of one item, so the
+// items of "emails" look like e-mail addresses.
+func singular(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "s"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
+
+// numberRange returns the bounds a number must fall within, defaulting to
+// a range that suits the property name.
+func numberRange(s *domain.Schema, name string, step float64) (lo, hi float64) {
+	lo, hi = defaultRange(name)
+	switch {
+	case s.Minimum != nil && s.Maximum != nil:
+		lo, hi = *s.Minimum, *s.Maximum
+	case s.Minimum != nil:
+		lo = *s.Minimum
+		hi = max(hi, lo+100)
+	case s.Maximum != nil:
+		hi = *s.Maximum
+		if lo > hi {
+			lo = hi - 100
+		}
+	}
+	if s.ExclusiveMinimum {
+		lo += step
+	}
+	if s.ExclusiveMaximum {
+		hi -= step
+	}
+	return lo, hi
+}
+
+func defaultRange(name string) (float64, float64) {
+	lower := strings.ToLower(name)
+	switch {
+	case lower == "age", strings.HasSuffix(name, "Age"):
+		return 1, 15
+	case strings.Contains(lower, "year"):
+		return 2000, 2025
+	case strings.Contains(lower, "price"), strings.Contains(lower, "amount"), strings.Contains(lower, "total"):
+		return 1, 500
+	case strings.Contains(lower, "count"), strings.Contains(lower, "quantity"), strings.Contains(lower, "size"):
+		return 1, 20
+	case strings.HasSuffix(lower, "id"):
+		return 1, 1000
+	}
+	return 1, 100
+}
+
+func (g *exampleGen) integer(s *domain.Schema, name string) int64 {
+	lo, hi := numberRange(s, name, 1)
+	l, h := int64(math.Ceil(lo)), int64(math.Floor(hi))
+	if h <= l {
+		return l
+	}
+	return l + g.r.Int64N(h-l+1)
+}
+
+// number picks a value with two decimals, like a price.
+func (g *exampleGen) number(s *domain.Schema, name string) float64 {
+	lo, hi := numberRange(s, name, 0.01)
+	if hi <= lo {
+		return lo
+	}
+	v := math.Round((lo+g.r.Float64()*(hi-lo))*100) / 100
+	return math.Min(math.Max(v, lo), hi)
+}
+
+func (g *exampleGen) str(s *domain.Schema, name string) string {
+	if s.Pattern != "" {
+		if v, ok := g.fromPattern(s.Pattern); ok && fitsLength(s, v) {
+			return v
+		}
+	}
+	v := g.formatted(s.Format, name)
+	n := utf8.RuneCountInString(v)
+	if n < s.MinLength {
+		v += strings.Repeat("x", s.MinLength-n)
+	}
+	if s.MaxLength != nil && utf8.RuneCountInString(v) > *s.MaxLength {
+		v = string([]rune(v)[:*s.MaxLength])
+	}
+	return v
+}
+
+func fitsLength(s *domain.Schema, v string) bool {
+	n := utf8.RuneCountInString(v)
+	return n >= s.MinLength && (s.MaxLength == nil || n <= *s.MaxLength)
+}
+
+// formatted generates a string for a format, falling back to the property
+// name when the format says nothing.
+func (g *exampleGen) formatted(format, name string) string {
+	switch format {
+	case "uuid":
+		return g.uuid()
+	case "date-time":
+		return g.time().Format(time.RFC3339)
+	case "date":
+		return g.time().Format(time.DateOnly)
+	case "time":
+		return g.time().Format(time.TimeOnly)
+	case "email":
+		return g.email()
+	case "uri", "url":
+		return "https://example.com/" + g.word() + "/" + fmt.Sprint(1+g.r.IntN(100))
+	case "uri-reference":
+		return "/" + g.word() + "/" + fmt.Sprint(1+g.r.IntN(100))
+	case "hostname":
+		return g.word() + ".example.com"
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", 1+g.r.IntN(254))
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", 1+g.r.IntN(0xfffe))
+	case "byte":
+		return base64.StdEncoding.EncodeToString([]byte(g.word()))
+	case "password":
+		return "s3cret-" + g.word()
+	}
+	return g.named(name)
+}
+
+// named picks a string that suits a property name.
+func (g *exampleGen) named(name string) string {
+	lower := strings.ToLower(name)
+	pick := func(options []string) string { return options[g.r.IntN(len(options))] }
+	switch {
+	case lower == "":
+		return g.word()
+	case strings.Contains(lower, "email"):
+		return g.email()
+	case strings.Contains(lower, "url"), strings.Contains(lower, "uri"), strings.Contains(lower, "link"), strings.Contains(lower, "website"):
+		return "https://example.com/" + g.word()
+	case strings.Contains(lower, "phone"):
+		return fmt.Sprintf("+1-555-%04d", g.r.IntN(10000))
+	case strings.HasSuffix(name, "At"), strings.HasSuffix(lower, "_at"), strings.HasSuffix(lower, "time"):
+		return g.time().Format(time.RFC3339)
+	case strings.Contains(lower, "date"):
+		return g.time().Format(time.DateOnly)
+	case lower == "id", strings.HasSuffix(lower, "_id"), strings.HasSuffix(name, "Id"), strings.HasSuffix(lower, "uuid"):
+		return g.uuid()
+	case strings.Contains(lower, "firstname"), strings.Contains(lower, "first_name"), strings.Contains(lower, "givenname"):
+		return pick(exampleFirstNames)
+	case strings.Contains(lower, "lastname"), strings.Contains(lower, "last_name"), strings.Contains(lower, "surname"), strings.Contains(lower, "familyname"):
+		return pick(exampleLastNames)
+	case strings.Contains(lower, "username"), strings.Contains(lower, "login"), strings.Contains(lower, "handle"):
+		return strings.ToLower(pick(exampleFirstNames)) + fmt.Sprint(g.r.IntN(100))
+	case strings.Contains(lower, "name"), lower == "author", lower == "owner":
+		return pick(exampleFirstNames) + " " + pick(exampleLastNames)
+	case strings.Contains(lower, "city"):
+		return pick(exampleCities)
+	case strings.Contains(lower, "country"):
+		return pick(exampleCountries)
+	case strings.Contains(lower, "currency"):
+		return pick(exampleCurrencies)
+	case strings.Contains(lower, "description"), strings.Contains(lower, "summary"), strings.Contains(lower, "comment"), strings.Contains(lower, "note"), strings.Contains(lower, "message"):
+		return g.sentence()
+	case strings.Contains(lower, "title"):
+		w := g.word()
+		return strings.ToUpper(w[:1]) + w[1:] + " " + g.word()
+	}
+	return g.word()
+}
+
+func (g *exampleGen) word() string { return exampleWords[g.r.IntN(len(exampleWords))] }
+
+func (g *exampleGen) sentence() string {
+	words := make([]string, 4+g.r.IntN(4))
+	for i := range words {
+		words[i] = g.word()
+	}
+	s := strings.Join(words, " ")
+	return strings.ToUpper(s[:1]) + s[1:] + "."
+}
+
+func (g *exampleGen) email() string {
+	first := exampleFirstNames[g.r.IntN(len(exampleFirstNames))]
+	last := exampleLastNames[g.r.IntN(len(exampleLastNames))]
+	return strings.ToLower(first+"."+last) + "@example.com"
+}
+
+func (g *exampleGen) time() time.Time {
+	return exampleEpoch.Add(time.Duration(g.r.IntN(365*24*3600)) * time.Second)
+}
+
+// uuid returns a version 4 UUID drawn from the generator's stream.
+func (g *exampleGen) uuid() string {
+	var b [16]byte
+	for i := range b {
+		b[i] = byte(g.r.IntN(256))
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// maxPatternRepeat bounds the repetitions generated for *, + and {n,}.
+const maxPatternRepeat = 3
+
+// fromPattern generates a string matching a regular expression, reporting
+// false when the pattern cannot be compiled or the result does not match.
+func (g *exampleGen) fromPattern(pattern string) (string, bool) {
+	re := compilePattern(pattern)
+	if re == nil {
+		return "", false
+	}
+	tree, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var sb strings.Builder
+	g.regex(tree.Simplify(), &sb)
+	v := sb.String()
+	return v, re.MatchString(v)
+}
+
+func (g *exampleGen) regex(re *syntax.Regexp, sb *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		sb.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		sb.WriteRune(g.classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		sb.WriteByte(byte('a' + g.r.IntN(26)))
+	case syntax.OpCapture:
+		g.regex(re.Sub[0], sb)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.regex(sub, sb)
+		}
+	case syntax.OpAlternate:
+		g.regex(re.Sub[g.r.IntN(len(re.Sub))], sb)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := 0, maxPatternRepeat
+		switch re.Op {
+		case syntax.OpPlus:
+			lo = 1
+		case syntax.OpQuest:
+			hi = 1
+		case syntax.OpRepeat:
+			lo, hi = re.Min, re.Max
+			if hi < 0 {
+				hi = lo + maxPatternRepeat
+			}
+		}
+		n := lo
+		if hi > lo {
+			n += g.r.IntN(hi - lo + 1)
+		}
+		for range n {
+			g.regex(re.Sub[0], sb)
+		}
+	}
+}
+
+// classRune picks a rune from a character class, preferring printable
+// ASCII so negated classes do not yield control characters.
+func (g *exampleGen) classRune(ranges []rune) rune {
+	var printable [][2]rune
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], ' '+1), min(ranges[i+1], '~')
+		if lo <= hi {
+			printable = append(printable, [2]rune{lo, hi})
+		}
+	}
+	if len(printable) == 0 {
+		if len(ranges) == 0 {
+			return 'x'
+		}
+		return ranges[0]
+	}
+	r := printable[g.r.IntN(len(printable))]
+	return r[0] + rune(g.r.IntN(int(r[1]-r[0])+1))
+}
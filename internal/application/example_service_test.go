@@ -0,0 +1,103 @@
+package application_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func petSchema() *domain.Schema {
+	return &domain.Schema{
+		Type:     domain.SchemaTypeObject,
+		Required: []string{"id", "name", "owner"},
+		Properties: map[string]*domain.Schema{
+			"id":         {Type: domain.SchemaTypeString, Format: "uuid"},
+			"name":       {Type: domain.SchemaTypeString, MinLength: 2, MaxLength: ptr(12)},
+			"status":     {Type: domain.SchemaTypeString, Enum: []any{"available", "sold"}},
+			"age":        {Type: domain.SchemaTypeInteger, Minimum: ptr(0.0), Maximum: ptr(3.0), ExclusiveMinimum: true},
+			"weight":     {Type: domain.SchemaTypeNumber, Minimum: ptr(0.5), Maximum: ptr(0.75)},
+			"createdAt":  {Type: domain.SchemaTypeString, Format: "date-time"},
+			"homepage":   {Type: domain.SchemaTypeString, Format: "uri"},
+			"code":       {Type: domain.SchemaTypeString, Pattern: `^[A-Z]{3}-\d{2,4}$`},
+			"vaccinated": {Type: domain.SchemaTypeBoolean},
+			"tags": {Type: domain.SchemaTypeArray, MinItems: 3, MaxItems: ptr(4),
+				Items: &domain.Schema{Type: domain.SchemaTypeString}},
+			"owner": {Type: domain.SchemaTypeObject, Required: []string{"email"}, Properties: map[string]*domain.Schema{
+				"email": {Type: domain.SchemaTypeString, Format: "email"},
+				"phone": {Type: domain.SchemaTypeString},
+			}},
+			"nickname": {Type: domain.SchemaTypeString, Example: "Rexy"},
+		},
+	}
+}
+
+// generatedBody validates a generated value as a request body.
+func generatedBody(t *testing.T, schema *domain.Schema, v any) []domain.Violation {
+	t.Helper()
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshalling: %v", err)
+	}
+	op := domain.Operation{Method: domain.POST, Path: "/pets", RequestBody: &domain.RequestBody{
+		Content: map[string]domain.MediaType{"application/json": {Schema: schema}},
+	}}
+	values := domain.RequestValues{ContentType: "application/json", Body: string(body)}
+	return application.NewValidationService().ValidateRequest(op, values)
+}
+
+func TestExampleService_ValidAgainstSchema(t *testing.T) {
+	for seed := range uint64(50) {
+		v := application.NewExampleService(seed).Generate(petSchema())
+		if violations := generatedBody(t, petSchema(), v); len(violations) > 0 {
+			t.Fatalf("seed %d: generated %v, violations %v", seed, v, violations)
+		}
+	}
+}
+
+func TestExampleService_Deterministic(t *testing.T) {
+	a := application.NewExampleService(7).Generate(petSchema())
+	b := application.NewExampleService(7).Generate(petSchema())
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("expected the same value for the same seed:\n%v\n%v", a, b)
+	}
+	c := application.NewExampleService(8).Generate(petSchema())
+	if reflect.DeepEqual(a, c) {
+		t.Error("expected another seed to give another value")
+	}
+}
+
+func TestExampleService_Formats(t *testing.T) {
+	pet := application.NewExampleService(1).Generate(petSchema()).(map[string]any)
+
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(pet["id"].(string)) {
+		t.Errorf("expected a v4 UUID, got %v", pet["id"])
+	}
+	if _, err := time.Parse(time.RFC3339, pet["createdAt"].(string)); err != nil {
+		t.Errorf("expected an RFC 3339 time, got %v", pet["createdAt"])
+	}
+	if pet["nickname"] != "Rexy" {
+		t.Errorf("expected the schema's example, got %v", pet["nickname"])
+	}
+	if n := len(pet["tags"].([]any)); n < 3 || n > 4 {
+		t.Errorf("expected 3 or 4 tags, got %d", n)
+	}
+	owner := pet["owner"].(map[string]any)
+	if !regexp.MustCompile(`^[a-z]+\.[a-z]+@example\.com$`).MatchString(owner["email"].(string)) {
+		t.Errorf("expected an e-mail address, got %v", owner["email"])
+	}
+	if !regexp.MustCompile(`^\+1-555-\d{4}$`).MatchString(owner["phone"].(string)) {
+		t.Errorf("expected a phone number from the property name, got %v", owner["phone"])
+	}
+}
+
+func TestExampleService_RequiredWithoutProperties(t *testing.T) {
+	v := application.NewExampleService(1).Generate(&domain.Schema{Type: domain.SchemaTypeObject, Required: []string{"token"}})
+	if m, ok := v.(map[string]any); !ok || m["token"] == nil {
+		t.Errorf("expected the required property, got %v", v)
+	}
+}
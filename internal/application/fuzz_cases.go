@@ -0,0 +1,574 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+// oversizedLength is the length of the oversized strings sent where a
+// schema sets no maximum.
+const oversizedLength = 64 << 10
+
+// oversizedItems is the most items an array fuzzing builds may have. Like
+// strings longer than oversizedLength, bounds past it are not broken: the
+// request would be too big to send.
+const oversizedItems = 100
+
+// awkwardText is a string servers often mishandle: non-ASCII letters, an
+// emoji, quotes and markup.
+const awkwardText = `Zoë 🐶 "quoted" <b>'tag'</b> & \ ; --`
+
+// fuzzGen generates the values of fuzz cases: valid values that favour
+// boundaries and edge cases, and values that each break one rule. With
+// full set, objects have every property and arrays at least one item, so
+// that any part of a body can be broken.
+type fuzzGen struct {
+	exampleGen
+	full bool
+}
+
+func newFuzzGen(op domain.Operation, seed uint64) *fuzzGen {
+	h := fnv.New64a()
+	h.Write([]byte(string(op.Method) + " " + op.Path))
+	return &fuzzGen{exampleGen: exampleGen{r: rand.New(rand.NewPCG(seed, h.Sum64()))}}
+}
+
+// fuzzInput is a case being built, its body still decoded.
+type fuzzInput struct {
+	values  domain.RequestValues
+	body    any
+	hasBody bool
+	rawBody string // sent instead of body when set
+}
+
+func (in fuzzInput) fuzzCase(mutation string) domain.FuzzCase {
+	c := domain.FuzzCase{Mutation: mutation, Values: in.values}
+	switch {
+	case in.rawBody != "":
+		c.Values.Body = in.rawBody
+	case in.hasBody:
+		data, _ := json.Marshal(in.body)
+		c.Values.Body = string(data)
+	}
+	return c
+}
+
+// jsonBodyType is the JSON media type an operation's body is fuzzed as,
+// empty when the operation takes no JSON body.
+func jsonBodyType(op domain.Operation) string {
+	if op.RequestBody == nil {
+		return ""
+	}
+	for _, name := range sortedKeys(op.RequestBody.Content) {
+		if isJSONMediaType(name) {
+			return name
+		}
+	}
+	return ""
+}
+
+// input generates values for every required parameter and, unless full is
+// off and a coin says otherwise, every optional one and the body.
+func (g *fuzzGen) input(op domain.Operation) fuzzInput {
+	in := fuzzInput{values: domain.RequestValues{
+		Path: map[string]string{}, Query: map[string]string{}, Header: map[string]string{}, Cookie: map[string]string{},
+	}}
+	for _, p := range op.Parameters {
+		if !p.Required && p.In != domain.ParameterInPath && !g.full && g.r.IntN(2) == 0 {
+			continue
+		}
+		v := parameterString(g.valid(p.Schema, p.Name))
+		if p.In == domain.ParameterInCookie && !cookieSafe(v) || strings.TrimSpace(v) == "" {
+			v = parameterString(g.exampleGen.value(p.Schema, p.Name))
+		}
+		setParameter(&in.values, p, v)
+	}
+
+	rb := op.RequestBody
+	if rb == nil || len(rb.Content) == 0 || !rb.Required && !g.full && g.r.IntN(4) == 0 {
+		return in
+	}
+	if mediaType := jsonBodyType(op); mediaType != "" {
+		in.values.ContentType = mediaType
+		in.body, in.hasBody = g.valid(rb.Content[mediaType].Schema, ""), true
+		return in
+	}
+	// Other media types are sent as an example, unchanged.
+	name := sortedKeys(rb.Content)[0]
+	in.values.ContentType = name
+	in.rawBody = parameterString(g.exampleGen.value(rb.Content[name].Schema, ""))
+	return in
+}
+
+func setParameter(values *domain.RequestValues, p domain.Parameter, v string) {
+	m := map[domain.ParameterIn]map[string]string{
+		domain.ParameterInPath: values.Path, domain.ParameterInQuery: values.Query,
+		domain.ParameterInHeader: values.Header, domain.ParameterInCookie: values.Cookie,
+	}[p.In]
+	if m == nil {
+		return
+	}
+	if v == "" {
+		delete(m, p.Name)
+		return
+	}
+	m[p.Name] = v
+}
+
+// parameterString writes a value the way it goes in a parameter: arrays
+// comma-separated, objects as JSON.
+func parameterString(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case float64:
+		return formatFloat(t)
+	case []any:
+		parts := make([]string, len(t))
+		for i, item := range t {
+			parts[i] = parameterString(item)
+		}
+		return strings.Join(parts, ",")
+	case map[string]any:
+		data, _ := json.Marshal(t)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+// cookieSafe reports whether a value can go in a cookie unchanged.
+func cookieSafe(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if b := s[i]; b < 0x20 || b >= 0x7f || b == '"' || b == ';' || b == '\\' || b == ' ' || b == ',' {
+			return false
+		}
+	}
+	return true
+}
+
+// valid generates a value valid against s, often one at a boundary.
+func (g *fuzzGen) valid(s *domain.Schema, name string) any {
+	if s == nil {
+		return g.word()
+	}
+	switch {
+	case len(s.Enum) > 0:
+		return s.Enum[g.r.IntN(len(s.Enum))]
+	case s.Nullable && !g.full && g.r.IntN(8) == 0:
+		return nil
+	case (s.Example != nil || s.Default != nil) && g.r.IntN(4) == 0:
+		return g.exampleGen.value(s, name)
+	}
+
+	switch schemaKind(s) {
+	case domain.SchemaTypeObject:
+		out := make(map[string]any, len(s.Properties))
+		for _, prop := range sortedKeys(s.Properties) {
+			if g.full || slices.Contains(s.Required, prop) || g.r.IntN(2) == 0 {
+				out[prop] = g.valid(s.Properties[prop], prop)
+			}
+		}
+		for _, prop := range s.Required {
+			if _, ok := out[prop]; !ok {
+				out[prop] = g.word()
+			}
+		}
+		return out
+	case domain.SchemaTypeArray:
+		lo, hi := s.MinItems, s.MinItems+3
+		if g.full {
+			lo = max(lo, 1)
+		}
+		if s.MaxItems != nil {
+			hi = min(hi, *s.MaxItems)
+		}
+		n := lo
+		if hi > lo {
+			n += g.r.IntN(hi - lo + 1)
+		}
+		item := singular(name)
+		out := make([]any, n)
+		for i := range out {
+			out[i] = g.valid(s.Items, item)
+		}
+		return out
+	case domain.SchemaTypeInteger:
+		return g.boundaryInteger(s, name)
+	case domain.SchemaTypeNumber:
+		return g.boundaryNumber(s, name)
+	case domain.SchemaTypeBoolean:
+		return g.r.IntN(2) == 0
+	}
+	return g.boundaryString(s, name)
+}
+
+// boundaryInteger picks a bound, zero or an extreme value half the time.
+func (g *fuzzGen) boundaryInteger(s *domain.Schema, name string) any {
+	options := []float64{0}
+	if s.Minimum != nil {
+		options = append(options, math.Ceil(*s.Minimum), math.Floor(*s.Minimum)+1)
+	} else {
+		options = append(options, -1, math.MinInt32)
+	}
+	if s.Maximum != nil {
+		options = append(options, math.Floor(*s.Maximum), math.Ceil(*s.Maximum)-1)
+	} else {
+		options = append(options, math.MaxInt32, 1<<53-1)
+	}
+	if v, ok := g.boundary(s, options); ok {
+		return int64(v)
+	}
+	return g.integer(s, name)
+}
+
+func (g *fuzzGen) boundaryNumber(s *domain.Schema, name string) any {
+	options := []float64{0, 0.5}
+	if s.Minimum != nil {
+		options = append(options, *s.Minimum, *s.Minimum+0.01)
+	} else {
+		options = append(options, -1e15)
+	}
+	if s.Maximum != nil {
+		options = append(options, *s.Maximum, *s.Maximum-0.01)
+	} else {
+		options = append(options, 1e15)
+	}
+	if v, ok := g.boundary(s, options); ok {
+		return v
+	}
+	return g.number(s, name)
+}
+
+// boundary picks one of the options s accepts half the time.
+func (g *fuzzGen) boundary(s *domain.Schema, options []float64) (float64, bool) {
+	if g.r.IntN(2) == 0 {
+		return 0, false
+	}
+	options = slices.DeleteFunc(options, func(v float64) bool {
+		return len(validateNumber(s, v, jsonpath.Location{})) > 0
+	})
+	if len(options) == 0 {
+		return 0, false
+	}
+	return options[g.r.IntN(len(options))], true
+}
+
+// boundaryString picks the shortest or longest string allowed, an
+// oversized one where no maximum is set, or awkward text, when the schema
+// does not constrain its form.
+func (g *fuzzGen) boundaryString(s *domain.Schema, name string) string {
+	if s.Pattern != "" || s.Format != "" {
+		return g.str(s, name)
+	}
+	var v string
+	switch g.r.IntN(8) {
+	case 0:
+		v = strings.Repeat("a", s.MinLength)
+	case 1:
+		if s.MaxLength != nil {
+			v = strings.Repeat("z", min(*s.MaxLength, oversizedLength))
+		} else {
+			v = strings.Repeat("x", oversizedLength)
+		}
+	case 2:
+		v = awkwardText
+	default:
+		return g.str(s, name)
+	}
+	if !fitsLength(s, v) {
+		return g.str(s, name)
+	}
+	return v
+}
+
+// fuzzMutation breaks one rule: it replaces or removes a parameter, a part
+// of the body, or the whole body.
+type fuzzMutation struct {
+	param  *domain.Parameter
+	path   []any // keys and indexes below the body's root
+	what   string
+	value  any
+	remove bool
+	raw    string // malformed body text
+}
+
+func (m fuzzMutation) String() string {
+	if m.param != nil {
+		return domain.ParameterPath(m.param.In, m.param.Name) + ": " + m.what
+	}
+	loc := jsonpath.Location{}
+	for _, step := range m.path {
+		if i, ok := step.(int); ok {
+			loc = loc.Index(i)
+		} else {
+			loc = loc.Key(step.(string))
+		}
+	}
+	return "body " + loc.String() + ": " + m.what
+}
+
+func (m fuzzMutation) apply(in *fuzzInput) {
+	switch {
+	case m.param != nil:
+		v := ""
+		if !m.remove {
+			v = parameterString(m.value)
+		}
+		setParameter(&in.values, *m.param, v)
+	case m.raw != "":
+		in.rawBody = m.raw
+	case len(m.path) == 0 && m.remove:
+		in.body, in.hasBody = nil, false
+	default:
+		in.body = replaceAt(in.body, m.path, m.value, m.remove)
+		in.hasBody = true
+	}
+}
+
+// replaceAt returns doc with the value at path replaced, or removed.
+// Objects and arrays along the path are created as needed.
+func replaceAt(doc any, path []any, v any, remove bool) any {
+	if len(path) == 0 {
+		return v
+	}
+	switch step := path[0].(type) {
+	case string:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			obj = map[string]any{}
+		}
+		if len(path) == 1 && remove {
+			delete(obj, step)
+		} else {
+			obj[step] = replaceAt(obj[step], path[1:], v, remove)
+		}
+		return obj
+	case int:
+		arr, _ := doc.([]any)
+		for len(arr) <= step {
+			arr = append(arr, nil)
+		}
+		if len(path) == 1 && remove {
+			return slices.Delete(arr, step, step+1)
+		}
+		arr[step] = replaceAt(arr[step], path[1:], v, remove)
+		return arr
+	}
+	return doc
+}
+
+// mutations lists the ways to break op's parameters and JSON body.
+func (g *fuzzGen) mutations(op domain.Operation) []fuzzMutation {
+	var out []fuzzMutation
+	for i := range op.Parameters {
+		p := &op.Parameters[i]
+		if p.Required && p.In != domain.ParameterInPath {
+			out = append(out, fuzzMutation{param: p, what: "required parameter missing", remove: true})
+		}
+		for _, b := range g.breaking(p.Schema, true) {
+			if p.In == domain.ParameterInCookie && !cookieSafe(parameterString(b.value)) {
+				continue
+			}
+			out = append(out, fuzzMutation{param: p, what: b.what, value: b.value})
+		}
+	}
+
+	rb := op.RequestBody
+	mediaType := jsonBodyType(op)
+	if rb == nil || mediaType == "" {
+		return out
+	}
+	if rb.Required {
+		out = append(out, fuzzMutation{what: "required body missing", remove: true})
+	}
+	out = append(out, fuzzMutation{what: "malformed JSON", raw: `{"unterminated": [1, 2`})
+	return append(out, g.bodyMutations(rb.Content[mediaType].Schema, nil)...)
+}
+
+func (g *fuzzGen) bodyMutations(s *domain.Schema, path []any) []fuzzMutation {
+	if s == nil {
+		return nil
+	}
+	var out []fuzzMutation
+	for _, b := range g.breaking(s, false) {
+		out = append(out, fuzzMutation{path: path, what: b.what, value: b.value})
+	}
+	switch schemaKind(s) {
+	case domain.SchemaTypeObject:
+		for _, name := range s.Required {
+			out = append(out, fuzzMutation{path: append(slices.Clip(path), name), what: "required property missing", remove: true})
+		}
+		for _, name := range sortedKeys(s.Properties) {
+			out = append(out, g.bodyMutations(s.Properties[name], append(slices.Clip(path), name))...)
+		}
+	case domain.SchemaTypeArray:
+		if s.MaxItems == nil || *s.MaxItems > 0 {
+			out = append(out, g.bodyMutations(s.Items, append(slices.Clip(path), 0))...)
+		}
+	}
+	return out
+}
+
+// broken is a value that breaks a rule of a schema.
+type broken struct {
+	what  string
+	value any
+}
+
+// breaking lists values that each break one rule of s. A parameter's
+// values are written as text, so only text that cannot be read as the
+// schema's type breaks its type.
+func (g *fuzzGen) breaking(s *domain.Schema, param bool) []broken {
+	if s == nil {
+		return nil
+	}
+	var out []broken
+	add := func(what string, v any) { out = append(out, broken{what, v}) }
+
+	kind := schemaKind(s)
+	switch kind {
+	case domain.SchemaTypeString:
+		if !param {
+			add("wrong type (number)", json.Number("12345"))
+		}
+	case domain.SchemaTypeInteger:
+		add("wrong type (string)", "twelve")
+		add("fractional number", json.Number("1.5"))
+	case domain.SchemaTypeNumber:
+		add("wrong type (string)", "twelve")
+	case domain.SchemaTypeBoolean:
+		if param {
+			add("wrong type (string)", "maybe")
+		} else {
+			add("wrong type (string)", "true")
+		}
+	case domain.SchemaTypeArray:
+		if !param {
+			add("wrong type (object)", map[string]any{"not": "an array"})
+		}
+	case domain.SchemaTypeObject:
+		if param {
+			add("wrong type (string)", "{not json")
+		} else {
+			add("wrong type (array)", []any{})
+		}
+	}
+	if !param && !s.Nullable && s.Type != "" {
+		add("null", nil)
+	}
+
+	if len(s.Enum) > 0 {
+		if v, ok := outsideEnum(s, kind); ok {
+			add("not one of the enum", v)
+		}
+	}
+	if m := s.Minimum; m != nil && (kind == domain.SchemaTypeInteger || kind == domain.SchemaTypeNumber) {
+		switch {
+		case s.ExclusiveMinimum:
+			add("equal to exclusive minimum "+formatFloat(*m), json.Number(formatFloat(*m)))
+		case kind == domain.SchemaTypeInteger:
+			add("below minimum "+formatFloat(*m), json.Number(formatFloat(math.Ceil(*m)-1)))
+		default:
+			add("below minimum "+formatFloat(*m), json.Number(formatFloat(*m-0.01)))
+		}
+	}
+	if m := s.Maximum; m != nil && (kind == domain.SchemaTypeInteger || kind == domain.SchemaTypeNumber) {
+		switch {
+		case s.ExclusiveMaximum:
+			add("equal to exclusive maximum "+formatFloat(*m), json.Number(formatFloat(*m)))
+		case kind == domain.SchemaTypeInteger:
+			add("above maximum "+formatFloat(*m), json.Number(formatFloat(math.Floor(*m)+1)))
+		default:
+			add("above maximum "+formatFloat(*m), json.Number(formatFloat(*m+0.01)))
+		}
+	}
+
+	if kind == domain.SchemaTypeString {
+		if s.MinLength > 0 && s.MinLength <= oversizedLength {
+			add(fmt.Sprintf("shorter than minLength %d", s.MinLength), strings.Repeat("a", s.MinLength-1))
+		}
+		if n := s.MaxLength; n != nil && *n < oversizedLength {
+			what := fmt.Sprintf("longer than maxLength %d", *n)
+			if *n >= 1024 {
+				what = fmt.Sprintf("oversized: %d characters (maxLength %d)", *n+1, *n)
+			}
+			add(what, strings.Repeat("z", *n+1))
+		}
+		if s.Pattern != "" {
+			if re := compilePattern(s.Pattern); re != nil {
+				for _, candidate := range []string{"!", "0", "a b", "~~~", "ZZZZZZZZ"} {
+					if !re.MatchString(candidate) && fitsLength(s, candidate) {
+						add("does not match pattern", candidate)
+						break
+					}
+				}
+			}
+		}
+		if s.Format != "" && !formatMatches(s.Format, "not-a-"+s.Format) {
+			add("malformed "+s.Format, "not-a-"+s.Format)
+		}
+	}
+
+	if kind == domain.SchemaTypeArray && !param {
+		if s.MinItems > 0 && s.MinItems <= oversizedItems {
+			items := make([]any, s.MinItems-1)
+			for i := range items {
+				items[i] = g.valid(s.Items, "")
+			}
+			add(fmt.Sprintf("fewer than minItems %d", s.MinItems), items)
+		}
+		if n := s.MaxItems; n != nil && *n < oversizedItems {
+			items := make([]any, *n+1)
+			for i := range items {
+				items[i] = g.valid(s.Items, "")
+			}
+			add(fmt.Sprintf("more than maxItems %d", *n), items)
+		}
+	}
+	return out
+}
+
+// outsideEnum finds a value of the schema's type that is not in its enum.
+func outsideEnum(s *domain.Schema, kind domain.SchemaType) (any, bool) {
+	var candidates []any
+	switch kind {
+	case domain.SchemaTypeInteger, domain.SchemaTypeNumber:
+		biggest := 0.0
+		for _, e := range s.Enum {
+			if n, ok := toFloat(e); ok {
+				biggest = max(biggest, n)
+			}
+		}
+		candidates = []any{json.Number(strconv.FormatFloat(biggest+1, 'f', -1, 64))}
+	case domain.SchemaTypeBoolean:
+		candidates = []any{true, false}
+	default:
+		candidates = []any{"not-in-enum", "zzz"}
+	}
+	for _, c := range candidates {
+		if !enumContains(s.Enum, c) {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// truncateRunes shortens s to n runes.
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,340 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// maxShrinkSends bounds the requests sent to shrink one failing case.
+const maxShrinkSends = 100
+
+// FuzzService implements domain.FuzzService. Every generated case is
+// checked with the validator, so a valid case passes validation and an
+// invalid one fails it.
+type FuzzService struct {
+	requests  domain.RequestService
+	envs      domain.EnvironmentService
+	validator domain.ValidationService
+	authz     domain.Authorizer
+}
+
+func NewFuzzService(requests domain.RequestService, envs domain.EnvironmentService, validator domain.ValidationService, authz domain.Authorizer) *FuzzService {
+	return &FuzzService{requests: requests, envs: envs, validator: validator, authz: authz}
+}
+
+// Cases draws from a random stream seeded by seed and the operation's
+// method and path, so an operation's cases do not depend on which others
+// are fuzzed. A valid case that fails validation is drawn again, up to
+// three times; an invalid one that passes is dropped.
+func (s *FuzzService) Cases(op domain.Operation, seed uint64, n int) []domain.FuzzCase {
+	g := newFuzzGen(op, seed)
+	var cases []domain.FuzzCase
+	for range n {
+		for range 3 {
+			c := g.input(op).fuzzCase("")
+			if len(s.validator.ValidateRequest(op, c.Values)) == 0 {
+				cases = append(cases, c)
+				break
+			}
+		}
+	}
+
+	g.full = true
+	for _, m := range g.mutations(op) {
+		in := g.input(op)
+		m.apply(&in)
+		c := in.fuzzCase(m.String())
+		if len(s.validator.ValidateRequest(op, c.Values)) > 0 {
+			cases = append(cases, c)
+		}
+	}
+	return cases
+}
+
+func (s *FuzzService) Fuzz(ctx context.Context, spec *domain.Spec, ops []domain.Operation, opts domain.FuzzOptions) (domain.FuzzReport, error) {
+	report := domain.FuzzReport{Seed: opts.Seed, Operations: len(ops)}
+	server, err := s.envs.Substitute(opts.Env, opts.Server)
+	if err != nil {
+		return report, fmt.Errorf("server: %w", err)
+	}
+	f := &fuzzer{svc: s, spec: spec, env: opts.Env, server: server, report: &report, seen: map[string]int{}}
+	for _, op := range ops {
+		for _, c := range s.Cases(op, opts.Seed, opts.Cases) {
+			if ctx.Err() != nil {
+				return report, nil
+			}
+			f.try(ctx, op, c)
+		}
+	}
+	return report, nil
+}
+
+// fuzzer holds the state of one run.
+type fuzzer struct {
+	svc    *FuzzService
+	spec   *domain.Spec
+	env    domain.Environment
+	server string
+	report *domain.FuzzReport
+	seen   map[string]int // finding key -> index in report.Findings
+}
+
+// fuzzOutcome is what a case's response showed; kind is empty when
+// nothing went wrong.
+type fuzzOutcome struct {
+	kind       domain.FuzzFindingKind
+	status     int
+	violations []domain.Violation
+	request    *domain.HTTPRequest
+}
+
+// key identifies a way of failing: the same kind of finding, for the same
+// status and broken input or, for schema violations, the same place in the
+// response.
+func (o fuzzOutcome) key(op domain.Operation, c domain.FuzzCase) string {
+	detail := c.Mutation
+	if o.kind == domain.FuzzSchemaViolation {
+		detail = o.violations[0].Path
+	}
+	return strings.Join([]string{string(op.Method), op.Path, string(o.kind), strconv.Itoa(o.status), detail}, "\x00")
+}
+
+func (f *fuzzer) try(ctx context.Context, op domain.Operation, c domain.FuzzCase) {
+	out, ok := f.send(ctx, op, c)
+	if !ok || out.kind == "" {
+		return
+	}
+	key := out.key(op, c)
+	if i, ok := f.seen[key]; ok {
+		f.report.Findings[i].Count++
+		return
+	}
+	c, out, shrinks := f.shrink(ctx, op, c, out)
+	f.seen[key] = len(f.report.Findings)
+	f.report.Findings = append(f.report.Findings, domain.FuzzFinding{
+		Kind: out.kind, OperationID: op.ID, Method: op.Method, Path: op.Path,
+		Case: c, Request: out.request, Status: out.status, Violations: out.violations,
+		Count: 1, Shrinks: shrinks,
+	})
+}
+
+// send sends a case and classifies its response. It reports false when
+// the request could not be sent.
+func (f *fuzzer) send(ctx context.Context, op domain.Operation, c domain.FuzzCase) (fuzzOutcome, bool) {
+	c.Values.Server = f.server
+	req, err := f.svc.requests.BuildRequest(op, c.Values)
+	if err != nil {
+		f.failed(err)
+		return fuzzOutcome{}, false
+	}
+	shown := req.Clone()
+	if err := f.svc.authz.Authorize(ctx, f.spec.SecuritySchemes, op, f.env, req); err != nil {
+		f.failed(err)
+		return fuzzOutcome{}, false
+	}
+	f.report.Sent++
+	resp, err := f.svc.requests.Send(ctx, req)
+	if err != nil {
+		f.failed(err)
+		return fuzzOutcome{}, false
+	}
+
+	out := fuzzOutcome{status: resp.StatusCode, request: shown}
+	switch {
+	case resp.StatusCode >= 500:
+		out.kind = domain.FuzzServerError
+	case !c.Valid() && resp.StatusCode < 300 && resp.StatusCode >= 200:
+		out.kind = domain.FuzzAcceptedInvalid
+	default:
+		if v := f.svc.validator.ValidateResponse(op, resp); len(v) > 0 {
+			out.kind, out.violations = domain.FuzzSchemaViolation, v
+		}
+	}
+	return out, true
+}
+
+func (f *fuzzer) failed(err error) {
+	f.report.Failed++
+	f.report.LastError = err.Error()
+}
+
+// shrink looks for a smaller case that fails the same way: with fewer
+// optional parameters, a smaller body or shorter values, and still valid,
+// or still breaking the same rule. It takes the first smaller case that
+// fails and starts again from it, until none does.
+func (f *fuzzer) shrink(ctx context.Context, op domain.Operation, c domain.FuzzCase, out fuzzOutcome) (domain.FuzzCase, fuzzOutcome, int) {
+	var broken domain.Violation
+	if !c.Valid() {
+		broken = f.svc.validator.ValidateRequest(op, c.Values)[0]
+	}
+	keeps := func(candidate domain.FuzzCase) bool {
+		violations := f.svc.validator.ValidateRequest(op, candidate.Values)
+		if c.Valid() {
+			return len(violations) == 0
+		}
+		for _, v := range violations {
+			if v == broken {
+				return true
+			}
+		}
+		return false
+	}
+
+	budget, shrinks := maxShrinkSends, 0
+	key := out.key(op, c)
+	for shrunk := true; shrunk; {
+		shrunk = false
+		for _, candidate := range smallerCases(op, c) {
+			if budget == 0 || ctx.Err() != nil {
+				return c, out, shrinks
+			}
+			if !keeps(candidate) {
+				continue
+			}
+			budget--
+			got, ok := f.send(ctx, op, candidate)
+			if !ok || got.kind != out.kind || got.key(op, candidate) != key {
+				continue
+			}
+			c, out, shrinks, shrunk = candidate, got, shrinks+1, true
+			break
+		}
+	}
+	return c, out, shrinks
+}
+
+// smallerCases lists the cases one step smaller than c, the largest steps
+// first: without an optional parameter, without the body, with part of
+// the body removed or made smaller, or with a shorter parameter value.
+func smallerCases(op domain.Operation, c domain.FuzzCase) []domain.FuzzCase {
+	var out []domain.FuzzCase
+	with := func(change func(v *domain.RequestValues)) {
+		next := c
+		next.Values = cloneValues(c.Values)
+		change(&next.Values)
+		out = append(out, next)
+	}
+
+	for _, p := range op.Parameters {
+		if p.Required || p.In == domain.ParameterInPath || parameterValue(c.Values, p) == "" {
+			continue
+		}
+		with(func(v *domain.RequestValues) { setParameter(v, p, "") })
+	}
+	if c.Values.Body != "" {
+		if op.RequestBody == nil || !op.RequestBody.Required {
+			with(func(v *domain.RequestValues) { v.Body = "" })
+		}
+		if doc, err := decodeJSON([]byte(c.Values.Body)); err == nil {
+			for _, smaller := range smallerJSON(doc) {
+				data, err := json.Marshal(smaller)
+				if err != nil {
+					continue
+				}
+				with(func(v *domain.RequestValues) { v.Body = string(data) })
+			}
+		}
+	}
+	for _, p := range op.Parameters {
+		value := parameterValue(c.Values, p)
+		for _, smaller := range smallerStrings(value) {
+			if smaller != "" {
+				with(func(v *domain.RequestValues) { setParameter(v, p, smaller) })
+			}
+		}
+	}
+	return out
+}
+
+func cloneValues(v domain.RequestValues) domain.RequestValues {
+	clone := func(m map[string]string) map[string]string {
+		out := make(map[string]string, len(m))
+		for k, val := range m {
+			out[k] = val
+		}
+		return out
+	}
+	v.Path, v.Query, v.Header, v.Cookie = clone(v.Path), clone(v.Query), clone(v.Header), clone(v.Cookie)
+	return v
+}
+
+// smallerJSON lists the values one step smaller than v: an object or array
+// with a member removed or made smaller, a shorter string, a number nearer
+// zero, false.
+func smallerJSON(v any) []any {
+	var out []any
+	switch t := v.(type) {
+	case map[string]any:
+		keys := sortedKeys(t)
+		for _, k := range keys {
+			next := make(map[string]any, len(t))
+			for name, value := range t {
+				if name != k {
+					next[name] = value
+				}
+			}
+			out = append(out, next)
+		}
+		for _, k := range keys {
+			for _, smaller := range smallerJSON(t[k]) {
+				next := make(map[string]any, len(t))
+				for name, value := range t {
+					next[name] = value
+				}
+				next[k] = smaller
+				out = append(out, next)
+			}
+		}
+	case []any:
+		for i := range t {
+			out = append(out, append(append([]any{}, t[:i]...), t[i+1:]...))
+		}
+		for i := range t {
+			for _, smaller := range smallerJSON(t[i]) {
+				next := append([]any{}, t...)
+				next[i] = smaller
+				out = append(out, next)
+			}
+		}
+	case string:
+		for _, s := range smallerStrings(t) {
+			out = append(out, s)
+		}
+	case json.Number:
+		if n, err := t.Int64(); err == nil {
+			if n != 0 {
+				out = append(out, json.Number("0"))
+			}
+			if n/2 != 0 {
+				out = append(out, json.Number(strconv.FormatInt(n/2, 10)))
+			}
+		} else if f, err := t.Float64(); err == nil && f != 0 {
+			out = append(out, json.Number("0"))
+			if whole := float64(int64(f)); whole != 0 {
+				out = append(out, json.Number(formatFloat(whole)))
+			}
+		}
+	case bool:
+		if t {
+			out = append(out, false)
+		}
+	}
+	return out
+}
+
+// smallerStrings lists shorter versions of s: empty, then its first half.
+func smallerStrings(s string) []string {
+	if s == "" {
+		return nil
+	}
+	out := []string{""}
+	if n := len([]rune(s)); n > 1 {
+		out = append(out, truncateRunes(s, n/2))
+	}
+	return out
+}
@@ -0,0 +1,209 @@
+package application_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/httpclient"
+)
+
+func newFuzzService() *application.FuzzService {
+	return application.NewFuzzService(application.NewRequestService(httpclient.NewClient(5*time.Second)),
+		application.NewEnvironmentService(nil), application.NewValidationService(), domain.Authorizer{})
+}
+
+func mockOperation(id string) domain.Operation {
+	for _, op := range mockSpec().Operations {
+		if op.ID == id {
+			return op
+		}
+	}
+	panic("no operation " + id)
+}
+
+func TestFuzzService_Cases(t *testing.T) {
+	svc, validator := newFuzzService(), application.NewValidationService()
+	op := mockOperation("createPet")
+
+	cases := svc.Cases(op, 7, 20)
+	if !reflect.DeepEqual(cases, svc.Cases(op, 7, 20)) {
+		t.Error("expected the same seed to give the same cases")
+	}
+	if reflect.DeepEqual(cases, svc.Cases(op, 8, 20)) {
+		t.Error("expected another seed to give other cases")
+	}
+
+	var valid int
+	var mutations []string
+	for _, c := range cases {
+		violations := validator.ValidateRequest(op, c.Values)
+		if c.Valid() {
+			valid++
+			if len(violations) > 0 {
+				t.Errorf("valid case %s has violations %v", c.Values.Body, violations)
+			}
+			continue
+		}
+		mutations = append(mutations, c.Mutation)
+		if len(violations) == 0 {
+			t.Errorf("invalid case %q passes validation: %s", c.Mutation, c.Values.Body)
+		}
+	}
+	if valid != 20 {
+		t.Errorf("expected 20 valid cases, got %d", valid)
+	}
+	for _, want := range []string{
+		"body $: required body missing",
+		"body $: malformed JSON",
+		"body $: wrong type (array)",
+		"body $.name: required property missing",
+		"body $.name: wrong type (number)",
+		"body $.name: shorter than minLength 1",
+		"body $.id: below minimum 1",
+		"body $.id: fractional number",
+	} {
+		if !slices.Contains(mutations, want) {
+			t.Errorf("expected a case for %q, got %q", want, mutations)
+		}
+	}
+
+	var limits []string
+	for _, c := range svc.Cases(mockOperation("listPets"), 7, 0) {
+		limits = append(limits, c.Mutation+" = "+c.Values.Query["limit"])
+	}
+	for _, want := range []string{"query limit: above maximum 100 = 101", "query limit: wrong type (string) = twelve"} {
+		if !slices.Contains(limits, want) {
+			t.Errorf("expected %q, got %q", want, limits)
+		}
+	}
+}
+
+func TestFuzzService_CasesCapSizes(t *testing.T) {
+	huge := 1 << 40
+	op := domain.Operation{ID: "tagPet", Method: domain.POST, Path: "/tags", RequestBody: &domain.RequestBody{
+		Content: map[string]domain.MediaType{"application/json": {Schema: &domain.Schema{
+			Type: domain.SchemaTypeObject,
+			Properties: map[string]*domain.Schema{
+				"note": {Type: domain.SchemaTypeString, MaxLength: ptr(huge)},
+				"code": {Type: domain.SchemaTypeString, MaxLength: ptr(2000)},
+				"tags": {Type: domain.SchemaTypeArray, MaxItems: ptr(huge), Items: &domain.Schema{Type: domain.SchemaTypeString}},
+			},
+		}}},
+	}}
+
+	var mutations []string
+	for _, c := range newFuzzService().Cases(op, 7, 20) {
+		if len(c.Values.Body) > 2*(64<<10) {
+			t.Errorf("case %q has a %d byte body", c.Mutation, len(c.Values.Body))
+		}
+		mutations = append(mutations, c.Mutation)
+	}
+	if !slices.Contains(mutations, "body $.code: oversized: 2001 characters (maxLength 2000)") {
+		t.Errorf("expected an oversized code, got %q", mutations)
+	}
+	for _, m := range mutations {
+		if strings.Contains(m, "$.note: oversized") || strings.Contains(m, "more than maxItems") {
+			t.Errorf("expected no case past the size limits, got %q", m)
+		}
+	}
+}
+
+// buggyPetServer fails on long names, accepts pets without one and
+// returns pets that do not match the schema.
+func buggyPetServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"id": "seven"}`))
+			return
+		}
+		var pet map[string]any
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &pet); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message": "bad JSON"}`))
+			return
+		}
+		if name, _ := pet["name"].(string); len(name) > 100 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": 1, "name": "Rex"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFuzzService_Fuzz(t *testing.T) {
+	srv := buggyPetServer(t)
+	ops := []domain.Operation{mockOperation("createPet"), mockOperation("getPet")}
+	opts := domain.FuzzOptions{Seed: 3, Cases: 30, Server: "{{baseUrl}}",
+		Env: domain.Environment{Variables: map[string]string{"baseUrl": srv.URL + "/v1"}}}
+
+	report, err := newFuzzService().Fuzz(context.Background(), mockSpec(), ops, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Seed != 3 || report.Operations != 2 || report.Sent == 0 || report.Failed != 0 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	find := func(kind domain.FuzzFindingKind, opID, mutation string) *domain.FuzzFinding {
+		for i, f := range report.Findings {
+			if f.Kind == kind && f.OperationID == opID && (mutation == "*" || f.Case.Mutation == mutation) {
+				return &report.Findings[i]
+			}
+		}
+		t.Errorf("no %s finding for %s %q in %+v", kind, opID, mutation, report.Findings)
+		return nil
+	}
+
+	if f := find(domain.FuzzServerError, "createPet", ""); f != nil {
+		// Shrunk to the name alone, no longer than needed to fail.
+		var body map[string]any
+		if err := json.Unmarshal([]byte(f.Case.Values.Body), &body); err != nil {
+			t.Fatalf("invalid body %q: %v", f.Case.Values.Body, err)
+		}
+		name, _ := body["name"].(string)
+		if len(body) != 1 || len(name) <= 100 || len(name) > 200 || f.Shrinks == 0 {
+			t.Errorf("expected the case to shrink to a name of 101 to 200 characters, got %d keys, %d characters in %d steps",
+				len(body), len(name), f.Shrinks)
+		}
+		if f.Status != 500 || string(f.Request.Body) != f.Case.Values.Body || !strings.HasSuffix(f.Request.URL, "/v1/pets") {
+			t.Errorf("expected the request to reproduce the shrunk case, got %+v", f.Request)
+		}
+	}
+	if f := find(domain.FuzzAcceptedInvalid, "createPet", "body $.name: required property missing"); f != nil {
+		if f.Status != 201 || f.Case.Values.Body != "{}" {
+			t.Errorf("expected the case to shrink to an empty object, got %d %s", f.Status, f.Case.Values.Body)
+		}
+	}
+	if f := find(domain.FuzzSchemaViolation, "getPet", "*"); f != nil {
+		if !slices.Contains(f.Violations, domain.Violation{Path: "$.id", Message: "expected integer, got string"}) || f.Count != 30 {
+			t.Errorf("expected the id violation once for every valid case, got %v ×%d", f.Violations, f.Count)
+		}
+	}
+	again, _ := newFuzzService().Fuzz(context.Background(), mockSpec(), ops, opts)
+	if !reflect.DeepEqual(report.Findings, again.Findings) {
+		t.Error("expected the same seed to find the same, equally shrunk cases")
+	}
+}
+
+func TestFuzzService_UnknownVariable(t *testing.T) {
+	_, err := newFuzzService().Fuzz(context.Background(), mockSpec(), nil, domain.FuzzOptions{Server: "{{baseUrl}}"})
+	if err == nil || !strings.Contains(err.Error(), "baseUrl") {
+		t.Errorf("expected an undefined variable error, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// transportHeaders are set by clients and browsers rather than by the API's
+// contract, so importing drops them without comment.
+var transportHeaders = map[string]bool{
+	"accept": true, "accept-encoding": true, "accept-language": true,
+	"user-agent": true, "content-length": true, "host": true,
+	"connection": true, "origin": true, "referer": true,
+	"cache-control": true, "pragma": true, "dnt": true,
+}
+
+// ImportService implements domain.ImportService.
+type ImportService struct{}
+
+func NewImportService() *ImportService {
+	return &ImportService{}
+}
+
+func (s *ImportService) ImportCurl(command string, ops []domain.Operation, servers []domain.Server) (domain.ImportedRequest, error) {
+	cmd, err := parseCurl(command)
+	if err != nil {
+		return domain.ImportedRequest{}, err
+	}
+	u, err := url.Parse(cmd.url)
+	if err != nil {
+		return domain.ImportedRequest{}, fmt.Errorf("parsing URL: %w", err)
+	}
+	method := domain.HTTPMethod(cmd.requestMethod())
+
+	matches := newRouter(ops, servers).find(u.EscapedPath())
+	if len(matches) == 0 {
+		return domain.ImportedRequest{}, fmt.Errorf("no operation matches %s %s", method, u.Path)
+	}
+	var mismatches []domain.Violation
+	match, ok := forMethod(matches, method)
+	if !ok {
+		match = matches[0]
+		mismatches = append(mismatches, domain.Violation{
+			Path:    "method",
+			Message: fmt.Sprintf("%s is not defined for %s; imported as %s", method, match.op.Path, match.op.Method),
+		})
+	}
+	op := match.op
+
+	imp := domain.ImportedRequest{
+		Operation: op,
+		Values: domain.RequestValues{
+			Server: importServer(u, match.base, servers),
+			Path:   match.params,
+			Query:  make(map[string]string),
+			Header: make(map[string]string),
+			Cookie: make(map[string]string),
+		},
+	}
+	declared := make(map[domain.ParameterIn]map[string]string)
+	for _, p := range op.Parameters {
+		if declared[p.In] == nil {
+			declared[p.In] = make(map[string]string)
+		}
+		key := p.Name
+		if p.In == domain.ParameterInHeader {
+			key = strings.ToLower(key)
+		}
+		declared[p.In][key] = p.Name
+	}
+
+	query := u.Query()
+	if cmd.get && len(cmd.data) > 0 {
+		if extra, err := url.ParseQuery(strings.Join(cmd.data, "&")); err == nil {
+			for k, vs := range extra {
+				query[k] = append(query[k], vs...)
+			}
+		}
+	}
+	for _, name := range sortedKeys(query) {
+		mismatches = append(mismatches, assign(imp.Values.Query, declared, domain.ParameterInQuery, name, query[name])...)
+	}
+
+	cookies := cmd.cookie
+	for _, name := range sortedKeys(cmd.header) {
+		lower := strings.ToLower(name)
+		values := cmd.header[name]
+		switch {
+		case lower == "content-type":
+			continue
+		case lower == "cookie":
+			cookies = joinNonEmpty(cookies, strings.Join(values, "; "), "; ")
+			continue
+		case lower == "authorization" && declared[domain.ParameterInHeader][lower] == "":
+			mismatches = append(mismatches, domain.Violation{
+				Path:    domain.ParameterPath(domain.ParameterInHeader, name),
+				Message: "credentials come from the security scheme; dropped",
+			})
+			continue
+		case transportHeaders[lower] || strings.HasPrefix(lower, "sec-"):
+			if declared[domain.ParameterInHeader][lower] == "" {
+				continue
+			}
+		}
+		mismatches = append(mismatches, assign(imp.Values.Header, declared, domain.ParameterInHeader, name, values)...)
+	}
+	if cookies != "" {
+		parsed, _ := http.ParseCookie(cookies)
+		byName := make(map[string][]string)
+		for _, c := range parsed {
+			byName[c.Name] = append(byName[c.Name], c.Value)
+		}
+		for _, name := range sortedKeys(byName) {
+			mismatches = append(mismatches, assign(imp.Values.Cookie, declared, domain.ParameterInCookie, name, byName[name])...)
+		}
+	}
+
+	if body := cmd.body(); body != "" {
+		mismatches = append(mismatches, importBody(&imp.Values, op, cmd.contentType(), body)...)
+	}
+	for _, opt := range cmd.unsupported {
+		mismatches = append(mismatches, domain.Violation{Path: "curl " + opt, Message: "not supported; ignored"})
+	}
+	// A {{name}} in the command is sent as it is, not expanded.
+	imp.Values = imp.Values.EscapeVariables()
+	imp.Mismatches = mismatches
+	return imp, nil
+}
+
+// assign stores a parameter's value under its declared name, reporting
+// parameters the operation does not declare and values beyond the first.
+func assign(dst map[string]string, declared map[domain.ParameterIn]map[string]string, in domain.ParameterIn, name string, values []string) []domain.Violation {
+	path := domain.ParameterPath(in, name)
+	key := name
+	if in == domain.ParameterInHeader {
+		key = strings.ToLower(name)
+	}
+	specName, ok := declared[in][key]
+	if !ok {
+		return []domain.Violation{{Path: path, Message: "not in the spec; dropped"}}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+	dst[specName] = values[0]
+	if len(values) > 1 {
+		return []domain.Violation{{Path: path, Message: fmt.Sprintf("%d values given; kept the first", len(values))}}
+	}
+	return nil
+}
+
+// importBody sets the body when the operation takes one in the body's
+// media type. JSON is indented for editing.
+func importBody(v *domain.RequestValues, op domain.Operation, contentType, body string) []domain.Violation {
+	if op.RequestBody == nil || len(op.RequestBody.Content) == 0 {
+		return []domain.Violation{{Path: "body", Message: "the operation takes no request body; dropped"}}
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	var mismatches []domain.Violation
+	if _, ok := op.RequestBody.Content[mediaType]; ok {
+		v.ContentType = mediaType
+	} else {
+		mismatches = append(mismatches, domain.Violation{
+			Path:    "body",
+			Message: fmt.Sprintf("content type %s is not in the spec (%s)", mediaType, strings.Join(sortedKeys(op.RequestBody.Content), ", ")),
+		})
+	}
+	if isJSONMediaType(mediaType) {
+		var buf bytes.Buffer
+		if json.Indent(&buf, []byte(body), "", "  ") == nil {
+			body = buf.String()
+		}
+	}
+	v.Body = body
+	return mismatches
+}
+
+func isJSONMediaType(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// importServer is the spec's server the URL falls under, written as in the
+// spec, or the URL's origin and base path.
+func importServer(u *url.URL, base string, servers []domain.Server) string {
+	origin := u.Scheme + "://" + u.Host + base
+	for _, s := range servers {
+		if strings.TrimRight(s.URL, "/") == origin {
+			return s.URL
+		}
+	}
+	return origin
+}
@@ -0,0 +1,168 @@
+package application_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func petOperations() []domain.Operation {
+	body := &domain.RequestBody{Content: map[string]domain.MediaType{"application/json": {}}}
+	return []domain.Operation{
+		{ID: "listPets", Method: domain.GET, Path: "/pets", Parameters: []domain.Parameter{
+			{Name: "limit", In: domain.ParameterInQuery},
+			{Name: "pageSize", In: domain.ParameterInQuery},
+			{Name: "X-Request-ID", In: domain.ParameterInHeader},
+		}},
+		{ID: "createPet", Method: domain.POST, Path: "/pets", RequestBody: body},
+		{ID: "getPet", Method: domain.GET, Path: "/pets/{petId}", Parameters: []domain.Parameter{
+			{Name: "petId", In: domain.ParameterInPath, Required: true},
+			{Name: "session", In: domain.ParameterInCookie},
+			{Name: "sessionId", In: domain.ParameterInCookie},
+		}},
+		{ID: "getMine", Method: domain.GET, Path: "/pets/mine"},
+		{ID: "getPhoto", Method: domain.GET, Path: "/pets/{petId}/photo.{format}", Parameters: []domain.Parameter{
+			{Name: "petId", In: domain.ParameterInPath, Required: true},
+			{Name: "format", In: domain.ParameterInPath, Required: true},
+		}},
+	}
+}
+
+var petServers = []domain.Server{{URL: "https://api.example.com/v1"}}
+
+func importCurl(t *testing.T, command string) domain.ImportedRequest {
+	t.Helper()
+	imp, err := application.NewImportService().ImportCurl(command, petOperations(), petServers)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return imp
+}
+
+func mismatchList(imp domain.ImportedRequest) string {
+	lines := make([]string, len(imp.Mismatches))
+	for i, m := range imp.Mismatches {
+		lines[i] = m.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestImportService_MatchesPathTemplates(t *testing.T) {
+	tests := []struct {
+		command string
+		id      string
+		path    map[string]string
+	}{
+		{"curl https://api.example.com/v1/pets/7", "getPet", map[string]string{"petId": "7"}},
+		{"curl https://api.example.com/v1/pets/mine/", "getMine", map[string]string{}},
+		{"curl 'https://api.example.com/v1/pets/a%20b/photo.png'", "getPhoto", map[string]string{"petId": "a b", "format": "png"}},
+		{"curl http://localhost:8080/pets/3", "getPet", map[string]string{"petId": "3"}},
+	}
+	for _, tt := range tests {
+		imp := importCurl(t, tt.command)
+		if imp.Operation.ID != tt.id {
+			t.Errorf("%s: expected %s, got %s", tt.command, tt.id, imp.Operation.ID)
+			continue
+		}
+		for k, v := range tt.path {
+			if imp.Values.Path[k] != v {
+				t.Errorf("%s: expected path %s=%q, got %q", tt.command, k, v, imp.Values.Path[k])
+			}
+		}
+	}
+
+	if imp := importCurl(t, "curl https://api.example.com/v1/pets/7"); imp.Values.Server != "https://api.example.com/v1" {
+		t.Errorf("expected the spec's server, got %q", imp.Values.Server)
+	}
+	if imp := importCurl(t, "curl localhost:8080/pets/3"); imp.Values.Server != "http://localhost:8080" {
+		t.Errorf("expected the URL's origin, got %q", imp.Values.Server)
+	}
+
+	_, err := application.NewImportService().ImportCurl("curl https://api.example.com/v1/owners", petOperations(), petServers)
+	if err == nil || !strings.Contains(err.Error(), "no operation matches GET /v1/owners") {
+		t.Errorf("expected no match, got %v", err)
+	}
+}
+
+func TestImportService_ExtractsParameters(t *testing.T) {
+	imp := importCurl(t, `curl -sS 'https://api.example.com/v1/pets?limit=5&limit=6&debug=1' \
+  -H 'x-request-id: abc' \
+  -H "Authorization: Bearer $TOKEN" \
+  -H 'Accept: application/json' \
+  -H 'X-Trace: 1'`)
+	if imp.Operation.ID != "listPets" {
+		t.Fatalf("expected listPets, got %s", imp.Operation.ID)
+	}
+	if imp.Values.Query["limit"] != "5" || imp.Values.Header["X-Request-ID"] != "abc" {
+		t.Errorf("expected limit and X-Request-ID, got %+v", imp.Values)
+	}
+	want := strings.Join([]string{
+		"query debug: not in the spec; dropped",
+		"query limit: 2 values given; kept the first",
+		"header Authorization: credentials come from the security scheme; dropped",
+		"header X-Trace: not in the spec; dropped",
+	}, "\n")
+	if got := mismatchList(imp); got != want {
+		t.Errorf("mismatches:\n%s\nwant:\n%s", got, want)
+	}
+
+	imp = importCurl(t, `curl https://api.example.com/v1/pets/7 -b 'session=s1; theme=dark'`)
+	if imp.Values.Cookie["session"] != "s1" || mismatchList(imp) != "cookie theme: not in the spec; dropped" {
+		t.Errorf("expected the session cookie, got %+v and %s", imp.Values.Cookie, mismatchList(imp))
+	}
+
+	// Only header names are case-insensitive.
+	imp = importCurl(t, `curl 'https://api.example.com/v1/pets?pageSize=10&pagesize=20'`)
+	if imp.Values.Query["pageSize"] != "10" || mismatchList(imp) != "query pagesize: not in the spec; dropped" {
+		t.Errorf("expected pageSize, got %+v and %s", imp.Values.Query, mismatchList(imp))
+	}
+	imp = importCurl(t, `curl https://api.example.com/v1/pets/7 -b 'sessionId=s2; SESSION=s3'`)
+	if imp.Values.Cookie["sessionId"] != "s2" || mismatchList(imp) != "cookie SESSION: not in the spec; dropped" {
+		t.Errorf("expected the sessionId cookie, got %+v and %s", imp.Values.Cookie, mismatchList(imp))
+	}
+}
+
+func TestImportService_Body(t *testing.T) {
+	imp := importCurl(t, `curl https://api.example.com/v1/pets --json $'{"name":"Rex\'s"}'`)
+	if imp.Operation.ID != "createPet" || imp.Values.ContentType != "application/json" {
+		t.Fatalf("expected a JSON createPet, got %s %q", imp.Operation.ID, imp.Values.ContentType)
+	}
+	if imp.Values.Body != "{\n  \"name\": \"Rex's\"\n}" || len(imp.Mismatches) != 0 {
+		t.Errorf("expected the indented body, got %q and %s", imp.Values.Body, mismatchList(imp))
+	}
+
+	imp = importCurl(t, `curl -XPOST https://api.example.com/v1/pets -d name=Rex`)
+	if got := mismatchList(imp); got != "body: content type application/x-www-form-urlencoded is not in the spec (application/json)" {
+		t.Errorf("expected a content type mismatch, got %s", got)
+	}
+
+	imp = importCurl(t, `curl -sX POST https://api.example.com/v1/pets -sH 'Content-Type: application/json' -sSd '{"name":"Rex"}'`)
+	if imp.Operation.ID != "createPet" || imp.Values.ContentType != "application/json" || len(imp.Mismatches) != 0 {
+		t.Errorf("expected a JSON createPet from combined flags, got %s %q and %s", imp.Operation.ID, imp.Values.ContentType, mismatchList(imp))
+	}
+	imp = importCurl(t, `curl -sGd limit=5 https://api.example.com/v1/pets`)
+	if imp.Operation.ID != "listPets" || imp.Values.Query["limit"] != "5" {
+		t.Errorf("expected limit in the query, got %s %+v", imp.Operation.ID, imp.Values.Query)
+	}
+
+	imp = importCurl(t, `curl https://api.example.com/v1/pets --json '{"name":"{{name}}"}'`)
+	if imp.Values.Body != "{\n  \"name\": \"\\{{name}}\"\n}" {
+		t.Errorf("expected the body escaped, got %q", imp.Values.Body)
+	}
+
+	imp = importCurl(t, `curl -X DELETE https://api.example.com/v1/pets/7 -F photo=@rex.png`)
+	want := "method: DELETE is not defined for /pets/{petId}; imported as GET\ncurl -F photo=@rex.png: not supported; ignored"
+	if imp.Operation.ID != "getPet" || mismatchList(imp) != want {
+		t.Errorf("expected getPet with mismatches, got %s:\n%s", imp.Operation.ID, mismatchList(imp))
+	}
+}
+
+func TestImportService_InvalidCommands(t *testing.T) {
+	for _, command := range []string{"wget https://api.example.com", "curl -s", `curl 'https://api.example.com`, "curl -H", "curl https://api.example.com -sH"} {
+		if _, err := application.NewImportService().ImportCurl(command, petOperations(), petServers); err == nil {
+			t.Errorf("expected an error for %q", command)
+		}
+	}
+}
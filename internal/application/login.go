@@ -0,0 +1,251 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// callbackPath is the redirect path used when no redirect URL is
+// configured.
+const callbackPath = "/callback"
+
+// shutdownTimeout bounds how long closing a session waits for the browser's
+// request to complete.
+const shutdownTimeout = 2 * time.Second
+
+// BeginLogin starts an authorization-code login with PKCE (RFC 7636). It
+// listens on a loopback address for the redirect (RFC 8252 §7.3), opens the
+// provider's page when a browser is set, and returns the session to wait
+// on. OpenID Connect schemes are discovered from their openIdConnectUrl.
+func (s *AuthService) BeginLogin(ctx context.Context, schemes map[string]domain.SecurityScheme, name string, env domain.Environment) (domain.LoginSession, error) {
+	scheme, ok := schemes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown security scheme %q", name)
+	}
+	if !scheme.SupportsLogin() {
+		return nil, fmt.Errorf("%s does not support logging in", name)
+	}
+	c, err := s.credentials(env, name)
+	if err != nil {
+		return nil, err
+	}
+	if c.ClientID == "" {
+		return nil, fmt.Errorf("%s: no client ID (set clientId in credentials.yaml or DAZZLE_%s_CLIENT_ID)", name, envVarName(name))
+	}
+	endpoints, err := s.endpoints(ctx, scheme, c)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	listener, redirectURI, err := listenLoopback(c.RedirectURL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	verifier := randomString(32)
+	state := randomString(16)
+	scopes := loginScopes(scheme, c)
+
+	authURL, err := url.Parse(endpoints.authorization)
+	if err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("%s: parsing authorization URL: %w", name, err)
+	}
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", c.ClientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("state", state)
+	query.Set("code_challenge", pkceChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	if len(scopes) > 0 {
+		query.Set("scope", strings.Join(scopes, " "))
+	}
+	authURL.RawQuery = query.Encode()
+
+	session := &loginSession{
+		authURL:  authURL.String(),
+		listener: listener,
+		results:  make(chan callbackResult, 1),
+		state:    state,
+	}
+	session.exchange = func(ctx context.Context, code string) error {
+		form := url.Values{
+			"grant_type":    {"authorization_code"},
+			"code":          {code},
+			"redirect_uri":  {redirectURI},
+			"code_verifier": {verifier},
+		}
+		token, err := s.requestToken(ctx, endpoints.token, c, form)
+		if err != nil {
+			return err
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return s.saveToken(env, name, token)
+	}
+	session.serve(callbackPathOf(redirectURI))
+
+	if s.openBrowser != nil {
+		// The URL is still shown, so a browser that fails to open is not fatal.
+		_ = s.openBrowser(session.authURL)
+	}
+	return session, nil
+}
+
+// loginScopes are the configured scopes, otherwise "openid" for OpenID
+// Connect and every scope the authorization-code flow documents for OAuth2.
+func loginScopes(scheme domain.SecurityScheme, c domain.Credentials) []string {
+	if len(c.Scopes) > 0 {
+		return c.Scopes
+	}
+	if scheme.Type == domain.SecuritySchemeOpenIDConnect {
+		return []string{"openid"}
+	}
+	return sortedKeys(scheme.Flows.AuthorizationCode.Scopes)
+}
+
+// listenLoopback listens on the configured redirect URL's port, or on any
+// free port of 127.0.0.1, and returns the redirect URI to register.
+func listenLoopback(redirectURL string) (net.Listener, string, error) {
+	if redirectURL == "" {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, "", fmt.Errorf("starting callback listener: %w", err)
+		}
+		return l, "http://" + l.Addr().String() + callbackPath, nil
+	}
+
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing redirect URL: %w", err)
+	}
+	if u.Scheme != "http" || !isLoopback(u.Hostname()) {
+		return nil, "", fmt.Errorf("redirect URL %s must be http on a loopback address", redirectURL)
+	}
+	l, err := net.Listen("tcp", u.Host)
+	if err != nil {
+		return nil, "", fmt.Errorf("starting callback listener: %w", err)
+	}
+	return l, redirectURL, nil
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func callbackPathOf(redirectURI string) string {
+	u, err := url.Parse(redirectURI)
+	if err != nil || u.Path == "" {
+		return "/"
+	}
+	return u.Path
+}
+
+// randomString returns n random bytes, base64url-encoded without padding:
+// for n = 32 a 43-character PKCE verifier.
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func pkceChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// loginSession implements domain.LoginSession.
+type loginSession struct {
+	authURL  string
+	listener net.Listener
+	server   *http.Server
+	state    string
+	results  chan callbackResult
+	exchange func(ctx context.Context, code string) error
+
+	closeOnce sync.Once
+}
+
+func (l *loginSession) AuthURL() string { return l.authURL }
+
+// serve handles the provider's redirect on path. Only the first redirect
+// with the expected state counts.
+func (l *loginSession) serve(path string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var result callbackResult
+		switch {
+		case q.Get("state") != l.state:
+			http.Error(w, "Login failed: unexpected state.", http.StatusBadRequest)
+			return
+		case q.Get("error") != "":
+			result.err = fmt.Errorf("login refused: %s", strings.TrimSuffix(q.Get("error")+": "+q.Get("error_description"), ": "))
+		case q.Get("code") == "":
+			result.err = errors.New("login failed: no authorization code in redirect")
+		default:
+			result.code = q.Get("code")
+		}
+
+		select {
+		case l.results <- result:
+		default:
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if result.err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintln(w, result.err)
+			return
+		}
+		fmt.Fprintln(w, "Authorization received. You can close this tab and return to dazzle.")
+	})
+	l.server = &http.Server{Handler: mux}
+	go func() { _ = l.server.Serve(l.listener) }()
+}
+
+func (l *loginSession) Wait(ctx context.Context) error {
+	defer l.Close()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case result := <-l.results:
+		if result.err != nil {
+			return result.err
+		}
+		return l.exchange(ctx, result.code)
+	}
+}
+
+// Close shuts the listener down, letting an in-flight redirect finish its
+// response first.
+func (l *loginSession) Close() error {
+	var err error
+	l.closeOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		err = l.server.Shutdown(ctx)
+	})
+	return err
+}
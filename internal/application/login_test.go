@@ -0,0 +1,246 @@
+package application_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/httpclient"
+)
+
+// stubIdP is a stand-in OpenID provider: discovery, an authorization
+// endpoint that signs the user in immediately, and a token endpoint that
+// checks the PKCE verifier.
+type stubIdP struct {
+	*httptest.Server
+	mu         sync.Mutex
+	authorize  url.Values
+	challenges map[string]string // code -> code_challenge
+	refuse     bool
+}
+
+func newStubIdP(t *testing.T) *stubIdP {
+	idp := &stubIdP{challenges: make(map[string]string)}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		idp.mu.Lock()
+		idp.authorize = q
+		idp.challenges["code-1"] = q.Get("code_challenge")
+		idp.mu.Unlock()
+
+		redirect, _ := url.Parse(q.Get("redirect_uri"))
+		back := url.Values{"state": {q.Get("state")}}
+		if idp.refuse {
+			back.Set("error", "access_denied")
+			back.Set("error_description", "user cancelled")
+		} else {
+			back.Set("code", "code-1")
+		}
+		redirect.RawQuery = back.Encode()
+		http.Redirect(w, r, redirect.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		idp.mu.Lock()
+		challenge := idp.challenges[r.PostForm.Get("code")]
+		idp.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if r.PostForm.Get("grant_type") != "authorization_code" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"access_token":"user-token","token_type":"Bearer","expires_in":3600,"refresh_token":"user-refresh"}`))
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// memoryTokens is an in-memory TokenRepository.
+type memoryTokens struct {
+	mu     sync.Mutex
+	tokens map[string]domain.Token
+}
+
+func (m *memoryTokens) LoadToken(env, scheme string) (*domain.Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.tokens[env+"/"+scheme]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+func (m *memoryTokens) SaveToken(env, scheme string, token domain.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.tokens == nil {
+		m.tokens = make(map[string]domain.Token)
+	}
+	m.tokens[env+"/"+scheme] = token
+	return nil
+}
+
+func oidcSchemes(idp *stubIdP) map[string]domain.SecurityScheme {
+	return map[string]domain.SecurityScheme{
+		"oidc": {Type: domain.SecuritySchemeOpenIDConnect, OpenIDConnectURL: idp.URL + "/.well-known/openid-configuration"},
+	}
+}
+
+// browse follows the login URL like a browser would, through the provider
+// and back to dazzle's loopback listener.
+func browse(t *testing.T, authURL string) string {
+	t.Helper()
+	resp, err := http.Get(authURL)
+	if err != nil {
+		t.Fatalf("following login URL: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestAuthService_LoginWithPKCE(t *testing.T) {
+	idp := newStubIdP(t)
+	store := &memoryTokens{}
+	env := domain.Environment{Name: "staging"}
+
+	svc := application.NewAuthService(credentialMap{"oidc": {ClientID: "dazzle-cli"}}, nil, httpclient.NewClient(5*time.Second))
+	svc.SetTokenStore(store)
+	var opened string
+	svc.SetBrowser(func(u string) error { opened = u; return nil })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := svc.BeginLogin(ctx, oidcSchemes(idp), "oidc", env)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opened != session.AuthURL() || !strings.HasPrefix(opened, idp.URL+"/authorize?") {
+		t.Fatalf("expected the browser to open the discovered authorization endpoint, got %q", opened)
+	}
+
+	if page := browse(t, session.AuthURL()); !strings.Contains(page, "You can close this tab") {
+		t.Errorf("unexpected callback page %q", page)
+	}
+	if err := session.Wait(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	q := idp.authorize
+	if q.Get("code_challenge_method") != "S256" || q.Get("client_id") != "dazzle-cli" || q.Get("scope") != "openid" {
+		t.Errorf("unexpected authorization request %v", q)
+	}
+	if !strings.HasPrefix(q.Get("redirect_uri"), "http://127.0.0.1:") {
+		t.Errorf("expected a loopback redirect URI, got %q", q.Get("redirect_uri"))
+	}
+	if tok, _ := store.LoadToken("staging", "oidc"); tok == nil || tok.AccessToken != "user-token" || tok.RefreshToken != "user-refresh" {
+		t.Errorf("expected the token to be stored for the environment, got %+v", tok)
+	}
+
+	// A fresh service, as after a restart, uses the stored token.
+	restarted := application.NewAuthService(credentialMap{"oidc": {ClientID: "dazzle-cli"}}, nil, httpclient.NewClient(5*time.Second))
+	restarted.SetTokenStore(store)
+	req := newRequest()
+	op := securedOperation(domain.SecurityRequirement{"oidc": {"openid"}})
+	if err := restarted.Authenticate(ctx, oidcSchemes(idp), op, env, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer user-token" {
+		t.Errorf("expected the stored user token, got %q", got)
+	}
+
+	// Other environments have their own logins.
+	req = newRequest()
+	if err := restarted.Authenticate(ctx, oidcSchemes(idp), op, domain.Environment{Name: "prod"}, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "" {
+		t.Errorf("expected no token for another environment, got %q", got)
+	}
+}
+
+func TestAuthService_LoginRefused(t *testing.T) {
+	idp := newStubIdP(t)
+	idp.refuse = true
+	svc := application.NewAuthService(credentialMap{"oidc": {ClientID: "dazzle-cli"}}, nil, httpclient.NewClient(5*time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := svc.BeginLogin(ctx, oidcSchemes(idp), "oidc", domain.Environment{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	browse(t, session.AuthURL())
+
+	err = session.Wait(ctx)
+	if err == nil || err.Error() != "login refused: access_denied: user cancelled" {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestAuthService_LoginAuthorizationCodeFlow(t *testing.T) {
+	idp := newStubIdP(t)
+	schemes := map[string]domain.SecurityScheme{
+		"oauth": {Type: domain.SecuritySchemeOAuth2, Flows: &domain.OAuthFlows{AuthorizationCode: &domain.OAuthFlow{
+			AuthorizationURL: idp.URL + "/authorize",
+			TokenURL:         idp.URL + "/token",
+			Scopes:           map[string]string{"write:pets": "", "read:pets": ""},
+		}}},
+	}
+	svc := application.NewAuthService(credentialMap{"oauth": {ClientID: "dazzle-cli"}}, nil, httpclient.NewClient(5*time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	session, err := svc.BeginLogin(ctx, schemes, "oauth", domain.Environment{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	browse(t, session.AuthURL())
+	if err := session.Wait(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := idp.authorize.Get("scope"); got != "read:pets write:pets" {
+		t.Errorf("expected the flow's scopes, got %q", got)
+	}
+
+	req := newRequest()
+	if err := svc.Authenticate(ctx, schemes, securedOperation(domain.SecurityRequirement{"oauth": nil}), domain.Environment{}, req); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer user-token" {
+		t.Errorf("expected the logged-in token, got %q", got)
+	}
+}
+
+func TestAuthService_LoginNeedsClientID(t *testing.T) {
+	idp := newStubIdP(t)
+	svc := application.NewAuthService(credentialMap{}, nil, httpclient.NewClient(5*time.Second))
+
+	_, err := svc.BeginLogin(context.Background(), oidcSchemes(idp), "oidc", domain.Environment{})
+	if err == nil || !strings.Contains(err.Error(), "DAZZLE_OIDC_CLIENT_ID") {
+		t.Errorf("expected a missing client ID error, got %v", err)
+	}
+}
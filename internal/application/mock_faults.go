@@ -0,0 +1,229 @@
+package application
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// defaultBytesPerSecond paces slow bodies that do not say how slow.
+const defaultBytesPerSecond = 1024
+
+// rateWindow counts an operation's requests in a fixed window.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// SetFaults makes the mock misbehave as cfg describes, drawing from a
+// random source seeded with seed so a run can be repeated.
+func (s *MockService) SetFaults(cfg domain.MockFaultConfig, seed uint64) error {
+	known := map[string]bool{}
+	for _, rt := range s.router.routes {
+		known[rt.op.ID] = true
+	}
+	for _, id := range sortedKeys(cfg.Operations) {
+		if !known[id] {
+			return fmt.Errorf("faults: unknown operation %q", id)
+		}
+		if err := checkFaults(cfg.Operations[id]); err != nil {
+			return fmt.Errorf("faults: %s: %w", id, err)
+		}
+	}
+	if err := checkFaults(cfg.Default); err != nil {
+		return fmt.Errorf("faults: default: %w", err)
+	}
+
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+	s.faults = cfg
+	s.rng = rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+	s.windows = map[string]*rateWindow{}
+	return nil
+}
+
+func checkFaults(f domain.MockFaults) error {
+	for _, rate := range []struct {
+		name  string
+		value float64
+	}{{"errorRate", f.ErrorRate}, {"resetRate", f.ResetRate}, {"slowBody rate", f.SlowBodyRate}} {
+		if rate.value < 0 || rate.value > 100 {
+			return fmt.Errorf("%s %v is not a percentage", rate.name, rate.value)
+		}
+	}
+	for _, code := range f.ErrorCodes {
+		if code < 400 || code > 599 {
+			return fmt.Errorf("error code %d is not a 4xx or 5xx", code)
+		}
+	}
+	if l := f.Latency; l != nil {
+		switch l.Distribution {
+		case domain.LatencyFixed, domain.LatencyUniform, domain.LatencyNormal, domain.LatencyExponential:
+		default:
+			return fmt.Errorf("unknown latency distribution %q", l.Distribution)
+		}
+	}
+	return nil
+}
+
+// faultsFor returns the operation's faults with the request's overrides.
+func (s *MockService) faultsFor(op domain.Operation, pref preference) domain.MockFaults {
+	s.faultMu.Lock()
+	f, ok := s.faults.Operations[op.ID]
+	if !ok {
+		f = s.faults.Default
+	}
+	s.faultMu.Unlock()
+
+	if pref.latency != nil {
+		f.Latency = pref.latency
+	}
+	if pref.errorRate != nil {
+		f.ErrorRate = *pref.errorRate
+	}
+	if pref.resetRate != nil {
+		f.ResetRate = *pref.resetRate
+	}
+	if pref.slowBody > 0 {
+		f.SlowBodyRate, f.BytesPerSecond = 100, pref.slowBody
+	}
+	if pref.noRateLimit {
+		f.RateLimit = 0
+	}
+	return f
+}
+
+// rateLimited counts the request against the operation's limit, answering
+// with a 429 once it is exceeded.
+func (s *MockService) rateLimited(m routeMatch, req domain.MockRequest, f domain.MockFaults) (domain.MockResponse, bool) {
+	if f.RateLimit <= 0 {
+		return domain.MockResponse{}, false
+	}
+	window := f.RateWindow
+	if window <= 0 {
+		window = time.Minute
+	}
+
+	s.faultMu.Lock()
+	now := s.now()
+	w, ok := s.windows[routeKey(m.op)]
+	if !ok || now.Sub(w.start) >= window {
+		w = &rateWindow{start: now}
+		s.windows[routeKey(m.op)] = w
+	}
+	w.count++
+	count, reset := w.count, w.start.Add(window)
+	s.faultMu.Unlock()
+
+	if count <= f.RateLimit {
+		return domain.MockResponse{}, false
+	}
+	var resp domain.MockResponse
+	if _, ok := domain.MatchResponse(m.op.Responses, "429"); ok {
+		resp = s.static(m, req, preference{code: "429"})
+	} else {
+		resp = mockError(http.StatusTooManyRequests, fmt.Sprintf("rate limit of %d requests per %s exceeded", f.RateLimit, window), nil)
+	}
+	resp.Header.Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(reset.Sub(now).Seconds())))))
+	return resp, true
+}
+
+// injectedError answers with one of the fault's error codes, or one of the
+// operation's declared errors.
+func (s *MockService) injectedError(m routeMatch, req domain.MockRequest, f domain.MockFaults) domain.MockResponse {
+	codes := f.ErrorCodes
+	if len(codes) == 0 {
+		codes = declaredErrors(m.op)
+	}
+	s.faultMu.Lock()
+	code := codes[s.rng.IntN(len(codes))]
+	s.faultMu.Unlock()
+
+	if _, ok := domain.MatchResponse(m.op.Responses, strconv.Itoa(code)); ok {
+		return s.static(m, req, preference{code: strconv.Itoa(code)})
+	}
+	return mockError(code, "injected fault", nil)
+}
+
+// declaredErrors lists the operation's 4xx and 5xx codes, with 500 for a
+// default response or when there are none.
+func declaredErrors(op domain.Operation) []int {
+	var codes []int
+	for code := range op.Responses {
+		if n, err := strconv.Atoi(code); err == nil && n >= 400 {
+			codes = append(codes, n)
+		} else if len(code) == 3 && strings.EqualFold(code[1:], "XX") && code[0] >= '4' {
+			codes = append(codes, int(code[0]-'0')*100)
+		}
+	}
+	if _, ok := op.Responses["default"]; ok || len(codes) == 0 {
+		codes = append(codes, http.StatusInternalServerError)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+// actOut sets the faults the server acts out on the response: a delay, a
+// dropped connection and a slow body.
+func (s *MockService) actOut(resp *domain.MockResponse, f domain.MockFaults) {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+	if f.Latency != nil {
+		resp.Delay = sampleLatency(s.rng, *f.Latency)
+	}
+	resp.Reset = s.rollLocked(f.ResetRate)
+	if s.rollLocked(f.SlowBodyRate) {
+		resp.BytesPerSecond = f.BytesPerSecond
+		if resp.BytesPerSecond <= 0 {
+			resp.BytesPerSecond = defaultBytesPerSecond
+		}
+	}
+}
+
+// roll reports whether a request falls within a percentage.
+func (s *MockService) roll(percent float64) bool {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+	return s.rollLocked(percent)
+}
+
+func (s *MockService) rollLocked(percent float64) bool {
+	switch {
+	case percent <= 0:
+		return false
+	case percent >= 100:
+		return true
+	}
+	return s.rng.Float64()*100 < percent
+}
+
+// sampleLatency draws a delay from the distribution, never negative.
+func sampleLatency(r *rand.Rand, l domain.Latency) time.Duration {
+	var d time.Duration
+	switch l.Distribution {
+	case domain.LatencyUniform:
+		d = l.Min
+		if l.Max > l.Min {
+			d += time.Duration(r.Int64N(int64(l.Max - l.Min + 1)))
+		}
+		return d
+	case domain.LatencyNormal:
+		d = l.Mean + time.Duration(r.NormFloat64()*float64(l.StdDev))
+	case domain.LatencyExponential:
+		d = time.Duration(r.ExpFloat64() * float64(l.Mean))
+	default:
+		return l.Mean
+	}
+	d = max(d, l.Min, 0)
+	if l.Max > 0 {
+		d = min(d, l.Max)
+	}
+	return d
+}
@@ -0,0 +1,420 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// MockService implements domain.MockService for one spec.
+type MockService struct {
+	router    *router
+	validator domain.ValidationService
+	examples  domain.ExampleService
+	resources *resourceMap
+	store     *resourceStore // nil unless EnableStore was called
+
+	faultMu sync.Mutex // guards the fields below
+	faults  domain.MockFaultConfig
+	rng     *rand.Rand
+	windows map[string]*rateWindow
+	now     func() time.Time
+}
+
+// NewMockService serves spec's operations below any of its servers' base
+// paths. Requests are checked with validator and responses without an
+// example are generated by examples.
+func NewMockService(spec *domain.Spec, validator domain.ValidationService, examples domain.ExampleService) *MockService {
+	return &MockService{
+		router:    newRouter(spec.Operations, spec.Servers),
+		validator: validator,
+		examples:  examples,
+		resources: inferResources(spec.Operations),
+		rng:       rand.New(rand.NewPCG(1, 1)),
+		windows:   map[string]*rateWindow{},
+		now:       time.Now,
+	}
+}
+
+// preference holds the directives of a Prefer header: which response to
+// answer with, and overrides of the operation's faults.
+type preference struct {
+	code    string
+	example string
+	dynamic bool
+
+	latency     *domain.Latency
+	errorRate   *float64
+	resetRate   *float64
+	slowBody    int
+	noRateLimit bool
+}
+
+// picksResponse reports whether the preference names the response, which
+// takes precedence over stored resources.
+func (p preference) picksResponse() bool {
+	return p.code != "" || p.example != "" || p.dynamic
+}
+
+func (s *MockService) Respond(req domain.MockRequest) domain.MockResponse {
+	method := domain.HTTPMethod(strings.ToUpper(string(req.Method)))
+	matches := s.router.find(req.Path)
+	if len(matches) == 0 {
+		return mockError(http.StatusNotFound, fmt.Sprintf("no operation matches %s %s", method, req.Path), nil)
+	}
+	m, ok := forMethod(matches, method)
+	if !ok && method == domain.HEAD {
+		m, ok = forMethod(matches, domain.GET)
+	}
+	if !ok {
+		resp := mockError(http.StatusMethodNotAllowed, fmt.Sprintf("%s is not defined for %s", method, matches[0].op.Path), nil)
+		resp.Header.Set("Allow", strings.Join(allowedMethods(matches), ", "))
+		return resp
+	}
+
+	resp := s.respond(m, req)
+	resp.OperationID = m.op.ID
+	if method == domain.HEAD {
+		resp.Body = nil
+	}
+	return resp
+}
+
+func (s *MockService) respond(m routeMatch, req domain.MockRequest) domain.MockResponse {
+	pref, err := parsePrefer(req.Header.Values("Prefer"))
+	if err != nil {
+		return mockError(http.StatusBadRequest, err.Error(), nil)
+	}
+	faults := s.faultsFor(m.op, pref)
+	resp, limited := s.rateLimited(m, req, faults)
+	if !limited {
+		resp = s.answer(m, req, pref, faults)
+	}
+	s.actOut(&resp, faults)
+	return resp
+}
+
+// answer validates the request, then answers with an injected error, a
+// stored resource or the spec's response.
+func (s *MockService) answer(m routeMatch, req domain.MockRequest, pref preference, faults domain.MockFaults) domain.MockResponse {
+	if violations := s.validator.ValidateRequest(m.op, receivedValues(m, req.Query, req.Header, req.Body)); len(violations) > 0 {
+		return mockError(http.StatusBadRequest, "request does not match the spec", violations)
+	}
+	if s.roll(faults.ErrorRate) {
+		return s.injectedError(m, req, faults)
+	}
+	if s.store != nil && !pref.picksResponse() {
+		if resp, ok := s.stateful(m, req); ok {
+			return resp
+		}
+	}
+	return s.static(m, req, pref)
+}
+
+// static answers with the response pref picks, regardless of any stored
+// resources.
+func (s *MockService) static(m routeMatch, req domain.MockRequest, pref preference) domain.MockResponse {
+	status, spec, err := chooseResponse(m.op, pref.code)
+	if err != nil {
+		return mockError(http.StatusBadRequest, err.Error(), nil)
+	}
+
+	resp := domain.MockResponse{StatusCode: status, Header: s.declaredHeaders(spec)}
+	if len(spec.Content) == 0 {
+		return resp
+	}
+
+	key, mediaType, ok := negotiate(spec.Content, req.Header.Get("Accept"))
+	if !ok {
+		return mockError(http.StatusNotAcceptable,
+			fmt.Sprintf("response %d is only available as %s", status, strings.Join(sortedKeys(spec.Content), ", ")), nil)
+	}
+	value, err := s.exampleValue(spec.Content[key], pref)
+	if err != nil {
+		return mockError(http.StatusBadRequest, fmt.Sprintf("response %d %s: %v", status, mediaType, err), nil)
+	}
+	if value == nil {
+		return resp
+	}
+	if strings.Contains(mediaType, "*") {
+		mediaType = "application/octet-stream"
+	}
+	resp.Header.Set("Content-Type", mediaType)
+	resp.Body = encodeExample(mediaType, value)
+	return resp
+}
+
+// declaredHeaders generates a value for each header the response declares
+// with a schema.
+func (s *MockService) declaredHeaders(spec domain.Response) http.Header {
+	header := http.Header{}
+	for _, name := range sortedKeys(spec.Headers) {
+		if h := spec.Headers[name]; h.Schema != nil {
+			if v, ok := headerValue(s.examples.Generate(h.Schema)); ok {
+				header.Set(name, v)
+			}
+		}
+	}
+	return header
+}
+
+// receivedValues collects the parts of a received request the operation
+// declares, the way the request builder would have entered them.
+func receivedValues(m routeMatch, query url.Values, header http.Header, body []byte) domain.RequestValues {
+	values := domain.RequestValues{
+		Path:        m.params,
+		Query:       map[string]string{},
+		Header:      map[string]string{},
+		Cookie:      map[string]string{},
+		ContentType: header.Get("Content-Type"),
+		Body:        string(body),
+	}
+	for name, vs := range query {
+		if len(vs) > 0 {
+			values.Query[name] = vs[0]
+		}
+	}
+	for _, line := range header.Values("Cookie") {
+		cookies, _ := http.ParseCookie(line)
+		for _, c := range cookies {
+			if _, ok := values.Cookie[c.Name]; !ok {
+				values.Cookie[c.Name] = c.Value
+			}
+		}
+	}
+	for _, p := range m.op.Parameters {
+		if p.In == domain.ParameterInHeader {
+			if v := header.Get(p.Name); v != "" {
+				values.Header[p.Name] = v
+			}
+		}
+	}
+	return values
+}
+
+func allowedMethods(matches []routeMatch) []string {
+	seen := map[domain.HTTPMethod]bool{}
+	for _, m := range matches {
+		seen[m.op.Method] = true
+	}
+	var allowed []string
+	for _, method := range domain.HTTPMethods {
+		if seen[method] {
+			allowed = append(allowed, string(method))
+		}
+	}
+	return allowed
+}
+
+// parsePrefer reads the preferences, which may share one header
+// ("code=404, example=missing") or come in several.
+func parsePrefer(headers []string) (preference, error) {
+	var pref preference
+	for _, header := range headers {
+		for _, part := range strings.FieldsFunc(header, func(r rune) bool { return r == ',' || r == ';' }) {
+			key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+			key = strings.ToLower(strings.TrimSpace(key))
+			value = strings.Trim(strings.TrimSpace(value), `"`)
+			switch key {
+			case "code":
+				pref.code = value
+			case "example":
+				pref.example = value
+			case "dynamic":
+				pref.dynamic = value == "" || strings.EqualFold(value, "true")
+			case "latency":
+				l, err := domain.ParseLatency(value)
+				if err != nil {
+					return preference{}, fmt.Errorf("Prefer: %w", err)
+				}
+				pref.latency = &l
+			case "error-rate", "reset-rate":
+				rate, err := strconv.ParseFloat(value, 64)
+				if err != nil || rate < 0 || rate > 100 {
+					return preference{}, fmt.Errorf("Prefer: %s %q is not a percentage", key, value)
+				}
+				if key == "error-rate" {
+					pref.errorRate = &rate
+				} else {
+					pref.resetRate = &rate
+				}
+			case "slow-body":
+				n, err := strconv.Atoi(value)
+				if err != nil || n <= 0 {
+					return preference{}, fmt.Errorf("Prefer: slow-body %q is not a number of bytes per second", value)
+				}
+				pref.slowBody = n
+			case "rate-limit":
+				pref.noRateLimit = strings.EqualFold(value, "off") || value == "0"
+			}
+		}
+	}
+	return pref, nil
+}
+
+// chooseResponse picks the response for a preferred status code, or the
+// operation's first success response. Ranges such as "2XX" and "default"
+// answer with the first code they cover.
+func chooseResponse(op domain.Operation, code string) (int, domain.Response, error) {
+	if code != "" {
+		status, err := strconv.Atoi(code)
+		if err != nil || status < 100 || status > 599 {
+			return 0, domain.Response{}, fmt.Errorf("preferred code %q is not a status code", code)
+		}
+		resp, ok := domain.MatchResponse(op.Responses, code)
+		if !ok {
+			return 0, domain.Response{}, fmt.Errorf("%s declares no %d response (have: %s)", op.ID, status, strings.Join(sortedKeys(op.Responses), ", "))
+		}
+		return status, resp, nil
+	}
+	if len(op.Responses) == 0 {
+		return http.StatusNoContent, domain.Response{}, nil
+	}
+
+	codes := sortedKeys(op.Responses)
+	for _, c := range codes {
+		if status, err := strconv.Atoi(c); err == nil && c[0] == '2' {
+			return status, op.Responses[c], nil
+		}
+	}
+	for _, c := range []string{"2XX", "2xx", "default"} {
+		if resp, ok := op.Responses[c]; ok {
+			return http.StatusOK, resp, nil
+		}
+	}
+	for _, c := range codes {
+		if status, err := strconv.Atoi(c); err == nil {
+			return status, op.Responses[c], nil
+		}
+	}
+	status, _ := strconv.Atoi(codes[0][:1] + "00")
+	return status, op.Responses[codes[0]], nil
+}
+
+// negotiate picks the documented media type for an Accept header, in the
+// header's order, and the content type to answer with: the accepted type
+// when the spec documents a range such as "image/*". Without a preference
+// JSON is chosen when documented.
+func negotiate(content map[string]domain.MediaType, accept string) (key, contentType string, ok bool) {
+	types := sortedKeys(content)
+	if strings.TrimSpace(accept) != "" {
+		for _, item := range strings.Split(accept, ",") {
+			want, params, err := mime.ParseMediaType(strings.TrimSpace(item))
+			if err != nil || params["q"] == "0" {
+				continue
+			}
+			if want == "*/*" {
+				break
+			}
+			major, _, _ := strings.Cut(want, "/")
+			for _, t := range types {
+				if t == want || (strings.HasSuffix(want, "/*") && strings.HasPrefix(t, major+"/")) {
+					return t, t, true
+				}
+			}
+			if strings.Contains(want, "*") {
+				continue
+			}
+			for _, wildcard := range []string{major + "/*", "*/*"} {
+				if _, ok := content[wildcard]; ok {
+					return wildcard, want, true
+				}
+			}
+		}
+		if !strings.Contains(accept, "*/*") {
+			return "", "", false
+		}
+	}
+	for _, t := range types {
+		if isJSONMediaType(t) {
+			return t, t, true
+		}
+	}
+	return types[0], types[0], true
+}
+
+// exampleValue picks the named example, or with dynamic one generated
+// from the schema. Otherwise the inline example comes first, then the
+// first named one, then a generated one.
+func (s *MockService) exampleValue(mt domain.MediaType, pref preference) (any, error) {
+	switch {
+	case pref.example != "":
+		ex, ok := mt.Examples[pref.example]
+		if !ok {
+			have := "none"
+			if len(mt.Examples) > 0 {
+				have = strings.Join(sortedKeys(mt.Examples), ", ")
+			}
+			return nil, fmt.Errorf("no example named %q (have: %s)", pref.example, have)
+		}
+		return ex.Value, nil
+	case pref.dynamic:
+	case mt.Example != nil:
+		return mt.Example, nil
+	case len(mt.Examples) > 0:
+		return mt.Examples[sortedKeys(mt.Examples)[0]].Value, nil
+	}
+	if mt.Schema == nil {
+		return nil, nil
+	}
+	return s.examples.Generate(mt.Schema), nil
+}
+
+// encodeExample writes a value as JSON, except strings for non-JSON media
+// types, which are sent as they are.
+func encodeExample(mediaType string, value any) []byte {
+	if s, ok := value.(string); ok && !isJSONMediaType(mediaType) {
+		return []byte(s)
+	}
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return []byte(fmt.Sprint(value))
+	}
+	return append(data, '\n')
+}
+
+// headerValue formats a generated scalar as a header value.
+func headerValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case nil, map[string]any, []any:
+		return "", false
+	case string:
+		return v, true
+	default:
+		return fmt.Sprint(v), true
+	}
+}
+
+// mockProblem is the JSON body of the mock's own errors.
+type mockProblem struct {
+	Error      string          `json:"error"`
+	Violations []mockViolation `json:"violations,omitempty"`
+}
+
+type mockViolation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func mockError(status int, message string, violations []domain.Violation) domain.MockResponse {
+	problem := mockProblem{Error: message}
+	for _, v := range violations {
+		problem.Violations = append(problem.Violations, mockViolation{Path: v.Path, Message: v.Message})
+	}
+	body, _ := json.MarshalIndent(problem, "", "  ")
+	return domain.MockResponse{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       append(body, '\n'),
+		Violations: violations,
+	}
+}
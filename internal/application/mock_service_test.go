@@ -0,0 +1,346 @@
+package application_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func mockSpec() *domain.Spec {
+	pet := &domain.Schema{
+		Type:     domain.SchemaTypeObject,
+		Required: []string{"id", "name"},
+		Properties: map[string]*domain.Schema{
+			"id":   {Type: domain.SchemaTypeInteger, Minimum: ptr(1.0)},
+			"name": {Type: domain.SchemaTypeString, MinLength: 1},
+		},
+	}
+	newPet := &domain.Schema{Type: domain.SchemaTypeObject, Required: []string{"name"}, Properties: map[string]*domain.Schema{
+		"id":   {Type: domain.SchemaTypeInteger, Minimum: ptr(1.0)},
+		"name": {Type: domain.SchemaTypeString, MinLength: 1},
+	}}
+	problem := &domain.Schema{Type: domain.SchemaTypeObject, Properties: map[string]*domain.Schema{
+		"message": {Type: domain.SchemaTypeString},
+	}}
+	return &domain.Spec{
+		Servers: []domain.Server{{URL: "https://api.example.com/v1"}},
+		Operations: []domain.Operation{
+			{ID: "listPets", Method: domain.GET, Path: "/pets",
+				Parameters: []domain.Parameter{{Name: "limit", In: domain.ParameterInQuery,
+					Schema: &domain.Schema{Type: domain.SchemaTypeInteger, Maximum: ptr(100.0)}}},
+				Responses: map[string]domain.Response{
+					"200": {Content: map[string]domain.MediaType{"application/json": {
+						Schema: &domain.Schema{Type: domain.SchemaTypeArray, Items: pet},
+						Examples: map[string]domain.Example{
+							"one":  {Value: []any{map[string]any{"id": 1, "name": "Rex"}}},
+							"none": {Value: []any{}},
+						},
+					}}, Headers: map[string]domain.Header{
+						"X-Total":       {Schema: &domain.Schema{Type: domain.SchemaTypeInteger, Example: 42}},
+						"X-Total-Count": {Schema: &domain.Schema{Type: domain.SchemaTypeInteger, Example: 42}},
+					}},
+				}},
+			{ID: "createPet", Method: domain.POST, Path: "/pets",
+				RequestBody: &domain.RequestBody{Required: true, Content: map[string]domain.MediaType{"application/json": {Schema: newPet}}},
+				Responses: map[string]domain.Response{
+					"201":     {Content: map[string]domain.MediaType{"application/json": {Schema: pet}}},
+					"default": {Content: map[string]domain.MediaType{"application/json": {Schema: problem}}},
+				}},
+			{ID: "getPet", Method: domain.GET, Path: "/pets/{petId}",
+				Parameters: []domain.Parameter{{Name: "petId", In: domain.ParameterInPath, Required: true,
+					Schema: &domain.Schema{Type: domain.SchemaTypeInteger}}},
+				Responses: map[string]domain.Response{
+					"200": {Content: map[string]domain.MediaType{
+						"application/json": {Schema: pet},
+						"text/plain":       {Example: "Rex"},
+					}},
+					"404": {Description: "not found"},
+				}},
+			{ID: "updatePet", Method: domain.PATCH, Path: "/pets/{petId}",
+				Parameters:  []domain.Parameter{{Name: "petId", In: domain.ParameterInPath, Required: true}},
+				RequestBody: &domain.RequestBody{Content: map[string]domain.MediaType{"application/json": {}}},
+				Responses: map[string]domain.Response{
+					"200": {Content: map[string]domain.MediaType{"application/json": {Schema: pet}}},
+				}},
+			{ID: "deletePet", Method: domain.DELETE, Path: "/pets/{petId}",
+				Parameters: []domain.Parameter{{Name: "petId", In: domain.ParameterInPath, Required: true}},
+				Responses:  map[string]domain.Response{"204": {}}},
+		},
+	}
+}
+
+func newMock() *application.MockService {
+	return application.NewMockService(mockSpec(), application.NewValidationService(), application.NewExampleService(1))
+}
+
+func mockRequest(svc domain.MockService, method domain.HTTPMethod, target string, header http.Header, body string) domain.MockResponse {
+	u, _ := url.Parse(target)
+	if header == nil {
+		header = http.Header{}
+	}
+	if body != "" && header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+	}
+	return svc.Respond(domain.MockRequest{Method: method, Path: u.EscapedPath(), Query: u.Query(), Header: header, Body: []byte(body)})
+}
+
+func mockRespond(method domain.HTTPMethod, target string, header http.Header, body string) domain.MockResponse {
+	return mockRequest(newMock(), method, target, header, body)
+}
+
+func TestMockService_Routes(t *testing.T) {
+	resp := mockRespond(domain.GET, "/v1/pets/7", nil, "")
+	if resp.StatusCode != http.StatusOK || resp.OperationID != "getPet" {
+		t.Fatalf("expected getPet to answer 200, got %d from %q: %s", resp.StatusCode, resp.OperationID, resp.Body)
+	}
+	var pet map[string]any
+	if err := json.Unmarshal(resp.Body, &pet); err != nil || pet["name"] == nil {
+		t.Errorf("expected a generated pet, got %s (%v)", resp.Body, err)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected JSON by default, got %q", resp.Header.Get("Content-Type"))
+	}
+
+	if resp := mockRespond(domain.GET, "/v1/owners", nil, ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected 404 for an unknown path, got %d", resp.StatusCode)
+	}
+	resp = mockRespond(domain.DELETE, "/v1/pets", nil, "")
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "GET, POST" {
+		t.Errorf("expected 405 allowing GET and POST, got %d %v", resp.StatusCode, resp.Header)
+	}
+	resp = mockRespond(domain.HEAD, "/v1/pets/7", nil, "")
+	if resp.StatusCode != http.StatusOK || len(resp.Body) != 0 {
+		t.Errorf("expected HEAD to answer like GET without a body, got %d %q", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestMockService_Validates(t *testing.T) {
+	resp := mockRespond(domain.GET, "/v1/pets?limit=500", nil, "")
+	if resp.StatusCode != http.StatusBadRequest || len(resp.Violations) != 1 || resp.Violations[0].Path != "query limit" {
+		t.Fatalf("expected a 400 for the limit, got %d %v", resp.StatusCode, resp.Violations)
+	}
+	if !strings.Contains(string(resp.Body), `"path": "query limit"`) {
+		t.Errorf("expected the violation in the body, got %s", resp.Body)
+	}
+
+	resp = mockRespond(domain.POST, "/v1/pets", http.Header{"Content-Type": {"application/json"}}, `{"id": 0}`)
+	if resp.StatusCode != http.StatusBadRequest || len(resp.Violations) != 2 {
+		t.Errorf("expected the body's violations, got %d %v", resp.StatusCode, resp.Violations)
+	}
+	resp = mockRespond(domain.POST, "/v1/pets", http.Header{"Content-Type": {"application/json"}}, `{"id": 3, "name": "Rex"}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201 for a valid body, got %d: %s", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestMockService_Prefer(t *testing.T) {
+	resp := mockRespond(domain.GET, "/v1/pets", nil, "")
+	if strings.Join(strings.Fields(string(resp.Body)), "") != "[]" || resp.Header.Get("X-Total") != "42" {
+		t.Errorf("expected the first named example and the header example, got %s %v", resp.Body, resp.Header)
+	}
+	resp = mockRespond(domain.GET, "/v1/pets", http.Header{"Prefer": {"example=one"}}, "")
+	if !strings.Contains(string(resp.Body), `"Rex"`) {
+		t.Errorf("expected the named example, got %s", resp.Body)
+	}
+	resp = mockRespond(domain.GET, "/v1/pets", http.Header{"Prefer": {"dynamic=true"}}, "")
+	if strings.Contains(string(resp.Body), `"Rex"`) || resp.StatusCode != http.StatusOK {
+		t.Errorf("expected a generated list, got %s", resp.Body)
+	}
+	resp = mockRespond(domain.GET, "/v1/pets", http.Header{"Prefer": {"example=missing"}}, "")
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(resp.Body), "have: none, one") {
+		t.Errorf("expected an error naming the examples, got %d %s", resp.StatusCode, resp.Body)
+	}
+
+	resp = mockRespond(domain.GET, "/v1/pets/7", http.Header{"Prefer": {"code=404"}}, "")
+	if resp.StatusCode != http.StatusNotFound || resp.OperationID != "getPet" || len(resp.Body) != 0 {
+		t.Errorf("expected the declared 404, got %d %s", resp.StatusCode, resp.Body)
+	}
+	resp = mockRespond(domain.POST, "/v1/pets", http.Header{"Prefer": {"code=503"}, "Content-Type": {"application/json"}}, `{"id": 3, "name": "Rex"}`)
+	if resp.StatusCode != http.StatusServiceUnavailable || !strings.Contains(string(resp.Body), "message") {
+		t.Errorf("expected the default response as a 503, got %d %s", resp.StatusCode, resp.Body)
+	}
+	resp = mockRespond(domain.GET, "/v1/pets/7", http.Header{"Prefer": {"code=500"}}, "")
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(resp.Body), "declares no 500 response") {
+		t.Errorf("expected an undeclared code to be refused, got %d %s", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestMockService_NegotiatesContentType(t *testing.T) {
+	resp := mockRespond(domain.GET, "/v1/pets/7", http.Header{"Accept": {"text/plain, application/json;q=0.5"}}, "")
+	if resp.Header.Get("Content-Type") != "text/plain" || string(resp.Body) != "Rex" {
+		t.Errorf("expected the plain text example, got %q %q", resp.Header.Get("Content-Type"), resp.Body)
+	}
+	if resp := mockRespond(domain.GET, "/v1/pets/7", http.Header{"Accept": {"application/xml"}}, ""); resp.StatusCode != http.StatusNotAcceptable {
+		t.Errorf("expected 406, got %d", resp.StatusCode)
+	}
+}
+
+func TestMockService_Stateful(t *testing.T) {
+	svc := newMock()
+	if err := svc.EnableStore(map[string][]any{"/pets": {map[string]any{"id": 5, "name": "Seed"}}}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	resp := mockRequest(svc, domain.POST, "/v1/pets", nil, `{"name": "Rex"}`)
+	if resp.StatusCode != http.StatusCreated || resp.Header.Get("Location") != "/v1/pets/6" {
+		t.Fatalf("expected 201 at /v1/pets/6, got %d %v: %s", resp.StatusCode, resp.Header, resp.Body)
+	}
+	var created map[string]any
+	if err := json.Unmarshal(resp.Body, &created); err != nil || created["id"] != 6.0 || created["name"] != "Rex" {
+		t.Errorf("expected the created pet with an assigned ID, got %s", resp.Body)
+	}
+
+	resp = mockRequest(svc, domain.GET, "/v1/pets/6", nil, "")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(resp.Body), `"Rex"`) {
+		t.Errorf("expected to read the created pet, got %d %s", resp.StatusCode, resp.Body)
+	}
+	resp = mockRequest(svc, domain.PATCH, "/v1/pets/6", nil, `{"name": "Max"}`)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(resp.Body), `"id": 6`) || !strings.Contains(string(resp.Body), `"Max"`) {
+		t.Errorf("expected the updated pet, got %d %s", resp.StatusCode, resp.Body)
+	}
+
+	var list []map[string]any
+	resp = mockRequest(svc, domain.GET, "/v1/pets", nil, "")
+	if err := json.Unmarshal(resp.Body, &list); err != nil || len(list) != 2 || list[0]["name"] != "Seed" || list[1]["name"] != "Max" {
+		t.Errorf("expected the seeded and created pets in order, got %s", resp.Body)
+	}
+	if resp.Header.Get("X-Total") != "42" || resp.Header.Get("X-Total-Count") != "2" {
+		t.Errorf("expected the declared headers with the real count, got %v", resp.Header)
+	}
+
+	if resp := mockRequest(svc, domain.DELETE, "/v1/pets/5", nil, ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", resp.StatusCode)
+	}
+	if resp := mockRequest(svc, domain.GET, "/v1/pets/5", nil, ""); resp.StatusCode != http.StatusNotFound || resp.OperationID != "getPet" {
+		t.Errorf("expected the declared 404 after deleting, got %d", resp.StatusCode)
+	}
+	if resp := mockRequest(svc, domain.DELETE, "/v1/pets/5", nil, ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected deleting twice to give 404, got %d", resp.StatusCode)
+	}
+	if resp := mockRequest(svc, domain.GET, "/v1/pets", http.Header{"Prefer": {"example=one"}}, ""); !strings.Contains(string(resp.Body), `"Rex"`) {
+		t.Errorf("expected Prefer to bypass the store, got %s", resp.Body)
+	}
+}
+
+func TestMockService_SeedErrors(t *testing.T) {
+	if err := newMock().EnableStore(map[string][]any{"/owners": {}}); err == nil || !strings.Contains(err.Error(), "/owners is not a collection") {
+		t.Errorf("expected an unknown collection to be refused, got %v", err)
+	}
+	if err := newMock().EnableStore(map[string][]any{"/pets": {"Rex"}}); err == nil || !strings.Contains(err.Error(), "/pets[0] is not an object") {
+		t.Errorf("expected a non-object to be refused, got %v", err)
+	}
+}
+
+func TestMockService_Faults(t *testing.T) {
+	svc := newMock()
+	err := svc.SetFaults(domain.MockFaultConfig{
+		Default: domain.MockFaults{Latency: &domain.Latency{Distribution: domain.LatencyUniform, Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}},
+		Operations: map[string]domain.MockFaults{
+			"getPet":    {RateLimit: 2, RateWindow: time.Minute},
+			"listPets":  {ErrorRate: 100},
+			"createPet": {ErrorRate: 100, ErrorCodes: []int{503}},
+		},
+	}, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := range 2 {
+		if resp := mockRequest(svc, domain.GET, "/v1/pets/7", nil, ""); resp.StatusCode != http.StatusOK || resp.Delay != 0 {
+			t.Fatalf("request %d: expected 200 without the default's latency, got %d after %s", i, resp.StatusCode, resp.Delay)
+		}
+	}
+	resp := mockRequest(svc, domain.GET, "/v1/pets/7", nil, "")
+	if resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "60" {
+		t.Errorf("expected 429 with Retry-After, got %d %v", resp.StatusCode, resp.Header)
+	}
+	if resp := mockRequest(svc, domain.GET, "/v1/pets/7", http.Header{"Prefer": {"rate-limit=off"}}, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the header to lift the limit, got %d", resp.StatusCode)
+	}
+
+	resp = mockRequest(svc, domain.GET, "/v1/pets", nil, "")
+	if resp.StatusCode != http.StatusInternalServerError || !strings.Contains(string(resp.Body), "injected fault") {
+		t.Errorf("expected an injected 500, got %d %s", resp.StatusCode, resp.Body)
+	}
+	if resp.Delay != 0 {
+		t.Errorf("expected the operation's entry to replace the default, got %s", resp.Delay)
+	}
+	if resp := mockRequest(svc, domain.DELETE, "/v1/pets/7", nil, ""); resp.Delay < 10*time.Millisecond || resp.Delay > 20*time.Millisecond {
+		t.Errorf("expected the default latency, got %s", resp.Delay)
+	}
+	resp = mockRequest(svc, domain.POST, "/v1/pets", nil, `{"name": "Rex"}`)
+	if resp.StatusCode != http.StatusServiceUnavailable || !strings.Contains(string(resp.Body), "message") {
+		t.Errorf("expected the declared default response as a 503, got %d %s", resp.StatusCode, resp.Body)
+	}
+	if resp := mockRequest(svc, domain.GET, "/v1/pets", http.Header{"Prefer": {"error-rate=0"}}, ""); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected the header to stop errors, got %d", resp.StatusCode)
+	}
+}
+
+func TestMockService_RateLimitUsesDeclaredResponse(t *testing.T) {
+	svc := newMock()
+	if err := svc.SetFaults(domain.MockFaultConfig{Operations: map[string]domain.MockFaults{
+		"createPet": {RateLimit: 1, RateWindow: time.Minute},
+	}}, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	mockRequest(svc, domain.POST, "/v1/pets", nil, `{"name": "Rex"}`)
+	// createPet declares no 429, so its default response answers.
+	resp := mockRequest(svc, domain.POST, "/v1/pets", nil, `{"name": "Rex"}`)
+	if resp.StatusCode != http.StatusTooManyRequests || strings.Contains(string(resp.Body), "rate limit of") || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("expected the default response as a 429, got %d %s", resp.StatusCode, resp.Body)
+	}
+}
+
+func TestMockService_FaultOverrides(t *testing.T) {
+	resp := mockRespond(domain.GET, "/v1/pets/7", http.Header{"Prefer": {"latency=250ms, reset-rate=100", "slow-body=64"}}, "")
+	if resp.Delay != 250*time.Millisecond || !resp.Reset || resp.BytesPerSecond != 64 {
+		t.Errorf("expected the header's faults, got delay %s, reset %v, %d B/s", resp.Delay, resp.Reset, resp.BytesPerSecond)
+	}
+	resp = mockRespond(domain.GET, "/v1/pets/7", http.Header{"Prefer": {"latency=soon"}}, "")
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(resp.Body), `latency \"soon\"`) {
+		t.Errorf("expected a bad latency to be refused, got %d %s", resp.StatusCode, resp.Body)
+	}
+
+	if err := newMock().SetFaults(domain.MockFaultConfig{Operations: map[string]domain.MockFaults{"getOwner": {}}}, 1); err == nil {
+		t.Error("expected an unknown operation to be refused")
+	}
+	if err := newMock().SetFaults(domain.MockFaultConfig{Default: domain.MockFaults{ErrorRate: 120}}, 1); err == nil {
+		t.Error("expected a rate above 100 to be refused")
+	}
+}
+
+func TestMockService_LatencyDistributions(t *testing.T) {
+	latency := &domain.Latency{Distribution: domain.LatencyNormal, Mean: 100 * time.Millisecond, StdDev: 50 * time.Millisecond, Max: 150 * time.Millisecond}
+	delays := func() []time.Duration {
+		svc := newMock()
+		if err := svc.SetFaults(domain.MockFaultConfig{Default: domain.MockFaults{Latency: latency}}, 42); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		var out []time.Duration
+		for range 50 {
+			out = append(out, mockRequest(svc, domain.GET, "/v1/pets/7", nil, "").Delay)
+		}
+		return out
+	}
+	first, second := delays(), delays()
+	var sum time.Duration
+	for i, d := range first {
+		if d < 0 || d > latency.Max {
+			t.Fatalf("delay %s outside [0, %s]", d, latency.Max)
+		}
+		if d != second[i] {
+			t.Fatalf("expected the same delays for the same seed, got %s and %s", d, second[i])
+		}
+		sum += d
+	}
+	if mean := sum / time.Duration(len(first)); mean < 70*time.Millisecond || mean > 120*time.Millisecond {
+		t.Errorf("expected delays around the mean, got %s", mean)
+	}
+}
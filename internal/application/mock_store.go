@@ -0,0 +1,438 @@
+package application
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dazzle/internal/domain"
+)
+
+// totalCountHeader, when a list response declares it, is set to the number
+// of items listed.
+const totalCountHeader = "X-Total-Count"
+
+type resourceKind int
+
+const (
+	collectionResource resourceKind = iota
+	itemResource
+)
+
+// resourceRoute is what an operation does to stored resources. template
+// is the collection's path template; param the item's ID parameter.
+type resourceRoute struct {
+	kind     resourceKind
+	template string
+	param    string
+}
+
+// resourceGroup describes the resources of a collection template: the
+// property holding their ID and its schema.
+type resourceGroup struct {
+	idField  string
+	idSchema *domain.Schema
+	hasItems bool
+}
+
+type resourceMap struct {
+	routes map[string]resourceRoute // by method and path template
+	groups map[string]*resourceGroup
+}
+
+var wholeParam = regexp.MustCompile(`^\{([^{}]+)\}$`)
+
+// cleanTemplate drops a path template's trailing slash.
+func cleanTemplate(path string) string {
+	return "/" + strings.Join(splitPath(path), "/")
+}
+
+func routeKey(op domain.Operation) string {
+	return string(op.Method) + " " + op.Path
+}
+
+// inferResources finds the collections and items among ops. A path
+// template ending in a literal segment such as /owners/{ownerId}/pets is a
+// collection when an item template below it exists
+// (/owners/{ownerId}/pets/{petId}) or it has both GET and POST. POST
+// creates in a collection and GET lists it; GET, PUT, PATCH and DELETE on
+// an item read, replace, update and remove it. Collections are stored by
+// their concrete path, so every owner has their own pets.
+func inferResources(ops []domain.Operation) *resourceMap {
+	rm := &resourceMap{routes: map[string]resourceRoute{}, groups: map[string]*resourceGroup{}}
+	methods := map[string]map[domain.HTTPMethod]bool{}
+	for _, op := range ops {
+		path := cleanTemplate(op.Path)
+		if methods[path] == nil {
+			methods[path] = map[domain.HTTPMethod]bool{}
+		}
+		methods[path][op.Method] = true
+	}
+
+	params := map[string]string{} // collection template → item parameter
+	for _, op := range ops {
+		segments := splitPath(op.Path)
+		if len(segments) < 2 {
+			continue
+		}
+		if sub := wholeParam.FindStringSubmatch(segments[len(segments)-1]); sub != nil {
+			parent := "/" + strings.Join(segments[:len(segments)-1], "/")
+			if !strings.Contains(parent[strings.LastIndex(parent, "/"):], "{") {
+				params[parent] = sub[1]
+			}
+		}
+	}
+	for path, m := range methods {
+		if path == "/" || strings.Contains(path[strings.LastIndex(path, "/"):], "{") {
+			continue
+		}
+		if _, ok := params[path]; !ok && m[domain.GET] && m[domain.POST] {
+			params[path] = ""
+		}
+	}
+
+	for template, param := range params {
+		rm.groups[template] = &resourceGroup{idField: "id", hasItems: param != ""}
+	}
+	for _, op := range ops {
+		template := cleanTemplate(op.Path)
+		if _, ok := rm.groups[template]; ok && (op.Method == domain.GET || op.Method == domain.POST) {
+			rm.routes[routeKey(op)] = resourceRoute{kind: collectionResource, template: template}
+		}
+		if i := strings.LastIndex(template, "/"); i > 0 {
+			parent := template[:i]
+			if param := params[parent]; param != "" && template[i+1:] == "{"+param+"}" {
+				switch op.Method {
+				case domain.GET, domain.PUT, domain.PATCH, domain.DELETE:
+					rm.routes[routeKey(op)] = resourceRoute{kind: itemResource, template: parent, param: param}
+				}
+			}
+		}
+	}
+
+	// The ID property is the one named like the item parameter, else "id".
+	for _, op := range ops {
+		rt, ok := rm.routes[routeKey(op)]
+		if !ok {
+			continue
+		}
+		g := rm.groups[rt.template]
+		for _, schema := range resourceSchemas(op) {
+			param := params[rt.template]
+			if p, ok := schema.Properties[param]; ok && param != "" {
+				g.idField, g.idSchema = param, p
+				break
+			}
+			if p, ok := schema.Properties["id"]; ok && g.idField == "id" && g.idSchema == nil {
+				g.idSchema = p
+			}
+		}
+	}
+	return rm
+}
+
+// resourceSchemas returns the JSON object schemas of an operation's body
+// and success responses.
+func resourceSchemas(op domain.Operation) []*domain.Schema {
+	var schemas []*domain.Schema
+	add := func(content map[string]domain.MediaType) {
+		for _, name := range sortedKeys(content) {
+			if s := content[name].Schema; isJSONMediaType(name) && s != nil {
+				if s.Type == domain.SchemaTypeArray && s.Items != nil {
+					s = s.Items
+				}
+				if len(s.Properties) > 0 {
+					schemas = append(schemas, s)
+				}
+			}
+		}
+	}
+	if op.RequestBody != nil {
+		add(op.RequestBody.Content)
+	}
+	for _, code := range sortedKeys(op.Responses) {
+		if code[0] == '2' {
+			add(op.Responses[code].Content)
+		}
+	}
+	return schemas
+}
+
+// resourceStore holds the resources created during a session.
+type resourceStore struct {
+	mu          sync.Mutex
+	collections map[string]*resourceCollection
+}
+
+// resourceCollection keeps its resources in insertion order. next is the
+// highest numeric ID seen, so generated IDs do not collide.
+type resourceCollection struct {
+	ids   []string
+	items map[string]map[string]any
+	next  int64
+}
+
+func (st *resourceStore) collection(key string) *resourceCollection {
+	c, ok := st.collections[key]
+	if !ok {
+		c = &resourceCollection{items: map[string]map[string]any{}}
+		st.collections[key] = c
+	}
+	return c
+}
+
+// put stores item, assigning an ID from the group when it has none.
+func (c *resourceCollection) put(g *resourceGroup, item map[string]any) string {
+	if item[g.idField] == nil {
+		c.next++
+		item[g.idField] = newResourceID(g.idSchema, c.next)
+	}
+	id := fmt.Sprint(item[g.idField])
+	if n, err := strconv.ParseInt(id, 10, 64); err == nil && n > c.next {
+		c.next = n
+	}
+	if _, ok := c.items[id]; !ok {
+		c.ids = append(c.ids, id)
+	}
+	c.items[id] = item
+	return id
+}
+
+func (c *resourceCollection) remove(id string) bool {
+	if _, ok := c.items[id]; !ok {
+		return false
+	}
+	delete(c.items, id)
+	for i, existing := range c.ids {
+		if existing == id {
+			c.ids = append(c.ids[:i], c.ids[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+func (c *resourceCollection) list() []any {
+	items := make([]any, len(c.ids))
+	for i, id := range c.ids {
+		items[i] = c.items[id]
+	}
+	return items
+}
+
+// newResourceID formats the nth ID for the ID property's schema: a number,
+// a UUID or a numeric string.
+func newResourceID(schema *domain.Schema, n int64) any {
+	if schema == nil || schema.Type == domain.SchemaTypeInteger || schema.Type == domain.SchemaTypeNumber {
+		return n
+	}
+	if schema.Format == "uuid" {
+		return fmt.Sprintf("00000000-0000-4000-8000-%012d", n)
+	}
+	return strconv.FormatInt(n, 10)
+}
+
+// EnableStore makes the mock keep the resources created through its
+// collections for the rest of the session. data seeds collections, keyed
+// by their path below the server's base path, such as "/pets" or
+// "/owners/1/pets".
+func (s *MockService) EnableStore(data map[string][]any) error {
+	store := &resourceStore{collections: map[string]*resourceCollection{}}
+	for _, path := range sortedKeys(data) {
+		key, g, ok := s.seedCollection(path)
+		if !ok {
+			return fmt.Errorf("seed data: %s is not a collection of the spec", path)
+		}
+		c := store.collection(key)
+		for i, v := range data[path] {
+			item, ok := v.(map[string]any)
+			if !ok {
+				return fmt.Errorf("seed data: %s[%d] is not an object", path, i)
+			}
+			c.put(g, item)
+		}
+	}
+	s.store = store
+	return nil
+}
+
+func (s *MockService) seedCollection(path string) (string, *resourceGroup, bool) {
+	escaped := (&url.URL{Path: "/" + strings.Trim(path, "/")}).EscapedPath()
+	for _, m := range s.router.find(escaped) {
+		if rt, ok := s.resources.routes[routeKey(m.op)]; ok && rt.kind == collectionResource {
+			return collectionKey(escaped, m.base, 0), s.resources.groups[rt.template], true
+		}
+	}
+	return "", nil, false
+}
+
+// collectionKey is the concrete path of a collection, below the server's
+// base path, from a request path with drop trailing segments.
+func collectionKey(path, base string, drop int) string {
+	segments := splitPath(strings.TrimPrefix(path, base))
+	return "/" + strings.Join(segments[:len(segments)-drop], "/")
+}
+
+// stateful answers requests to collections and items from the store,
+// reporting false for operations that are neither.
+func (s *MockService) stateful(m routeMatch, req domain.MockRequest) (domain.MockResponse, bool) {
+	rt, ok := s.resources.routes[routeKey(m.op)]
+	if !ok {
+		return domain.MockResponse{}, false
+	}
+	g := s.resources.groups[rt.template]
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+
+	if rt.kind == collectionResource {
+		key := collectionKey(req.Path, m.base, 0)
+		c := s.store.collection(key)
+		if m.op.Method == domain.GET {
+			items := c.list()
+			resp, ok := s.resourceResponse(m.op, req, func(schema *domain.Schema) (any, bool) {
+				return s.listBody(schema, items)
+			})
+			if ok && resp.Header.Get(totalCountHeader) != "" {
+				resp.Header.Set(totalCountHeader, strconv.Itoa(len(items)))
+			}
+			return resp, ok
+		}
+		body, ok := jsonObject(req.Body)
+		if !ok {
+			return domain.MockResponse{}, false
+		}
+		var id string
+		resp, ok := s.resourceResponse(m.op, req, func(schema *domain.Schema) (any, bool) {
+			created := s.newResource(schema, body, g)
+			id = c.put(g, created)
+			return created, true
+		})
+		if !ok {
+			return resp, false
+		}
+		if id == "" {
+			id = c.put(g, body)
+		}
+		if g.hasItems {
+			resp.Header.Set("Location", m.base+key+"/"+url.PathEscape(id))
+		}
+		return resp, true
+	}
+
+	c := s.store.collection(collectionKey(req.Path, m.base, 1))
+	id := m.params[rt.param]
+	item, found := c.items[id]
+	if !found {
+		return s.notFound(m, req, fmt.Sprintf("%s %s not found", strings.TrimPrefix(rt.template, "/"), id)), true
+	}
+	switch m.op.Method {
+	case domain.DELETE:
+		c.remove(id)
+		return s.resourceResponse(m.op, req, func(*domain.Schema) (any, bool) { return item, true })
+	case domain.PUT, domain.PATCH:
+		body, ok := jsonObject(req.Body)
+		if !ok {
+			return domain.MockResponse{}, false
+		}
+		if m.op.Method == domain.PATCH {
+			merged := make(map[string]any, len(item)+len(body))
+			for k, v := range item {
+				merged[k] = v
+			}
+			for k, v := range body {
+				merged[k] = v
+			}
+			body = merged
+		}
+		body[g.idField] = item[g.idField]
+		c.put(g, body)
+		item = body
+	}
+	return s.resourceResponse(m.op, req, func(*domain.Schema) (any, bool) { return item, true })
+}
+
+// resourceResponse answers with the operation's first success response,
+// its declared headers and its JSON body built by body from the response
+// schema. It reports false when that response is not JSON.
+func (s *MockService) resourceResponse(op domain.Operation, req domain.MockRequest, body func(*domain.Schema) (any, bool)) (domain.MockResponse, bool) {
+	status, spec, _ := chooseResponse(op, "")
+	resp := domain.MockResponse{StatusCode: status, Header: s.declaredHeaders(spec)}
+	if len(spec.Content) == 0 {
+		return resp, true
+	}
+	key, mediaType, ok := negotiate(spec.Content, req.Header.Get("Accept"))
+	if !ok || !isJSONMediaType(mediaType) {
+		return domain.MockResponse{}, false
+	}
+	value, ok := body(spec.Content[key].Schema)
+	if !ok {
+		return domain.MockResponse{}, false
+	}
+	resp.Header.Set("Content-Type", mediaType)
+	resp.Body = encodeExample(mediaType, value)
+	return resp, true
+}
+
+// newResource fills the properties body lacks from a value generated for
+// the response schema, so the stored resource has what responses need.
+// The generated ID gives way to an assigned one.
+func (s *MockService) newResource(schema *domain.Schema, body map[string]any, g *resourceGroup) map[string]any {
+	resource := map[string]any{}
+	if schema != nil {
+		if generated, ok := s.examples.Generate(schema).(map[string]any); ok {
+			resource = generated
+		}
+		delete(resource, g.idField)
+	}
+	for k, v := range body {
+		resource[k] = v
+	}
+	return resource
+}
+
+// listBody places items in the list response: the body itself for an
+// array schema, or the one array property of an envelope object.
+func (s *MockService) listBody(schema *domain.Schema, items []any) (any, bool) {
+	if schema == nil || schema.Type == domain.SchemaTypeArray {
+		return items, true
+	}
+	var field string
+	for name, p := range schema.Properties {
+		if p.Type == domain.SchemaTypeArray {
+			if field != "" {
+				return nil, false
+			}
+			field = name
+		}
+	}
+	envelope, ok := s.examples.Generate(schema).(map[string]any)
+	if field == "" || !ok {
+		return nil, false
+	}
+	envelope[field] = items
+	return envelope, true
+}
+
+// notFound answers with the operation's 404 response when it declares
+// one, otherwise the mock's own error.
+func (s *MockService) notFound(m routeMatch, req domain.MockRequest, message string) domain.MockResponse {
+	if _, ok := domain.MatchResponse(m.op.Responses, "404"); ok {
+		return s.static(m, req, preference{code: "404"})
+	}
+	return mockError(http.StatusNotFound, message, nil)
+}
+
+// jsonObject decodes a request body holding a JSON object.
+func jsonObject(body []byte) (map[string]any, bool) {
+	v, err := decodeJSON(body)
+	if err != nil {
+		return nil, false
+	}
+	obj, ok := v.(map[string]any)
+	return obj, ok
+}
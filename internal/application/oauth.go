@@ -0,0 +1,285 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// expirySkew refreshes tokens this long before they expire, so a token is
+// not sent just as it lapses.
+const expirySkew = 30 * time.Second
+
+func tokenValid(t *domain.Token, now time.Time) bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || now.Add(expirySkew).Before(t.Expiry))
+}
+
+// oauthEndpoints are the URLs used for one scheme.
+type oauthEndpoints struct {
+	authorization string
+	token         string
+	refresh       string
+}
+
+// endpoints returns the URLs for an OAuth2 or OpenID Connect scheme. The
+// credentials' TokenURL wins over the spec; the refresh endpoint defaults
+// to the token endpoint. OpenID Connect schemes are discovered.
+func (s *AuthService) endpoints(ctx context.Context, scheme domain.SecurityScheme, c domain.Credentials) (oauthEndpoints, error) {
+	var e oauthEndpoints
+	switch scheme.Type {
+	case domain.SecuritySchemeOpenIDConnect:
+		doc, err := s.discover(ctx, scheme.OpenIDConnectURL)
+		if err != nil {
+			return e, err
+		}
+		e.authorization, e.token = doc.AuthorizationEndpoint, doc.TokenEndpoint
+	case domain.SecuritySchemeOAuth2:
+		if f := scheme.Flows; f != nil {
+			if f.AuthorizationCode != nil {
+				e.authorization = f.AuthorizationCode.AuthorizationURL
+			}
+			for _, flow := range []*domain.OAuthFlow{f.ClientCredentials, f.AuthorizationCode, f.Password} {
+				if flow == nil {
+					continue
+				}
+				if e.token == "" {
+					e.token = flow.TokenURL
+				}
+				if e.refresh == "" {
+					e.refresh = flow.RefreshURL
+				}
+			}
+		}
+	}
+	if c.TokenURL != "" {
+		e.token, e.refresh = c.TokenURL, ""
+	}
+	if e.refresh == "" {
+		e.refresh = e.token
+	}
+	return e, nil
+}
+
+// oidcConfiguration is the part of an OpenID Provider's metadata dazzle
+// uses (OpenID Connect Discovery §3).
+type oidcConfiguration struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// discover fetches and caches the provider metadata at an
+// openIdConnectUrl.
+func (s *AuthService) discover(ctx context.Context, discoveryURL string) (*oidcConfiguration, error) {
+	s.discoveryMu.Lock()
+	defer s.discoveryMu.Unlock()
+	if doc, ok := s.discovery[discoveryURL]; ok {
+		return doc, nil
+	}
+	if s.client == nil {
+		return nil, errors.New("no HTTP client for discovery")
+	}
+
+	resp, err := s.client.Do(ctx, &domain.HTTPRequest{
+		Method: domain.GET,
+		URL:    discoveryURL,
+		Header: http.Header{"Accept": {"application/json"}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("OIDC discovery: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("OIDC discovery: %s returned %d", discoveryURL, resp.StatusCode)
+	}
+	var doc oidcConfiguration
+	if err := json.Unmarshal(resp.Body, &doc); err != nil {
+		return nil, fmt.Errorf("OIDC discovery: decoding %s: %w", discoveryURL, err)
+	}
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" {
+		return nil, fmt.Errorf("OIDC discovery: %s lacks authorization or token endpoint", discoveryURL)
+	}
+	s.discovery[discoveryURL] = &doc
+	return &doc, nil
+}
+
+// storedToken returns the token cached in memory for a scheme, falling back
+// to the token store. Callers hold s.mu.
+func (s *AuthService) storedToken(env domain.Environment, name string) *domain.Token {
+	key := tokenKey(env, name)
+	if t, ok := s.tokens[key]; ok {
+		return t
+	}
+	if s.store == nil {
+		return nil
+	}
+	t, err := s.store.LoadToken(env.Name, name)
+	if err != nil || t == nil {
+		return nil
+	}
+	s.tokens[key] = t
+	return t
+}
+
+// saveToken caches a token and persists it when a store is set. Callers
+// hold s.mu.
+func (s *AuthService) saveToken(env domain.Environment, name string, t *domain.Token) error {
+	s.tokens[tokenKey(env, name)] = t
+	if s.store == nil {
+		return nil
+	}
+	if err := s.store.SaveToken(env.Name, name, *t); err != nil {
+		return fmt.Errorf("saving token: %w", err)
+	}
+	return nil
+}
+
+func tokenKey(env domain.Environment, name string) string {
+	return env.Name + "\x00" + name
+}
+
+// accessToken returns a valid access token for an OAuth2 or OpenID Connect
+// scheme. A cached or stored token is reused until it nears expiry; it is
+// then refreshed with its refresh token, falling back to the configured
+// refresh token and then to the client-credentials grant.
+func (s *AuthService) accessToken(ctx context.Context, env domain.Environment, name string, scheme domain.SecurityScheme, c domain.Credentials, scopes []string) (string, error) {
+	if c.Token != "" {
+		return c.Token, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cached := s.storedToken(env, name)
+	if tokenValid(cached, s.now()) {
+		return cached.AccessToken, nil
+	}
+
+	var attempts []url.Values
+	if cached != nil && cached.RefreshToken != "" {
+		attempts = append(attempts, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {cached.RefreshToken}})
+	}
+	if c.RefreshToken != "" {
+		attempts = append(attempts, url.Values{"grant_type": {"refresh_token"}, "refresh_token": {c.RefreshToken}})
+	}
+	if c.ClientID != "" && hasClientCredentialsFlow(scheme, c) {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		if len(scopes) > 0 {
+			form.Set("scope", strings.Join(scopes, " "))
+		}
+		attempts = append(attempts, form)
+	}
+	if len(attempts) == 0 {
+		if cached != nil {
+			return "", errors.New("token expired; log in again")
+		}
+		return "", errors.New("no client credentials or refresh token to obtain a token with")
+	}
+
+	endpoints, err := s.endpoints(ctx, scheme, c)
+	if err != nil {
+		return "", err
+	}
+
+	var errs []error
+	for _, form := range attempts {
+		endpoint := endpoints.token
+		if form.Get("grant_type") == "refresh_token" {
+			endpoint = endpoints.refresh
+		}
+		token, err := s.requestToken(ctx, endpoint, c, form)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if token.RefreshToken == "" {
+			token.RefreshToken = form.Get("refresh_token")
+		}
+		if err := s.saveToken(env, name, token); err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	}
+	return "", errors.Join(errs...)
+}
+
+// hasClientCredentialsFlow reports whether the client-credentials grant can
+// be used: the spec documents the flow, or the user supplied a token URL
+// for a scheme without an interactive flow.
+func hasClientCredentialsFlow(scheme domain.SecurityScheme, c domain.Credentials) bool {
+	if scheme.Flows != nil && scheme.Flows.ClientCredentials != nil {
+		return true
+	}
+	return c.TokenURL != "" && scheme.Type == domain.SecuritySchemeOAuth2 && !scheme.SupportsLogin()
+}
+
+// tokenResponse is the token endpoint's JSON response (RFC 6749 §5).
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// requestToken posts a grant to the token endpoint. A confidential client
+// authenticates with HTTP Basic; a public one sends its client_id.
+func (s *AuthService) requestToken(ctx context.Context, endpoint string, c domain.Credentials, form url.Values) (*domain.Token, error) {
+	if endpoint == "" {
+		return nil, errors.New("no token URL")
+	}
+	if s.client == nil {
+		return nil, errors.New("no HTTP client for token requests")
+	}
+
+	header := http.Header{
+		"Content-Type": {"application/x-www-form-urlencoded"},
+		"Accept":       {"application/json"},
+	}
+	switch {
+	case c.ClientSecret != "":
+		header.Set("Authorization", "Basic "+basicCredentials(url.QueryEscape(c.ClientID), url.QueryEscape(c.ClientSecret)))
+	case c.ClientID != "":
+		form.Set("client_id", c.ClientID)
+	}
+
+	requested := s.now()
+	resp, err := s.client.Do(ctx, &domain.HTTPRequest{
+		Method: domain.POST,
+		URL:    endpoint,
+		Header: header,
+		Body:   []byte(form.Encode()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s grant: %w", form.Get("grant_type"), err)
+	}
+
+	var body tokenResponse
+	decodeErr := json.Unmarshal(resp.Body, &body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail := strings.TrimSpace(string(resp.Body))
+		if decodeErr == nil && body.Error != "" {
+			detail = strings.TrimSuffix(body.Error+": "+body.ErrorDescription, ": ")
+		}
+		return nil, fmt.Errorf("%s grant: token endpoint returned %d: %s", form.Get("grant_type"), resp.StatusCode, detail)
+	}
+	if decodeErr != nil {
+		return nil, fmt.Errorf("%s grant: decoding token response: %w", form.Get("grant_type"), decodeErr)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("%s grant: token response has no access_token", form.Get("grant_type"))
+	}
+
+	token := &domain.Token{AccessToken: body.AccessToken, RefreshToken: body.RefreshToken}
+	if body.ExpiresIn > 0 {
+		token.Expiry = requested.Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
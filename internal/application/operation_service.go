@@ -1,6 +1,7 @@
 package application
 
 import (
+	"slices"
 	"sort"
 	"strings"
 
@@ -65,11 +66,15 @@ func matchesFilter(op domain.Operation, f domain.OperationFilter) bool {
 		return false
 	}
 
+	if len(f.Methods) > 0 && !slices.Contains(f.Methods, op.Method) {
+		return false
+	}
+
 	if len(f.Tags) > 0 && !hasMatchingTag(op.Tags, f.Tags) {
 		return false
 	}
 
-	return true
+	return matchesStructured(op, f)
 }
 
 func hasMatchingTag(opTags, filterTags []string) bool {
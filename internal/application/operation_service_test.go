@@ -71,6 +71,17 @@ func TestOperationService_FilterByMethod(t *testing.T) {
 	}
 }
 
+func TestOperationService_FilterByMethods(t *testing.T) {
+	svc := application.NewOperationService()
+	result := svc.FilterOperations(newTestOperations(), domain.OperationFilter{
+		Methods: []domain.HTTPMethod{domain.POST, domain.DELETE},
+	})
+
+	if len(result) != 2 {
+		t.Errorf("expected 2 POST or DELETE operations, got %d", len(result))
+	}
+}
+
 func TestOperationService_FilterByTag(t *testing.T) {
 	svc := application.NewOperationService()
 	result := svc.FilterOperations(newTestOperations(), domain.OperationFilter{Tags: []string{"users"}})
@@ -0,0 +1,97 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// RequestService implements domain.RequestService.
+type RequestService struct {
+	client domain.HTTPClient
+}
+
+func NewRequestService(client domain.HTTPClient) *RequestService {
+	return &RequestService{client: client}
+}
+
+// BuildRequest resolves an operation and the user's values into a request:
+// path parameters are substituted into the template, query parameters are
+// encoded in name order, and cookies are folded into a Cookie header.
+func (s *RequestService) BuildRequest(op domain.Operation, values domain.RequestValues) (*domain.HTTPRequest, error) {
+	if values.Server == "" {
+		return nil, fmt.Errorf("no server URL for %s %s", op.Method, op.Path)
+	}
+
+	path := op.Path
+	for name, value := range values.Path {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+	if i := strings.Index(path, "{"); i >= 0 {
+		end := strings.Index(path[i:], "}")
+		if end > 0 {
+			return nil, fmt.Errorf("missing path parameter %s", path[i+1:i+end])
+		}
+	}
+
+	u, err := url.Parse(strings.TrimRight(values.Server, "/") + path)
+	if err != nil {
+		return nil, fmt.Errorf("parsing URL: %w", err)
+	}
+
+	query := u.Query()
+	for _, name := range sortedKeys(values.Query) {
+		if v := values.Query[name]; v != "" {
+			query.Set(name, v)
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	header := make(http.Header)
+	for name, v := range values.Header {
+		if v != "" {
+			header.Set(name, v)
+		}
+	}
+
+	var cookies []string
+	for _, name := range sortedKeys(values.Cookie) {
+		if v := values.Cookie[name]; v != "" {
+			cookies = append(cookies, (&http.Cookie{Name: name, Value: v}).String())
+		}
+	}
+	if len(cookies) > 0 {
+		header.Set("Cookie", strings.Join(cookies, "; "))
+	}
+
+	req := &domain.HTTPRequest{
+		Method: op.Method,
+		URL:    u.String(),
+		Header: header,
+	}
+	if values.Body != "" {
+		req.Body = []byte(values.Body)
+		if values.ContentType != "" && header.Get("Content-Type") == "" {
+			header.Set("Content-Type", values.ContentType)
+		}
+	}
+	return req, nil
+}
+
+func (s *RequestService) Send(ctx context.Context, req *domain.HTTPRequest) (*domain.HTTPResponse, error) {
+	return s.client.Do(ctx, req)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,112 @@
+package application_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/httpclient"
+)
+
+func getPetOperation() domain.Operation {
+	return domain.Operation{ID: "getPet", Path: "/pets/{petId}", Method: domain.GET}
+}
+
+func TestRequestService_BuildRequest(t *testing.T) {
+	svc := application.NewRequestService(nil)
+
+	req, err := svc.BuildRequest(getPetOperation(), domain.RequestValues{
+		Server: "https://api.example.com/v1/",
+		Path:   map[string]string{"petId": "a b"},
+		Query:  map[string]string{"limit": "10", "expand": "owner", "empty": ""},
+		Header: map[string]string{"X-Trace": "abc"},
+		Cookie: map[string]string{"session": "s1", "theme": "dark"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if req.URL != "https://api.example.com/v1/pets/a%20b?expand=owner&limit=10" {
+		t.Errorf("unexpected URL %q", req.URL)
+	}
+	if req.Header.Get("X-Trace") != "abc" {
+		t.Errorf("expected X-Trace header, got %v", req.Header)
+	}
+	if got := req.Header.Get("Cookie"); got != "session=s1; theme=dark" {
+		t.Errorf("unexpected Cookie header %q", got)
+	}
+	if req.Body != nil {
+		t.Errorf("expected no body, got %q", req.Body)
+	}
+}
+
+func TestRequestService_BuildRequestBody(t *testing.T) {
+	svc := application.NewRequestService(nil)
+	op := domain.Operation{ID: "createPet", Path: "/pets", Method: domain.POST}
+
+	req, err := svc.BuildRequest(op, domain.RequestValues{
+		Server:      "https://api.example.com",
+		ContentType: "application/json",
+		Body:        `{"name":"Rex"}`,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(req.Body) != `{"name":"Rex"}` {
+		t.Errorf("unexpected body %q", req.Body)
+	}
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected Content-Type from values, got %q", req.Header.Get("Content-Type"))
+	}
+}
+
+func TestRequestService_BuildRequestErrors(t *testing.T) {
+	svc := application.NewRequestService(nil)
+
+	if _, err := svc.BuildRequest(getPetOperation(), domain.RequestValues{}); err == nil {
+		t.Error("expected error without a server")
+	}
+
+	_, err := svc.BuildRequest(getPetOperation(), domain.RequestValues{Server: "https://api.example.com"})
+	if err == nil || !strings.Contains(err.Error(), "petId") {
+		t.Errorf("expected missing path parameter error, got %v", err)
+	}
+}
+
+func TestRequestService_Send(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"method":"`+r.Method+`","body":`+string(body)+`}`)
+	}))
+	defer srv.Close()
+
+	svc := application.NewRequestService(httpclient.NewClient(5 * time.Second))
+	op := domain.Operation{ID: "createPet", Path: "/pets", Method: domain.POST}
+
+	req, err := svc.BuildRequest(op, domain.RequestValues{Server: srv.URL, ContentType: "application/json", Body: `{"name":"Rex"}`})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp, err := svc.Send(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		t.Errorf("expected 201, got %d", resp.StatusCode)
+	}
+	if string(resp.Body) != `{"method":"POST","body":{"name":"Rex"}}` {
+		t.Errorf("unexpected body %q", resp.Body)
+	}
+	if resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected response headers, got %v", resp.Header)
+	}
+}
@@ -0,0 +1,175 @@
+package application
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// router matches request paths against operations' path templates. A
+// template segment may hold a whole parameter ("{id}") or mix literals and
+// parameters ("{name}.json").
+type router struct {
+	routes []route
+	bases  []string // server base paths, longest first, always ending with ""
+}
+
+type route struct {
+	op       domain.Operation
+	segments []segmentPattern
+	literals int // literal segments; more literal templates are preferred
+}
+
+// segmentPattern matches one path segment. A literal segment has no
+// pattern.
+type segmentPattern struct {
+	literal string
+	pattern *regexp.Regexp
+	names   []string
+}
+
+// routeMatch is an operation whose template matches a path, with the
+// decoded path parameters and the server base path that was stripped.
+type routeMatch struct {
+	op     domain.Operation
+	params map[string]string
+	base   string
+}
+
+var templateParam = regexp.MustCompile(`\{([^{}]+)\}`)
+
+func newRouter(ops []domain.Operation, servers []domain.Server) *router {
+	r := &router{bases: basePaths(servers)}
+	for _, op := range ops {
+		rt := route{op: op}
+		for _, seg := range splitPath(op.Path) {
+			if !strings.Contains(seg, "{") {
+				rt.segments = append(rt.segments, segmentPattern{literal: seg})
+				rt.literals++
+				continue
+			}
+			var expr strings.Builder
+			var names []string
+			last := 0
+			for _, loc := range templateParam.FindAllStringSubmatchIndex(seg, -1) {
+				expr.WriteString(regexp.QuoteMeta(seg[last:loc[0]]))
+				expr.WriteString("([^/]+?)")
+				names = append(names, seg[loc[2]:loc[3]])
+				last = loc[1]
+			}
+			expr.WriteString(regexp.QuoteMeta(seg[last:]))
+			rt.segments = append(rt.segments, segmentPattern{
+				pattern: regexp.MustCompile("^" + expr.String() + "$"),
+				names:   names,
+			})
+		}
+		r.routes = append(r.routes, rt)
+	}
+	return r
+}
+
+// basePaths returns the path part of each server URL, such as "/v1",
+// longest first so the most specific base is stripped.
+func basePaths(servers []domain.Server) []string {
+	seen := map[string]bool{"": true}
+	var bases []string
+	for _, s := range servers {
+		u, err := url.Parse(s.URL)
+		if err != nil {
+			continue
+		}
+		base := strings.TrimRight(u.EscapedPath(), "/")
+		if !seen[base] {
+			seen[base] = true
+			bases = append(bases, base)
+		}
+	}
+	sort.SliceStable(bases, func(i, j int) bool { return len(bases[i]) > len(bases[j]) })
+	return append(bases, "")
+}
+
+// find returns the operations whose templates match an escaped request
+// path, most specific first. Only the templates below the longest base
+// path that matches anything are considered.
+func (r *router) find(path string) []routeMatch {
+	for _, base := range r.bases {
+		rest, ok := strings.CutPrefix(path, base)
+		if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+			continue
+		}
+		if matches := r.findBelow(rest, base); len(matches) > 0 {
+			return matches
+		}
+	}
+	return nil
+}
+
+func (r *router) findBelow(path, base string) []routeMatch {
+	segments := splitPath(path)
+	type scored struct {
+		routeMatch
+		literals int
+	}
+	var found []scored
+	for _, rt := range r.routes {
+		if params, ok := rt.match(segments); ok {
+			found = append(found, scored{routeMatch{op: rt.op, params: params, base: base}, rt.literals})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].literals > found[j].literals })
+	matches := make([]routeMatch, len(found))
+	for i, f := range found {
+		matches[i] = f.routeMatch
+	}
+	return matches
+}
+
+// forMethod picks the match for method, reporting false when the path is
+// only defined for other methods.
+func forMethod(matches []routeMatch, method domain.HTTPMethod) (routeMatch, bool) {
+	for _, m := range matches {
+		if strings.EqualFold(string(m.op.Method), string(method)) {
+			return m, true
+		}
+	}
+	return routeMatch{}, false
+}
+
+func (rt route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, p := range rt.segments {
+		if p.pattern == nil {
+			if p.literal != segments[i] {
+				return nil, false
+			}
+			continue
+		}
+		sub := p.pattern.FindStringSubmatch(segments[i])
+		if sub == nil {
+			return nil, false
+		}
+		for j, name := range p.names {
+			value, err := url.PathUnescape(sub[j+1])
+			if err != nil {
+				value = sub[j+1]
+			}
+			params[name] = value
+		}
+	}
+	return params, true
+}
+
+// splitPath splits a path into segments, ignoring a trailing slash.
+func splitPath(path string) []string {
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, "/")
+}
@@ -0,0 +1,263 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+// validateValue checks a decoded JSON value against a schema and returns a
+// violation for every mismatch, located relative to loc. Values are
+// expected to come from a json.Decoder with UseNumber, though float64 is
+// accepted too. A type mismatch stops descent into that value.
+func validateValue(s *domain.Schema, v any, loc jsonpath.Location) []domain.Violation {
+	if s == nil {
+		return nil
+	}
+	at := func(format string, args ...any) []domain.Violation {
+		return []domain.Violation{{Path: loc.String(), Message: fmt.Sprintf(format, args...)}}
+	}
+
+	if v == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return at("expected %s, got null", s.Type)
+	}
+
+	if s.Type != "" && !typeMatches(s.Type, v) {
+		return at("expected %s, got %s", s.Type, jsonTypeName(v))
+	}
+
+	var violations []domain.Violation
+	if len(s.Enum) > 0 && !enumContains(s.Enum, v) {
+		violations = append(violations, at("%s is not one of %s", scalarString(v), formatEnum(s.Enum))...)
+	}
+
+	switch t := v.(type) {
+	case string:
+		violations = append(violations, validateString(s, t, loc)...)
+	case json.Number, float64:
+		n, _ := toFloat(t)
+		violations = append(violations, validateNumber(s, n, loc)...)
+	case []any:
+		if s.MinItems > 0 && len(t) < s.MinItems {
+			violations = append(violations, at("expected at least %s, got %d", domain.Plural(s.MinItems, "item"), len(t))...)
+		}
+		if s.MaxItems != nil && len(t) > *s.MaxItems {
+			violations = append(violations, at("expected at most %s, got %d", domain.Plural(*s.MaxItems, "item"), len(t))...)
+		}
+		for i, item := range t {
+			violations = append(violations, validateValue(s.Items, item, loc.Index(i))...)
+		}
+	case map[string]any:
+		for _, name := range s.Required {
+			if _, ok := t[name]; !ok {
+				violations = append(violations, domain.Violation{Path: loc.Key(name).String(), Message: "required property missing"})
+			}
+		}
+		for _, name := range sortedKeys(t) {
+			violations = append(violations, validateValue(s.Properties[name], t[name], loc.Key(name))...)
+		}
+	}
+	return violations
+}
+
+func validateString(s *domain.Schema, str string, loc jsonpath.Location) []domain.Violation {
+	var violations []domain.Violation
+	add := func(format string, args ...any) {
+		violations = append(violations, domain.Violation{Path: loc.String(), Message: fmt.Sprintf(format, args...)})
+	}
+
+	n := utf8.RuneCountInString(str)
+	if n < s.MinLength {
+		add("expected at least %s, got %d", domain.Plural(s.MinLength, "character"), n)
+	}
+	if s.MaxLength != nil && n > *s.MaxLength {
+		add("expected at most %s, got %d", domain.Plural(*s.MaxLength, "character"), n)
+	}
+	if s.Pattern != "" {
+		if re := compilePattern(s.Pattern); re != nil && !re.MatchString(str) {
+			add("does not match pattern %s", s.Pattern)
+		}
+	}
+	if s.Format != "" && !formatMatches(s.Format, str) {
+		add("expected %s format", s.Format)
+	}
+	return violations
+}
+
+func validateNumber(s *domain.Schema, n float64, loc jsonpath.Location) []domain.Violation {
+	var violations []domain.Violation
+	add := func(format string, args ...any) {
+		violations = append(violations, domain.Violation{Path: loc.String(), Message: fmt.Sprintf(format, args...)})
+	}
+
+	if m := s.Minimum; m != nil {
+		if s.ExclusiveMinimum && n <= *m {
+			add("expected greater than %s", formatFloat(*m))
+		} else if n < *m {
+			add("expected at least %s", formatFloat(*m))
+		}
+	}
+	if m := s.Maximum; m != nil {
+		if s.ExclusiveMaximum && n >= *m {
+			add("expected less than %s", formatFloat(*m))
+		} else if n > *m {
+			add("expected at most %s", formatFloat(*m))
+		}
+	}
+	return violations
+}
+
+// typeMatches reports whether v is of the schema type. Integers are numbers;
+// a number is an integer when it has no fractional part.
+func typeMatches(t domain.SchemaType, v any) bool {
+	actual := jsonTypeName(v)
+	switch t {
+	case domain.SchemaTypeNumber:
+		return actual == "number" || actual == "integer"
+	default:
+		return string(t) == actual
+	}
+}
+
+// jsonTypeName names the JSON type of a decoded value, distinguishing
+// integers from other numbers.
+func jsonTypeName(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number, float64, int, int64:
+		if n, ok := toFloat(t); ok && n == math.Trunc(n) && !math.IsInf(n, 0) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func toFloat(v any) (float64, bool) {
+	switch t := v.(type) {
+	case json.Number:
+		f, err := t.Float64()
+		return f, err == nil
+	case float64:
+		return t, true
+	case int:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	}
+	return 0, false
+}
+
+// enumContains compares numbers by value so that a json.Number from the
+// body matches the float64 an enum was decoded into.
+func enumContains(enum []any, v any) bool {
+	n, isNum := toFloat(v)
+	for _, e := range enum {
+		if isNum {
+			if en, ok := toFloat(e); ok && en == n {
+				return true
+			}
+			continue
+		}
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+func formatEnum(enum []any) string {
+	parts := make([]string, len(enum))
+	for i, e := range enum {
+		parts[i] = scalarString(e)
+	}
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+
+// scalarString formats a value for messages, quoting strings.
+func scalarString(v any) string {
+	switch t := v.(type) {
+	case string:
+		return strconv.Quote(t)
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprint(v)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// formatMatches checks the string formats dazzle knows about. Unknown
+// formats always match.
+func formatMatches(format, s string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, s)
+		return err == nil
+	case "date":
+		_, err := time.Parse(time.DateOnly, s)
+		return err == nil
+	case "email":
+		addr, err := mail.ParseAddress(s)
+		return err == nil && addr.Address == s
+	case "uuid":
+		return uuidPattern.MatchString(s)
+	case "uri", "url":
+		u, err := url.Parse(s)
+		return err == nil && u.Scheme != ""
+	case "ipv4":
+		ip := net.ParseIP(s)
+		return ip != nil && ip.To4() != nil && strings.Contains(s, ".")
+	case "ipv6":
+		ip := net.ParseIP(s)
+		return ip != nil && strings.Contains(s, ":")
+	}
+	return true
+}
+
+var patternCache sync.Map // pattern -> *regexp.Regexp (nil when invalid)
+
+// compilePattern compiles and caches a schema pattern. Patterns Go cannot
+// compile are skipped rather than reported against the value.
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patternCache.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		re = nil
+	}
+	patternCache.Store(pattern, re)
+	return re
+}
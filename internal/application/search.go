@@ -0,0 +1,344 @@
+package application
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+
+	"dazzle/internal/domain"
+)
+
+// ParseQuery parses the structured search syntax into a filter. Qualifiers
+// take the form key:value and may list several comma-separated values;
+// values and free-text terms may be double-quoted to include spaces:
+//
+//	method:post,put tag:billing param:customerId body:amount
+//	status:409 status:5xx ext:x-internal deprecated:false "refund"
+//
+// Tokens with an unknown qualifier are kept as free-text terms.
+func (s *OperationService) ParseQuery(query string) domain.OperationFilter {
+	var f domain.OperationFilter
+	for _, tok := range tokenizeQuery(query) {
+		key, value, ok := strings.Cut(tok, ":")
+		if !ok || tok[0] == '"' || value == "" {
+			f.Terms = append(f.Terms, unquote(tok))
+			continue
+		}
+
+		values := splitValues(unquote(value))
+		switch strings.ToLower(key) {
+		case "method":
+			for _, v := range values {
+				f.Methods = append(f.Methods, domain.HTTPMethod(strings.ToUpper(v)))
+			}
+		case "tag":
+			f.Tags = append(f.Tags, values...)
+		case "param":
+			f.Params = append(f.Params, values...)
+		case "body":
+			f.BodyFields = append(f.BodyFields, values...)
+		case "status":
+			f.Statuses = append(f.Statuses, values...)
+		case "ext":
+			f.Extensions = append(f.Extensions, values...)
+		case "deprecated":
+			if b, ok := parseBool(value); ok {
+				f.Deprecated = &b
+			} else {
+				f.Terms = append(f.Terms, unquote(tok))
+			}
+		default:
+			f.Terms = append(f.Terms, unquote(tok))
+		}
+	}
+	return f
+}
+
+// SearchOperations filters operations and orders them by how well they match
+// the filter's terms. Operations with equal scores keep their input order.
+func (s *OperationService) SearchOperations(operations []domain.Operation, filter domain.OperationFilter) []domain.Operation {
+	type scored struct {
+		op    domain.Operation
+		score int
+	}
+
+	var matches []scored
+	for _, op := range operations {
+		if matchesFilter(op, filter) {
+			matches = append(matches, scored{op: op, score: relevance(op, filter)})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	result := make([]domain.Operation, len(matches))
+	for i, m := range matches {
+		result[i] = m.op
+	}
+	return result
+}
+
+// Field weights used to rank free-text matches. A term found in the path
+// or summary is a stronger signal than one buried in a schema or extension.
+const (
+	weightPath        = 8
+	weightSummary     = 6
+	weightID          = 5
+	weightTag         = 4
+	weightParam       = 3
+	weightProperty    = 2
+	weightDescription = 1
+	weightResponse    = 1
+	weightExtension   = 1
+)
+
+// relevance scores an operation against the filter's free-text terms. Each
+// term contributes the weight of the best field it appears in, with a bonus
+// when it matches a whole path segment exactly.
+func relevance(op domain.Operation, f domain.OperationFilter) int {
+	if len(f.Terms) == 0 {
+		return 0
+	}
+	fields := searchFields(op)
+	segments := strings.Split(strings.ToLower(op.Path), "/")
+
+	score := 0
+	for _, term := range f.Terms {
+		t := strings.ToLower(term)
+		best := 0
+		for _, field := range fields {
+			if field.weight > best && strings.Contains(field.text, t) {
+				best = field.weight
+			}
+		}
+		if slices.Contains(segments, t) {
+			best += weightPath
+		}
+		score += best
+	}
+	return score
+}
+
+type searchField struct {
+	text   string
+	weight int
+}
+
+// searchFields flattens everything the free-text search looks at into
+// lower-cased strings tagged with their ranking weight.
+func searchFields(op domain.Operation) []searchField {
+	add := func(fields []searchField, weight int, texts ...string) []searchField {
+		for _, t := range texts {
+			if t != "" {
+				fields = append(fields, searchField{text: strings.ToLower(t), weight: weight})
+			}
+		}
+		return fields
+	}
+
+	var fields []searchField
+	fields = add(fields, weightPath, op.Path)
+	fields = add(fields, weightSummary, op.Summary)
+	fields = add(fields, weightID, op.ID)
+	fields = add(fields, weightTag, op.Tags...)
+	fields = add(fields, weightDescription, op.Description)
+
+	for _, p := range op.Parameters {
+		fields = add(fields, weightParam, p.Name)
+		fields = add(fields, weightDescription, p.Description)
+	}
+
+	if op.RequestBody != nil {
+		fields = add(fields, weightDescription, op.RequestBody.Description)
+		for _, mt := range op.RequestBody.Content {
+			fields = add(fields, weightProperty, schemaPropertyNames(mt.Schema)...)
+		}
+	}
+
+	for code, resp := range op.Responses {
+		fields = add(fields, weightResponse, code, resp.Description)
+		for _, mt := range resp.Content {
+			fields = add(fields, weightProperty, schemaPropertyNames(mt.Schema)...)
+		}
+		for name := range resp.Headers {
+			fields = add(fields, weightResponse, name)
+		}
+	}
+
+	for name, value := range op.Extensions {
+		fields = add(fields, weightExtension, name, fmt.Sprint(value))
+	}
+
+	return fields
+}
+
+// schemaPropertyNames collects property names from a schema, descending into
+// nested objects and array items.
+func schemaPropertyNames(s *domain.Schema) []string {
+	if s == nil {
+		return nil
+	}
+	var names []string
+	for name, prop := range s.Properties {
+		names = append(names, name)
+		names = append(names, schemaPropertyNames(prop)...)
+	}
+	return append(names, schemaPropertyNames(s.Items)...)
+}
+
+// matchesStructured applies the filter fields produced by ParseQuery.
+func matchesStructured(op domain.Operation, f domain.OperationFilter) bool {
+	if f.Deprecated != nil && op.Deprecated != *f.Deprecated {
+		return false
+	}
+
+	if len(f.Params) > 0 && !anyContains(parameterNames(op), f.Params) {
+		return false
+	}
+
+	if len(f.BodyFields) > 0 && !anyContains(requestBodyFields(op), f.BodyFields) {
+		return false
+	}
+
+	if len(f.Statuses) > 0 && !hasMatchingStatus(op, f.Statuses) {
+		return false
+	}
+
+	if len(f.Extensions) > 0 && !hasMatchingExtension(op, f.Extensions) {
+		return false
+	}
+
+	for _, term := range f.Terms {
+		if !matchesTerm(op, term) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func matchesTerm(op domain.Operation, term string) bool {
+	t := strings.ToLower(term)
+	for _, field := range searchFields(op) {
+		if strings.Contains(field.text, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func parameterNames(op domain.Operation) []string {
+	names := make([]string, len(op.Parameters))
+	for i, p := range op.Parameters {
+		names[i] = p.Name
+	}
+	return names
+}
+
+func requestBodyFields(op domain.Operation) []string {
+	if op.RequestBody == nil {
+		return nil
+	}
+	var names []string
+	for _, mt := range op.RequestBody.Content {
+		names = append(names, schemaPropertyNames(mt.Schema)...)
+	}
+	return names
+}
+
+// anyContains reports whether any candidate contains any of the wanted
+// values, case-insensitively.
+func anyContains(candidates, wanted []string) bool {
+	for _, w := range wanted {
+		w = strings.ToLower(w)
+		for _, c := range candidates {
+			if strings.Contains(strings.ToLower(c), w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasMatchingStatus matches exact codes ("409"), classes ("4xx") and
+// "default" against the operation's declared responses.
+func hasMatchingStatus(op domain.Operation, statuses []string) bool {
+	for _, want := range statuses {
+		want = strings.ToLower(want)
+		for code := range op.Responses {
+			code = strings.ToLower(code)
+			if code == want {
+				return true
+			}
+			if len(want) == 3 && strings.HasSuffix(want, "xx") && len(code) == 3 && code[0] == want[0] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasMatchingExtension(op domain.Operation, wanted []string) bool {
+	for _, w := range wanted {
+		for name := range op.Extensions {
+			if strings.EqualFold(name, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tokenizeQuery splits a query on whitespace, keeping double-quoted runs
+// (including a quoted qualifier value) together with their quotes.
+func tokenizeQuery(query string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuote := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			cur.WriteRune(r)
+		case unicode.IsSpace(r) && !inQuote:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
+
+func unquote(s string) string {
+	return strings.ReplaceAll(s, `"`, "")
+}
+
+func splitValues(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func parseBool(s string) (bool, bool) {
+	switch strings.ToLower(unquote(s)) {
+	case "true", "yes":
+		return true, true
+	case "false", "no":
+		return false, true
+	}
+	return false, false
+}
@@ -0,0 +1,136 @@
+package application_test
+
+import (
+	"slices"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func newSearchOperations() []domain.Operation {
+	return []domain.Operation{
+		{
+			ID: "listInvoices", Path: "/invoices", Method: domain.GET, Summary: "List invoices",
+			Tags: []string{"billing"},
+			Parameters: []domain.Parameter{
+				{Name: "customerId", In: domain.ParameterInQuery},
+			},
+			Responses: map[string]domain.Response{"200": {Description: "OK"}},
+		},
+		{
+			ID: "createRefund", Path: "/refunds", Method: domain.POST, Summary: "Create a refund",
+			Description: "Refunds a charge.",
+			Tags:        []string{"billing"},
+			RequestBody: &domain.RequestBody{Content: map[string]domain.MediaType{
+				"application/json": {Schema: &domain.Schema{
+					Type:       domain.SchemaTypeObject,
+					Properties: map[string]*domain.Schema{"amount": {Type: domain.SchemaTypeInteger}},
+				}},
+			}},
+			Responses: map[string]domain.Response{
+				"201": {Description: "Created"},
+				"409": {Description: "Already refunded"},
+			},
+		},
+		{
+			ID: "voidCharge", Path: "/charges/{id}/void", Method: domain.POST, Summary: "Void a charge",
+			Description: "Use refunds for settled charges.",
+			Tags:        []string{"billing"},
+			Deprecated:  true,
+			Extensions:  map[string]any{"x-internal": true},
+			Responses:   map[string]domain.Response{"503": {Description: "Unavailable"}},
+		},
+	}
+}
+
+func searchIDs(ops []domain.Operation) []string {
+	ids := make([]string, len(ops))
+	for i, op := range ops {
+		ids[i] = op.ID
+	}
+	return ids
+}
+
+func TestOperationService_ParseQuery(t *testing.T) {
+	svc := application.NewOperationService()
+	f := svc.ParseQuery(`method:post,put tag:billing param:customerId body:amount status:409 ext:x-internal deprecated:false "full refund" misc`)
+
+	if !slices.Equal(f.Methods, []domain.HTTPMethod{domain.POST, domain.PUT}) {
+		t.Errorf("unexpected methods: %v", f.Methods)
+	}
+	if !slices.Equal(f.Tags, []string{"billing"}) {
+		t.Errorf("unexpected tags: %v", f.Tags)
+	}
+	if !slices.Equal(f.Params, []string{"customerId"}) {
+		t.Errorf("unexpected params: %v", f.Params)
+	}
+	if !slices.Equal(f.BodyFields, []string{"amount"}) {
+		t.Errorf("unexpected body fields: %v", f.BodyFields)
+	}
+	if !slices.Equal(f.Statuses, []string{"409"}) {
+		t.Errorf("unexpected statuses: %v", f.Statuses)
+	}
+	if !slices.Equal(f.Extensions, []string{"x-internal"}) {
+		t.Errorf("unexpected extensions: %v", f.Extensions)
+	}
+	if f.Deprecated == nil || *f.Deprecated {
+		t.Errorf("expected deprecated:false, got %v", f.Deprecated)
+	}
+	if !slices.Equal(f.Terms, []string{"full refund", "misc"}) {
+		t.Errorf("unexpected terms: %v", f.Terms)
+	}
+}
+
+func TestOperationService_ParseQuery_UnknownQualifierIsTerm(t *testing.T) {
+	svc := application.NewOperationService()
+	f := svc.ParseQuery("color:red deprecated:maybe")
+
+	if !slices.Equal(f.Terms, []string{"color:red", "deprecated:maybe"}) {
+		t.Errorf("expected unknown qualifiers kept as terms, got %v", f.Terms)
+	}
+	if f.Deprecated != nil {
+		t.Error("expected invalid deprecated value to be ignored")
+	}
+}
+
+func TestOperationService_SearchQualifiers(t *testing.T) {
+	svc := application.NewOperationService()
+	ops := newSearchOperations()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"param:customerid", []string{"listInvoices"}},
+		{"body:amount", []string{"createRefund"}},
+		{"status:409", []string{"createRefund"}},
+		{"status:5xx", []string{"voidCharge"}},
+		{"ext:x-internal", []string{"voidCharge"}},
+		{"deprecated:true", []string{"voidCharge"}},
+		{"method:post deprecated:false", []string{"createRefund"}},
+		{"tag:billing method:get", []string{"listInvoices"}},
+		{`"already refunded"`, []string{"createRefund"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			got := searchIDs(svc.SearchOperations(ops, svc.ParseQuery(tt.query)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOperationService_SearchRanksByRelevance(t *testing.T) {
+	svc := application.NewOperationService()
+
+	// "refund" appears in createRefund's path and summary but only in
+	// voidCharge's description, so createRefund ranks first.
+	got := searchIDs(svc.SearchOperations(newSearchOperations(), svc.ParseQuery("refund")))
+
+	if !slices.Equal(got, []string{"createRefund", "voidCharge"}) {
+		t.Errorf("expected createRefund ranked above voidCharge, got %v", got)
+	}
+}
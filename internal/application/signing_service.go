@@ -0,0 +1,140 @@
+package application
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/signing"
+)
+
+// SigningService implements domain.SigningService. Signers are loaded once
+// from the repository; their settings may reference environment variables.
+type SigningService struct {
+	repo   domain.SignerRepository
+	aws    domain.AWSCredentialRepository
+	envSvc domain.EnvironmentService
+	now    func() time.Time
+
+	loadOnce sync.Once
+	signers  []domain.SignerConfig
+	loadErr  error
+}
+
+// NewSigningService creates a SigningService. aws supplies SigV4
+// credentials and envSvc resolves {{var}} references; either may be nil.
+func NewSigningService(repo domain.SignerRepository, aws domain.AWSCredentialRepository, envSvc domain.EnvironmentService) *SigningService {
+	return &SigningService{repo: repo, aws: aws, envSvc: envSvc, now: time.Now}
+}
+
+func (s *SigningService) Sign(req *domain.HTTPRequest, env domain.Environment) error {
+	s.loadOnce.Do(func() {
+		if s.repo != nil {
+			s.signers, s.loadErr = s.repo.LoadSigners()
+		}
+	})
+	if s.loadErr != nil {
+		return s.loadErr
+	}
+	if len(s.signers) == 0 {
+		return nil
+	}
+
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return fmt.Errorf("parsing URL: %w", err)
+	}
+	for _, cfg := range s.signers {
+		if !matchesHost(cfg.Hosts, u.Hostname()) {
+			continue
+		}
+		if err := s.sign(cfg, env, req); err != nil {
+			return fmt.Errorf("%s: %w", signerName(cfg), err)
+		}
+		return nil
+	}
+	return nil
+}
+
+func (s *SigningService) sign(cfg domain.SignerConfig, env domain.Environment, req *domain.HTTPRequest) error {
+	cfg, err := s.resolve(cfg, env)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.Type {
+	case domain.SignerSigV4:
+		var creds domain.AWSCredentials
+		if s.aws != nil {
+			if creds, err = s.aws.LoadAWSCredentials(cfg.Profile); err != nil {
+				return err
+			}
+		}
+		region := cfg.Region
+		if region == "" {
+			region = creds.Region
+		}
+		return signing.SigV4{
+			AccessKeyID:     creds.AccessKeyID,
+			SecretAccessKey: creds.SecretAccessKey,
+			SessionToken:    creds.SessionToken,
+			Region:          region,
+			Service:         cfg.Service,
+		}.Sign(req, s.now())
+	case domain.SignerHMAC:
+		return signing.HMAC{
+			KeyID:           cfg.KeyID,
+			Secret:          cfg.Secret,
+			Algorithm:       cfg.Algorithm,
+			Headers:         cfg.Headers,
+			Canonical:       cfg.Canonical,
+			Header:          cfg.Header,
+			Format:          cfg.Format,
+			Encoding:        cfg.Encoding,
+			TimestampHeader: cfg.TimestampHeader,
+		}.Sign(req, s.now())
+	}
+	return fmt.Errorf("unknown signer type %q", cfg.Type)
+}
+
+// resolve substitutes the environment's variables into the settings that
+// commonly hold secrets or per-environment values.
+func (s *SigningService) resolve(cfg domain.SignerConfig, env domain.Environment) (domain.SignerConfig, error) {
+	if s.envSvc == nil {
+		return cfg, nil
+	}
+	for _, field := range []*string{&cfg.Service, &cfg.Region, &cfg.Profile, &cfg.KeyID, &cfg.Secret} {
+		resolved, err := s.envSvc.Substitute(env, *field)
+		if err != nil {
+			return cfg, err
+		}
+		*field = resolved
+	}
+	return cfg, nil
+}
+
+// matchesHost reports whether host matches one of the patterns, ignoring
+// case. No patterns match every host.
+func matchesHost(patterns []string, host string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, p := range patterns {
+		if ok, _ := path.Match(strings.ToLower(p), host); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func signerName(cfg domain.SignerConfig) string {
+	if cfg.Name != "" {
+		return cfg.Name
+	}
+	return cfg.Type
+}
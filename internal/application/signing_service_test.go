@@ -0,0 +1,86 @@
+package application_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+// signerList is an in-memory SignerRepository.
+type signerList []domain.SignerConfig
+
+func (l signerList) LoadSigners() ([]domain.SignerConfig, error) { return l, nil }
+
+// awsProfiles is an in-memory AWSCredentialRepository keyed by profile.
+type awsProfiles map[string]domain.AWSCredentials
+
+func (p awsProfiles) LoadAWSCredentials(profile string) (domain.AWSCredentials, error) {
+	if profile == "" {
+		profile = "default"
+	}
+	c, ok := p[profile]
+	if !ok {
+		return c, errors.New("no AWS credentials for profile " + profile)
+	}
+	return c, nil
+}
+
+func TestSigningService_MatchesHosts(t *testing.T) {
+	signers := signerList{
+		{Name: "gateway", Type: domain.SignerSigV4, Hosts: []string{"*.execute-api.*.amazonaws.com"}, Service: "execute-api"},
+		{Name: "partner", Type: domain.SignerHMAC, Hosts: []string{"API.example.com"}, KeyID: "dazzle", Secret: "{{secret}}"},
+	}
+	aws := awsProfiles{"default": {AccessKeyID: "AKIDEXAMPLE", SecretAccessKey: "secret", Region: "eu-west-1"}}
+	svc := application.NewSigningService(signers, aws, application.NewEnvironmentService(nil))
+	env := domain.Environment{Name: "test", Variables: map[string]string{"secret": "s3cret"}}
+
+	req := newRequest()
+	req.URL = "https://abc123.execute-api.eu-west-1.amazonaws.com/prod/pets"
+	if err := svc.Sign(req, env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); !strings.HasPrefix(got, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") || !strings.Contains(got, "/eu-west-1/execute-api/aws4_request") {
+		t.Errorf("expected a SigV4 signature using the profile's region, got %q", got)
+	}
+
+	req = newRequest()
+	if err := svc.Sign(req, env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.Header.Get("Authorization"); !strings.HasPrefix(got, `Signature keyId="dazzle",algorithm="hmac-sha256"`) {
+		t.Errorf("expected an HMAC signature, got %q", got)
+	}
+	if req.Header.Get("Date") == "" {
+		t.Error("expected the signed Date header to be set")
+	}
+
+	req = newRequest()
+	req.URL = "https://other.example.com/pets"
+	if err := svc.Sign(req, env); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(req.Header) != 0 {
+		t.Errorf("expected an unmatched request to be left alone, got %v", req.Header)
+	}
+}
+
+func TestSigningService_Errors(t *testing.T) {
+	svc := application.NewSigningService(signerList{
+		{Name: "gateway", Type: domain.SignerSigV4, Service: "execute-api", Profile: "ci"},
+	}, awsProfiles{}, nil)
+	err := svc.Sign(newRequest(), domain.Environment{})
+	if err == nil || err.Error() != "gateway: no AWS credentials for profile ci" {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	svc = application.NewSigningService(signerList{
+		{Type: domain.SignerHMAC, Secret: "{{secret}}"},
+	}, nil, application.NewEnvironmentService(nil))
+	err = svc.Sign(newRequest(), domain.Environment{Name: "prod"})
+	if err == nil || !strings.HasPrefix(err.Error(), "hmac: undefined variable secret") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
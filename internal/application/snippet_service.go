@@ -0,0 +1,186 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"dazzle/internal/domain"
+)
+
+// SnippetService implements domain.SnippetService. Snippets reproduce the
+// request exactly: method, URL, every header and the body.
+type SnippetService struct{}
+
+func NewSnippetService() *SnippetService {
+	return &SnippetService{}
+}
+
+func (s *SnippetService) Snippets(req *domain.HTTPRequest) []domain.Snippet {
+	return []domain.Snippet{
+		{Language: domain.SnippetCurl, Label: "cURL", Syntax: "bash", Code: curlSnippet(req)},
+		{Language: domain.SnippetHTTPie, Label: "HTTPie", Syntax: "bash", Code: httpieSnippet(req)},
+		{Language: domain.SnippetGo, Label: "Go", Syntax: "go", Code: goSnippet(req)},
+		{Language: domain.SnippetPython, Label: "Python", Syntax: "python", Code: pythonSnippet(req)},
+		{Language: domain.SnippetJavaScript, Label: "JavaScript", Syntax: "javascript", Code: fetchSnippet(req)},
+	}
+}
+
+// headerPair is one header line; repeated headers give several pairs.
+type headerPair struct{ name, value string }
+
+// headerPairs lists the request's headers sorted by name, keeping the
+// order of repeated values.
+func headerPairs(h http.Header) []headerPair {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var pairs []headerPair
+	for _, name := range names {
+		for _, v := range h[name] {
+			pairs = append(pairs, headerPair{name, v})
+		}
+	}
+	return pairs
+}
+
+// joinedHeaders lists each header once, repeated values joined by ", ",
+// for languages whose header objects hold one value per name.
+func joinedHeaders(h http.Header) []headerPair {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := make([]headerPair, len(names))
+	for i, name := range names {
+		pairs[i] = headerPair{name, strings.Join(h[name], ", ")}
+	}
+	return pairs
+}
+
+// shellQuote single-quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// shellCommand joins arguments, one per continuation line after the first
+// line, which holds the command and URL.
+func shellCommand(first string, args []string) string {
+	if len(args) == 0 {
+		return first
+	}
+	return first + " \\\n  " + strings.Join(args, " \\\n  ")
+}
+
+func curlSnippet(req *domain.HTTPRequest) string {
+	first := "curl " + shellQuote(req.URL)
+	if req.Method != domain.GET || len(req.Body) > 0 {
+		first = "curl -X " + string(req.Method) + " " + shellQuote(req.URL)
+	}
+	var args []string
+	for _, h := range headerPairs(req.Header) {
+		args = append(args, "-H "+shellQuote(h.name+": "+h.value))
+	}
+	if len(req.Body) > 0 {
+		args = append(args, "--data-raw "+shellQuote(string(req.Body)))
+	}
+	return shellCommand(first, args)
+}
+
+func httpieSnippet(req *domain.HTTPRequest) string {
+	first := "http " + string(req.Method) + " " + shellQuote(req.URL)
+	var args []string
+	for _, h := range headerPairs(req.Header) {
+		args = append(args, shellQuote(h.name+":"+h.value))
+	}
+	if len(req.Body) > 0 {
+		args = append(args, "--raw "+shellQuote(string(req.Body)))
+	}
+	return shellCommand(first, args)
+}
+
+func goSnippet(req *domain.HTTPRequest) string {
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if len(req.Body) > 0 {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+	body := "nil"
+	if len(req.Body) > 0 {
+		fmt.Fprintf(&b, "\tbody := strings.NewReader(%s)\n", goString(string(req.Body)))
+		body = "body"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(string(req.Method)), strconv.Quote(req.URL), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, h := range headerPairs(req.Header) {
+		fmt.Fprintf(&b, "\treq.Header.Add(%s, %s)\n", strconv.Quote(h.name), strconv.Quote(h.value))
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tdata, err := io.ReadAll(resp.Body)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	b.WriteString("\tfmt.Println(resp.Status)\n\tfmt.Println(string(data))\n}")
+	return b.String()
+}
+
+// goString prefers a raw string literal, which keeps JSON readable.
+func goString(s string) string {
+	if utf8.ValidString(s) && !strings.ContainsAny(s, "`\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+func pythonSnippet(req *domain.HTTPRequest) string {
+	var b strings.Builder
+	b.WriteString("import requests\n\nresponse = requests.request(\n")
+	fmt.Fprintf(&b, "    %s,\n    %s,\n", jsString(string(req.Method)), jsString(req.URL))
+	if headers := joinedHeaders(req.Header); len(headers) > 0 {
+		b.WriteString("    headers={\n")
+		for _, h := range headers {
+			fmt.Fprintf(&b, "        %s: %s,\n", jsString(h.name), jsString(h.value))
+		}
+		b.WriteString("    },\n")
+	}
+	if len(req.Body) > 0 {
+		fmt.Fprintf(&b, "    data=%s,\n", jsString(string(req.Body)))
+	}
+	b.WriteString(")\nprint(response.status_code)\nprint(response.text)")
+	return b.String()
+}
+
+func fetchSnippet(req *domain.HTTPRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "const response = await fetch(%s, {\n", jsString(req.URL))
+	fmt.Fprintf(&b, "  method: %s,\n", jsString(string(req.Method)))
+	if headers := joinedHeaders(req.Header); len(headers) > 0 {
+		b.WriteString("  headers: {\n")
+		for _, h := range headers {
+			fmt.Fprintf(&b, "    %s: %s,\n", jsString(h.name), jsString(h.value))
+		}
+		b.WriteString("  },\n")
+	}
+	if len(req.Body) > 0 {
+		fmt.Fprintf(&b, "  body: %s,\n", jsString(string(req.Body)))
+	}
+	b.WriteString("});\nconsole.log(response.status);\nconsole.log(await response.text());")
+	return b.String()
+}
+
+// jsString quotes s as a JSON string, which is also a valid JavaScript and
+// Python string literal.
+func jsString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,110 @@
+package application_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func postRequest() *domain.HTTPRequest {
+	return &domain.HTTPRequest{
+		Method: domain.POST,
+		URL:    "https://api.example.com/pets?tag=dog's",
+		Header: http.Header{
+			"Content-Type":  {"application/json"},
+			"Authorization": {"Bearer abc"},
+		},
+		Body: []byte(`{"name":"Rex's <ball>"}`),
+	}
+}
+
+func snippetCode(t *testing.T, snippets []domain.Snippet, lang domain.SnippetLanguage) string {
+	t.Helper()
+	for _, s := range snippets {
+		if s.Language == lang {
+			return s.Code
+		}
+	}
+	t.Fatalf("no %s snippet", lang)
+	return ""
+}
+
+func TestSnippetService_Languages(t *testing.T) {
+	snippets := application.NewSnippetService().Snippets(postRequest())
+	var labels []string
+	for _, s := range snippets {
+		labels = append(labels, s.Label)
+	}
+	if got := strings.Join(labels, ","); got != "cURL,HTTPie,Go,Python,JavaScript" {
+		t.Errorf("unexpected languages %s", got)
+	}
+}
+
+func TestSnippetService_Curl(t *testing.T) {
+	snippets := application.NewSnippetService().Snippets(postRequest())
+	want := `curl -X POST 'https://api.example.com/pets?tag=dog'\''s' \
+  -H 'Authorization: Bearer abc' \
+  -H 'Content-Type: application/json' \
+  --data-raw '{"name":"Rex'\''s <ball>"}'`
+	if got := snippetCode(t, snippets, domain.SnippetCurl); got != want {
+		t.Errorf("curl snippet:\n%s\nwant:\n%s", got, want)
+	}
+
+	get := application.NewSnippetService().Snippets(newRequest())
+	if got := snippetCode(t, get, domain.SnippetCurl); got != "curl 'https://api.example.com/pets?limit=1'" {
+		t.Errorf("expected a bare GET, got %s", got)
+	}
+}
+
+func TestSnippetService_HTTPie(t *testing.T) {
+	got := snippetCode(t, application.NewSnippetService().Snippets(postRequest()), domain.SnippetHTTPie)
+	for _, want := range []string{
+		`http POST 'https://api.example.com/pets?tag=dog'\''s'`,
+		`'Content-Type:application/json'`,
+		`--raw '{"name":"Rex'\''s <ball>"}'`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %s in\n%s", want, got)
+		}
+	}
+}
+
+func TestSnippetService_Code(t *testing.T) {
+	snippets := application.NewSnippetService().Snippets(postRequest())
+	tests := []struct {
+		lang domain.SnippetLanguage
+		want []string
+	}{
+		{domain.SnippetGo, []string{
+			"body := strings.NewReader(`{\"name\":\"Rex's <ball>\"}`)",
+			`http.NewRequest("POST", "https://api.example.com/pets?tag=dog's", body)`,
+			`req.Header.Add("Authorization", "Bearer abc")`,
+		}},
+		{domain.SnippetPython, []string{
+			`"POST",`,
+			`"Content-Type": "application/json",`,
+			`data="{\"name\":\"Rex's <ball>\"}",`,
+		}},
+		{domain.SnippetJavaScript, []string{
+			`await fetch("https://api.example.com/pets?tag=dog's", {`,
+			`method: "POST",`,
+			`body: "{\"name\":\"Rex's <ball>\"}",`,
+		}},
+	}
+	for _, tt := range tests {
+		got := snippetCode(t, snippets, tt.lang)
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: expected %s in\n%s", tt.lang, want, got)
+			}
+		}
+	}
+
+	goCode := snippetCode(t, application.NewSnippetService().Snippets(newRequest()), domain.SnippetGo)
+	if strings.Contains(goCode, `"strings"`) || !strings.Contains(goCode, `"https://api.example.com/pets?limit=1", nil)`) {
+		t.Errorf("expected a bodiless Go request without the strings import:\n%s", goCode)
+	}
+}
@@ -0,0 +1,350 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+// TestService implements domain.TestService, building and sending requests
+// the way the request builder does.
+type TestService struct {
+	requests  domain.RequestService
+	envs      domain.EnvironmentService
+	validator domain.ValidationService
+	authz     domain.Authorizer
+	now       func() time.Time
+}
+
+func NewTestService(requests domain.RequestService, envs domain.EnvironmentService, validator domain.ValidationService, authz domain.Authorizer) *TestService {
+	return &TestService{requests: requests, envs: envs, validator: validator, authz: authz, now: time.Now}
+}
+
+// Run starts the requests in order, so with parallel at 1 each one runs
+// after the one before it.
+func (s *TestService) Run(ctx context.Context, spec *domain.Spec, requests []domain.SavedRequest, env domain.Environment, parallel int) domain.TestRun {
+	run := domain.TestRun{Environment: env.Name, Started: s.now(), Results: make([]domain.TestResult, len(requests))}
+	ops := make(map[string]domain.Operation, len(spec.Operations))
+	for _, op := range spec.Operations {
+		ops[op.ID] = op
+	}
+
+	needs := capturedVariables(requests)
+	done := make([]chan struct{}, len(requests))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+	slots := make(chan struct{}, max(1, parallel))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[i])
+			defer func() { <-slots }()
+
+			// The results of the requests waited for are written before
+			// their done channels close.
+			vars := make(map[string]string, len(env.Variables)+len(needs[i]))
+			for name, value := range env.Variables {
+				vars[name] = value
+			}
+			for _, name := range sortedKeys(needs[i]) {
+				from := needs[i][name]
+				<-done[from]
+				value, ok := run.Results[from].Captured[name]
+				if !ok {
+					run.Results[i] = skipped(req, fmt.Sprintf("%s was not captured by %q", name, requests[from].Name))
+					return
+				}
+				// A captured value is sent as it is, not expanded.
+				vars[name] = domain.EscapeVariables(value)
+			}
+			run.Results[i] = s.runOne(ctx, spec, ops, req, domain.Environment{Name: env.Name, Variables: vars})
+		}()
+	}
+	wg.Wait()
+	run.Duration = s.now().Sub(run.Started)
+	return run
+}
+
+// capturedVariables finds, for each request, the variables it uses that an
+// earlier request captures, and the last request before it to capture each.
+func capturedVariables(requests []domain.SavedRequest) []map[string]int {
+	capturedBy := map[string]int{}
+	needs := make([]map[string]int, len(requests))
+	for i, req := range requests {
+		needs[i] = map[string]int{}
+		for _, name := range referencedVariables(req) {
+			if from, ok := capturedBy[name]; ok {
+				needs[i][name] = from
+			}
+		}
+		for _, c := range req.Captures {
+			capturedBy[c.Variable] = i
+		}
+	}
+	return needs
+}
+
+// referencedVariables lists the {{name}} references in a request's values
+// and expectations.
+func referencedVariables(req domain.SavedRequest) []string {
+	v := req.Values
+	texts := []string{v.Server, v.Body}
+	for _, m := range []map[string]string{v.Path, v.Query, v.Header, v.Cookie} {
+		for _, value := range m {
+			texts = append(texts, value)
+		}
+	}
+	if e := req.Expect; e != nil {
+		for _, value := range e.Headers {
+			texts = append(texts, value)
+		}
+		for _, b := range e.Body {
+			if s, ok := b.Value.(string); ok {
+				texts = append(texts, s)
+			}
+		}
+	}
+	var names []string
+	for _, text := range texts {
+		for _, m := range variablePattern.FindAllStringSubmatch(text, -1) {
+			if m[1] != "" {
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names
+}
+
+func skipped(req domain.SavedRequest, reason string) domain.TestResult {
+	return domain.TestResult{Name: req.Name, Collection: req.Collection, OperationID: req.OperationID, Skipped: reason}
+}
+
+func (s *TestService) runOne(ctx context.Context, spec *domain.Spec, ops map[string]domain.Operation, req domain.SavedRequest, env domain.Environment) domain.TestResult {
+	res := domain.TestResult{Name: req.Name, Collection: req.Collection, OperationID: req.OperationID}
+	op, ok := ops[req.OperationID]
+	if !ok {
+		res.Error = fmt.Sprintf("operation %q is not in the spec", req.OperationID)
+		return res
+	}
+	res.Method = op.Method
+
+	values, err := s.envs.SubstituteValues(env, req.Values)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	httpReq, err := s.requests.BuildRequest(op, values)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.URL = httpReq.URL
+	if err := s.authz.Authorize(ctx, spec.SecuritySchemes, op, env, httpReq); err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	resp, err := s.requests.Send(ctx, httpReq)
+	if err != nil {
+		res.Error = err.Error()
+		return res
+	}
+	res.Status, res.Duration = resp.StatusCode, resp.Duration
+
+	check := responseCheck{resp: resp, env: env, envs: s.envs}
+	if e := req.Expect; e != nil {
+		check.expect(e)
+		if e.Schema {
+			for _, v := range s.validator.ValidateResponse(op, resp) {
+				check.fail("schema: %s", v)
+			}
+		}
+	}
+	res.Captured = check.capture(req.Captures)
+	res.Failures = check.failures
+	return res
+}
+
+// responseCheck collects the failures of one response's checks, decoding
+// its body once.
+type responseCheck struct {
+	resp     *domain.HTTPResponse
+	env      domain.Environment
+	envs     domain.EnvironmentService
+	failures []string
+
+	decoded bool
+	body    any
+	bodyErr error
+}
+
+func (c *responseCheck) fail(format string, args ...any) {
+	c.failures = append(c.failures, fmt.Sprintf(format, args...))
+}
+
+func (c *responseCheck) json() (any, error) {
+	if !c.decoded {
+		c.decoded = true
+		c.body, c.bodyErr = decodeJSON(c.resp.Body)
+		if c.bodyErr != nil {
+			c.bodyErr = fmt.Errorf("body is not JSON: %w", c.bodyErr)
+		}
+	}
+	return c.body, c.bodyErr
+}
+
+func (c *responseCheck) expect(e *domain.Expectations) {
+	if e.Status != "" && !statusMatches(e.Status, c.resp.StatusCode) {
+		c.fail("status %d, expected %s", c.resp.StatusCode, e.Status)
+	}
+	for _, name := range sortedKeys(e.Headers) {
+		want, err := c.envs.Substitute(c.env, e.Headers[name])
+		if err != nil {
+			c.fail("header %s: %v", name, err)
+			continue
+		}
+		if len(c.resp.Header.Values(name)) == 0 {
+			c.fail("header %s is missing", name)
+			continue
+		}
+		if got := c.resp.Header.Get(name); !c.matches(want, got) {
+			c.fail("header %s is %q, expected %s", name, got, want)
+		}
+	}
+	for _, b := range e.Body {
+		c.expectBody(b)
+	}
+	if e.MaxLatency > 0 && c.resp.Duration > e.MaxLatency {
+		c.fail("took %s, over %s", c.resp.Duration.Round(time.Millisecond), e.MaxLatency)
+	}
+}
+
+func (c *responseCheck) expectBody(b domain.BodyExpectation) {
+	doc, err := c.json()
+	if err != nil {
+		c.fail("%s: %v", b.Path, err)
+		return
+	}
+	found, err := jsonpath.Eval(b.Path, doc)
+	if err != nil {
+		c.fail("%v", err)
+		return
+	}
+	if len(found) == 0 {
+		c.fail("%s matched nothing", b.Path)
+		return
+	}
+	var got any = found
+	if len(found) == 1 {
+		got = found[0]
+	}
+	want := b.Value
+	if s, ok := want.(string); ok {
+		if want, err = c.envs.Substitute(c.env, s); err != nil {
+			c.fail("%s: %v", b.Path, err)
+			return
+		}
+		// Variables hold text, so a value built from them matches the
+		// number or boolean it spells.
+		if _, isString := got.(string); want != s && !isString && displayValue(got) == want {
+			return
+		}
+	}
+	if w, ok := want.(string); ok && isPattern(w) {
+		text, ok := got.(string)
+		if !ok {
+			text = displayValue(got)
+		}
+		if !c.matches(w, text) {
+			c.fail("%s is %s, expected %s", b.Path, displayValue(got), w)
+		}
+		return
+	}
+	if !sameJSON(got, want) {
+		c.fail("%s is %s, expected %s", b.Path, displayValue(got), displayValue(want))
+	}
+}
+
+// matches compares an actual value with an expected one, or with the
+// expected pattern.
+func (c *responseCheck) matches(want, got string) bool {
+	if !isPattern(want) {
+		return want == got
+	}
+	re, err := regexp.Compile(want[1 : len(want)-1])
+	if err != nil {
+		c.fail("invalid pattern %s: %v", want, err)
+		return true
+	}
+	return re.MatchString(got)
+}
+
+// capture extracts the captured variables, reporting those it cannot find.
+func (c *responseCheck) capture(captures []domain.Capture) map[string]string {
+	var out map[string]string
+	for _, cp := range captures {
+		value, err := extractCapture(cp, c.resp, c.json)
+		if err != nil {
+			c.fail("capturing %s: %v", cp.Variable, err)
+			continue
+		}
+		if out == nil {
+			out = map[string]string{}
+		}
+		out[cp.Variable] = value
+	}
+	return out
+}
+
+// statusMatches checks a status code against "201" or a class such as
+// "2XX".
+func statusMatches(want string, code int) bool {
+	got := strconv.Itoa(code)
+	if len(want) == 3 && strings.EqualFold(want[1:], "XX") {
+		return want[0] == got[0]
+	}
+	return want == got
+}
+
+func isPattern(s string) bool {
+	return len(s) >= 2 && strings.HasPrefix(s, "/") && strings.HasSuffix(s, "/")
+}
+
+// sameJSON compares two values as JSON, so 1 and 1.0 are equal whether
+// they came from a response or a YAML file.
+func sameJSON(a, b any) bool {
+	na, errA := normalizeJSON(a)
+	nb, errB := normalizeJSON(b)
+	return errA == nil && errB == nil && reflect.DeepEqual(na, nb)
+}
+
+func normalizeJSON(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out any
+	err = json.Unmarshal(data, &out)
+	return out, err
+}
+
+// displayValue writes a value as compact JSON.
+func displayValue(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
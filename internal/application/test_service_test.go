@@ -0,0 +1,196 @@
+package application_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/httpclient"
+)
+
+// petServer serves the mock spec's pets: POST /v1/pets creates pet 7,
+// GET /v1/pets/7 returns it, pet 0 breaks the schema and any other pet is
+// not found. It also reports the most requests it served at once.
+func petServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var inFlight, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/pets":
+			w.Header().Set("Location", "/v1/pets/7")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 7, "name": "Rex"}`))
+		case r.URL.Path == "/v1/pets/7":
+			_, _ = w.Write([]byte(`{"id": 7, "name": "Rex", "tags": ["good", "boy"]}`))
+		case r.URL.Path == "/v1/pets/0":
+			_, _ = w.Write([]byte(`{"id": 0}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message": "not found"}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &peak
+}
+
+func runTests(t *testing.T, parallel int, requests ...domain.SavedRequest) domain.TestRun {
+	t.Helper()
+	srv, _ := petServer(t)
+	return runTestsAgainst(srv, parallel, requests...)
+}
+
+func runTestsAgainst(srv *httptest.Server, parallel int, requests ...domain.SavedRequest) domain.TestRun {
+	svc := application.NewTestService(application.NewRequestService(httpclient.NewClient(5*time.Second)),
+		application.NewEnvironmentService(nil), application.NewValidationService(), domain.Authorizer{})
+	env := domain.Environment{Name: "test", Variables: map[string]string{"baseUrl": srv.URL + "/v1"}}
+	for i := range requests {
+		requests[i].Collection = "pets"
+		requests[i].Values.Server = "{{baseUrl}}"
+	}
+	return svc.Run(context.Background(), mockSpec(), requests, env, parallel)
+}
+
+func TestTestService_Expectations(t *testing.T) {
+	run := runTests(t, 1,
+		domain.SavedRequest{Name: "passes", OperationID: "getPet",
+			Values: domain.RequestValues{Path: map[string]string{"petId": "7"}},
+			Expect: &domain.Expectations{
+				Status:  "2XX",
+				Headers: map[string]string{"Content-Type": "/json/"},
+				Body: []domain.BodyExpectation{
+					{Path: "$.name", Value: "Rex"},
+					{Path: "$.id", Value: 7},
+					{Path: "$.tags[*]", Value: []any{"good", "boy"}},
+				},
+				Schema:     true,
+				MaxLatency: time.Minute,
+			}},
+		domain.SavedRequest{Name: "fails", OperationID: "getPet",
+			Values: domain.RequestValues{Path: map[string]string{"petId": "0"}},
+			Expect: &domain.Expectations{
+				Status:     "201",
+				Headers:    map[string]string{"Location": "x", "Content-Type": "text/plain"},
+				Body:       []domain.BodyExpectation{{Path: "$.name", Value: "Rex"}, {Path: "$.id", Value: "/^[1-9]/"}},
+				Schema:     true,
+				MaxLatency: time.Nanosecond,
+			}},
+		domain.SavedRequest{Name: "unknown", OperationID: "feedPet"},
+	)
+
+	if r := run.Results[0]; !r.Passed() || r.Status != 200 || r.Method != domain.GET {
+		t.Errorf("expected the first test to pass, got %+v", r)
+	}
+	got := strings.Join(run.Results[1].Failures, "\n")
+	for _, want := range []string{
+		"status 200, expected 201",
+		`header Content-Type is "application/json", expected text/plain`,
+		"header Location is missing",
+		"$.name matched nothing",
+		"$.id is 0, expected /^[1-9]/",
+		"took ",
+		"schema: ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected a failure containing %q, got:\n%s", want, got)
+		}
+	}
+	if r := run.Results[2]; r.Error != `operation "feedPet" is not in the spec` {
+		t.Errorf("expected an unknown operation error, got %+v", r)
+	}
+	if passed, failed, skipped := run.Counts(); passed != 1 || failed != 2 || skipped != 0 {
+		t.Errorf("unexpected counts %d/%d/%d", passed, failed, skipped)
+	}
+}
+
+func TestTestService_Captures(t *testing.T) {
+	run := runTests(t, 4,
+		domain.SavedRequest{Name: "create", OperationID: "createPet",
+			Values: domain.RequestValues{ContentType: "application/json", Body: `{"name": "Rex"}`},
+			Captures: []domain.Capture{
+				{Variable: "petId", Path: "$.id"},
+				{Variable: "location", Header: "Location"},
+			}},
+		domain.SavedRequest{Name: "fetch", OperationID: "getPet",
+			Values: domain.RequestValues{Path: map[string]string{"petId": "{{petId}}"}},
+			Expect: &domain.Expectations{
+				Status: "200",
+				Body:   []domain.BodyExpectation{{Path: "$.id", Value: "{{petId}}"}},
+			},
+			Captures: []domain.Capture{{Variable: "owner", Path: "$.owner"}}},
+		domain.SavedRequest{Name: "fetch owner", OperationID: "getPet",
+			Values: domain.RequestValues{Path: map[string]string{"petId": "{{owner}}"}}},
+	)
+
+	create := run.Results[0]
+	if !create.Passed() || create.Captured["petId"] != "7" || create.Captured["location"] != "/v1/pets/7" {
+		t.Errorf("expected petId and location to be captured, got %+v", create)
+	}
+	fetch := run.Results[1]
+	if !strings.HasSuffix(fetch.URL, "/v1/pets/7") {
+		t.Errorf("expected the captured id in the URL, got %q", fetch.URL)
+	}
+	if len(fetch.Failures) != 1 || fetch.Failures[0] != "capturing owner: $.owner matched nothing" {
+		t.Errorf("unexpected failures %q", fetch.Failures)
+	}
+	if r := run.Results[2]; r.Skipped != `owner was not captured by "fetch"` || r.URL != "" {
+		t.Errorf("expected the last test to be skipped, got %+v", r)
+	}
+}
+
+func TestTestService_CapturedValuesStayLiteral(t *testing.T) {
+	t.Setenv("DAZZLE_TEST_TOKEN", "s3cret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id": "{{env:DAZZLE_TEST_TOKEN}}"}`))
+	}))
+	defer srv.Close()
+
+	run := runTestsAgainst(srv, 1,
+		domain.SavedRequest{Name: "create", OperationID: "createPet",
+			Values:   domain.RequestValues{ContentType: "application/json", Body: `{"name": "Rex"}`},
+			Captures: []domain.Capture{{Variable: "petId", Path: "$.id"}}},
+		domain.SavedRequest{Name: "fetch", OperationID: "getPet",
+			Values: domain.RequestValues{Path: map[string]string{"petId": "{{petId}}"}}},
+	)
+	if url := run.Results[1].URL; strings.Contains(url, "s3cret") || !strings.Contains(url, "DAZZLE_TEST_TOKEN") {
+		t.Errorf("expected the captured value sent as it is, got %q", url)
+	}
+}
+
+func TestTestService_Parallel(t *testing.T) {
+	srv, peak := petServer(t)
+	var requests []domain.SavedRequest
+	for range 6 {
+		requests = append(requests, domain.SavedRequest{Name: "fetch", OperationID: "getPet",
+			Values: domain.RequestValues{Path: map[string]string{"petId": "7"}},
+			Expect: &domain.Expectations{Status: "200"}})
+	}
+
+	run := runTestsAgainst(srv, 3, requests...)
+	if passed, _, _ := run.Counts(); passed != 6 {
+		t.Errorf("expected every test to pass, got %+v", run.Results)
+	}
+	if got := peak.Load(); got < 2 || got > 3 {
+		t.Errorf("expected up to 3 requests at once, saw %d", got)
+	}
+
+	peak.Store(0)
+	runTestsAgainst(srv, 1, requests...)
+	if got := peak.Load(); got != 1 {
+		t.Errorf("expected one request at a time, saw %d", got)
+	}
+}
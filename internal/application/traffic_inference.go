@@ -0,0 +1,584 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+// idSegment matches path segments that look like identifiers rather than
+// names: numbers, UUIDs and long hexadecimal strings.
+var idSegment = regexp.MustCompile(`^(\d+|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}|[0-9a-fA-F]{16,})$`)
+
+// comparison accumulates what a set of exchanges shows.
+type comparison struct {
+	endpoints map[string]*domain.ObservedEndpoint // by method and template
+	responses map[string]*observedResponse        // by operation, status and media type
+}
+
+// observedResponse is a declared response whose JSON bodies were seen.
+type observedResponse struct {
+	op        domain.Operation
+	status    string
+	mediaType string
+	schema    *domain.Schema
+	seen      map[string]bool // field locations
+	extra     map[string]*domain.FieldFinding
+}
+
+// Compare leaves out CORS preflight requests, and checks fields only in
+// whole JSON responses.
+func (s *TrafficService) Compare(exchanges []domain.RecordedExchange) domain.TrafficReport {
+	c := &comparison{endpoints: map[string]*domain.ObservedEndpoint{}, responses: map[string]*observedResponse{}}
+	report := domain.TrafficReport{}
+	for _, ex := range exchanges {
+		method := domain.HTTPMethod(strings.ToUpper(string(ex.Method)))
+		if method == domain.OPTIONS && ex.RequestHeader.Get("Access-Control-Request-Method") != "" {
+			continue
+		}
+		u, err := url.ParseRequestURI(ex.URL)
+		if err != nil {
+			continue
+		}
+		report.Exchanges++
+
+		matches := s.router.find(u.EscapedPath())
+		m, ok := forMethod(matches, method)
+		if !ok && method == domain.HEAD {
+			m, ok = forMethod(matches, domain.GET)
+		}
+		if !ok {
+			c.endpoint(ex, method, u, matches, s.router)
+			continue
+		}
+		if ex.Error == "" && !ex.ResponseTruncated {
+			c.response(m.op, ex)
+		}
+	}
+
+	for _, key := range sortedKeys(c.endpoints) {
+		report.Endpoints = append(report.Endpoints, *c.endpoints[key])
+	}
+	sort.SliceStable(report.Endpoints, func(i, j int) bool {
+		a, b := report.Endpoints[i], report.Endpoints[j]
+		if a.Path != b.Path {
+			return a.Path < b.Path
+		}
+		return slices.Index(domain.HTTPMethods, a.Method) < slices.Index(domain.HTTPMethods, b.Method)
+	})
+	for _, key := range sortedKeys(c.responses) {
+		r := c.responses[key]
+		for _, field := range sortedKeys(r.extra) {
+			report.UndeclaredFields = append(report.UndeclaredFields, *r.extra[field])
+		}
+		report.UnobservedFields = append(report.UnobservedFields, r.unobserved()...)
+	}
+	return report
+}
+
+// endpoint records an exchange no operation matches.
+func (c *comparison) endpoint(ex domain.RecordedExchange, method domain.HTTPMethod, u *url.URL, matches []routeMatch, r *router) {
+	path, known := "", len(matches) > 0
+	if known {
+		path = matches[0].op.Path
+	} else {
+		path = pathTemplate(r.below(u.EscapedPath()))
+	}
+	key := string(method) + " " + path
+	e, ok := c.endpoints[key]
+	if !ok {
+		e = &domain.ObservedEndpoint{Method: method, Path: path, Known: known, Responses: map[string]domain.ObservedBody{}}
+		c.endpoints[key] = e
+	}
+	e.Count++
+	for name := range u.Query() {
+		if !slices.Contains(e.Query, name) {
+			e.Query = append(e.Query, name)
+		}
+	}
+	sort.Strings(e.Query)
+	if ex.RequestBody != "" {
+		body := observeBody(e.Request, ex.RequestHeader.Get("Content-Type"), ex.RequestBody, ex.RequestTruncated)
+		e.Request = &body
+	}
+	if ex.Error == "" {
+		status := strconv.Itoa(ex.Status)
+		var prev *domain.ObservedBody
+		if b, ok := e.Responses[status]; ok {
+			prev = &b
+		}
+		e.Responses[status] = observeBody(prev, ex.ResponseHeader.Get("Content-Type"), ex.ResponseBody, ex.ResponseTruncated)
+	}
+}
+
+// observeBody merges a body into what was seen before under the same
+// status, inferring a schema from JSON.
+func observeBody(prev *domain.ObservedBody, contentType, body string, truncated bool) domain.ObservedBody {
+	var out domain.ObservedBody
+	if prev != nil {
+		out = *prev
+	}
+	if contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil {
+			mediaType = strings.ToLower(strings.TrimSpace(contentType))
+		}
+		out.MediaType = mediaType
+	}
+	if body == "" || truncated || !strings.Contains(out.MediaType, "json") {
+		return out
+	}
+	if v, err := decodeJSON([]byte(body)); err == nil {
+		out.Schema = mergeSchemas(out.Schema, inferSchema(v))
+	}
+	return out
+}
+
+// below returns an escaped path relative to the longest server base path
+// it falls under.
+func (r *router) below(path string) string {
+	for _, base := range r.bases {
+		if rest, ok := strings.CutPrefix(path, base); ok && (rest == "" || strings.HasPrefix(rest, "/")) {
+			return rest
+		}
+	}
+	return path
+}
+
+// pathTemplate turns segments that look like IDs into parameters named
+// after the segment before them, so "/owners/7/pets/3" becomes
+// "/owners/{ownerId}/pets/{petId}".
+func pathTemplate(path string) string {
+	segments := splitPath(path)
+	used := map[string]bool{}
+	for i, seg := range segments {
+		if raw, err := url.PathUnescape(seg); err == nil {
+			seg = raw
+		}
+		segments[i] = seg
+		if !idSegment.MatchString(seg) {
+			continue
+		}
+		name := "id"
+		if i > 0 && !strings.HasPrefix(segments[i-1], "{") {
+			if prev := nonAlnum.ReplaceAllString(singular(segments[i-1]), ""); prev != "" {
+				name = prev + "Id"
+			}
+		}
+		unique := name
+		for n := 2; used[unique]; n++ {
+			unique = fmt.Sprintf("%s%d", name, n)
+		}
+		used[unique] = true
+		segments[i] = "{" + unique + "}"
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// response records the fields of a response to op, when it is declared
+// and its body is JSON.
+func (c *comparison) response(op domain.Operation, ex domain.RecordedExchange) {
+	status, ok := domain.ResponseKey(op.Responses, strconv.Itoa(ex.Status))
+	if !ok {
+		return
+	}
+	content := op.Responses[status].Content
+	key, _, ok := contentKey(content, ex.ResponseHeader.Get("Content-Type"))
+	if !ok || !strings.Contains(key, "json") || content[key].Schema == nil {
+		return
+	}
+	body, err := decodeJSON([]byte(ex.ResponseBody))
+	if err != nil {
+		return
+	}
+
+	id := routeKey(op) + " " + status + " " + key
+	r, ok := c.responses[id]
+	if !ok {
+		r = &observedResponse{
+			op: op, status: status, mediaType: key, schema: content[key].Schema,
+			seen: map[string]bool{}, extra: map[string]*domain.FieldFinding{},
+		}
+		c.responses[id] = r
+	}
+	r.walk(r.schema, body, jsonpath.Location{}, r.schemaPath())
+}
+
+// schemaPath locates the response's schema in the spec document.
+func (r *observedResponse) schemaPath() specLocation {
+	return specLocation("$").key("paths").key(r.op.Path).key(strings.ToLower(string(r.op.Method))).
+		key("responses").key(r.status).key("content").key(r.mediaType).key("schema").at(r.schema)
+}
+
+// walk marks the declared fields of v as seen and records the undeclared
+// ones. Objects whose schema lists no properties may hold anything, and
+// undeclared fields are not looked into.
+func (r *observedResponse) walk(s *domain.Schema, v any, loc jsonpath.Location, schemaPath specLocation) {
+	if s == nil {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		if len(s.Properties) == 0 {
+			return
+		}
+		for name, value := range v {
+			at := loc.Key(name)
+			prop, ok := s.Properties[name]
+			if !ok {
+				r.undeclared(at, name, schemaPath, value)
+				continue
+			}
+			r.seen[at.String()] = true
+			r.walk(prop, value, at, schemaPath.key("properties").key(name).at(prop))
+		}
+	case []any:
+		for _, item := range v {
+			r.walk(s.Items, item, loc.Each(), schemaPath.key("items").at(s.Items))
+		}
+	}
+}
+
+func (r *observedResponse) undeclared(at jsonpath.Location, name string, schemaPath specLocation, value any) {
+	f, ok := r.extra[at.String()]
+	if !ok {
+		f = r.finding(at.String(), name, schemaPath)
+		r.extra[at.String()] = f
+	}
+	f.Schema = mergeSchemas(f.Schema, inferSchema(value))
+	f.Count++
+}
+
+func (r *observedResponse) finding(field, name string, schemaPath specLocation) *domain.FieldFinding {
+	return &domain.FieldFinding{
+		OperationID: r.op.ID, Method: r.op.Method, Path: r.op.Path, Status: r.status, MediaType: r.mediaType,
+		Field: field, Property: name, SchemaPath: string(schemaPath),
+	}
+}
+
+// unobserved lists the declared fields never seen, leaving out those below
+// a field that was never seen either.
+func (r *observedResponse) unobserved() []domain.FieldFinding {
+	var out []domain.FieldFinding
+	var visit func(s *domain.Schema, loc jsonpath.Location, schemaPath specLocation)
+	visit = func(s *domain.Schema, loc jsonpath.Location, schemaPath specLocation) {
+		if s == nil {
+			return
+		}
+		for _, name := range sortedKeys(s.Properties) {
+			at := loc.Key(name)
+			if !r.seen[at.String()] {
+				out = append(out, *r.finding(at.String(), name, schemaPath))
+				continue
+			}
+			prop := s.Properties[name]
+			visit(prop, at, schemaPath.key("properties").key(name).at(prop))
+		}
+		visit(s.Items, loc.Each(), schemaPath.key("items").at(s.Items))
+	}
+	visit(r.schema, jsonpath.Location{}, r.schemaPath())
+	return out
+}
+
+// specLocation is a JSONPath into the spec document, as overlay targets
+// are written.
+type specLocation string
+
+var plainName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func (l specLocation) key(name string) specLocation {
+	if plainName.MatchString(name) {
+		return l + specLocation("."+name)
+	}
+	return l + specLocation("['"+strings.ReplaceAll(strings.ReplaceAll(name, `\`, `\\`), `'`, `\'`)+"']")
+}
+
+// at moves to the component a schema was defined in, when it was reached
+// through a reference within the spec.
+func (l specLocation) at(s *domain.Schema) specLocation {
+	if s == nil || !strings.HasPrefix(s.Ref, "#/") {
+		return l
+	}
+	loc := specLocation("$")
+	for _, part := range strings.Split(strings.TrimPrefix(s.Ref, "#/"), "/") {
+		if raw, err := url.PathUnescape(part); err == nil {
+			part = raw
+		}
+		loc = loc.key(strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~"))
+	}
+	return loc
+}
+
+// inferSchema describes a JSON value decoded with json.Number. Every
+// member of an object is required, until mergeSchemas sees one missing.
+func inferSchema(v any) *domain.Schema {
+	switch v := v.(type) {
+	case nil:
+		return &domain.Schema{Nullable: true}
+	case bool:
+		return &domain.Schema{Type: domain.SchemaTypeBoolean}
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return &domain.Schema{Type: domain.SchemaTypeInteger}
+		}
+		return &domain.Schema{Type: domain.SchemaTypeNumber}
+	case string:
+		s := &domain.Schema{Type: domain.SchemaTypeString}
+		for _, format := range []string{"date-time", "date", "uuid", "email"} {
+			if formatMatches(format, v) {
+				s.Format = format
+				break
+			}
+		}
+		return s
+	case []any:
+		s := &domain.Schema{Type: domain.SchemaTypeArray}
+		for _, item := range v {
+			s.Items = mergeSchemas(s.Items, inferSchema(item))
+		}
+		return s
+	case map[string]any:
+		s := &domain.Schema{Type: domain.SchemaTypeObject, Properties: make(map[string]*domain.Schema, len(v))}
+		for name, value := range v {
+			s.Properties[name] = inferSchema(value)
+		}
+		s.Required = sortedKeys(v)
+		return s
+	}
+	return &domain.Schema{}
+}
+
+// mergeSchemas combines two inferred schemas into one that covers both.
+// Integers and numbers make numbers; other mixed types leave the type
+// open.
+func mergeSchemas(a, b *domain.Schema) *domain.Schema {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	out := &domain.Schema{Type: a.Type, Format: a.Format, Nullable: a.Nullable || b.Nullable}
+	switch {
+	case a.Type == "" && a.Nullable:
+		out.Type, out.Format = b.Type, b.Format
+	case b.Type == "" && b.Nullable:
+	case a.Type != b.Type:
+		out.Type, out.Format = "", ""
+		if isNumeric(a.Type) && isNumeric(b.Type) {
+			out.Type = domain.SchemaTypeNumber
+		}
+	case a.Format != b.Format:
+		out.Format = ""
+	}
+
+	if a.Properties != nil || b.Properties != nil {
+		out.Properties = map[string]*domain.Schema{}
+		for _, side := range []*domain.Schema{a, b} {
+			for name, p := range side.Properties {
+				out.Properties[name] = mergeSchemas(out.Properties[name], p)
+			}
+		}
+	}
+	switch {
+	case a.Type == domain.SchemaTypeObject && b.Type == domain.SchemaTypeObject:
+		for _, name := range a.Required {
+			if slices.Contains(b.Required, name) {
+				out.Required = append(out.Required, name)
+			}
+		}
+	case a.Type == domain.SchemaTypeObject:
+		out.Required = a.Required
+	case b.Type == domain.SchemaTypeObject:
+		out.Required = b.Required
+	}
+	out.Items = mergeSchemas(a.Items, b.Items)
+	return out
+}
+
+func isNumeric(t domain.SchemaType) bool {
+	return t == domain.SchemaTypeInteger || t == domain.SchemaTypeNumber
+}
+
+// Overlay adds each path with its endpoints in one action, and each
+// schema's undeclared properties in another.
+func (s *TrafficService) Overlay(report domain.TrafficReport) domain.Overlay {
+	overlay := domain.Overlay{Title: "Additions to " + s.info.Title + " seen in traffic", Version: "1.0.0"}
+
+	type pathUpdate struct {
+		known      bool
+		operations map[string]any
+		methods    []string
+		count      int
+	}
+	var paths []string
+	updates := map[string]*pathUpdate{}
+	for _, e := range report.Endpoints {
+		u, ok := updates[e.Path]
+		if !ok {
+			u = &pathUpdate{known: e.Known, operations: map[string]any{}}
+			updates[e.Path] = u
+			paths = append(paths, e.Path)
+		}
+		u.operations[strings.ToLower(string(e.Method))] = operationDocument(e, s.pathParameters(e.Path))
+		u.methods = append(u.methods, string(e.Method))
+		u.count += e.Count
+	}
+	for _, path := range paths {
+		u := updates[path]
+		action := domain.OverlayAction{
+			Target:      string(specLocation("$").key("paths")),
+			Description: fmt.Sprintf("%s %s, seen %s", strings.Join(u.methods, ", "), path, times(u.count)),
+			Update:      map[string]any{path: u.operations},
+		}
+		if u.known {
+			action.Target = string(specLocation("$").key("paths").key(path))
+			action.Update = u.operations
+		}
+		overlay.Actions = append(overlay.Actions, action)
+	}
+
+	var targets []string
+	properties := map[string]map[string]any{}
+	described := map[string][]string{}
+	for _, f := range report.UndeclaredFields {
+		target := f.SchemaPath + string(specLocation("").key("properties"))
+		if _, ok := properties[target]; !ok {
+			properties[target] = map[string]any{}
+			targets = append(targets, target)
+		}
+		properties[target][f.Property] = schemaDocument(f.Schema)
+		described[target] = append(described[target], f.Field)
+	}
+	for _, target := range targets {
+		overlay.Actions = append(overlay.Actions, domain.OverlayAction{
+			Target:      target,
+			Description: "Undeclared " + strings.Join(described[target], ", "),
+			Update:      properties[target],
+		})
+	}
+	return overlay
+}
+
+// pathParameters returns the path parameters the spec's operations on path
+// declare, by name, preferring declarations with a schema.
+func (s *TrafficService) pathParameters(path string) map[string]domain.Parameter {
+	params := map[string]domain.Parameter{}
+	for _, rt := range s.router.routes {
+		if rt.op.Path != path {
+			continue
+		}
+		for _, p := range rt.op.Parameters {
+			if p.In != domain.ParameterInPath {
+				continue
+			}
+			if prev, ok := params[p.Name]; !ok || prev.Schema == nil {
+				params[p.Name] = p
+			}
+		}
+	}
+	return params
+}
+
+// operationDocument describes an observed endpoint as an OpenAPI operation.
+// Path parameters the spec declares elsewhere keep their schema; others
+// are typed as strings, since a few values say little about their type.
+func operationDocument(e domain.ObservedEndpoint, declared map[string]domain.Parameter) map[string]any {
+	op := map[string]any{"summary": fmt.Sprintf("Seen %s in recorded traffic", times(e.Count))}
+	var params []any
+	for _, loc := range templateParam.FindAllStringSubmatch(e.Path, -1) {
+		param := map[string]any{"name": loc[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"}}
+		if p, ok := declared[loc[1]]; ok && p.Schema != nil {
+			param["schema"] = schemaDocument(p.Schema)
+		}
+		params = append(params, param)
+	}
+	for _, name := range e.Query {
+		params = append(params, map[string]any{"name": name, "in": "query", "schema": map[string]any{"type": "string"}})
+	}
+	if params != nil {
+		op["parameters"] = params
+	}
+	if e.Request != nil {
+		op["requestBody"] = map[string]any{"content": contentDocument(*e.Request)}
+	}
+	responses := map[string]any{}
+	for status, body := range e.Responses {
+		resp := map[string]any{"description": http.StatusText(atoi(status))}
+		if body.MediaType != "" {
+			resp["content"] = contentDocument(body)
+		}
+		responses[status] = resp
+	}
+	if len(responses) == 0 {
+		responses["default"] = map[string]any{"description": "No response was recorded"}
+	}
+	op["responses"] = responses
+	return op
+}
+
+func contentDocument(body domain.ObservedBody) map[string]any {
+	mt := map[string]any{}
+	if body.Schema != nil {
+		mt["schema"] = schemaDocument(body.Schema)
+	}
+	return map[string]any{body.MediaType: mt}
+}
+
+// schemaDocument writes a schema as an OpenAPI schema object, or as a
+// reference to where it was defined.
+func schemaDocument(s *domain.Schema) map[string]any {
+	doc := map[string]any{}
+	if s == nil {
+		return doc
+	}
+	if s.Ref != "" {
+		doc["$ref"] = s.Ref
+		return doc
+	}
+	if s.Type != "" {
+		doc["type"] = string(s.Type)
+	}
+	if s.Format != "" {
+		doc["format"] = s.Format
+	}
+	if s.Nullable {
+		doc["nullable"] = true
+	}
+	if len(s.Properties) > 0 {
+		props := make(map[string]any, len(s.Properties))
+		for name, p := range s.Properties {
+			props[name] = schemaDocument(p)
+		}
+		doc["properties"] = props
+	}
+	if len(s.Required) > 0 {
+		doc["required"] = s.Required
+	}
+	if s.Items != nil {
+		doc["items"] = schemaDocument(s.Items)
+	}
+	return doc
+}
+
+func times(n int) string {
+	if n == 1 {
+		return "once"
+	}
+	return fmt.Sprintf("%d times", n)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
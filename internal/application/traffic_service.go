@@ -0,0 +1,78 @@
+package application
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// TrafficService implements domain.TrafficService for one spec.
+type TrafficService struct {
+	router    *router
+	validator domain.ValidationService
+	info      domain.SpecInfo
+}
+
+// NewTrafficService matches traffic against spec's operations below any of
+// its servers' base paths, checking it with validator.
+func NewTrafficService(spec *domain.Spec, validator domain.ValidationService) *TrafficService {
+	return &TrafficService{router: newRouter(spec.Operations, spec.Servers), validator: validator, info: spec.Info}
+}
+
+// Check leaves truncated bodies unchecked, since only part of them was
+// recorded.
+func (s *TrafficService) Check(ex domain.RecordedExchange) domain.RecordedExchange {
+	ex.OperationID, ex.RequestViolations, ex.ResponseViolations = "", nil, nil
+	method := domain.HTTPMethod(strings.ToUpper(string(ex.Method)))
+	u, err := url.ParseRequestURI(ex.URL)
+	if err != nil {
+		ex.RequestViolations = []domain.Violation{{Path: "url", Message: err.Error()}}
+		return ex
+	}
+
+	matches := s.router.find(u.EscapedPath())
+	m, ok := forMethod(matches, method)
+	if !ok && method == domain.HEAD {
+		m, ok = forMethod(matches, domain.GET)
+	}
+	if !ok {
+		msg := fmt.Sprintf("%s %s is not in the spec", method, u.Path)
+		if len(matches) > 0 {
+			msg = fmt.Sprintf("%s is not defined for %s", method, matches[0].op.Path)
+		}
+		ex.RequestViolations = []domain.Violation{{Path: "operation", Message: msg}}
+		return ex
+	}
+
+	ex.OperationID = m.op.ID
+	for _, v := range s.validator.ValidateRequest(m.op, receivedValues(m, u.Query(), ex.RequestHeader, []byte(ex.RequestBody))) {
+		if ex.RequestTruncated && strings.HasPrefix(v.Path, "$") {
+			continue
+		}
+		ex.RequestViolations = append(ex.RequestViolations, v)
+	}
+	if ex.Error != "" {
+		return ex
+	}
+	resp := &domain.HTTPResponse{StatusCode: ex.Status, Header: ex.ResponseHeader, Body: []byte(ex.ResponseBody)}
+	op := m.op
+	if method == domain.HEAD {
+		op.Method = domain.HEAD
+	}
+	for _, v := range s.validator.ValidateResponse(op, resp) {
+		if ex.ResponseTruncated && strings.HasPrefix(v.Path, "$") {
+			continue
+		}
+		ex.ResponseViolations = append(ex.ResponseViolations, v)
+	}
+	return ex
+}
+
+func (s *TrafficService) Mask(ex domain.RecordedExchange) domain.RecordedExchange {
+	ex.URL = maskURL(ex.URL)
+	ex.RequestHeader = maskHeader(ex.RequestHeader)
+	ex.ResponseHeader = maskHeader(ex.ResponseHeader)
+	return ex
+}
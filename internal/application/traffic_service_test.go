@@ -0,0 +1,175 @@
+package application_test
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func checkTraffic(ex domain.RecordedExchange) domain.RecordedExchange {
+	return application.NewTrafficService(mockSpec(), application.NewValidationService()).Check(ex)
+}
+
+func TestTrafficService_Check(t *testing.T) {
+	json := http.Header{"Content-Type": {"application/json"}}
+	ex := checkTraffic(domain.RecordedExchange{
+		Method: domain.GET, URL: "/v1/pets/7", Status: 200,
+		ResponseHeader: json, ResponseBody: `{"id": 7, "name": "Rex"}`,
+	})
+	if ex.OperationID != "getPet" || ex.Violations() != 0 {
+		t.Errorf("expected a clean getPet, got %q %v %v", ex.OperationID, ex.RequestViolations, ex.ResponseViolations)
+	}
+
+	ex = checkTraffic(domain.RecordedExchange{
+		Method: domain.GET, URL: "/v1/pets?limit=500", Status: 200,
+		ResponseHeader: json, ResponseBody: `[{"id": "seven"}]`,
+	})
+	if len(ex.RequestViolations) != 1 || ex.RequestViolations[0].Path != "query limit" {
+		t.Errorf("expected the limit violation, got %v", ex.RequestViolations)
+	}
+	var paths []string
+	for _, v := range ex.ResponseViolations {
+		paths = append(paths, v.Path)
+	}
+	slices.Sort(paths)
+	if got := strings.Join(paths, ","); got != "$[0].id,$[0].name" {
+		t.Errorf("expected the body's violations, got %v", ex.ResponseViolations)
+	}
+
+	ex = checkTraffic(domain.RecordedExchange{Method: domain.GET, URL: "/v1/pets/7", Status: 500})
+	if len(ex.ResponseViolations) != 1 || ex.ResponseViolations[0].Message != "500 is not a documented response" {
+		t.Errorf("expected an undocumented status, got %v", ex.ResponseViolations)
+	}
+	ex = checkTraffic(domain.RecordedExchange{Method: domain.GET, URL: "/v1/pets/7", Status: 200,
+		ResponseHeader: json, ResponseBody: `{"id": 7, "na`, ResponseTruncated: true})
+	if ex.Violations() != 0 {
+		t.Errorf("expected a truncated body to go unchecked, got %v", ex.ResponseViolations)
+	}
+	ex = checkTraffic(domain.RecordedExchange{Method: domain.POST, URL: "/v1/pets", Status: 201,
+		RequestHeader: json, RequestBody: `{"name": "Re`, RequestTruncated: true,
+		ResponseHeader: json, ResponseBody: `{"id": 7, "name": "Rex"}`})
+	if ex.Violations() != 0 {
+		t.Errorf("expected a truncated request body to go unchecked, got %v", ex.RequestViolations)
+	}
+	ex = checkTraffic(domain.RecordedExchange{Method: domain.GET, URL: "/v1/pets/7", Error: "connection refused"})
+	if ex.OperationID != "getPet" || ex.Violations() != 0 {
+		t.Errorf("expected a failed exchange to be matched without response checks, got %v", ex.ResponseViolations)
+	}
+}
+
+func TestTrafficService_Mask(t *testing.T) {
+	ex := domain.RecordedExchange{
+		Method: domain.GET, URL: "/v1/pets?api_key=k1&limit=5",
+		RequestHeader:  http.Header{"Authorization": {"Bearer abc"}, "Cookie": {"session=s1"}, "Accept": {"application/json"}},
+		ResponseHeader: http.Header{"Set-Cookie": {"session=s2"}},
+	}
+	masked := application.NewTrafficService(mockSpec(), application.NewValidationService()).Mask(ex)
+	if strings.Contains(masked.URL, "k1") || !strings.Contains(masked.URL, "limit=5") {
+		t.Errorf("expected the API key masked, got %s", masked.URL)
+	}
+	if masked.RequestHeader.Get("Authorization") != "Bearer ••••" || masked.RequestHeader.Get("Cookie") != "••••" ||
+		masked.RequestHeader.Get("Accept") != "application/json" || masked.ResponseHeader.Get("Set-Cookie") != "••••" {
+		t.Errorf("unexpected headers %v %v", masked.RequestHeader, masked.ResponseHeader)
+	}
+	if ex.RequestHeader.Get("Authorization") != "Bearer abc" {
+		t.Error("expected the exchange itself left as it was")
+	}
+}
+
+func TestTrafficService_Unmatched(t *testing.T) {
+	ex := checkTraffic(domain.RecordedExchange{Method: domain.GET, URL: "/v1/owners", Status: 200})
+	if ex.OperationID != "" || len(ex.RequestViolations) != 1 || ex.RequestViolations[0].String() != "operation: GET /v1/owners is not in the spec" {
+		t.Errorf("expected an unknown path, got %v", ex.RequestViolations)
+	}
+	ex = checkTraffic(domain.RecordedExchange{Method: domain.PUT, URL: "/v1/pets", Status: 200})
+	if len(ex.RequestViolations) != 1 || ex.RequestViolations[0].Message != "PUT is not defined for /pets" {
+		t.Errorf("expected an unknown method, got %v", ex.RequestViolations)
+	}
+}
+
+func inferenceTraffic() []domain.RecordedExchange {
+	json := http.Header{"Content-Type": {"application/json"}}
+	return []domain.RecordedExchange{
+		{Method: domain.GET, URL: "/v1/pets/7", Status: 200, ResponseHeader: json, ResponseBody: `{"id": 7, "name": "Rex", "nickname": "R"}`},
+		{Method: domain.GET, URL: "/v1/pets/8", Status: 200, ResponseHeader: json, ResponseBody: `{"id": 8, "name": "Max", "nickname": null}`},
+		{Method: domain.GET, URL: "/v1/pets", Status: 200, ResponseHeader: json, ResponseBody: `[{"id": 1, "name": "Rex", "tags": ["old"]}]`},
+		{Method: domain.POST, URL: "/v1/pets", Status: 201, ResponseHeader: json, ResponseBody: `{"id": 2}`},
+		{Method: domain.GET, URL: "/v1/owners/12/pets/3f2b9c1e-8d4a-4c6b-9e2f-1a2b3c4d5e6f?expand=toys", Status: 200,
+			ResponseHeader: json, ResponseBody: `{"id": 3, "born": "2020-01-02T03:04:05Z"}`},
+		{Method: domain.GET, URL: "/v1/owners/13/pets/3f2b9c1e-8d4a-4c6b-9e2f-1a2b3c4d5e6f", Status: 200,
+			ResponseHeader: json, ResponseBody: `{"id": 3.5}`},
+		{Method: domain.PUT, URL: "/v1/pets/7", RequestHeader: json, RequestBody: `{"name": "Rex"}`, Status: 204},
+		{Method: domain.OPTIONS, URL: "/v1/pets", RequestHeader: http.Header{"Access-Control-Request-Method": {"POST"}}, Status: 204},
+	}
+}
+
+func TestTrafficService_Compare(t *testing.T) {
+	report := application.NewTrafficService(mockSpec(), application.NewValidationService()).Compare(inferenceTraffic())
+
+	if report.Exchanges != 7 {
+		t.Errorf("expected the preflight to be left out, got %d exchanges", report.Exchanges)
+	}
+	if len(report.Endpoints) != 2 {
+		t.Fatalf("expected two endpoints, got %+v", report.Endpoints)
+	}
+	owners, put := report.Endpoints[0], report.Endpoints[1]
+	if owners.Method != domain.GET || owners.Path != "/owners/{ownerId}/pets/{petId}" || owners.Known || owners.Count != 2 ||
+		!slices.Equal(owners.Query, []string{"expand"}) {
+		t.Errorf("unexpected endpoint %+v", owners)
+	}
+	body := owners.Responses["200"]
+	if body.MediaType != "application/json" || body.Schema.Properties["id"].Type != domain.SchemaTypeNumber ||
+		body.Schema.Properties["born"].Format != "date-time" || !slices.Equal(body.Schema.Required, []string{"id"}) {
+		t.Errorf("unexpected inferred response %+v", body.Schema)
+	}
+	if put.Method != domain.PUT || put.Path != "/pets/{petId}" || !put.Known || put.Request == nil ||
+		put.Request.Schema.Properties["name"].Type != domain.SchemaTypeString {
+		t.Errorf("unexpected endpoint %+v", put)
+	}
+
+	var undeclared []string
+	for _, f := range report.UndeclaredFields {
+		undeclared = append(undeclared, f.OperationID+" "+f.Field)
+	}
+	if !slices.Equal(undeclared, []string{"listPets $[*].tags", "getPet $.nickname"}) {
+		t.Fatalf("unexpected undeclared fields %v", undeclared)
+	}
+	nickname := report.UndeclaredFields[1]
+	if nickname.Count != 2 || nickname.Schema.Type != domain.SchemaTypeString || !nickname.Schema.Nullable ||
+		nickname.SchemaPath != "$.paths['/pets/{petId}'].get.responses['200'].content['application/json'].schema" {
+		t.Errorf("unexpected finding %+v", nickname)
+	}
+	if len(report.UnobservedFields) != 1 || report.UnobservedFields[0].OperationID != "createPet" || report.UnobservedFields[0].Field != "$.name" {
+		t.Errorf("expected the created pet's name to be unobserved, got %+v", report.UnobservedFields)
+	}
+}
+
+func TestTrafficService_Overlay(t *testing.T) {
+	spec := mockSpec()
+	// Pets are declared once, as a component.
+	spec.Operations[0].Responses["200"].Content["application/json"].Schema.Items.Ref = "#/components/schemas/Pet"
+	svc := application.NewTrafficService(spec, application.NewValidationService())
+	overlay := svc.Overlay(svc.Compare(inferenceTraffic()))
+
+	var targets []string
+	for _, a := range overlay.Actions {
+		targets = append(targets, a.Target)
+	}
+	if !slices.Equal(targets, []string{"$.paths", "$.paths['/pets/{petId}']", "$.components.schemas.Pet.properties"}) {
+		t.Fatalf("unexpected targets %v", targets)
+	}
+	put := overlay.Actions[1].Update.(map[string]any)["put"].(map[string]any)
+	param := put["parameters"].([]any)[0].(map[string]any)
+	if param["name"] != "petId" || param["schema"].(map[string]any)["type"] != "integer" {
+		t.Errorf("expected the declared path parameter, got %v", param)
+	}
+	props := overlay.Actions[2].Update.(map[string]any)
+	if len(props) != 2 || props["tags"].(map[string]any)["type"] != "array" ||
+		props["nickname"].(map[string]any)["nullable"] != true {
+		t.Errorf("unexpected properties %v", props)
+	}
+}
@@ -0,0 +1,231 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"strconv"
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+// ValidationService implements domain.ValidationService.
+type ValidationService struct{}
+
+func NewValidationService() *ValidationService {
+	return &ValidationService{}
+}
+
+// ValidateRequest checks parameters for presence and against their schemas
+// (enum, pattern, bounds, format), then the body against the schema of its
+// content type.
+func (s *ValidationService) ValidateRequest(op domain.Operation, values domain.RequestValues) []domain.Violation {
+	var violations []domain.Violation
+	for _, p := range op.Parameters {
+		path := domain.ParameterPath(p.In, p.Name)
+		value := strings.TrimSpace(parameterValue(values, p))
+		if value == "" {
+			if p.Required || p.In == domain.ParameterInPath {
+				violations = append(violations, domain.Violation{Path: path, Message: "required"})
+			}
+			continue
+		}
+		for _, msg := range validateParameterValue(p.Schema, value) {
+			violations = append(violations, domain.Violation{Path: path, Message: msg})
+		}
+	}
+
+	rb := op.RequestBody
+	if rb == nil || len(rb.Content) == 0 {
+		return violations
+	}
+	if strings.TrimSpace(values.Body) == "" {
+		if rb.Required {
+			violations = append(violations, domain.Violation{Path: "$", Message: "request body is required"})
+		}
+		return violations
+	}
+	return append(violations, validateBody(rb.Content, values.ContentType, []byte(values.Body))...)
+}
+
+func parameterValue(values domain.RequestValues, p domain.Parameter) string {
+	switch p.In {
+	case domain.ParameterInPath:
+		return values.Path[p.Name]
+	case domain.ParameterInQuery:
+		return values.Query[p.Name]
+	case domain.ParameterInHeader:
+		return values.Header[p.Name]
+	case domain.ParameterInCookie:
+		return values.Cookie[p.Name]
+	}
+	return ""
+}
+
+// ValidateResponse checks the status code against the documented responses
+// and, for the matching response, its headers and body schema. A truncated
+// body is not checked, since only part of it arrived.
+func (s *ValidationService) ValidateResponse(op domain.Operation, resp *domain.HTTPResponse) []domain.Violation {
+	if len(op.Responses) == 0 {
+		return nil
+	}
+
+	code := strconv.Itoa(resp.StatusCode)
+	spec, ok := domain.MatchResponse(op.Responses, code)
+	if !ok {
+		return []domain.Violation{{Path: "status", Message: fmt.Sprintf("%s is not a documented response", code)}}
+	}
+
+	var violations []domain.Violation
+	for _, name := range sortedKeys(spec.Headers) {
+		h := spec.Headers[name]
+		values := resp.Header.Values(name)
+		if len(values) == 0 {
+			if h.Required {
+				violations = append(violations, domain.Violation{Path: "header " + name, Message: "required header missing"})
+			}
+			continue
+		}
+		for _, v := range validateParameterValue(h.Schema, values[0]) {
+			violations = append(violations, domain.Violation{Path: "header " + name, Message: v})
+		}
+	}
+
+	if op.Method == domain.HEAD || len(spec.Content) == 0 || resp.Truncated {
+		return violations
+	}
+	return append(violations, validateBody(spec.Content, resp.Header.Get("Content-Type"), resp.Body)...)
+}
+
+// validateBody checks a body against the media type matching contentType.
+// Only JSON bodies are checked against their schema.
+func validateBody(content map[string]domain.MediaType, contentType string, body []byte) []domain.Violation {
+	mediaType, mt, ok := matchMediaType(content, contentType)
+	if !ok {
+		return []domain.Violation{{Path: "$", Message: fmt.Sprintf("content type %q is not documented", contentType)}}
+	}
+	if mt.Schema == nil || !strings.Contains(mediaType, "json") {
+		return nil
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return []domain.Violation{{Path: "$", Message: "expected a body, got none"}}
+	}
+
+	doc, err := decodeJSON(body)
+	if err != nil {
+		return []domain.Violation{{Path: "$", Message: "invalid JSON: " + err.Error()}}
+	}
+	return validateValue(mt.Schema, doc, jsonpath.Location{})
+}
+
+// matchMediaType picks the documented media type for a Content-Type header,
+// honouring wildcards such as "application/*" and "*/*". Without a header a
+// single documented type is assumed.
+func matchMediaType(content map[string]domain.MediaType, contentType string) (string, domain.MediaType, bool) {
+	key, mediaType, ok := contentKey(content, contentType)
+	return mediaType, content[key], ok
+}
+
+// contentKey is the key in content matchMediaType picks, along with the
+// media type of the Content-Type header (the key itself without a header).
+func contentKey(content map[string]domain.MediaType, contentType string) (key, mediaType string, ok bool) {
+	if contentType == "" {
+		if len(content) == 1 {
+			for name := range content {
+				return name, name, true
+			}
+		}
+		return "", "", false
+	}
+
+	base, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		base = strings.TrimSpace(strings.ToLower(contentType))
+	}
+	for _, candidate := range []string{base, strings.SplitN(base, "/", 2)[0] + "/*", "*/*"} {
+		if _, ok := content[candidate]; ok {
+			return candidate, base, true
+		}
+	}
+	return "", "", false
+}
+
+// decodeJSON decodes a single JSON value, keeping numbers as json.Number.
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return v, nil
+}
+
+// validateParameterValue checks a raw string value, as found in a header or
+// query string, against a schema. The value is converted to the schema's
+// type first; the returned messages have no location.
+func validateParameterValue(s *domain.Schema, raw string) []string {
+	if s == nil {
+		return nil
+	}
+	v, err := coerceParameter(s, raw)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	var messages []string
+	for _, violation := range validateValue(s, v, jsonpath.Location{}) {
+		messages = append(messages, violation.Message)
+	}
+	return messages
+}
+
+// coerceParameter converts a raw string to the JSON value the schema
+// expects. Arrays are comma-separated, as in the default "form" style.
+func coerceParameter(s *domain.Schema, raw string) (any, error) {
+	switch s.Type {
+	case domain.SchemaTypeInteger:
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, fmt.Errorf("expected integer, got %q", raw)
+		}
+		return json.Number(raw), nil
+	case domain.SchemaTypeNumber:
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, fmt.Errorf("expected number, got %q", raw)
+		}
+		return json.Number(raw), nil
+	case domain.SchemaTypeBoolean:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected boolean, got %q", raw)
+		}
+		return b, nil
+	case domain.SchemaTypeArray:
+		var items []any
+		for _, part := range strings.Split(raw, ",") {
+			item := any(part)
+			if s.Items != nil {
+				v, err := coerceParameter(s.Items, part)
+				if err != nil {
+					return nil, err
+				}
+				item = v
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	case domain.SchemaTypeObject:
+		v, err := decodeJSON([]byte(raw))
+		if err != nil {
+			return nil, fmt.Errorf("expected JSON object: %v", err)
+		}
+		return v, nil
+	}
+	return raw, nil
+}
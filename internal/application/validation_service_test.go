@@ -0,0 +1,243 @@
+package application_test
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func listItemsOperation() domain.Operation {
+	item := &domain.Schema{
+		Type:     domain.SchemaTypeObject,
+		Required: []string{"id", "price"},
+		Properties: map[string]*domain.Schema{
+			"id":     {Type: domain.SchemaTypeString, Format: "uuid"},
+			"price":  {Type: domain.SchemaTypeNumber, Minimum: ptr(0.0)},
+			"status": {Type: domain.SchemaTypeString, Enum: []any{"available", "sold"}},
+			"note":   {Type: domain.SchemaTypeString, Nullable: true},
+		},
+	}
+	return domain.Operation{
+		ID: "listItems", Path: "/items", Method: domain.GET,
+		Responses: map[string]domain.Response{
+			"200": {
+				Headers: map[string]domain.Header{
+					"X-Total-Count": {Required: true, Schema: &domain.Schema{Type: domain.SchemaTypeInteger}},
+				},
+				Content: map[string]domain.MediaType{
+					"application/json": {Schema: &domain.Schema{
+						Type:     domain.SchemaTypeObject,
+						Required: []string{"items"},
+						Properties: map[string]*domain.Schema{
+							"items": {Type: domain.SchemaTypeArray, Items: item, MaxItems: ptr(10)},
+						},
+					}},
+				},
+			},
+			"4XX": {Description: "client error"},
+		},
+	}
+}
+
+func validate(t *testing.T, code int, header http.Header, body string) []string {
+	t.Helper()
+	svc := application.NewValidationService()
+	violations := svc.ValidateResponse(listItemsOperation(), &domain.HTTPResponse{
+		StatusCode: code, Header: header, Body: []byte(body),
+	})
+	var got []string
+	for _, v := range violations {
+		got = append(got, v.String())
+	}
+	return got
+}
+
+func jsonHeader(count string) http.Header {
+	h := http.Header{"Content-Type": {"application/json; charset=utf-8"}}
+	if count != "" {
+		h.Set("X-Total-Count", count)
+	}
+	return h
+}
+
+func TestValidationService_ValidResponse(t *testing.T) {
+	body := `{"items":[{"id":"0b7f4c1e-3b1c-4a8e-9f57-0c1b2d3e4f50","price":9.5,"status":"sold","note":null}]}`
+	if got := validate(t, 200, jsonHeader("1"), body); len(got) != 0 {
+		t.Errorf("expected no violations, got %v", got)
+	}
+}
+
+func TestValidationService_BodyViolations(t *testing.T) {
+	body := `{"items":[
+		{"id":"0b7f4c1e-3b1c-4a8e-9f57-0c1b2d3e4f50","price":1},
+		{"id":"nope","price":-1,"status":"lost"},
+		{"price":"12.00"},
+		{"id":"0b7f4c1e-3b1c-4a8e-9f57-0c1b2d3e4f50","price":"free"}
+	]}`
+
+	want := []string{
+		"$.items[1].id: expected uuid format",
+		"$.items[1].price: expected at least 0",
+		`$.items[1].status: "lost" is not one of ["available", "sold"]`,
+		"$.items[2].id: required property missing",
+		"$.items[2].price: expected number, got string",
+		"$.items[3].price: expected number, got string",
+	}
+	if got := validate(t, 200, jsonHeader("4"), body); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestValidationService_StatusAndHeaders(t *testing.T) {
+	if got := validate(t, 500, jsonHeader(""), ""); !reflect.DeepEqual(got, []string{"status: 500 is not a documented response"}) {
+		t.Errorf("unexpected violations %q", got)
+	}
+
+	// 404 matches the 4XX class, which declares no content.
+	if got := validate(t, 404, http.Header{}, "gone"); len(got) != 0 {
+		t.Errorf("expected class match without violations, got %q", got)
+	}
+
+	want := []string{"header X-Total-Count: required header missing"}
+	if got := validate(t, 200, jsonHeader(""), `{"items":[]}`); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	want = []string{`header X-Total-Count: expected integer, got "many"`}
+	if got := validate(t, 200, jsonHeader("many"), `{"items":[]}`); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestValidationService_ContentProblems(t *testing.T) {
+	tests := []struct {
+		name   string
+		header http.Header
+		body   string
+		want   string
+	}{
+		{"undocumented type", http.Header{"Content-Type": {"text/html"}, "X-Total-Count": {"0"}}, "<p>", `$: content type "text/html" is not documented`},
+		{"invalid JSON", jsonHeader("0"), `{"items":`, "$: invalid JSON: unexpected EOF"},
+		{"empty body", jsonHeader("0"), "", "$: expected a body, got none"},
+		{"too many items", jsonHeader("0"), `{"items":[` + repeatItems(11) + `]}`, "$.items: expected at most 10 items, got 11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validate(t, 200, tt.header, tt.body)
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationService_TruncatedBody(t *testing.T) {
+	violations := application.NewValidationService().ValidateResponse(listItemsOperation(), &domain.HTTPResponse{
+		StatusCode: 200, Header: jsonHeader(""), Body: []byte(`{"items":[{"id":`), Truncated: true,
+	})
+	want := []domain.Violation{{Path: "header X-Total-Count", Message: "required header missing"}}
+	if !reflect.DeepEqual(violations, want) {
+		t.Errorf("expected only the header checked, got %v", violations)
+	}
+}
+
+func repeatItems(n int) string {
+	s := ""
+	for i := range n {
+		if i > 0 {
+			s += ","
+		}
+		s += `{"id":"0b7f4c1e-3b1c-4a8e-9f57-0c1b2d3e4f50","price":1}`
+	}
+	return s
+}
+
+func searchOperation() domain.Operation {
+	return domain.Operation{
+		ID: "searchPets", Path: "/owners/{ownerId}/pets", Method: domain.POST,
+		Parameters: []domain.Parameter{
+			{Name: "ownerId", In: domain.ParameterInPath, Required: true, Schema: &domain.Schema{Type: domain.SchemaTypeString, Format: "uuid"}},
+			{Name: "limit", In: domain.ParameterInQuery, Schema: &domain.Schema{Type: domain.SchemaTypeInteger, Minimum: ptr(1.0), Maximum: ptr(100.0)}},
+			{Name: "sort", In: domain.ParameterInQuery, Schema: &domain.Schema{Type: domain.SchemaTypeString, Enum: []any{"asc", "desc"}}},
+			{Name: "X-Request-Id", In: domain.ParameterInHeader, Required: true, Schema: &domain.Schema{Type: domain.SchemaTypeString, Pattern: "^[a-z0-9]{8}$"}},
+			{Name: "since", In: domain.ParameterInQuery, Schema: &domain.Schema{Type: domain.SchemaTypeString, Format: "date-time"}},
+		},
+		RequestBody: &domain.RequestBody{
+			Required: true,
+			Content: map[string]domain.MediaType{
+				"application/json": {Schema: &domain.Schema{
+					Type:     domain.SchemaTypeObject,
+					Required: []string{"name"},
+					Properties: map[string]*domain.Schema{
+						"name": {Type: domain.SchemaTypeString, MinLength: 1, MaxLength: ptr(20)},
+					},
+				}},
+			},
+		},
+	}
+}
+
+func validateRequest(values domain.RequestValues) []string {
+	var got []string
+	for _, v := range application.NewValidationService().ValidateRequest(searchOperation(), values) {
+		got = append(got, v.String())
+	}
+	return got
+}
+
+func TestValidationService_ValidRequest(t *testing.T) {
+	got := validateRequest(domain.RequestValues{
+		Path:        map[string]string{"ownerId": "0b7f4c1e-3b1c-4a8e-9f57-0c1b2d3e4f50"},
+		Query:       map[string]string{"limit": "50", "sort": "asc", "since": "2024-01-02T03:04:05Z"},
+		Header:      map[string]string{"X-Request-Id": "abcd1234"},
+		ContentType: "application/json",
+		Body:        `{"name":"Rex"}`,
+	})
+	if len(got) != 0 {
+		t.Errorf("expected no violations, got %q", got)
+	}
+}
+
+func TestValidationService_RequestViolations(t *testing.T) {
+	got := validateRequest(domain.RequestValues{
+		Path:        map[string]string{"ownerId": "42"},
+		Query:       map[string]string{"limit": "500", "sort": "sideways", "since": "yesterday"},
+		Header:      map[string]string{"X-Request-Id": "NOPE"},
+		ContentType: "application/json",
+		Body:        `{"name":""}`,
+	})
+
+	want := []string{
+		"path ownerId: expected uuid format",
+		"query limit: expected at most 100",
+		`query sort: "sideways" is not one of ["asc", "desc"]`,
+		"header X-Request-Id: does not match pattern ^[a-z0-9]{8}$",
+		"query since: expected date-time format",
+		"$.name: expected at least 1 character, got 0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestValidationService_RequestMissingValues(t *testing.T) {
+	got := validateRequest(domain.RequestValues{
+		Query:       map[string]string{"limit": "ten"},
+		ContentType: "application/json",
+	})
+
+	want := []string{
+		"path ownerId: required",
+		`query limit: expected integer, got "ten"`,
+		"header X-Request-Id: required",
+		"$: request body is required",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,21 @@
+package domain
+
+// SavedRequest is a filled-in request kept under a name. It references its
+// operation by ID rather than by method and path, so it survives the path
+// or method changing in the spec. Expect and Captures are used when the
+// request runs as a test.
+type SavedRequest struct {
+	Name        string
+	Collection  string
+	OperationID string
+	Values      RequestValues
+	Expect      *Expectations
+	Captures    []Capture
+}
+
+// Collection is a named group of saved requests, in the order they were
+// saved.
+type Collection struct {
+	Name     string
+	Requests []SavedRequest
+}
@@ -0,0 +1,102 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Expectations are the checks a saved request's response must pass when
+// it runs as a test. Zero fields check nothing. Expected header values and
+// string body values may use variables; written as "/pattern/" they are
+// regular expressions the actual value must match.
+type Expectations struct {
+	Status     string            // a code such as "201", or a class such as "2XX"
+	Headers    map[string]string // by header name
+	Body       []BodyExpectation
+	Schema     bool // the response must match the spec
+	MaxLatency time.Duration
+}
+
+// BodyExpectation compares what a JSONPath expression selects in a JSON
+// body with Value: the single value selected, or a list of them.
+type BodyExpectation struct {
+	Path  string
+	Value any
+}
+
+// Capture stores part of a response in a variable, for the requests after
+// it: a header's value, or the value a JSONPath expression selects in the
+// JSON body. Regex narrows that value, or the whole body when neither is
+// set, to its first group, or to the whole match when it has none.
+type Capture struct {
+	Variable string
+	Header   string
+	Path     string
+	Regex    string
+}
+
+// String describes the capture, as in "petId ← $.id" or
+// "token ← header Authorization /Bearer (.+)/".
+func (c Capture) String() string {
+	var from []string
+	switch {
+	case c.Header != "":
+		from = append(from, "header "+c.Header)
+	case c.Path != "":
+		from = append(from, c.Path)
+	default:
+		from = append(from, "body")
+	}
+	if c.Regex != "" {
+		from = append(from, "/"+c.Regex+"/")
+	}
+	return c.Variable + " ← " + strings.Join(from, " ")
+}
+
+// TestResult is the outcome of running one saved request as a test. Error
+// is set when no response could be checked, Skipped when the request was
+// not sent because a variable it uses was never captured. Captured values,
+// often tokens, are left out of reports.
+type TestResult struct {
+	Name        string            `json:"name"`
+	Collection  string            `json:"collection"`
+	OperationID string            `json:"operationId"`
+	Method      HTTPMethod        `json:"method,omitempty"`
+	URL         string            `json:"url,omitempty"`
+	Status      int               `json:"status,omitempty"`
+	Duration    time.Duration     `json:"duration"`
+	Failures    []string          `json:"failures,omitempty"`
+	Error       string            `json:"error,omitempty"`
+	Skipped     string            `json:"skipped,omitempty"`
+	Captured    map[string]string `json:"-"`
+}
+
+// Passed reports whether the request was sent and passed every check.
+func (r TestResult) Passed() bool {
+	return r.Error == "" && r.Skipped == "" && len(r.Failures) == 0
+}
+
+// TestRun is the outcome of running a suite of saved requests, in the
+// order they were given.
+type TestRun struct {
+	Environment string        `json:"environment,omitempty"`
+	Started     time.Time     `json:"started"`
+	Duration    time.Duration `json:"duration"`
+	Results     []TestResult  `json:"results"`
+}
+
+// Counts returns how many tests passed, failed (including errors) and were
+// skipped.
+func (r TestRun) Counts() (passed, failed, skipped int) {
+	for _, res := range r.Results {
+		switch {
+		case res.Skipped != "":
+			skipped++
+		case res.Passed():
+			passed++
+		default:
+			failed++
+		}
+	}
+	return passed, failed, skipped
+}
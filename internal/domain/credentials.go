@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Credentials are the secrets used for one security scheme. They come from
+// the user's configuration or process environment, never from the spec, and
+// may contain {{var}} references resolved against the active environment.
+type Credentials struct {
+	APIKey       string
+	Username     string
+	Password     string
+	Token        string // a bearer or OAuth2 access token, used as is
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+	Scopes       []string
+	TokenURL     string // overrides the tokenUrl of the scheme's flows
+	RedirectURL  string // loopback URL registered for logins; any free port when empty
+}
+
+// Token is an OAuth2 token obtained for a scheme.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitzero"` // zero when the server gave no lifetime
+}
+
+// LoginSession is an interactive authorization-code login waiting for the
+// user to sign in with their browser.
+type LoginSession interface {
+	// AuthURL is the identity provider page to open.
+	AuthURL() string
+	// Wait blocks until the provider redirects back, then exchanges the
+	// code for a token and stores it.
+	Wait(ctx context.Context) error
+	// Close stops waiting and releases the callback listener.
+	Close() error
+}
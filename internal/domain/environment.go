@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"maps"
+	"sort"
+	"strings"
+)
+
+// Environment is a named set of variables, such as baseUrl and token for
+// "staging", substituted into requests wherever {{name}} appears.
+type Environment struct {
+	Name      string
+	Variables map[string]string
+}
+
+// EscapeVariables escapes every {{ in text as \{{, so that substituting the
+// result gives text back. Values the user did not write, such as those taken
+// from a response, are escaped so a {{env:NAME}} in them cannot reveal a
+// secret.
+func EscapeVariables(text string) string {
+	return strings.ReplaceAll(text, "{{", `\{{`)
+}
+
+// Clone returns a copy of the environment that shares no map with it, for
+// use where the set may change meanwhile.
+func (e Environment) Clone() Environment {
+	e.Variables = maps.Clone(e.Variables)
+	return e
+}
+
+// EnvironmentSet holds every configured environment and the one to use when
+// none is chosen explicitly.
+type EnvironmentSet struct {
+	Default      string
+	Environments map[string]Environment
+}
+
+// Get returns the named environment.
+func (s *EnvironmentSet) Get(name string) (Environment, bool) {
+	if s == nil {
+		return Environment{}, false
+	}
+	env, ok := s.Environments[name]
+	return env, ok
+}
+
+// Names returns the environment names in sorted order.
+func (s *EnvironmentSet) Names() []string {
+	if s == nil {
+		return nil
+	}
+	names := make([]string, 0, len(s.Environments))
+	for name := range s.Environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetVariable sets a variable in the named environment, adding the
+// environment when there is none by that name. The value is escaped with
+// EscapeVariables, so it is used as it is rather than expanded. The
+// variables are copied rather than changed in place, so environments already
+// handed out keep their values.
+func (s *EnvironmentSet) SetVariable(env, name, value string) {
+	if s.Environments == nil {
+		s.Environments = map[string]Environment{}
+	}
+	e, ok := s.Environments[env]
+	if !ok {
+		e = Environment{Name: env}
+	}
+	vars := make(map[string]string, len(e.Variables)+1)
+	maps.Copy(vars, e.Variables)
+	vars[name] = EscapeVariables(value)
+	e.Variables = vars
+	s.Environments[env] = e
+}
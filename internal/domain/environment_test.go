@@ -0,0 +1,33 @@
+package domain_test
+
+import (
+	"testing"
+
+	"dazzle/internal/domain"
+)
+
+func TestEnvironmentSet_SetVariableCopiesVariables(t *testing.T) {
+	set := &domain.EnvironmentSet{Environments: map[string]domain.Environment{
+		"local": {Name: "local", Variables: map[string]string{"baseUrl": "http://localhost:8080"}},
+	}}
+	before, _ := set.Get("local")
+
+	set.SetVariable("local", "petId", "7")
+	set.SetVariable("session", "token", "abc")
+
+	if _, ok := before.Variables["petId"]; ok {
+		t.Error("expected an environment already read to keep its variables")
+	}
+	after, _ := set.Get("local")
+	if after.Variables["petId"] != "7" || after.Variables["baseUrl"] != "http://localhost:8080" {
+		t.Errorf("unexpected variables %v", after.Variables)
+	}
+	if session, ok := set.Get("session"); !ok || session.Name != "session" || session.Variables["token"] != "abc" {
+		t.Errorf("expected a session environment, got %+v", session)
+	}
+
+	set.SetVariable("local", "echo", "{{env:HOME}}")
+	if after, _ := set.Get("local"); after.Variables["echo"] != `\{{env:HOME}}` {
+		t.Errorf("expected the value escaped, got %q", after.Variables["echo"])
+	}
+}
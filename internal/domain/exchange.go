@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestValues holds the user-supplied inputs for an operation: the server
+// to call, a value per parameter keyed by name, and the raw request body.
+type RequestValues struct {
+	Server      string            `json:"server"`
+	Path        map[string]string `json:"path,omitempty"`
+	Query       map[string]string `json:"query,omitempty"`
+	Header      map[string]string `json:"header,omitempty"`
+	Cookie      map[string]string `json:"cookie,omitempty"`
+	ContentType string            `json:"contentType,omitempty"`
+	Body        string            `json:"body,omitempty"`
+}
+
+// EscapeVariables returns the values with each one escaped by
+// EscapeVariables, for values taken from a response or a pasted command
+// that are to be sent as they are.
+func (v RequestValues) EscapeVariables() RequestValues {
+	escape := func(m map[string]string) map[string]string {
+		if m == nil {
+			return nil
+		}
+		out := make(map[string]string, len(m))
+		for k, val := range m {
+			out[k] = EscapeVariables(val)
+		}
+		return out
+	}
+	v.Server = EscapeVariables(v.Server)
+	v.Path = escape(v.Path)
+	v.Query = escape(v.Query)
+	v.Header = escape(v.Header)
+	v.Cookie = escape(v.Cookie)
+	v.Body = EscapeVariables(v.Body)
+	return v
+}
+
+// HTTPRequest is a fully resolved request, ready to send.
+type HTTPRequest struct {
+	Method HTTPMethod
+	URL    string
+	Header http.Header
+	Body   []byte
+}
+
+// Clone returns a copy of r with its own headers, such as the request as
+// built, kept before credentials are added.
+func (r *HTTPRequest) Clone() *HTTPRequest {
+	out := *r
+	out.Header = r.Header.Clone()
+	return &out
+}
+
+// HTTPResponse is a response received from a server. Truncated is set
+// when the body was cut short at the client's limit.
+type HTTPResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	Truncated  bool
+	Duration   time.Duration
+	Timings    Timings
+}
+
+// Timings break a request's duration down by phase. Phases that did not
+// happen, such as DNS and connecting on a reused connection, are zero.
+type Timings struct {
+	DNS       time.Duration `json:"dns,omitempty"`
+	Connect   time.Duration `json:"connect,omitempty"`
+	TLS       time.Duration `json:"tls,omitempty"`
+	FirstByte time.Duration `json:"firstByte,omitempty"` // from sending until the first response byte
+	Total     time.Duration `json:"total"`
+}
+
+// HTTPClient sends requests over the network.
+type HTTPClient interface {
+	Do(ctx context.Context, req *HTTPRequest) (*HTTPResponse, error)
+}
@@ -0,0 +1,57 @@
+package domain
+
+// FuzzOptions configure a fuzzing run.
+type FuzzOptions struct {
+	Seed   uint64 // the same seed generates the same cases
+	Cases  int    // valid requests per operation
+	Server string // base URL; may use the environment's variables
+	Env    Environment
+}
+
+// FuzzCase is one generated request. Invalid cases break a single rule of
+// the operation's schemas, which Mutation describes, such as
+// "body $.name: required property missing"; valid cases have none.
+type FuzzCase struct {
+	Mutation string
+	Values   RequestValues
+}
+
+func (c FuzzCase) Valid() bool { return c.Mutation == "" }
+
+// FuzzFindingKind classifies what went wrong.
+type FuzzFindingKind string
+
+const (
+	// FuzzServerError is a 5xx response.
+	FuzzServerError FuzzFindingKind = "server error"
+	// FuzzSchemaViolation is a response that does not match the spec.
+	FuzzSchemaViolation FuzzFindingKind = "schema violation"
+	// FuzzAcceptedInvalid is a 2xx response to an invalid request.
+	FuzzAcceptedInvalid FuzzFindingKind = "accepted invalid input"
+)
+
+// FuzzFinding is a way an operation failed. Cases that fail the same way
+// are reported once, with the first of them shrunk to the smallest request
+// that still fails.
+type FuzzFinding struct {
+	Kind        FuzzFindingKind
+	OperationID string
+	Method      HTTPMethod
+	Path        string
+	Case        FuzzCase
+	Request     *HTTPRequest // the shrunk case, as sent before authentication
+	Status      int
+	Violations  []Violation // of the response, for schema violations
+	Count       int         // cases that failed this way
+	Shrinks     int         // steps taken to shrink the case
+}
+
+// FuzzReport is the outcome of a fuzzing run.
+type FuzzReport struct {
+	Seed       uint64
+	Operations int
+	Sent       int    // requests sent, shrinking included
+	Failed     int    // requests that got no response
+	LastError  string // why the last of those failed
+	Findings   []FuzzFinding
+}
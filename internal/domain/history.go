@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+// MaskedValue replaces secrets, such as credentials in headers, in
+// recorded requests.
+const MaskedValue = "••••"
+
+// HistoryEntry is one request sent from dazzle and its outcome.
+type HistoryEntry struct {
+	ID          string        `json:"id"`
+	Time        time.Time     `json:"time"`
+	OperationID string        `json:"operationId"`
+	Method      HTTPMethod    `json:"method"`
+	Path        string        `json:"path"`
+	Environment string        `json:"environment,omitempty"`
+	Values      RequestValues `json:"values"` // as entered, before substitution
+
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"requestHeader,omitempty"`
+	RequestBody   string      `json:"requestBody,omitempty"`
+
+	Status            int         `json:"status,omitempty"`
+	ResponseHeader    http.Header `json:"responseHeader,omitempty"`
+	ResponseBody      string      `json:"responseBody,omitempty"` // an excerpt
+	ResponseTruncated bool        `json:"responseTruncated,omitempty"`
+	Timings           Timings     `json:"timings"`
+	Error             string      `json:"error,omitempty"` // set when no response arrived
+}
+
+// ReplayValues returns the entry's values without masked ones, so
+// replaying it leaves credentials to the AuthService.
+func (e HistoryEntry) ReplayValues() RequestValues {
+	v := e.Values
+	v.Path = unmasked(v.Path)
+	v.Query = unmasked(v.Query)
+	v.Header = unmasked(v.Header)
+	v.Cookie = unmasked(v.Cookie)
+	return v
+}
+
+func unmasked(values map[string]string) map[string]string {
+	out := make(map[string]string, len(values))
+	for k, v := range values {
+		if v != MaskedValue {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// StatusClassError filters the history to requests that got no response.
+const StatusClassError = "error"
+
+// HistoryFilter narrows the history. Empty fields match everything.
+type HistoryFilter struct {
+	OperationID string
+	// StatusClass is "2xx" to "5xx", or StatusClassError.
+	StatusClass string
+}
@@ -0,0 +1,11 @@
+package domain
+
+// ImportedRequest is a request captured outside dazzle, such as a pasted
+// curl command, matched to an operation of the spec.
+type ImportedRequest struct {
+	Operation Operation
+	Values    RequestValues
+	// Mismatches are the parts of the request the spec does not describe,
+	// located like violations. Most were dropped from Values.
+	Mismatches []Violation
+}
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// MockRequest is a request received by the mock server. Path is the
+// escaped request path.
+type MockRequest struct {
+	Method HTTPMethod
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// MockResponse is the mock server's answer. OperationID names the
+// operation that handled the request, empty when none matched; Violations
+// are the reasons a request was rejected.
+//
+// Delay, Reset and BytesPerSecond are faults for the server to act out:
+// wait before answering, drop the connection instead of answering, or
+// send the body at that pace.
+type MockResponse struct {
+	StatusCode  int
+	Header      http.Header
+	Body        []byte
+	OperationID string
+	Violations  []Violation
+
+	Delay          time.Duration
+	Reset          bool
+	BytesPerSecond int
+}
+
+// Latency distributions.
+const (
+	LatencyFixed       = "fixed"
+	LatencyUniform     = "uniform"
+	LatencyNormal      = "normal"
+	LatencyExponential = "exponential"
+)
+
+// Latency is a distribution of response delays. A fixed delay is Mean;
+// uniform delays lie between Min and Max; normal ones spread around Mean
+// by StdDev and exponential ones average Mean. Max, when set, caps the
+// last two.
+type Latency struct {
+	Distribution string
+	Mean         time.Duration
+	StdDev       time.Duration
+	Min          time.Duration
+	Max          time.Duration
+}
+
+// ParseLatency reads the short forms of a latency: "200ms" is fixed and
+// "50ms-200ms" uniform.
+func ParseLatency(s string) (Latency, error) {
+	lo, hi, ranged := strings.Cut(strings.TrimSpace(s), "-")
+	from, err := time.ParseDuration(strings.TrimSpace(lo))
+	if err != nil {
+		return Latency{}, fmt.Errorf("latency %q: %w", s, err)
+	}
+	if !ranged {
+		return Latency{Distribution: LatencyFixed, Mean: from}, nil
+	}
+	to, err := time.ParseDuration(strings.TrimSpace(hi))
+	if err != nil {
+		return Latency{}, fmt.Errorf("latency %q: %w", s, err)
+	}
+	if to < from {
+		return Latency{}, fmt.Errorf("latency %q: the maximum is below the minimum", s)
+	}
+	return Latency{Distribution: LatencyUniform, Min: from, Max: to}, nil
+}
+
+// MockFaults make the mock misbehave for an operation. Rates are
+// percentages of requests: ErrorRate answers with one of ErrorCodes, or
+// any declared 4xx or 5xx response when there are none; ResetRate drops
+// the connection; SlowBodyRate sends the body at BytesPerSecond. At most
+// RateLimit requests are served per RateWindow, the rest get a 429 with
+// Retry-After.
+type MockFaults struct {
+	Latency        *Latency
+	ErrorRate      float64
+	ErrorCodes     []int
+	ResetRate      float64
+	SlowBodyRate   float64
+	BytesPerSecond int
+	RateLimit      int
+	RateWindow     time.Duration
+}
+
+// MockFaultConfig holds the faults of each operation by ID. Default
+// applies to operations without an entry of their own.
+type MockFaultConfig struct {
+	Default    MockFaults
+	Operations map[string]MockFaults
+}
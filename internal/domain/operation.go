@@ -1,6 +1,8 @@
 package domain
 
 // Operation represents a single API operation (path + method).
+// Security holds the effective requirements: the operation's own, or the
+// spec-wide default when the operation does not override it.
 type Operation struct {
 	ID          string
 	Path        string
@@ -11,6 +13,9 @@ type Operation struct {
 	Parameters  []Parameter
 	RequestBody *RequestBody
 	Responses   map[string]Response
+	Deprecated  bool
+	Extensions  map[string]any
+	Security    []SecurityRequirement
 }
 
 // HTTPMethod represents an HTTP request method.
@@ -26,9 +31,26 @@ const (
 	OPTIONS HTTPMethod = "OPTIONS"
 )
 
+// HTTPMethods lists the supported methods in display order.
+var HTTPMethods = []HTTPMethod{GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS}
+
 // OperationFilter defines criteria for filtering operations.
+// Method restricts results to a single method; Methods matches any of
+// several and is used by the method facets. Tags match if any tag is shared.
+//
+// Query matches path and summary only. Terms, produced by the structured
+// search syntax, must each appear somewhere in the operation: descriptions,
+// parameter names, schema property names, response codes or extensions.
+// Every other non-empty field must match for an operation to be included.
 type OperationFilter struct {
-	Query  string
-	Tags   []string
-	Method HTTPMethod
+	Query      string
+	Terms      []string
+	Tags       []string
+	Method     HTTPMethod
+	Methods    []HTTPMethod
+	Params     []string
+	BodyFields []string
+	Statuses   []string
+	Extensions []string
+	Deprecated *bool
 }
@@ -6,3 +6,49 @@ import "context"
 type SpecRepository interface {
 	Load(ctx context.Context, source string) (*Spec, error)
 }
+
+// EnvironmentRepository loads the configured environments.
+type EnvironmentRepository interface {
+	Load() (*EnvironmentSet, error)
+}
+
+// CredentialRepository loads configured credentials keyed by security
+// scheme name.
+type CredentialRepository interface {
+	Load() (map[string]Credentials, error)
+}
+
+// TokenRepository persists OAuth2 tokens per environment and scheme, so a
+// login survives restarts.
+type TokenRepository interface {
+	// LoadToken returns the stored token, or nil when there is none.
+	LoadToken(env, scheme string) (*Token, error)
+	SaveToken(env, scheme string, token Token) error
+}
+
+// SignerRepository loads the configured request signers, in the order they
+// are tried.
+type SignerRepository interface {
+	LoadSigners() ([]SignerConfig, error)
+}
+
+// AWSCredentialRepository resolves AWS credentials for a profile; an empty
+// profile means the default one.
+type AWSCredentialRepository interface {
+	LoadAWSCredentials(profile string) (AWSCredentials, error)
+}
+
+// HistoryRepository stores sent requests.
+type HistoryRepository interface {
+	Append(entry HistoryEntry) error
+	// List returns the stored entries, oldest first.
+	List() ([]HistoryEntry, error)
+}
+
+// CollectionRepository stores saved requests grouped into collections.
+type CollectionRepository interface {
+	LoadCollections() ([]Collection, error)
+	// SaveRequest adds req to its collection, replacing a request with the
+	// same name there.
+	SaveRequest(req SavedRequest) error
+}
@@ -8,6 +8,16 @@ type RequestBody struct {
 }
 
 // MediaType represents a media type with schema and example.
+// Example holds the single inline example; Examples the named ones.
 type MediaType struct {
-	Schema *Schema
+	Schema   *Schema
+	Example  any
+	Examples map[string]Example
+}
+
+// Example is a named example value.
+type Example struct {
+	Summary     string
+	Description string
+	Value       any
 }
@@ -5,10 +5,48 @@ type Response struct {
 	Description string
 	Content     map[string]MediaType
 	Headers     map[string]Header
+	Links       map[string]Link
+}
+
+// MatchResponse finds the response documented for a status code: the
+// code's own, then its class's ("2XX"), then the default.
+func MatchResponse(responses map[string]Response, code string) (Response, bool) {
+	key, ok := ResponseKey(responses, code)
+	return responses[key], ok
+}
+
+// ResponseKey is the key of the response MatchResponse picks.
+func ResponseKey(responses map[string]Response, code string) (string, bool) {
+	if _, ok := responses[code]; ok {
+		return code, true
+	}
+	if code != "" {
+		for _, class := range []string{code[:1] + "XX", code[:1] + "xx"} {
+			if _, ok := responses[class]; ok {
+				return class, true
+			}
+		}
+	}
+	_, ok := responses["default"]
+	return "default", ok
+}
+
+// Link describes an operation that can follow a response, and how to fill
+// in its request from the exchange. Parameter values and RequestBody are
+// constants or runtime expressions such as "$response.body#/id", and
+// parameter names may be qualified by location, as in "path.id".
+type Link struct {
+	OperationID  string
+	OperationRef string // "#/paths/~1pets~1{petId}/get", when there is no OperationID
+	Parameters   map[string]any
+	RequestBody  any
+	Description  string
+	Server       string
 }
 
 // Header represents a response header.
 type Header struct {
 	Description string
+	Required    bool
 	Schema      *Schema
 }
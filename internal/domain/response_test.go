@@ -0,0 +1,29 @@
+package domain_test
+
+import (
+	"testing"
+
+	"dazzle/internal/domain"
+)
+
+func TestMatchResponse(t *testing.T) {
+	responses := map[string]domain.Response{
+		"201":     {Description: "created"},
+		"2xx":     {Description: "success"},
+		"4XX":     {Description: "client error"},
+		"default": {Description: "error"},
+	}
+	for code, want := range map[string]string{
+		"201": "created",
+		"200": "success",
+		"404": "client error",
+		"500": "error",
+	} {
+		if got, ok := domain.MatchResponse(responses, code); !ok || got.Description != want {
+			t.Errorf("%s: expected %q, got %q", code, want, got.Description)
+		}
+	}
+	if _, ok := domain.MatchResponse(map[string]domain.Response{"200": {}}, "500"); ok {
+		t.Error("expected no response for an undocumented code")
+	}
+}
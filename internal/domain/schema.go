@@ -1,7 +1,12 @@
 package domain
 
-// Schema represents a JSON Schema definition.
+// Schema represents a JSON Schema definition. The constraint fields are
+// those checked when validating requests and responses; a nil bound means
+// unbounded. Example and Default are the schema's own sample values, nil
+// when absent. Ref is the reference the schema was reached through, such as
+// "#/components/schemas/Pet", empty for schemas defined in place.
 type Schema struct {
+	Ref         string
 	Type        SchemaType
 	Format      string
 	Description string
@@ -9,6 +14,19 @@ type Schema struct {
 	Properties  map[string]*Schema
 	Items       *Schema
 	Enum        []any
+	Nullable    bool
+	Example     any
+	Default     any
+
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum bool
+	ExclusiveMaximum bool
+	MinLength        int
+	MaxLength        *int
+	Pattern          string
+	MinItems         int
+	MaxItems         *int
 }
 
 // SchemaType represents the data type of a schema.
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+)
+
+// SecurityScheme describes one way an API authenticates requests.
+type SecurityScheme struct {
+	Type             SecuritySchemeType
+	Description      string
+	Name             string      // apiKey: the header, query or cookie name
+	In               ParameterIn // apiKey: where the key is sent
+	Scheme           string      // http: e.g. "basic" or "bearer"
+	BearerFormat     string
+	Flows            *OAuthFlows
+	OpenIDConnectURL string
+}
+
+// SupportsLogin reports whether a user can log in interactively for this
+// scheme: OAuth2 with an authorization-code flow, or OpenID Connect.
+func (s SecurityScheme) SupportsLogin() bool {
+	switch s.Type {
+	case SecuritySchemeOAuth2:
+		return s.Flows != nil && s.Flows.AuthorizationCode != nil
+	case SecuritySchemeOpenIDConnect:
+		return s.OpenIDConnectURL != ""
+	}
+	return false
+}
+
+// SecuritySchemeType is the kind of security scheme.
+type SecuritySchemeType string
+
+const (
+	SecuritySchemeAPIKey        SecuritySchemeType = "apiKey"
+	SecuritySchemeHTTP          SecuritySchemeType = "http"
+	SecuritySchemeOAuth2        SecuritySchemeType = "oauth2"
+	SecuritySchemeOpenIDConnect SecuritySchemeType = "openIdConnect"
+	SecuritySchemeMutualTLS     SecuritySchemeType = "mutualTLS"
+)
+
+// OAuthFlows lists the OAuth2 flows a scheme supports.
+type OAuthFlows struct {
+	Implicit          *OAuthFlow
+	Password          *OAuthFlow
+	ClientCredentials *OAuthFlow
+	AuthorizationCode *OAuthFlow
+}
+
+// OAuthFlow holds the endpoints and scopes of a single OAuth2 flow.
+type OAuthFlow struct {
+	AuthorizationURL string
+	TokenURL         string
+	RefreshURL       string
+	Scopes           map[string]string
+}
+
+// SecurityRequirement maps scheme names to the scopes required from them.
+// All schemes in one requirement apply together; an operation's list of
+// requirements are alternatives, and an empty requirement means anonymous
+// access is allowed.
+type SecurityRequirement map[string][]string
+
+// Authorizer adds credentials to a built request, as every request is sent:
+// the operation's security schemes first, which may fetch a token, then the
+// signer, whose signature covers the headers added before it. Either
+// service may be nil.
+type Authorizer struct {
+	Auth   AuthService
+	Signer SigningService
+}
+
+// Authorize adds req's credentials, saying which step failed if one does.
+func (a Authorizer) Authorize(ctx context.Context, schemes map[string]SecurityScheme, op Operation, env Environment, req *HTTPRequest) error {
+	if a.Auth != nil {
+		if err := a.Auth.Authenticate(ctx, schemes, op, env, req); err != nil {
+			return fmt.Errorf("authenticating: %w", err)
+		}
+	}
+	if a.Signer != nil {
+		if err := a.Signer.Sign(req, env); err != nil {
+			return fmt.Errorf("signing: %w", err)
+		}
+	}
+	return nil
+}
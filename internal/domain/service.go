@@ -13,4 +13,163 @@ type OperationService interface {
 	ListOperations(spec *Spec) []Operation
 	FilterOperations(operations []Operation, filter OperationFilter) []Operation
 	SortOperations(operations []Operation) []Operation
+	// ParseQuery parses the structured search syntax, e.g.
+	// `method:post tag:billing status:409 "refund"`, into a filter.
+	ParseQuery(query string) OperationFilter
+	// SearchOperations filters operations and orders them by relevance to
+	// the filter's terms, most relevant first.
+	SearchOperations(operations []Operation, filter OperationFilter) []Operation
+}
+
+// RequestService turns user input into HTTP requests and sends them.
+type RequestService interface {
+	BuildRequest(op Operation, values RequestValues) (*HTTPRequest, error)
+	Send(ctx context.Context, req *HTTPRequest) (*HTTPResponse, error)
+}
+
+// ValidationService checks live traffic against an operation's schemas.
+type ValidationService interface {
+	// ValidateRequest checks user-entered values before they are sent.
+	// Parameter violations are located as "<in> <name>", e.g. "query limit";
+	// body violations by JSONPath.
+	ValidateRequest(op Operation, values RequestValues) []Violation
+	ValidateResponse(op Operation, resp *HTTPResponse) []Violation
+}
+
+// EnvironmentService loads environments and substitutes their variables.
+type EnvironmentService interface {
+	LoadEnvironments() (*EnvironmentSet, error)
+	// Substitute replaces {{name}} references in text with the
+	// environment's variables; \{{ stands for a literal {{. Unknown names
+	// are an error.
+	Substitute(env Environment, text string) (string, error)
+	// SubstituteValues applies Substitute to the server, every parameter
+	// and header value, and the body.
+	SubstituteValues(env Environment, values RequestValues) (RequestValues, error)
+}
+
+// AuthService authenticates requests according to an operation's security
+// requirements.
+type AuthService interface {
+	// Authenticate adds credentials to req for the first of op's security
+	// requirements whose schemes all have credentials, fetching OAuth2
+	// tokens as needed. A request without usable credentials is left
+	// unchanged.
+	Authenticate(ctx context.Context, schemes map[string]SecurityScheme, op Operation, env Environment, req *HTTPRequest) error
+	// BeginLogin starts an authorization-code login with PKCE for the named
+	// scheme, listening on a loopback address for the provider's redirect.
+	BeginLogin(ctx context.Context, schemes map[string]SecurityScheme, name string, env Environment) (LoginSession, error)
+}
+
+// SigningService signs fully built requests for gateways that authenticate
+// the request itself, such as AWS SigV4 or a shared-secret HMAC.
+type SigningService interface {
+	// Sign applies the first configured signer whose hosts match the
+	// request. A request no signer matches is left unchanged.
+	Sign(req *HTTPRequest, env Environment) error
+}
+
+// HistoryService records every request sent and lists them for replay.
+type HistoryService interface {
+	// Record stores a request and its response, or the error when none
+	// arrived. Credentials in headers and parameters are masked.
+	Record(op Operation, env string, values RequestValues, req *HTTPRequest, resp *HTTPResponse, sendErr error) (HistoryEntry, error)
+	// List returns the matching entries, newest first.
+	List(filter HistoryFilter) ([]HistoryEntry, error)
+}
+
+// CollectionService manages saved requests.
+type CollectionService interface {
+	LoadCollections() ([]Collection, error)
+	SaveRequest(req SavedRequest) error
+	// ByOperation groups the collections' requests by operation ID, in
+	// collection order.
+	ByOperation(collections []Collection) map[string][]SavedRequest
+}
+
+// SnippetService renders requests as code that reproduces them.
+type SnippetService interface {
+	// Snippets renders req in every supported language, in a fixed order.
+	Snippets(req *HTTPRequest) []Snippet
+}
+
+// ImportService turns requests captured elsewhere into builder values.
+type ImportService interface {
+	// ImportCurl parses a curl command line and matches its method and URL
+	// against the operations' path templates, below any of the servers'
+	// base paths.
+	ImportCurl(command string, ops []Operation, servers []Server) (ImportedRequest, error)
+}
+
+// ExampleService synthesizes example values from schemas, for where the
+// spec has none.
+type ExampleService interface {
+	// Generate returns a value valid against schema, built from plain Go
+	// values (map[string]any, []any, string, float64, int64, bool). The
+	// same schema always gives the same value for the service's seed.
+	Generate(schema *Schema) any
+}
+
+// MockService answers requests on behalf of a spec's operations.
+type MockService interface {
+	// Respond routes req to an operation by its path template, validates
+	// it, and answers with the response the request's Prefer header picks
+	// ("code=404", "example=name", "dynamic=true"), by default the first
+	// success response, with its example or one generated from its schema.
+	// Invalid requests get a 400 listing the violations.
+	Respond(req MockRequest) MockResponse
+}
+
+// TrafficService checks live traffic, such as a proxy sees, against a
+// spec.
+type TrafficService interface {
+	// Check matches the exchange to an operation by method and path
+	// template and fills in its request and response violations. An
+	// exchange no operation matches has a single violation saying so.
+	Check(ex RecordedExchange) RecordedExchange
+	// Mask copies the exchange with credentials masked, as in the
+	// history, for writing it to a recording.
+	Mask(ex RecordedExchange) RecordedExchange
+	// Compare reports what the exchanges show that the spec leaves out, and
+	// the declared response fields they never showed. Only responses that
+	// were seen are checked for unobserved fields.
+	Compare(exchanges []RecordedExchange) TrafficReport
+	// Overlay turns a report's endpoints and undeclared fields into an
+	// overlay adding them to the spec, for review.
+	Overlay(report TrafficReport) Overlay
+}
+
+// TestService runs saved requests as contract tests.
+type TestService interface {
+	// Run sends the requests against env, at most parallel at once, and
+	// checks each response against the request's expectations. A request
+	// using variables captured by earlier ones waits for them, and is
+	// skipped when they were not captured.
+	Run(ctx context.Context, spec *Spec, requests []SavedRequest, env Environment, parallel int) TestRun
+}
+
+// ChainService carries values from one response into the requests after
+// it.
+type ChainService interface {
+	// Capture extracts each capture's value from resp, by variable. The
+	// captures that found nothing are reported in the error; the others are
+	// still returned.
+	Capture(captures []Capture, resp *HTTPResponse) (map[string]string, error)
+	// FollowLink finds link's operation among ops and fills in its request
+	// from the values and request that got resp.
+	FollowLink(link Link, ops []Operation, values RequestValues, req *HTTPRequest, resp *HTTPResponse) (Operation, RequestValues, error)
+}
+
+// FuzzService sends generated requests to a server to find where it breaks
+// its spec.
+type FuzzService interface {
+	// Cases generates n valid requests for op from seed, then one invalid
+	// request for every rule of its schemas that can be broken: missing
+	// required values, wrong types, values past their bounds, oversized
+	// strings, unknown enum values and malformed formats.
+	Cases(op Operation, seed uint64, n int) []FuzzCase
+	// Fuzz sends the cases of each operation and reports 5xx responses,
+	// responses that violate the spec and invalid requests that succeed.
+	// It stops early, with what it found, when ctx is cancelled.
+	Fuzz(ctx context.Context, spec *Spec, ops []Operation, opts FuzzOptions) (FuzzReport, error)
 }
@@ -0,0 +1,42 @@
+package domain
+
+// Signer types.
+const (
+	SignerSigV4 = "aws-sigv4"
+	SignerHMAC  = "hmac"
+)
+
+// SignerConfig configures a request signer. Signers apply to requests whose
+// host matches one of Hosts (shell patterns such as *.execute-api.*.amazonaws.com),
+// or to every request when Hosts is empty. String fields may contain
+// {{var}} references resolved against the active environment.
+type SignerConfig struct {
+	Name  string
+	Type  string // SignerSigV4 or SignerHMAC
+	Hosts []string
+
+	// AWS Signature Version 4. Credentials come from the process
+	// environment or the shared credentials file's Profile.
+	Service string
+	Region  string // defaults to AWS_REGION or the profile's region
+	Profile string // defaults to AWS_PROFILE, then "default"
+
+	// HMAC.
+	KeyID           string
+	Secret          string
+	Algorithm       string   // hmac-sha1, hmac-sha256 (default) or hmac-sha512
+	Headers         []string // signed headers, e.g. (request-target), host, date
+	Canonical       string   // template for the signed string, replacing Headers' lines
+	Header          string   // header receiving the signature; default Authorization
+	Format          string   // template for that header's value
+	Encoding        string   // base64 (default) or hex
+	TimestampHeader string   // also send the signing time, in Unix seconds, in this header
+}
+
+// AWSCredentials are the keys and region of an AWS profile.
+type AWSCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Region          string
+}
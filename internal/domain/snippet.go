@@ -0,0 +1,25 @@
+package domain
+
+// SnippetLanguage names a language or tool a request can be rendered in.
+type SnippetLanguage string
+
+const (
+	SnippetCurl       SnippetLanguage = "curl"
+	SnippetHTTPie     SnippetLanguage = "httpie"
+	SnippetGo         SnippetLanguage = "go"
+	SnippetPython     SnippetLanguage = "python"
+	SnippetJavaScript SnippetLanguage = "javascript"
+)
+
+// Snippet is a request rendered as code.
+type Snippet struct {
+	Language SnippetLanguage
+	Label    string // display name, e.g. "cURL"
+	Syntax   string // lexer name for highlighting
+	Code     string
+}
+
+// Clipboard copies text to the user's clipboard.
+type Clipboard interface {
+	Copy(text string) error
+}
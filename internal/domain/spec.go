@@ -2,9 +2,10 @@ package domain
 
 // Spec represents a parsed OpenAPI specification.
 type Spec struct {
-	Info       SpecInfo
-	Servers    []Server
-	Operations []Operation
+	Info            SpecInfo
+	Servers         []Server
+	Operations      []Operation
+	SecuritySchemes map[string]SecurityScheme
 }
 
 // SpecInfo contains metadata about the API.
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"net/http"
+	"time"
+)
+
+// RecordedExchange is a request that passed through the proxy and the
+// response to it, matched to an operation and checked against the spec.
+// OperationID is empty when no operation matches; Error is set when the
+// upstream gave no response. Bodies are recorded up to a limit, decoded
+// when the upstream compressed them.
+type RecordedExchange struct {
+	ID          int           `json:"id"`
+	Time        time.Time     `json:"time"`
+	Duration    time.Duration `json:"duration"`
+	OperationID string        `json:"operationId,omitempty"`
+
+	Method           HTTPMethod  `json:"method"`
+	URL              string      `json:"url"` // the path and query as received
+	RequestHeader    http.Header `json:"requestHeader,omitempty"`
+	RequestBody      string      `json:"requestBody,omitempty"`
+	RequestTruncated bool        `json:"requestTruncated,omitempty"`
+
+	Status            int         `json:"status,omitempty"`
+	ResponseHeader    http.Header `json:"responseHeader,omitempty"`
+	ResponseBody      string      `json:"responseBody,omitempty"`
+	ResponseTruncated bool        `json:"responseTruncated,omitempty"`
+	Error             string      `json:"error,omitempty"`
+
+	RequestViolations  []Violation `json:"requestViolations,omitempty"`
+	ResponseViolations []Violation `json:"responseViolations,omitempty"`
+}
+
+// Violations counts the exchange's request and response violations.
+func (e RecordedExchange) Violations() int {
+	return len(e.RequestViolations) + len(e.ResponseViolations)
+}
+
+// TrafficReport compares recorded traffic with the spec: the endpoints it
+// does not describe, the response fields it does not declare and the
+// declared fields that never appeared. Fields are located like violations,
+// with "[*]" standing for every element of an array.
+type TrafficReport struct {
+	Exchanges        int
+	Endpoints        []ObservedEndpoint
+	UndeclaredFields []FieldFinding
+	UnobservedFields []FieldFinding
+}
+
+// ObservedEndpoint is an endpoint seen in traffic that the spec lacks. Path
+// is a template below the spec's servers, with segments that look like IDs
+// turned into parameters; Known is set when the spec has the path for other
+// methods.
+type ObservedEndpoint struct {
+	Method    HTTPMethod
+	Path      string
+	Known     bool
+	Count     int
+	Query     []string // names of the query parameters seen
+	Request   *ObservedBody
+	Responses map[string]ObservedBody // by status code
+}
+
+// ObservedBody is the media type a body was sent as and, for JSON, a
+// schema covering every value seen.
+type ObservedBody struct {
+	MediaType string
+	Schema    *Schema
+}
+
+// FieldFinding is a property of an operation's response body: the declared
+// response (by status code key) and its media type, the property's location
+// in the body, and SchemaPath, the JSONPath of the schema in the spec
+// document that declares it or would. Schema and Count describe the values
+// seen.
+type FieldFinding struct {
+	OperationID string
+	Method      HTTPMethod
+	Path        string
+	Status      string
+	MediaType   string
+	Field       string
+	Property    string
+	SchemaPath  string
+	Schema      *Schema
+	Count       int
+}
+
+// Overlay is an OpenAPI Overlay document: each action merges Update into
+// the parts of the spec its JSONPath Target selects.
+type Overlay struct {
+	Title   string
+	Version string
+	Actions []OverlayAction
+}
+
+// OverlayAction updates the nodes at Target. Update is a document tree of
+// maps, slices and scalars.
+type OverlayAction struct {
+	Target      string
+	Description string
+	Update      any
+}
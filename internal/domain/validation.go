@@ -0,0 +1,29 @@
+package domain
+
+import "strconv"
+
+// Violation is a disagreement between a value and the spec. Path locates
+// it: a JSONPath such as "$.items[3].price" for body values, otherwise a
+// short label such as "status" or "header X-Rate-Limit".
+type Violation struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	return v.Path + ": " + v.Message
+}
+
+// ParameterPath is the violation path for a request parameter, e.g.
+// "query limit".
+func ParameterPath(in ParameterIn, name string) string {
+	return string(in) + " " + name
+}
+
+// Plural counts n of a noun in messages, as in "1 item" or "3 items".
+func Plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return strconv.Itoa(n) + " " + noun + "s"
+}
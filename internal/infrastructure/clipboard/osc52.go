@@ -0,0 +1,44 @@
+// Package clipboard copies text to the user's clipboard through the
+// terminal, so copying works over SSH.
+package clipboard
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// OSC52 writes the OSC 52 escape sequence, which asks the terminal to set
+// its clipboard. Inside tmux or GNU screen the sequence is wrapped so the
+// multiplexer passes it through to the outer terminal.
+type OSC52 struct {
+	w      io.Writer
+	getenv func(string) string
+}
+
+// NewOSC52 writes to w, normally the terminal the program runs in.
+func NewOSC52(w io.Writer) *OSC52 {
+	return &OSC52{w: w, getenv: os.Getenv}
+}
+
+func (c *OSC52) Copy(text string) error {
+	if _, err := io.WriteString(c.w, Sequence(text, c.getenv("TMUX") != "", strings.HasPrefix(c.getenv("TERM"), "screen"))); err != nil {
+		return fmt.Errorf("copying to clipboard: %w", err)
+	}
+	return nil
+}
+
+// Sequence returns the escape sequence setting the clipboard to text,
+// wrapped for tmux or screen.
+func Sequence(text string, tmux, screen bool) string {
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	switch {
+	case tmux:
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case screen:
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
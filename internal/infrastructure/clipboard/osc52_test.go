@@ -0,0 +1,39 @@
+package clipboard_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/infrastructure/clipboard"
+)
+
+func TestSequence(t *testing.T) {
+	tests := []struct {
+		name         string
+		tmux, screen bool
+		want         string
+	}{
+		{"plain", false, false, "\x1b]52;c;aGVsbG8=\x07"},
+		{"tmux", true, false, "\x1bPtmux;\x1b\x1b]52;c;aGVsbG8=\x07\x1b\\"},
+		{"screen", false, true, "\x1bP\x1b]52;c;aGVsbG8=\x07\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := clipboard.Sequence("hello", tt.tmux, tt.screen); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOSC52_Copy(t *testing.T) {
+	t.Setenv("TMUX", "")
+	t.Setenv("TERM", "xterm-256color")
+	var b strings.Builder
+	if err := clipboard.NewOSC52(&b).Copy("curl 'https://api.example.com'"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(b.String(), "\x1b]52;c;") || !strings.HasSuffix(b.String(), "\x07") {
+		t.Errorf("expected an OSC 52 sequence, got %q", b.String())
+	}
+}
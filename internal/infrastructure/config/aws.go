@@ -0,0 +1,127 @@
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"dazzle/internal/domain"
+)
+
+// AWSCredentialStore resolves AWS credentials much as the AWS CLI does:
+// AWS_ACCESS_KEY_ID and friends when set and no profile was named,
+// otherwise the profile's keys in the shared credentials file. The region comes from AWS_REGION,
+// AWS_DEFAULT_REGION or the profile's entry in the shared config file.
+type AWSCredentialStore struct{}
+
+func NewAWSCredentialStore() *AWSCredentialStore {
+	return &AWSCredentialStore{}
+}
+
+func (s *AWSCredentialStore) LoadAWSCredentials(profile string) (domain.AWSCredentials, error) {
+	var creds domain.AWSCredentials
+	explicit := profile != ""
+	if !explicit {
+		profile = s.env("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	// Keys in the environment win unless the signer names a profile.
+	if id := s.env("AWS_ACCESS_KEY_ID"); id != "" && !explicit {
+		creds.AccessKeyID = id
+		creds.SecretAccessKey = s.env("AWS_SECRET_ACCESS_KEY")
+		creds.SessionToken = s.env("AWS_SESSION_TOKEN")
+	} else {
+		path, err := s.awsFile("AWS_SHARED_CREDENTIALS_FILE", "credentials")
+		if err != nil {
+			return creds, err
+		}
+		section, err := readINISection(path, profile)
+		if err != nil {
+			return creds, err
+		}
+		creds.AccessKeyID = section["aws_access_key_id"]
+		creds.SecretAccessKey = section["aws_secret_access_key"]
+		creds.SessionToken = section["aws_session_token"]
+		if creds.AccessKeyID == "" {
+			return creds, fmt.Errorf("no AWS credentials for profile %q (set AWS_ACCESS_KEY_ID or add it to %s)", profile, path)
+		}
+	}
+
+	creds.Region = s.env("AWS_REGION")
+	if creds.Region == "" {
+		creds.Region = s.env("AWS_DEFAULT_REGION")
+	}
+	if creds.Region == "" {
+		path, err := s.awsFile("AWS_CONFIG_FILE", "config")
+		if err != nil {
+			return creds, err
+		}
+		name := "profile " + profile
+		if profile == "default" {
+			name = "default"
+		}
+		section, err := readINISection(path, name)
+		if err != nil {
+			return creds, err
+		}
+		creds.Region = section["region"]
+	}
+	return creds, nil
+}
+
+func (s *AWSCredentialStore) env(name string) string {
+	return os.Getenv(name)
+}
+
+// awsFile returns the file named by the environment variable, or
+// ~/.aws/<name>.
+func (s *AWSCredentialStore) awsFile(envVar, name string) (string, error) {
+	if path := s.env(envVar); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating home directory: %w", err)
+	}
+	return filepath.Join(home, ".aws", name), nil
+}
+
+// readINISection returns the keys of one [section] of an INI file. A
+// missing file or section is empty.
+func readINISection(path, section string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer f.Close()
+
+	values := make(map[string]string)
+	in := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			in = strings.TrimSpace(line[1:len(line)-1]) == section
+		case in:
+			if k, v, ok := strings.Cut(line, "="); ok {
+				values[strings.ToLower(strings.TrimSpace(k))] = strings.TrimSpace(v)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return values, nil
+}
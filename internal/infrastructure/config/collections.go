@@ -0,0 +1,315 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"dazzle/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// collectionsDir is the directory of collection files under ProjectDir.
+const collectionsDir = "collections"
+
+// collectionDoc is the on-disk format of one collection, named after its
+// file:
+//
+//	requests:
+//	  - name: Rex
+//	    operationId: getPet
+//	    server: "{{baseUrl}}"
+//	    path:
+//	      petId: "7"
+//	  - name: New pet
+//	    operationId: createPet
+//	    contentType: application/json
+//	    body: |
+//	      {"name": "Rex"}
+//	    expect:
+//	      status: 201
+//	      headers:
+//	        Content-Type: /^application\/json/
+//	      body:
+//	        $.name: Rex
+//	      schema: true
+//	      maxLatency: 500ms
+//	    capture:
+//	      - variable: petId
+//	        jsonpath: $.id
+//	      - variable: petPath
+//	        header: Location
+//	        regex: /pets/\d+$
+type collectionDoc struct {
+	Requests []savedRequestEntry `yaml:"requests"`
+}
+
+type savedRequestEntry struct {
+	Name        string            `yaml:"name"`
+	OperationID string            `yaml:"operationId"`
+	Server      string            `yaml:"server,omitempty"`
+	Path        map[string]string `yaml:"path,omitempty"`
+	Query       map[string]string `yaml:"query,omitempty"`
+	Header      map[string]string `yaml:"header,omitempty"`
+	Cookie      map[string]string `yaml:"cookie,omitempty"`
+	ContentType string            `yaml:"contentType,omitempty"`
+	Body        string            `yaml:"body,omitempty"`
+	Expect      *expectEntry      `yaml:"expect,omitempty"`
+	Capture     []captureEntry    `yaml:"capture,omitempty"`
+}
+
+type expectEntry struct {
+	Status     string            `yaml:"status,omitempty"`
+	Headers    map[string]string `yaml:"headers,omitempty"`
+	Body       bodyExpectations  `yaml:"body,omitempty"`
+	Schema     bool              `yaml:"schema,omitempty"`
+	MaxLatency duration          `yaml:"maxLatency,omitempty"`
+}
+
+type captureEntry struct {
+	Variable string `yaml:"variable"`
+	Header   string `yaml:"header,omitempty"`
+	JSONPath string `yaml:"jsonpath,omitempty"`
+	Regex    string `yaml:"regex,omitempty"`
+}
+
+// bodyExpectations is a mapping of JSONPath expressions to expected values,
+// kept in file order.
+type bodyExpectations []domain.BodyExpectation
+
+func (b *bodyExpectations) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected a mapping of JSONPath expressions to values", node.Line)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		var value any
+		if err := node.Content[i+1].Decode(&value); err != nil {
+			return err
+		}
+		*b = append(*b, domain.BodyExpectation{Path: node.Content[i].Value, Value: value})
+	}
+	return nil
+}
+
+func (b bodyExpectations) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, e := range b {
+		var value yaml.Node
+		if err := value.Encode(e.Value); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: e.Path}, &value)
+	}
+	return node, nil
+}
+
+type duration time.Duration
+
+func (d *duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+func (d duration) MarshalYAML() (any, error) {
+	return time.Duration(d).String(), nil
+}
+
+// CollectionStore keeps each collection in <dir>/<name>.yaml, meant to be
+// committed alongside the spec.
+type CollectionStore struct {
+	dir string
+}
+
+func NewCollectionStore(dir string) *CollectionStore {
+	return &CollectionStore{dir: dir}
+}
+
+// DefaultCollectionDir is .dazzle/collections in the working directory.
+func DefaultCollectionDir() string {
+	return filepath.Join(ProjectDir, collectionsDir)
+}
+
+// LoadCollections reads every *.yaml file in the directory, sorted by name.
+// A missing directory means no collections.
+func (s *CollectionStore) LoadCollections() ([]domain.Collection, error) {
+	files, err := filepath.Glob(filepath.Join(s.dir, "*.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var collections []domain.Collection
+	for _, path := range files {
+		name := strings.TrimSuffix(filepath.Base(path), ".yaml")
+		doc, err := s.read(path)
+		if err != nil {
+			return nil, err
+		}
+		c := domain.Collection{Name: name}
+		for _, e := range doc.Requests {
+			req, err := e.toDomain(name)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			c.Requests = append(c.Requests, req)
+		}
+		collections = append(collections, c)
+	}
+	return collections, nil
+}
+
+func (s *CollectionStore) SaveRequest(req domain.SavedRequest) error {
+	path, err := s.path(req.Collection)
+	if err != nil {
+		return err
+	}
+	doc, err := s.read(path)
+	if err != nil {
+		return err
+	}
+
+	entry := fromSavedRequest(req)
+	replaced := false
+	for i, e := range doc.Requests {
+		if e.Name == req.Name {
+			// The request builder knows nothing of expectations, and only
+			// of the captures it was given or added, so a request saved
+			// from it keeps the ones it had.
+			if entry.Expect == nil {
+				entry.Expect = e.Expect
+			}
+			if entry.Capture == nil {
+				entry.Capture = e.Capture
+			}
+			doc.Requests[i] = entry
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		doc.Requests = append(doc.Requests, entry)
+	}
+
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return fmt.Errorf("creating %s: %w", s.dir, err)
+	}
+	return writeFileAtomic(path, data, 0o644)
+}
+
+func (s *CollectionStore) read(path string) (collectionDoc, error) {
+	var doc collectionDoc
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return doc, nil
+	}
+	if err != nil {
+		return doc, fmt.Errorf("reading %s: %w", path, err)
+	}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return doc, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// path returns the file for a collection, refusing names that would
+// escape the directory.
+func (s *CollectionStore) path(collection string) (string, error) {
+	if collection == "" || collection != filepath.Base(collection) || strings.HasPrefix(collection, ".") {
+		return "", fmt.Errorf("invalid collection name %q", collection)
+	}
+	return filepath.Join(s.dir, collection+".yaml"), nil
+}
+
+func (e savedRequestEntry) toDomain(collection string) (domain.SavedRequest, error) {
+	req := domain.SavedRequest{
+		Name:        e.Name,
+		Collection:  collection,
+		OperationID: e.OperationID,
+		Values: domain.RequestValues{
+			Server:      e.Server,
+			Path:        e.Path,
+			Query:       e.Query,
+			Header:      e.Header,
+			Cookie:      e.Cookie,
+			ContentType: e.ContentType,
+			Body:        e.Body,
+		},
+	}
+	if x := e.Expect; x != nil {
+		req.Expect = &domain.Expectations{
+			Status:     x.Status,
+			Headers:    x.Headers,
+			Body:       x.Body,
+			Schema:     x.Schema,
+			MaxLatency: time.Duration(x.MaxLatency),
+		}
+	}
+	for _, c := range e.Capture {
+		if c.Variable == "" || (c.Header != "" && c.JSONPath != "") || (c.Header == "" && c.JSONPath == "" && c.Regex == "") {
+			return req, fmt.Errorf("request %q: a capture needs a variable and a header, a jsonpath or a regex", e.Name)
+		}
+		if _, err := regexp.Compile(c.Regex); err != nil {
+			return req, fmt.Errorf("request %q: capturing %s: %w", e.Name, c.Variable, err)
+		}
+		req.Captures = append(req.Captures, domain.Capture{Variable: c.Variable, Header: c.Header, Path: c.JSONPath, Regex: c.Regex})
+	}
+	return req, nil
+}
+
+// fromSavedRequest drops empty parameters, which only restate the
+// builder's defaults, to keep files short.
+func fromSavedRequest(r domain.SavedRequest) savedRequestEntry {
+	e := savedRequestEntry{
+		Name:        r.Name,
+		OperationID: r.OperationID,
+		Server:      r.Values.Server,
+		Path:        nonEmpty(r.Values.Path),
+		Query:       nonEmpty(r.Values.Query),
+		Header:      nonEmpty(r.Values.Header),
+		Cookie:      nonEmpty(r.Values.Cookie),
+		ContentType: r.Values.ContentType,
+		Body:        r.Values.Body,
+	}
+	if x := r.Expect; x != nil {
+		e.Expect = &expectEntry{
+			Status:     x.Status,
+			Headers:    x.Headers,
+			Body:       x.Body,
+			Schema:     x.Schema,
+			MaxLatency: duration(x.MaxLatency),
+		}
+	}
+	for _, c := range r.Captures {
+		e.Capture = append(e.Capture, captureEntry{Variable: c.Variable, Header: c.Header, JSONPath: c.Path, Regex: c.Regex})
+	}
+	return e
+}
+
+func nonEmpty(m map[string]string) map[string]string {
+	var out map[string]string
+	for k, v := range m {
+		if v == "" {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[k] = v
+	}
+	return out
+}
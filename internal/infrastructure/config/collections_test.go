@@ -0,0 +1,148 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/config"
+)
+
+func TestCollectionStore_SaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), ".dazzle", "collections")
+	store := config.NewCollectionStore(dir)
+
+	rex := domain.SavedRequest{
+		Name: "Rex", Collection: "pets", OperationID: "getPet",
+		Values: domain.RequestValues{Server: "{{baseUrl}}", Path: map[string]string{"petId": "7"}, Query: map[string]string{"fields": ""}},
+	}
+	create := domain.SavedRequest{
+		Name: "New pet", Collection: "pets", OperationID: "createPet",
+		Values: domain.RequestValues{ContentType: "application/json", Body: "{\n  \"name\": \"Rex\"\n}"},
+	}
+	for _, r := range []domain.SavedRequest{rex, create} {
+		if err := store.SaveRequest(r); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Saving under an existing name replaces that request in place.
+	rex.Values.Path["petId"] = "8"
+	if err := store.SaveRequest(rex); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "pets.yaml"))
+	if err != nil {
+		t.Fatalf("expected pets.yaml: %v", err)
+	}
+	if text := string(data); !strings.Contains(text, "operationId: getPet") || !strings.Contains(text, "body: |-") || strings.Contains(text, "fields") {
+		t.Errorf("expected readable YAML without empty parameters, got:\n%s", text)
+	}
+
+	collections, err := store.LoadCollections()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(collections) != 1 || collections[0].Name != "pets" || len(collections[0].Requests) != 2 {
+		t.Fatalf("unexpected collections %+v", collections)
+	}
+	got := collections[0].Requests
+	if got[0].Name != "Rex" || got[0].Collection != "pets" || got[0].Values.Path["petId"] != "8" || got[0].Values.Server != "{{baseUrl}}" {
+		t.Errorf("unexpected first request %+v", got[0])
+	}
+	if got[1].Values.Body != create.Values.Body {
+		t.Errorf("expected the body to round-trip, got %q", got[1].Values.Body)
+	}
+}
+
+func TestCollectionStore_RejectsUnsafeNames(t *testing.T) {
+	store := config.NewCollectionStore(t.TempDir())
+	for _, name := range []string{"../escape", ".hidden", "a/b"} {
+		if err := store.SaveRequest(domain.SavedRequest{Name: "x", Collection: name, OperationID: "op"}); err == nil {
+			t.Errorf("expected %q to be refused", name)
+		}
+	}
+}
+
+func TestCollectionStore_MissingDirectory(t *testing.T) {
+	collections, err := config.NewCollectionStore(filepath.Join(t.TempDir(), "missing")).LoadCollections()
+	if err != nil || len(collections) != 0 {
+		t.Errorf("expected no collections, got %v, %v", collections, err)
+	}
+}
+
+func TestCollectionStore_Expectations(t *testing.T) {
+	dir := t.TempDir()
+	yamlText := `requests:
+  - name: New pet
+    operationId: createPet
+    body: '{"name": "Rex"}'
+    expect:
+      status: 201
+      headers:
+        Content-Type: /json/
+      body:
+        $.name: Rex
+        $.tags: [a, b]
+        $.id: 7
+      schema: true
+      maxLatency: 500ms
+    capture:
+      - variable: petId
+        jsonpath: $.id
+      - variable: location
+        header: Location
+        regex: /pets/(\d+)$
+`
+	if err := os.WriteFile(filepath.Join(dir, "pets.yaml"), []byte(yamlText), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := config.NewCollectionStore(dir)
+	collections, err := store.LoadCollections()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	req := collections[0].Requests[0]
+	e := req.Expect
+	if e == nil || e.Status != "201" || e.Headers["Content-Type"] != "/json/" || !e.Schema || e.MaxLatency != 500*time.Millisecond {
+		t.Fatalf("unexpected expectations %+v", e)
+	}
+	if len(e.Body) != 3 || e.Body[0].Path != "$.name" || e.Body[0].Value != "Rex" || e.Body[2].Path != "$.id" || e.Body[2].Value != 7 {
+		t.Errorf("expected body expectations in file order, got %+v", e.Body)
+	}
+	if len(req.Captures) != 2 || req.Captures[0] != (domain.Capture{Variable: "petId", Path: "$.id"}) || req.Captures[1] != (domain.Capture{Variable: "location", Header: "Location", Regex: `/pets/(\d+)$`}) {
+		t.Errorf("unexpected captures %+v", req.Captures)
+	}
+
+	// Saving from the request builder, which has no expectations, keeps them.
+	if err := store.SaveRequest(domain.SavedRequest{Name: "New pet", Collection: "pets", OperationID: "createPet"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	collections, err = store.LoadCollections()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	again := collections[0].Requests[0]
+	if again.Expect == nil || again.Expect.MaxLatency != e.MaxLatency || len(again.Expect.Body) != 3 || again.Expect.Body[1].Path != "$.tags" || len(again.Captures) != 2 {
+		t.Errorf("expected the expectations to survive saving, got %+v %+v", again.Expect, again.Captures)
+	}
+}
+
+func TestCollectionStore_InvalidCapture(t *testing.T) {
+	for capture, want := range map[string]string{
+		"variable: id":                 "a header, a jsonpath or a regex",
+		"{variable: id, regex: '(id'}": "capturing id: error parsing regexp",
+	} {
+		dir := t.TempDir()
+		yamlText := "requests:\n  - name: x\n    operationId: op\n    capture:\n      - " + capture + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "pets.yaml"), []byte(yamlText), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := config.NewCollectionStore(dir).LoadCollections(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected an error containing %q, got %v", capture, want, err)
+		}
+	}
+}
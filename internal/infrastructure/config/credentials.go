@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"dazzle/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// credentialsFile is the file name used in both config directories. Keep
+// it out of version control, or store only {{env:NAME}} references in it.
+const credentialsFile = "credentials.yaml"
+
+// credentialsDoc is the on-disk format, keyed by security scheme name:
+//
+//	schemes:
+//	  petstore_auth:
+//	    clientId: dazzle
+//	    clientSecret: "{{env:PETSTORE_SECRET}}"
+//	    scopes: [read:pets]
+//	  api_key:
+//	    apiKey: "{{apiKey}}"
+type credentialsDoc struct {
+	Schemes map[string]credentialsEntry `yaml:"schemes"`
+}
+
+type credentialsEntry struct {
+	APIKey       string   `yaml:"apiKey"`
+	Username     string   `yaml:"username"`
+	Password     string   `yaml:"password"`
+	Token        string   `yaml:"token"`
+	ClientID     string   `yaml:"clientId"`
+	ClientSecret string   `yaml:"clientSecret"`
+	RefreshToken string   `yaml:"refreshToken"`
+	Scopes       []string `yaml:"scopes"`
+	TokenURL     string   `yaml:"tokenUrl"`
+	RedirectURL  string   `yaml:"redirectUrl"`
+}
+
+// CredentialStore loads credentials from a list of files. A scheme in a
+// later file replaces the same scheme from earlier files as a whole, so
+// credentials are never mixed between files. Missing files are skipped.
+type CredentialStore struct {
+	paths []string
+}
+
+func NewCredentialStore(paths ...string) *CredentialStore {
+	return &CredentialStore{paths: paths}
+}
+
+// DefaultCredentialPaths returns the user file followed by the project file.
+func DefaultCredentialPaths() []string {
+	return defaultPaths(credentialsFile)
+}
+
+func (s *CredentialStore) Load() (map[string]domain.Credentials, error) {
+	creds := make(map[string]domain.Credentials)
+	for _, path := range s.paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		var doc credentialsDoc
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		for name, e := range doc.Schemes {
+			creds[name] = domain.Credentials(e)
+		}
+	}
+	return creds, nil
+}
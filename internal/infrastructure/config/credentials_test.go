@@ -0,0 +1,39 @@
+package config_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"dazzle/internal/infrastructure/config"
+)
+
+func TestCredentialStore_Load(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.yaml", `
+schemes:
+  petstore_auth:
+    clientId: dazzle
+    clientSecret: "{{env:PETSTORE_SECRET}}"
+    scopes: [read:pets, write:pets]
+  api_key:
+    apiKey: personal
+`)
+	project := writeFile(t, dir, "project.yaml", `
+schemes:
+  api_key:
+    apiKey: "{{apiKey}}"
+`)
+
+	creds, err := config.NewCredentialStore(user, filepath.Join(dir, "missing.yaml"), project).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	oauth := creds["petstore_auth"]
+	if oauth.ClientID != "dazzle" || oauth.ClientSecret != "{{env:PETSTORE_SECRET}}" || len(oauth.Scopes) != 2 {
+		t.Errorf("unexpected oauth credentials %+v", oauth)
+	}
+	if creds["api_key"].APIKey != "{{apiKey}}" {
+		t.Errorf("expected the project file to replace api_key, got %+v", creds["api_key"])
+	}
+}
@@ -0,0 +1,113 @@
+// Package config reads dazzle's configuration files: the project-local
+// .dazzle directory and the per-user config directory.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"dazzle/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectDir is the project-local configuration directory, relative to the
+// working directory.
+const ProjectDir = ".dazzle"
+
+// environmentsFile is the file name used in both config directories.
+const environmentsFile = "environments.yaml"
+
+// UserDir returns the per-user configuration directory, e.g.
+// ~/.config/dazzle.
+func UserDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user config directory: %w", err)
+	}
+	return filepath.Join(dir, "dazzle"), nil
+}
+
+// environmentsDoc is the on-disk format:
+//
+//	default: local
+//	environments:
+//	  local:
+//	    baseUrl: http://localhost:8080
+//	  staging:
+//	    baseUrl: https://staging.example.com
+//	    token: "{{env:STAGING_TOKEN}}"
+type environmentsDoc struct {
+	Default      string                       `yaml:"default"`
+	Environments map[string]map[string]string `yaml:"environments"`
+}
+
+// EnvironmentStore loads environments from a list of files. Later files
+// take precedence: their variables override those of the same environment
+// in earlier files, and their default wins. Missing files are skipped.
+type EnvironmentStore struct {
+	paths []string
+}
+
+func NewEnvironmentStore(paths ...string) *EnvironmentStore {
+	return &EnvironmentStore{paths: paths}
+}
+
+// DefaultEnvironmentPaths returns the user file followed by the project
+// file, so project settings override personal ones.
+func DefaultEnvironmentPaths() []string {
+	return defaultPaths(environmentsFile)
+}
+
+// defaultPaths returns name in the user config directory, then in the
+// project directory.
+func defaultPaths(name string) []string {
+	var paths []string
+	if dir, err := UserDir(); err == nil {
+		paths = append(paths, filepath.Join(dir, name))
+	}
+	return append(paths, filepath.Join(ProjectDir, name))
+}
+
+func (s *EnvironmentStore) Load() (*domain.EnvironmentSet, error) {
+	set := &domain.EnvironmentSet{Environments: make(map[string]domain.Environment)}
+
+	for _, path := range s.paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		var doc environmentsDoc
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+
+		if doc.Default != "" {
+			set.Default = doc.Default
+		}
+		for name, vars := range doc.Environments {
+			env, ok := set.Environments[name]
+			if !ok {
+				env = domain.Environment{Name: name, Variables: make(map[string]string)}
+			}
+			for k, v := range vars {
+				env.Variables[k] = v
+			}
+			set.Environments[name] = env
+		}
+	}
+
+	if set.Default != "" {
+		if _, ok := set.Environments[set.Default]; !ok {
+			return nil, fmt.Errorf("default environment %q is not defined", set.Default)
+		}
+	}
+	return set, nil
+}
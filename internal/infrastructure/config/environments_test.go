@@ -0,0 +1,88 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"dazzle/internal/infrastructure/config"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestEnvironmentStore_MergesFiles(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.yaml", `
+default: local
+environments:
+  local:
+    baseUrl: http://localhost:8080
+  staging:
+    baseUrl: https://staging.example.com
+    token: personal
+`)
+	project := writeFile(t, dir, "project.yaml", `
+default: staging
+environments:
+  staging:
+    tenantId: "42"
+    token: "{{env:STAGING_TOKEN}}"
+`)
+
+	set, err := config.NewEnvironmentStore(user, filepath.Join(dir, "missing.yaml"), project).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if set.Default != "staging" {
+		t.Errorf("expected the later file's default, got %q", set.Default)
+	}
+	staging, ok := set.Get("staging")
+	if !ok {
+		t.Fatal("expected staging environment")
+	}
+	want := map[string]string{
+		"baseUrl":  "https://staging.example.com",
+		"tenantId": "42",
+		"token":    "{{env:STAGING_TOKEN}}",
+	}
+	for k, v := range want {
+		if staging.Variables[k] != v {
+			t.Errorf("staging %s = %q, want %q", k, staging.Variables[k], v)
+		}
+	}
+	if names := set.Names(); len(names) != 2 || names[0] != "local" || names[1] != "staging" {
+		t.Errorf("unexpected names %v", names)
+	}
+}
+
+func TestEnvironmentStore_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	bad := writeFile(t, dir, "bad.yaml", "environments: [")
+	if _, err := config.NewEnvironmentStore(bad).Load(); err == nil {
+		t.Error("expected a parse error")
+	}
+
+	undefined := writeFile(t, dir, "undefined.yaml", "default: prod\n")
+	if _, err := config.NewEnvironmentStore(undefined).Load(); err == nil {
+		t.Error("expected an error for an undefined default")
+	}
+}
+
+func TestEnvironmentStore_NoFiles(t *testing.T) {
+	set, err := config.NewEnvironmentStore(filepath.Join(t.TempDir(), "none.yaml")).Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(set.Environments) != 0 || set.Default != "" {
+		t.Errorf("expected an empty set, got %+v", set)
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"dazzle/internal/domain"
+)
+
+// DefaultHistoryLimit is how many entries HistoryStore keeps by default.
+const DefaultHistoryLimit = 1000
+
+// HistoryStore keeps sent requests in a JSON Lines file readable only by
+// the user. New entries are appended; once the file holds twice the limit
+// it is rewritten with the newest entries, so appending stays cheap.
+type HistoryStore struct {
+	path  string
+	limit int
+
+	mu    sync.Mutex
+	count int // entries in the file; -1 until first counted
+}
+
+func NewHistoryStore(path string, limit int) *HistoryStore {
+	return &HistoryStore{path: path, limit: limit, count: -1}
+}
+
+// DefaultHistoryPath is history.jsonl under DataDir.
+func DefaultHistoryPath() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+func (s *HistoryStore) Append(entry domain.HistoryEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if s.count < 0 {
+		entries, err := s.read()
+		if err != nil {
+			return err
+		}
+		s.count = len(entries)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(s.path), err)
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("opening history: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("writing history: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("writing history: %w", err)
+	}
+	s.count++
+
+	if s.limit > 0 && s.count >= 2*s.limit {
+		return s.compact()
+	}
+	return nil
+}
+
+func (s *HistoryStore) List() ([]domain.HistoryEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	s.count = len(entries)
+	if s.limit > 0 && len(entries) > s.limit {
+		entries = entries[len(entries)-s.limit:]
+	}
+	return entries, nil
+}
+
+// read parses the file. Lines that do not parse, such as one cut short by
+// a crash, are skipped.
+func (s *HistoryStore) read() ([]domain.HistoryEntry, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading history: %w", err)
+	}
+
+	var entries []domain.HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, len(data)+1)
+	for scanner.Scan() {
+		var e domain.HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err == nil {
+			entries = append(entries, e)
+		}
+	}
+	return entries, scanner.Err()
+}
+
+// compact rewrites the file with the newest limit entries.
+func (s *HistoryStore) compact() error {
+	entries, err := s.read()
+	if err != nil {
+		return err
+	}
+	if len(entries) > s.limit {
+		entries = entries[len(entries)-s.limit:]
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	if err := writeFileAtomic(s.path, buf.Bytes(), 0o600); err != nil {
+		return fmt.Errorf("compacting history: %w", err)
+	}
+	s.count = len(entries)
+	return nil
+}
@@ -0,0 +1,80 @@
+package config_test
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/config"
+)
+
+func TestHistoryStore_AppendAndList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data", "history.jsonl")
+	store := config.NewHistoryStore(path, 10)
+
+	entry := domain.HistoryEntry{
+		ID:            "1",
+		Time:          time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		OperationID:   "getPet",
+		Method:        domain.GET,
+		Environment:   "staging",
+		Values:        domain.RequestValues{Server: "{{baseUrl}}", Path: map[string]string{"petId": "7"}},
+		URL:           "https://staging.example.com/pets/7",
+		RequestHeader: http.Header{"Authorization": {"Bearer " + domain.MaskedValue}},
+		Status:        200,
+		Timings:       domain.Timings{FirstByte: 80 * time.Millisecond, Total: 120 * time.Millisecond},
+	}
+	if err := store.Append(entry); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("expected the history file to exist: %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Errorf("expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	entries, err := config.NewHistoryStore(path, 10).List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	got := entries[0]
+	if got.OperationID != "getPet" || got.Values.Path["petId"] != "7" || got.RequestHeader.Get("Authorization") != "Bearer "+domain.MaskedValue || got.Timings.Total != 120*time.Millisecond {
+		t.Errorf("unexpected entry %+v", got)
+	}
+}
+
+func TestHistoryStore_KeepsNewestEntries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.jsonl")
+	store := config.NewHistoryStore(path, 3)
+	for i := range 7 {
+		if err := store.Append(domain.HistoryEntry{ID: fmt.Sprint(i)}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// A torn final line, as after a crash, is skipped.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	_, _ = f.WriteString(`{"id":"tor`)
+	f.Close()
+
+	entries, err := store.List()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var ids string
+	for _, e := range entries {
+		ids += e.ID
+	}
+	if ids != "456" {
+		t.Errorf("expected the newest three entries, got %q", ids)
+	}
+}
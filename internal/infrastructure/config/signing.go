@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"dazzle/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// signingFile is the file name used in both config directories.
+const signingFile = "signing.yaml"
+
+// signingDoc is the on-disk format, a list of signers tried in order:
+//
+//	signers:
+//	  - name: gateway
+//	    type: aws-sigv4
+//	    hosts: ["*.execute-api.*.amazonaws.com"]
+//	    service: execute-api
+//	    region: eu-west-1
+//	  - name: partner
+//	    type: hmac
+//	    hosts: [api.partner.example.com]
+//	    keyId: dazzle
+//	    secret: "{{env:PARTNER_SECRET}}"
+//	    headers: ["(request-target)", host, date, digest]
+type signingDoc struct {
+	Signers []signerEntry `yaml:"signers"`
+}
+
+type signerEntry struct {
+	Name            string   `yaml:"name"`
+	Type            string   `yaml:"type"`
+	Hosts           []string `yaml:"hosts"`
+	Service         string   `yaml:"service"`
+	Region          string   `yaml:"region"`
+	Profile         string   `yaml:"profile"`
+	KeyID           string   `yaml:"keyId"`
+	Secret          string   `yaml:"secret"`
+	Algorithm       string   `yaml:"algorithm"`
+	Headers         []string `yaml:"headers"`
+	Canonical       string   `yaml:"canonical"`
+	Header          string   `yaml:"header"`
+	Format          string   `yaml:"format"`
+	Encoding        string   `yaml:"encoding"`
+	TimestampHeader string   `yaml:"timestampHeader"`
+}
+
+// SignerStore loads signers from a list of files. Signers from later files
+// are tried first, so a project signer takes precedence over a personal one
+// for the same host. Missing files are skipped.
+type SignerStore struct {
+	paths []string
+}
+
+func NewSignerStore(paths ...string) *SignerStore {
+	return &SignerStore{paths: paths}
+}
+
+// DefaultSigningPaths returns the user file followed by the project file.
+func DefaultSigningPaths() []string {
+	return defaultPaths(signingFile)
+}
+
+func (s *SignerStore) LoadSigners() ([]domain.SignerConfig, error) {
+	var signers []domain.SignerConfig
+	for _, path := range s.paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+
+		var doc signingDoc
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		file := make([]domain.SignerConfig, 0, len(doc.Signers))
+		for i, e := range doc.Signers {
+			if e.Type != domain.SignerSigV4 && e.Type != domain.SignerHMAC {
+				return nil, fmt.Errorf("%s: signer %d: unknown type %q (want %s or %s)", path, i+1, e.Type, domain.SignerSigV4, domain.SignerHMAC)
+			}
+			file = append(file, domain.SignerConfig(e))
+		}
+		signers = append(file, signers...)
+	}
+	return signers, nil
+}
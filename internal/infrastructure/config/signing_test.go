@@ -0,0 +1,113 @@
+package config_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/config"
+)
+
+func TestSignerStore_LoadSigners(t *testing.T) {
+	dir := t.TempDir()
+	user := writeFile(t, dir, "user.yaml", `
+signers:
+  - name: personal
+    type: hmac
+    secret: s3cret
+`)
+	project := writeFile(t, dir, "project.yaml", `
+signers:
+  - name: gateway
+    type: aws-sigv4
+    hosts: ["*.execute-api.*.amazonaws.com"]
+    service: execute-api
+  - name: partner
+    type: hmac
+    hosts: [api.partner.example.com]
+    keyId: dazzle
+    secret: "{{env:PARTNER_SECRET}}"
+    headers: ["(request-target)", host, date, digest]
+    encoding: hex
+`)
+
+	signers, err := config.NewSignerStore(user, filepath.Join(dir, "missing.yaml"), project).LoadSigners()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, s := range signers {
+		names = append(names, s.Name)
+	}
+	if got := strings.Join(names, ","); got != "gateway,partner,personal" {
+		t.Fatalf("expected project signers before personal ones, got %s", got)
+	}
+	if s := signers[0]; s.Type != domain.SignerSigV4 || s.Service != "execute-api" || len(s.Hosts) != 1 {
+		t.Errorf("unexpected SigV4 signer %+v", s)
+	}
+	if s := signers[1]; s.Secret != "{{env:PARTNER_SECRET}}" || len(s.Headers) != 4 || s.Encoding != "hex" {
+		t.Errorf("unexpected HMAC signer %+v", s)
+	}
+}
+
+func TestSignerStore_UnknownType(t *testing.T) {
+	path := writeFile(t, t.TempDir(), "signing.yaml", "signers:\n  - type: rsa\n")
+	_, err := config.NewSignerStore(path).LoadSigners()
+	if err == nil || !strings.Contains(err.Error(), `unknown type "rsa"`) {
+		t.Errorf("expected an unknown type error, got %v", err)
+	}
+}
+
+func TestAWSCredentialStore(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", writeFile(t, dir, "credentials", `
+[default]
+aws_access_key_id = AKIDDEFAULT
+aws_secret_access_key = default-secret
+
+[ci]
+aws_access_key_id = AKIDCI
+aws_secret_access_key = ci-secret
+aws_session_token = ci-token
+`))
+	t.Setenv("AWS_CONFIG_FILE", writeFile(t, dir, "config", `
+[default]
+region = us-east-1
+
+[profile ci]
+region = eu-west-1
+`))
+	for _, name := range []string{"AWS_PROFILE", "AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_REGION", "AWS_DEFAULT_REGION"} {
+		t.Setenv(name, "")
+	}
+	store := config.NewAWSCredentialStore()
+
+	creds, err := store.LoadAWSCredentials("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds != (domain.AWSCredentials{AccessKeyID: "AKIDDEFAULT", SecretAccessKey: "default-secret", Region: "us-east-1"}) {
+		t.Errorf("unexpected default profile %+v", creds)
+	}
+
+	creds, _ = store.LoadAWSCredentials("ci")
+	if creds != (domain.AWSCredentials{AccessKeyID: "AKIDCI", SecretAccessKey: "ci-secret", SessionToken: "ci-token", Region: "eu-west-1"}) {
+		t.Errorf("unexpected ci profile %+v", creds)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDENV")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "env-secret")
+	t.Setenv("AWS_REGION", "ap-south-1")
+	creds, _ = store.LoadAWSCredentials("")
+	if creds.AccessKeyID != "AKIDENV" || creds.SecretAccessKey != "env-secret" || creds.Region != "ap-south-1" {
+		t.Errorf("expected the environment's keys, got %+v", creds)
+	}
+	if creds, _ = store.LoadAWSCredentials("ci"); creds.AccessKeyID != "AKIDCI" {
+		t.Errorf("expected a named profile to win over the environment, got %+v", creds)
+	}
+
+	if _, err := store.LoadAWSCredentials("missing"); err == nil || !strings.Contains(err.Error(), `profile "missing"`) {
+		t.Errorf("expected a missing profile error, got %v", err)
+	}
+}
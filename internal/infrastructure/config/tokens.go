@@ -0,0 +1,131 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+
+	"dazzle/internal/domain"
+)
+
+// DataDir returns the per-user data directory, $XDG_DATA_HOME/dazzle or
+// ~/.local/share/dazzle.
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "dazzle"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("locating user data directory: %w", err)
+	}
+	return filepath.Join(home, ".local", "share", "dazzle"), nil
+}
+
+// TokenStore keeps OAuth2 tokens in one file per environment,
+// <dir>/<environment>.json, readable only by the user. Tokens used without
+// an environment go in default.json.
+type TokenStore struct {
+	dir string
+	mu  sync.Mutex
+}
+
+func NewTokenStore(dir string) *TokenStore {
+	return &TokenStore{dir: dir}
+}
+
+// DefaultTokenDir is the tokens directory under DataDir.
+func DefaultTokenDir() (string, error) {
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "tokens"), nil
+}
+
+func (s *TokenStore) LoadToken(env, scheme string) (*domain.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read(env)
+	if err != nil {
+		return nil, err
+	}
+	t, ok := tokens[scheme]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+func (s *TokenStore) SaveToken(env, scheme string, token domain.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens, err := s.read(env)
+	if err != nil {
+		return err
+	}
+	tokens[scheme] = token
+
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("creating %s: %w", s.dir, err)
+	}
+	return writeFileAtomic(s.path(env), data, 0o600)
+}
+
+func (s *TokenStore) read(env string) (map[string]domain.Token, error) {
+	tokens := make(map[string]domain.Token)
+	data, err := os.ReadFile(s.path(env))
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading tokens: %w", err)
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", s.path(env), err)
+	}
+	return tokens, nil
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+func (s *TokenStore) path(env string) string {
+	name := unsafeFileChars.ReplaceAllString(env, "_")
+	if name == "" || name == "." || name == ".." {
+		name = "default"
+	}
+	return filepath.Join(s.dir, name+".json")
+}
+
+// writeFileAtomic writes data to a temporary file and renames it over path,
+// so a crash never leaves a half-written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,47 @@
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/config"
+)
+
+func TestTokenStore_PerEnvironment(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "tokens")
+	store := config.NewTokenStore(dir)
+
+	expiry := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := store.SaveToken("staging", "oidc", domain.Token{AccessToken: "a1", RefreshToken: "r1", Expiry: expiry}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := store.SaveToken("", "oidc", domain.Token{AccessToken: "a2"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := config.NewTokenStore(dir).LoadToken("staging", "oidc")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got == nil || got.AccessToken != "a1" || got.RefreshToken != "r1" || !got.Expiry.Equal(expiry) {
+		t.Errorf("unexpected token %+v", got)
+	}
+
+	if got, _ := store.LoadToken("", "oidc"); got == nil || got.AccessToken != "a2" {
+		t.Errorf("expected the token without an environment, got %+v", got)
+	}
+	if got, _ := store.LoadToken("prod", "oidc"); got != nil {
+		t.Errorf("expected no token for another environment, got %+v", got)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "staging.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected the token file to be private, got %v", perm)
+	}
+}
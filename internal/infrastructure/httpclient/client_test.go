@@ -0,0 +1,78 @@
+package httpclient_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/httpclient"
+)
+
+func TestClient_Do(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Echo", r.Header.Get("X-Request"))
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+	}))
+	defer srv.Close()
+
+	c := httpclient.NewClient(5 * time.Second)
+	resp, err := c.Do(context.Background(), &domain.HTTPRequest{
+		Method: domain.GET,
+		URL:    srv.URL,
+		Header: http.Header{"X-Request": {"hello"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusTeapot {
+		t.Errorf("expected 418, got %d", resp.StatusCode)
+	}
+	if resp.Header.Get("X-Echo") != "hello" {
+		t.Errorf("expected request header to reach the server, got %v", resp.Header)
+	}
+	if string(resp.Body) != "short and stout" || resp.Truncated {
+		t.Errorf("unexpected body %q", resp.Body)
+	}
+	if resp.Duration <= 0 {
+		t.Error("expected a positive duration")
+	}
+	if resp.Timings.Total != resp.Duration || resp.Timings.Connect <= 0 || resp.Timings.FirstByte <= 0 {
+		t.Errorf("expected connect and first-byte timings, got %+v", resp.Timings)
+	}
+}
+
+func TestClient_DoTruncatesLargeBodies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bytes.Repeat([]byte("x"), 11<<20))
+	}))
+	defer srv.Close()
+
+	resp, err := httpclient.NewClient(5*time.Second).Do(context.Background(), &domain.HTTPRequest{Method: domain.GET, URL: srv.URL})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !resp.Truncated || len(resp.Body) != 10<<20 {
+		t.Errorf("expected the body cut to 10 MiB, got %d bytes, truncated %v", len(resp.Body), resp.Truncated)
+	}
+}
+
+func TestClient_DoCancelled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	c := httpclient.NewClient(5 * time.Second)
+	if _, err := c.Do(ctx, &domain.HTTPRequest{Method: domain.GET, URL: srv.URL}); err == nil {
+		t.Error("expected error for a cancelled context")
+	}
+}
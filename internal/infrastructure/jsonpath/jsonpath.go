@@ -0,0 +1,242 @@
+// Package jsonpath evaluates a small JSONPath/jq-style path language over
+// decoded JSON values (the map[string]any / []any trees produced by
+// encoding/json).
+//
+// Supported syntax, with an optional leading "$":
+//
+//	.name  ["name"]  ['name']   object member
+//	[2]  [-1]                   array element, negative counts from the end
+//	.*  [*]  []                 every member or element
+//	..name                      name at any depth
+package jsonpath
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type stepKind int
+
+const (
+	stepKey stepKind = iota
+	stepIndex
+	stepWildcard
+	stepDescend
+)
+
+type step struct {
+	kind  stepKind
+	key   string
+	index int
+}
+
+// Path is a compiled path expression.
+type Path struct {
+	expr  string
+	steps []step
+}
+
+func (p *Path) String() string { return p.expr }
+
+// Parse compiles a path expression.
+func Parse(expr string) (*Path, error) {
+	p := &Path{expr: expr}
+	s := strings.TrimSpace(expr)
+	s = strings.TrimPrefix(s, "$")
+
+	for s != "" {
+		switch {
+		case strings.HasPrefix(s, ".."):
+			name, rest := readName(s[2:])
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath %q: expected name after '..'", expr)
+			}
+			p.steps = append(p.steps, step{kind: stepDescend, key: name})
+			s = rest
+		case strings.HasPrefix(s, ".*"):
+			p.steps = append(p.steps, step{kind: stepWildcard})
+			s = s[2:]
+		case s[0] == '.':
+			name, rest := readName(s[1:])
+			if name != "" {
+				p.steps = append(p.steps, step{kind: stepKey, key: name})
+			}
+			s = rest
+		case s[0] == '[' && startsQuoted(s[1:]):
+			name, rest, err := readQuoted(strings.TrimLeft(s[1:], " "))
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: %w", expr, err)
+			}
+			rest = strings.TrimLeft(rest, " ")
+			if !strings.HasPrefix(rest, "]") {
+				return nil, fmt.Errorf("jsonpath %q: expected ']' after %q", expr, name)
+			}
+			p.steps = append(p.steps, step{kind: stepKey, key: name})
+			s = rest[1:]
+		case s[0] == '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonpath %q: unclosed '['", expr)
+			}
+			st, err := parseBracket(strings.TrimSpace(s[1:end]))
+			if err != nil {
+				return nil, fmt.Errorf("jsonpath %q: %w", expr, err)
+			}
+			p.steps = append(p.steps, st)
+			s = s[end+1:]
+		default:
+			// A bare leading name, as in "items[0]".
+			name, rest := readName(s)
+			if name == "" {
+				return nil, fmt.Errorf("jsonpath %q: unexpected %q", expr, s[:1])
+			}
+			p.steps = append(p.steps, step{kind: stepKey, key: name})
+			s = rest
+		}
+	}
+	return p, nil
+}
+
+// MustParse is like Parse but panics on error. It is meant for expressions
+// known at compile time.
+func MustParse(expr string) *Path {
+	p, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func readName(s string) (name, rest string) {
+	i := 0
+	for i < len(s) && s[i] != '.' && s[i] != '[' && s[i] != ']' {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func startsQuoted(s string) bool {
+	s = strings.TrimLeft(s, " ")
+	return s != "" && (s[0] == '"' || s[0] == '\'')
+}
+
+// readQuoted reads the quoted name s starts with, in Go's escaping as
+// Location.Key writes it, and returns the text after the closing quote.
+// In single quotes, \' stands for a quote and " needs no escape.
+func readQuoted(s string) (name, rest string, err error) {
+	quote := s[0]
+	var lit strings.Builder
+	lit.WriteByte('"')
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == quote:
+			lit.WriteByte('"')
+			name, err := strconv.Unquote(lit.String())
+			if err != nil {
+				return "", "", fmt.Errorf("invalid quoted name %s", s[:i+1])
+			}
+			return name, s[i+1:], nil
+		case c == '\\' && i+1 < len(s):
+			i++
+			if s[i] != '\'' {
+				lit.WriteByte('\\')
+			}
+			lit.WriteByte(s[i])
+		case c == '"':
+			lit.WriteString(`\"`)
+		default:
+			lit.WriteByte(c)
+		}
+	}
+	return "", "", fmt.Errorf("unclosed quote in %s", s)
+}
+
+func parseBracket(inner string) (step, error) {
+	switch {
+	case inner == "" || inner == "*":
+		return step{kind: stepWildcard}, nil
+	default:
+		n, err := strconv.Atoi(inner)
+		if err != nil {
+			return step{}, fmt.Errorf("invalid index %q", inner)
+		}
+		return step{kind: stepIndex, index: n}, nil
+	}
+}
+
+// Eval returns every value the path selects from doc, in document order.
+// Object members are visited in sorted key order so results are stable.
+func (p *Path) Eval(doc any) []any {
+	current := []any{doc}
+	for _, st := range p.steps {
+		var next []any
+		for _, v := range current {
+			next = append(next, apply(st, v)...)
+		}
+		current = next
+	}
+	return current
+}
+
+// Eval parses and evaluates expr against doc.
+func Eval(expr string, doc any) ([]any, error) {
+	p, err := Parse(expr)
+	if err != nil {
+		return nil, err
+	}
+	return p.Eval(doc), nil
+}
+
+func apply(st step, v any) []any {
+	switch st.kind {
+	case stepKey:
+		if m, ok := v.(map[string]any); ok {
+			if child, ok := m[st.key]; ok {
+				return []any{child}
+			}
+		}
+	case stepIndex:
+		if a, ok := v.([]any); ok {
+			i := st.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				return []any{a[i]}
+			}
+		}
+	case stepWildcard:
+		return children(v)
+	case stepDescend:
+		var out []any
+		descend(v, st.key, &out)
+		return out
+	}
+	return nil
+}
+
+func children(v any) []any {
+	switch t := v.(type) {
+	case map[string]any:
+		out := make([]any, 0, len(t))
+		for _, k := range sortedKeys(t) {
+			out = append(out, t[k])
+		}
+		return out
+	case []any:
+		return t
+	}
+	return nil
+}
+
+func descend(v any, key string, out *[]any) {
+	if m, ok := v.(map[string]any); ok {
+		if child, ok := m[key]; ok {
+			*out = append(*out, child)
+		}
+	}
+	for _, c := range children(v) {
+		descend(c, key, out)
+	}
+}
@@ -0,0 +1,101 @@
+package jsonpath_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestEval(t *testing.T) {
+	doc := decode(t, `{
+		"items": [
+			{"id": 1, "name": "Rex", "tags": ["dog"]},
+			{"id": 2, "name": "Tom", "owner": {"id": 9}}
+		],
+		"next page": "abc"
+	}`)
+
+	tests := []struct {
+		expr string
+		want []any
+	}{
+		{"$", []any{doc}},
+		{".", []any{doc}},
+		{"$.items[0].name", []any{"Rex"}},
+		{".items[1].owner.id", []any{float64(9)}},
+		{"items[-1].name", []any{"Tom"}},
+		{`$["next page"]`, []any{"abc"}},
+		{`$['next page']`, []any{"abc"}},
+		{`$[ 'next page' ]`, []any{"abc"}},
+		{".items[].id", []any{float64(1), float64(2)}},
+		{"$.items[*].name", []any{"Rex", "Tom"}},
+		{"$.items[0].*", []any{float64(1), "Rex", []any{"dog"}}},
+		{"$..id", []any{float64(1), float64(2), float64(9)}},
+		{"$.items[5]", nil},
+		{"$.missing.name", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := jsonpath.Eval(tt.expr, doc)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	for _, expr := range []string{"$.items[0", "$.items[x]", "$..", "$]"} {
+		if _, err := jsonpath.Parse(expr); err == nil {
+			t.Errorf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestLocation(t *testing.T) {
+	loc := jsonpath.Location{}.Key("items").Index(3).Key("price")
+	if got := loc.String(); got != "$.items[3].price" {
+		t.Errorf("got %q", got)
+	}
+
+	if got := (jsonpath.Location{}).Key("next page").String(); got != `$["next page"]` {
+		t.Errorf("got %q", got)
+	}
+
+	if got := (jsonpath.Location{}).Each().Key("id").String(); got != "$[*].id" {
+		t.Errorf("got %q", got)
+	}
+
+	if got := (jsonpath.Location{}).String(); got != "$" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestLocation_RoundTrips(t *testing.T) {
+	for _, key := range []string{`a"]b`, "it's", `back\slash`, "a.b", "next page", "ü b", "line\nbreak", ""} {
+		expr := jsonpath.Location{}.Key(key).String()
+		got, err := jsonpath.Eval(expr, map[string]any{key: 1})
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", expr, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, []any{1}) {
+			t.Errorf("%s: got %v, want [1]", expr, got)
+		}
+	}
+}
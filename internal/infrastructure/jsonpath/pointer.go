@@ -0,0 +1,66 @@
+package jsonpath
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Location builds the "$.items[3].price" style paths used to report where
+// in a document something was found. The zero value is the root.
+type Location struct {
+	parts []string
+}
+
+// Key returns the location of an object member.
+func (l Location) Key(name string) Location {
+	if isIdentifier(name) {
+		return l.with("." + name)
+	}
+	return l.with("[" + strconv.Quote(name) + "]")
+}
+
+// Index returns the location of an array element.
+func (l Location) Index(i int) Location {
+	return l.with("[" + strconv.Itoa(i) + "]")
+}
+
+// Each returns the location of every element of an array.
+func (l Location) Each() Location {
+	return l.with("[*]")
+}
+
+func (l Location) with(part string) Location {
+	parts := make([]string, len(l.parts), len(l.parts)+1)
+	copy(parts, l.parts)
+	return Location{parts: append(parts, part)}
+}
+
+func (l Location) String() string {
+	return "$" + strings.Join(l.parts, "")
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case r == '_' || r == '$' || r == '-':
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
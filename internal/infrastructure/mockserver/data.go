@@ -0,0 +1,25 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// LoadData reads the mock's seed data: a JSON object mapping collection
+// paths such as "/pets" to arrays of resources. Numbers are kept as
+// json.Number so IDs keep their exact form.
+func LoadData(path string) (map[string][]any, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading seed data: %w", err)
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var data map[string][]any
+	if err := dec.Decode(&data); err != nil {
+		return nil, fmt.Errorf("parsing seed data %s: %w", path, err)
+	}
+	return data, nil
+}
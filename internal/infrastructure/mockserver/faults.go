@@ -0,0 +1,136 @@
+package mockserver
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"dazzle/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// faultsDoc is the on-disk format of the mock's faults. An operation's
+// entry replaces the default rather than adding to it:
+//
+//	default:
+//	  latency: 20ms-80ms
+//	operations:
+//	  getPet:
+//	    latency:
+//	      distribution: normal
+//	      mean: 200ms
+//	      stddev: 50ms
+//	      max: 1s
+//	    errorRate: 10
+//	    errorCodes: [500, 503]
+//	    resetRate: 2
+//	    slowBody:
+//	      rate: 25
+//	      bytesPerSecond: 512
+//	    rateLimit:
+//	      requests: 5
+//	      window: 1m
+type faultsDoc struct {
+	Default    faultsEntry            `yaml:"default"`
+	Operations map[string]faultsEntry `yaml:"operations"`
+}
+
+type faultsEntry struct {
+	Latency    *latencyEntry `yaml:"latency"`
+	ErrorRate  float64       `yaml:"errorRate"`
+	ErrorCodes []int         `yaml:"errorCodes"`
+	ResetRate  float64       `yaml:"resetRate"`
+	SlowBody   struct {
+		Rate           float64 `yaml:"rate"`
+		BytesPerSecond int     `yaml:"bytesPerSecond"`
+	} `yaml:"slowBody"`
+	RateLimit struct {
+		Requests int      `yaml:"requests"`
+		Window   duration `yaml:"window"`
+	} `yaml:"rateLimit"`
+}
+
+// latencyEntry accepts the short forms "200ms" and "50ms-200ms" as well as
+// a mapping naming the distribution.
+type latencyEntry struct {
+	domain.Latency
+}
+
+func (l *latencyEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		parsed, err := domain.ParseLatency(node.Value)
+		if err != nil {
+			return err
+		}
+		l.Latency = parsed
+		return nil
+	}
+	var m struct {
+		Distribution string   `yaml:"distribution"`
+		Mean         duration `yaml:"mean"`
+		StdDev       duration `yaml:"stddev"`
+		Min          duration `yaml:"min"`
+		Max          duration `yaml:"max"`
+	}
+	if err := node.Decode(&m); err != nil {
+		return err
+	}
+	l.Latency = domain.Latency{
+		Distribution: m.Distribution,
+		Mean:         time.Duration(m.Mean),
+		StdDev:       time.Duration(m.StdDev),
+		Min:          time.Duration(m.Min),
+		Max:          time.Duration(m.Max),
+	}
+	if l.Distribution == "" {
+		l.Distribution = domain.LatencyFixed
+	}
+	return nil
+}
+
+// duration reads a Go duration string such as "1m30s".
+type duration time.Duration
+
+func (d *duration) UnmarshalYAML(node *yaml.Node) error {
+	parsed, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*d = duration(parsed)
+	return nil
+}
+
+// LoadFaults reads the mock's fault configuration from a YAML file.
+func LoadFaults(path string) (domain.MockFaultConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return domain.MockFaultConfig{}, fmt.Errorf("reading faults: %w", err)
+	}
+	var doc faultsDoc
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return domain.MockFaultConfig{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	cfg := domain.MockFaultConfig{Default: doc.Default.faults(), Operations: map[string]domain.MockFaults{}}
+	for id, e := range doc.Operations {
+		cfg.Operations[id] = e.faults()
+	}
+	return cfg, nil
+}
+
+func (e faultsEntry) faults() domain.MockFaults {
+	f := domain.MockFaults{
+		ErrorRate:      e.ErrorRate,
+		ErrorCodes:     e.ErrorCodes,
+		ResetRate:      e.ResetRate,
+		SlowBodyRate:   e.SlowBody.Rate,
+		BytesPerSecond: e.SlowBody.BytesPerSecond,
+		RateLimit:      e.RateLimit.Requests,
+		RateWindow:     time.Duration(e.RateLimit.Window),
+	}
+	if e.Latency != nil {
+		l := e.Latency.Latency
+		f.Latency = &l
+	}
+	return f
+}
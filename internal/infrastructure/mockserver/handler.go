@@ -0,0 +1,154 @@
+// Package mockserver serves a domain.MockService over HTTP.
+package mockserver
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// slowBodyTicks is how many chunks a slow body is sent in per second.
+const slowBodyTicks = 10
+
+// maxBody caps the request bodies read, so a runaway client cannot exhaust
+// memory.
+const maxBody = 10 << 20
+
+// Handler answers HTTP requests with a mock service and logs one line per
+// request, followed by the violations of rejected ones.
+type Handler struct {
+	svc domain.MockService
+	log io.Writer
+	mu  sync.Mutex // serializes log lines
+	now func() time.Time
+}
+
+// NewHandler logs to log, or nowhere when it is nil.
+func NewHandler(svc domain.MockService, log io.Writer) *Handler {
+	if log == nil {
+		log = io.Discard
+	}
+	return &Handler{svc: svc, log: log, now: time.Now}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := h.now()
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBody))
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		http.Error(w, fmt.Sprintf("reading body: %v", err), status)
+		return
+	}
+
+	resp := h.svc.Respond(domain.MockRequest{
+		Method: domain.HTTPMethod(r.Method),
+		Path:   r.URL.EscapedPath(),
+		Query:  r.URL.Query(),
+		Header: r.Header,
+		Body:   body,
+	})
+	if !h.wait(r, resp.Delay) {
+		return
+	}
+	if resp.Reset {
+		h.logRequest(r, resp, h.now().Sub(start))
+		reset(w)
+	}
+	for name, values := range resp.Header {
+		w.Header()[name] = values
+	}
+	if resp.BytesPerSecond > 0 {
+		// Announce the length so a client can tell a slow body from a
+		// short one.
+		w.Header().Set("Content-Length", strconv.Itoa(len(resp.Body)))
+	}
+	w.WriteHeader(resp.StatusCode)
+	if r.Method != http.MethodHead {
+		h.writeBody(w, r, resp)
+	}
+	h.logRequest(r, resp, h.now().Sub(start))
+}
+
+// wait sleeps for d, reporting false when the client went away first.
+func (h *Handler) wait(r *http.Request, d time.Duration) bool {
+	if d <= 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-r.Context().Done():
+		return false
+	}
+}
+
+// reset drops the connection without a response. On TCP the socket closes
+// with no linger, so the client sees a reset rather than a clean close.
+func reset(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+	panic(http.ErrAbortHandler)
+}
+
+// writeBody sends the body, at resp.BytesPerSecond when that is set.
+func (h *Handler) writeBody(w http.ResponseWriter, r *http.Request, resp domain.MockResponse) {
+	if resp.BytesPerSecond <= 0 {
+		_, _ = w.Write(resp.Body)
+		return
+	}
+	rc := http.NewResponseController(w)
+	chunk := max(1, resp.BytesPerSecond/slowBodyTicks)
+	for body := resp.Body; len(body) > 0; {
+		n := min(chunk, len(body))
+		if _, err := w.Write(body[:n]); err != nil {
+			return
+		}
+		_ = rc.Flush()
+		if body = body[n:]; len(body) > 0 && !h.wait(r, time.Second/slowBodyTicks) {
+			return
+		}
+	}
+}
+
+func (h *Handler) logRequest(r *http.Request, resp domain.MockResponse, elapsed time.Duration) {
+	op := resp.OperationID
+	if op == "" {
+		op = "-"
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	outcome := strconv.Itoa(resp.StatusCode)
+	if resp.Reset {
+		outcome = "reset"
+	}
+	var faults string
+	if resp.Delay > 0 {
+		faults += fmt.Sprintf(", delayed %s", resp.Delay.Round(time.Millisecond))
+	}
+	if resp.BytesPerSecond > 0 && !resp.Reset {
+		faults += fmt.Sprintf(", body at %d B/s", resp.BytesPerSecond)
+	}
+	fmt.Fprintf(h.log, "%s %-7s %s → %s %s (%s%s)\n", h.now().Format("15:04:05"), r.Method, r.URL.RequestURI(), outcome, op, elapsed.Round(time.Microsecond), faults)
+	for _, v := range resp.Violations {
+		fmt.Fprintf(h.log, "    %s\n", v)
+	}
+}
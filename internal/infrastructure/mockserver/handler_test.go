@@ -0,0 +1,175 @@
+package mockserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/mockserver"
+)
+
+type mockFunc func(domain.MockRequest) domain.MockResponse
+
+func (f mockFunc) Respond(req domain.MockRequest) domain.MockResponse { return f(req) }
+
+func TestHandler_ServesResponses(t *testing.T) {
+	var got domain.MockRequest
+	svc := mockFunc(func(req domain.MockRequest) domain.MockResponse {
+		got = req
+		return domain.MockResponse{
+			StatusCode:  http.StatusBadRequest,
+			Header:      http.Header{"Content-Type": {"application/json"}},
+			Body:        []byte(`{"error":"bad"}`),
+			OperationID: "createPet",
+			Violations:  []domain.Violation{{Path: "$.name", Message: "required"}},
+		}
+	})
+	var log bytes.Buffer
+	srv := httptest.NewServer(mockserver.NewHandler(svc, &log))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/v1/pets%2Fx?limit=2", "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if got.Method != domain.POST || got.Path != "/v1/pets%2Fx" || got.Query.Get("limit") != "2" || string(got.Body) != "{}" {
+		t.Errorf("unexpected request %+v", got)
+	}
+	if resp.StatusCode != http.StatusBadRequest || resp.Header.Get("Content-Type") != "application/json" || string(body) != `{"error":"bad"}` {
+		t.Errorf("unexpected response %d %v %s", resp.StatusCode, resp.Header, body)
+	}
+	srv.Close()
+	if !strings.Contains(log.String(), "POST    /v1/pets%2Fx?limit=2 → 400 createPet") || !strings.Contains(log.String(), "    $.name: required\n") {
+		t.Errorf("unexpected log:\n%s", log.String())
+	}
+}
+
+func TestHandler_RejectsLargeBodies(t *testing.T) {
+	called := false
+	svc := mockFunc(func(domain.MockRequest) domain.MockResponse {
+		called = true
+		return domain.MockResponse{StatusCode: http.StatusCreated}
+	})
+	srv := httptest.NewServer(mockserver.NewHandler(svc, nil))
+	defer srv.Close()
+
+	resp, err := http.Post(srv.URL+"/pets", "application/json", bytes.NewReader(make([]byte, 10<<20+1)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge || called {
+		t.Errorf("expected 413 without asking the mock, got %d (called %v)", resp.StatusCode, called)
+	}
+}
+
+func TestLoadData(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "seed.json")
+	if err := os.WriteFile(path, []byte(`{"/pets": [{"id": 12345678901234567890, "name": "Rex"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	data, err := mockserver.LoadData(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pet := data["/pets"][0].(map[string]any)
+	if pet["id"] != json.Number("12345678901234567890") || pet["name"] != "Rex" {
+		t.Errorf("expected the pet with its exact ID, got %v", pet)
+	}
+
+	if err := os.WriteFile(path, []byte(`["/pets"]`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mockserver.LoadData(path); err == nil {
+		t.Error("expected an error for data that is not an object")
+	}
+}
+
+func TestHandler_ActsOutFaults(t *testing.T) {
+	var fault domain.MockResponse
+	svc := mockFunc(func(domain.MockRequest) domain.MockResponse {
+		resp := fault
+		resp.StatusCode, resp.Body = http.StatusOK, []byte("0123456789")
+		return resp
+	})
+	var log bytes.Buffer
+	srv := httptest.NewServer(mockserver.NewHandler(svc, &log))
+	defer srv.Close()
+
+	fault = domain.MockResponse{Delay: 50 * time.Millisecond, BytesPerSecond: 50}
+	start := time.Now()
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "0123456789" || resp.ContentLength != 10 {
+		t.Errorf("expected the whole body with its length, got %q (%d)", body, resp.ContentLength)
+	}
+	if elapsed := time.Since(start); elapsed < 140*time.Millisecond {
+		t.Errorf("expected the delay and a slow body, took %s", elapsed)
+	}
+
+	fault = domain.MockResponse{Reset: true}
+	if _, err := http.Get(srv.URL); err == nil {
+		t.Error("expected the connection to be dropped")
+	}
+	srv.Close() // waits for the handlers' log lines
+	if !strings.Contains(log.String(), "delayed 50ms, body at 50 B/s") || !strings.Contains(log.String(), "→ reset") {
+		t.Errorf("expected the faults in the log:\n%s", log.String())
+	}
+}
+
+func TestLoadFaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "faults.yaml")
+	doc := `
+default:
+  latency: 20ms-80ms
+operations:
+  getPet:
+    latency:
+      distribution: normal
+      mean: 200ms
+      stddev: 50ms
+    errorRate: 10
+    errorCodes: [500, 503]
+    slowBody: {rate: 25, bytesPerSecond: 512}
+    rateLimit: {requests: 5, window: 1m}
+`
+	if err := os.WriteFile(path, []byte(doc), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := mockserver.LoadFaults(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l := cfg.Default.Latency; l == nil || l.Distribution != domain.LatencyUniform || l.Min != 20*time.Millisecond || l.Max != 80*time.Millisecond {
+		t.Errorf("expected a uniform default latency, got %+v", l)
+	}
+	f := cfg.Operations["getPet"]
+	if f.Latency.Distribution != domain.LatencyNormal || f.Latency.StdDev != 50*time.Millisecond || f.ErrorRate != 10 || len(f.ErrorCodes) != 2 {
+		t.Errorf("unexpected getPet faults %+v", f)
+	}
+	if f.SlowBodyRate != 25 || f.BytesPerSecond != 512 || f.RateLimit != 5 || f.RateWindow != time.Minute {
+		t.Errorf("unexpected getPet faults %+v", f)
+	}
+
+	if err := os.WriteFile(path, []byte("default:\n  rateLimit: {window: soon}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := mockserver.LoadFaults(path); err == nil {
+		t.Error("expected a bad duration to be refused")
+	}
+}
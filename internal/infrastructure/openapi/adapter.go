@@ -16,9 +16,14 @@ func adaptSpec(doc *oas.T) *domain.Spec {
 		Servers: adaptServers(doc.Servers),
 	}
 
+	if doc.Components != nil {
+		spec.SecuritySchemes = adaptSecuritySchemes(doc.Components.SecuritySchemes)
+	}
+
+	defaultSecurity := adaptSecurityRequirements(doc.Security)
 	if doc.Paths != nil {
 		for path, item := range doc.Paths.Map() {
-			spec.Operations = append(spec.Operations, extractOperations(path, item)...)
+			spec.Operations = append(spec.Operations, extractOperations(path, item, defaultSecurity)...)
 		}
 	}
 
@@ -36,7 +41,7 @@ func adaptServers(servers oas.Servers) []domain.Server {
 	return result
 }
 
-func extractOperations(path string, item *oas.PathItem) []domain.Operation {
+func extractOperations(path string, item *oas.PathItem, defaultSecurity []domain.SecurityRequirement) []domain.Operation {
 	type entry struct {
 		method domain.HTTPMethod
 		op     *oas.Operation
@@ -55,18 +60,25 @@ func extractOperations(path string, item *oas.PathItem) []domain.Operation {
 	var ops []domain.Operation
 	for _, c := range candidates {
 		if c.op != nil {
-			ops = append(ops, adaptOperation(path, c.method, item.Parameters, c.op))
+			ops = append(ops, adaptOperation(path, c.method, item.Parameters, c.op, defaultSecurity))
 		}
 	}
 	return ops
 }
 
-func adaptOperation(path string, method domain.HTTPMethod, pathParams oas.Parameters, op *oas.Operation) domain.Operation {
+func adaptOperation(path string, method domain.HTTPMethod, pathParams oas.Parameters, op *oas.Operation, defaultSecurity []domain.SecurityRequirement) domain.Operation {
 	id := op.OperationID
 	if id == "" {
 		id = string(method) + " " + path
 	}
 
+	// An operation-level security list, even an empty one, replaces the
+	// spec-wide default.
+	security := defaultSecurity
+	if op.Security != nil {
+		security = adaptSecurityRequirements(*op.Security)
+	}
+
 	return domain.Operation{
 		ID:          id,
 		Path:        path,
@@ -77,9 +89,19 @@ func adaptOperation(path string, method domain.HTTPMethod, pathParams oas.Parame
 		Parameters:  adaptParameters(mergeParameters(pathParams, op.Parameters)),
 		RequestBody: adaptRequestBody(op.RequestBody),
 		Responses:   adaptResponses(op.Responses),
+		Deprecated:  op.Deprecated,
+		Extensions:  adaptExtensions(op.Extensions),
+		Security:    security,
 	}
 }
 
+func adaptExtensions(ext map[string]any) map[string]any {
+	if len(ext) == 0 {
+		return nil
+	}
+	return ext
+}
+
 // mergeParameters combines path-level and operation-level parameters.
 // Operation-level parameters override path-level parameters with the same name and location.
 // The key uses In+Name directly — In is a lowercase enum per the OpenAPI spec,
@@ -164,6 +186,7 @@ func adaptResponses(responses *oas.Responses) map[string]domain.Response {
 		resp := domain.Response{
 			Content: adaptContent(r.Content),
 			Headers: adaptHeaders(r.Headers),
+			Links:   adaptLinks(r.Links),
 		}
 		if r.Description != nil {
 			resp.Description = *r.Description
@@ -173,6 +196,31 @@ func adaptResponses(responses *oas.Responses) map[string]domain.Response {
 	return result
 }
 
+func adaptLinks(links oas.Links) map[string]domain.Link {
+	if len(links) == 0 {
+		return nil
+	}
+	result := make(map[string]domain.Link, len(links))
+	for name, ref := range links {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		l := ref.Value
+		link := domain.Link{
+			OperationID:  l.OperationID,
+			OperationRef: l.OperationRef,
+			Parameters:   l.Parameters,
+			RequestBody:  l.RequestBody,
+			Description:  l.Description,
+		}
+		if l.Server != nil {
+			link.Server = l.Server.URL
+		}
+		result[name] = link
+	}
+	return result
+}
+
 func adaptContent(content oas.Content) map[string]domain.MediaType {
 	if len(content) == 0 {
 		return nil
@@ -180,7 +228,27 @@ func adaptContent(content oas.Content) map[string]domain.MediaType {
 	result := make(map[string]domain.MediaType, len(content))
 	for mediaType, mt := range content {
 		result[mediaType] = domain.MediaType{
-			Schema: adaptSchemaRef(mt.Schema),
+			Schema:   adaptSchemaRef(mt.Schema),
+			Example:  mt.Example,
+			Examples: adaptExamples(mt.Examples),
+		}
+	}
+	return result
+}
+
+func adaptExamples(examples oas.Examples) map[string]domain.Example {
+	if len(examples) == 0 {
+		return nil
+	}
+	result := make(map[string]domain.Example, len(examples))
+	for name, ref := range examples {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		result[name] = domain.Example{
+			Summary:     ref.Value.Summary,
+			Description: ref.Value.Description,
+			Value:       ref.Value.Value,
 		}
 	}
 	return result
@@ -193,7 +261,9 @@ func adaptSchemaRef(ref *oas.SchemaRef) *domain.Schema {
 	if ref == nil || ref.Value == nil {
 		return nil
 	}
-	return adaptSchema(ref.Value, schemaMaxDepth)
+	s := adaptSchema(ref.Value, schemaMaxDepth)
+	s.Ref = ref.Ref
+	return s
 }
 
 // adaptSchema maps an OAS schema to a domain schema, recursing into Properties
@@ -205,13 +275,25 @@ func adaptSchema(s *oas.Schema, depth int) *domain.Schema {
 		Required:    s.Required,
 	}
 
-	if s.Type != nil && len(*s.Type) > 0 {
-		ds.Type = domain.SchemaType((*s.Type)[0])
+	if s.Type != nil {
+		// OpenAPI 3.1 spells nullable as a "null" member of the type list.
+		for _, t := range *s.Type {
+			if t == "null" {
+				ds.Nullable = true
+			} else if ds.Type == "" {
+				ds.Type = domain.SchemaType(t)
+			}
+		}
 	}
+	ds.Nullable = ds.Nullable || s.Nullable
 
 	if len(s.Enum) > 0 {
 		ds.Enum = s.Enum
 	}
+	ds.Example = s.Example
+	ds.Default = s.Default
+
+	adaptConstraints(s, ds)
 
 	if depth <= 0 {
 		return ds
@@ -219,6 +301,7 @@ func adaptSchema(s *oas.Schema, depth int) *domain.Schema {
 
 	if s.Items != nil && s.Items.Value != nil {
 		ds.Items = adaptSchema(s.Items.Value, depth-1)
+		ds.Items.Ref = s.Items.Ref
 	}
 
 	if len(s.Properties) > 0 {
@@ -226,6 +309,7 @@ func adaptSchema(s *oas.Schema, depth int) *domain.Schema {
 		for name, propRef := range s.Properties {
 			if propRef.Value != nil {
 				ds.Properties[name] = adaptSchema(propRef.Value, depth-1)
+				ds.Properties[name].Ref = propRef.Ref
 			}
 		}
 	}
@@ -233,6 +317,35 @@ func adaptSchema(s *oas.Schema, depth int) *domain.Schema {
 	return ds
 }
 
+// adaptConstraints copies the validation keywords. OpenAPI 3.0 marks a bound
+// exclusive with a boolean; 3.1 gives the exclusive bound as a number.
+func adaptConstraints(s *oas.Schema, ds *domain.Schema) {
+	ds.Minimum = s.Min
+	ds.Maximum = s.Max
+	ds.ExclusiveMinimum = s.ExclusiveMin.IsTrue()
+	ds.ExclusiveMaximum = s.ExclusiveMax.IsTrue()
+	if v := s.ExclusiveMin.Value; v != nil {
+		ds.Minimum, ds.ExclusiveMinimum = v, true
+	}
+	if v := s.ExclusiveMax.Value; v != nil {
+		ds.Maximum, ds.ExclusiveMaximum = v, true
+	}
+
+	ds.MinLength = int(s.MinLength)
+	ds.MaxLength = uintPtrToInt(s.MaxLength)
+	ds.Pattern = s.Pattern
+	ds.MinItems = int(s.MinItems)
+	ds.MaxItems = uintPtrToInt(s.MaxItems)
+}
+
+func uintPtrToInt(u *uint64) *int {
+	if u == nil {
+		return nil
+	}
+	n := int(*u)
+	return &n
+}
+
 func adaptHeaders(headers oas.Headers) map[string]domain.Header {
 	if len(headers) == 0 {
 		return nil
@@ -244,6 +357,7 @@ func adaptHeaders(headers oas.Headers) map[string]domain.Header {
 		}
 		h := domain.Header{
 			Description: ref.Value.Description,
+			Required:    ref.Value.Required,
 		}
 		if ref.Value.Schema != nil {
 			h.Schema = adaptSchemaRef(ref.Value.Schema)
@@ -252,3 +366,68 @@ func adaptHeaders(headers oas.Headers) map[string]domain.Header {
 	}
 	return result
 }
+
+func adaptSecuritySchemes(schemes oas.SecuritySchemes) map[string]domain.SecurityScheme {
+	if len(schemes) == 0 {
+		return nil
+	}
+	result := make(map[string]domain.SecurityScheme, len(schemes))
+	for name, ref := range schemes {
+		if ref == nil || ref.Value == nil {
+			continue
+		}
+		s := ref.Value
+		result[name] = domain.SecurityScheme{
+			Type:             domain.SecuritySchemeType(s.Type),
+			Description:      s.Description,
+			Name:             s.Name,
+			In:               domain.ParameterIn(s.In),
+			Scheme:           s.Scheme,
+			BearerFormat:     s.BearerFormat,
+			Flows:            adaptOAuthFlows(s.Flows),
+			OpenIDConnectURL: s.OpenIdConnectUrl,
+		}
+	}
+	return result
+}
+
+func adaptOAuthFlows(flows *oas.OAuthFlows) *domain.OAuthFlows {
+	if flows == nil {
+		return nil
+	}
+	return &domain.OAuthFlows{
+		Implicit:          adaptOAuthFlow(flows.Implicit),
+		Password:          adaptOAuthFlow(flows.Password),
+		ClientCredentials: adaptOAuthFlow(flows.ClientCredentials),
+		AuthorizationCode: adaptOAuthFlow(flows.AuthorizationCode),
+	}
+}
+
+func adaptOAuthFlow(flow *oas.OAuthFlow) *domain.OAuthFlow {
+	if flow == nil {
+		return nil
+	}
+	return &domain.OAuthFlow{
+		AuthorizationURL: flow.AuthorizationURL,
+		TokenURL:         flow.TokenURL,
+		RefreshURL:       flow.RefreshURL,
+		Scopes:           flow.Scopes,
+	}
+}
+
+// adaptSecurityRequirements preserves the difference between nil (nothing
+// declared) and an empty list (security explicitly disabled).
+func adaptSecurityRequirements(reqs oas.SecurityRequirements) []domain.SecurityRequirement {
+	if reqs == nil {
+		return nil
+	}
+	result := make([]domain.SecurityRequirement, len(reqs))
+	for i, r := range reqs {
+		req := make(domain.SecurityRequirement, len(r))
+		for name, scopes := range r {
+			req[name] = scopes
+		}
+		result[i] = req
+	}
+	return result
+}
@@ -446,6 +446,53 @@ func TestAdaptSchema_NilType(t *testing.T) {
 	}
 }
 
+func TestAdaptSchema_Constraints(t *testing.T) {
+	minimum, maximum := 1.0, 100.0
+	maxLen := uint64(20)
+	schema := &oas.Schema{
+		Type:         &oas.Types{"number"},
+		Min:          &minimum,
+		Max:          &maximum,
+		ExclusiveMax: oas.ExclusiveBound{Bool: oas.Ptr(true)},
+		MinLength:    2,
+		MaxLength:    &maxLen,
+		Pattern:      "^[a-z]+$",
+		Nullable:     true,
+	}
+
+	ds := adaptSchema(schema, schemaMaxDepth)
+
+	if ds.Minimum == nil || *ds.Minimum != 1 || ds.ExclusiveMinimum {
+		t.Errorf("unexpected minimum %v exclusive=%v", ds.Minimum, ds.ExclusiveMinimum)
+	}
+	if ds.Maximum == nil || *ds.Maximum != 100 || !ds.ExclusiveMaximum {
+		t.Errorf("unexpected maximum %v exclusive=%v", ds.Maximum, ds.ExclusiveMaximum)
+	}
+	if ds.MinLength != 2 || ds.MaxLength == nil || *ds.MaxLength != 20 {
+		t.Errorf("unexpected length bounds %d..%v", ds.MinLength, ds.MaxLength)
+	}
+	if ds.Pattern != "^[a-z]+$" || !ds.Nullable {
+		t.Errorf("unexpected pattern %q nullable=%v", ds.Pattern, ds.Nullable)
+	}
+}
+
+func TestAdaptSchema_NullTypeAndNumericExclusiveBound(t *testing.T) {
+	bound := 0.0
+	schema := &oas.Schema{
+		Type:         &oas.Types{"null", "integer"},
+		ExclusiveMin: oas.ExclusiveBound{Value: &bound},
+	}
+
+	ds := adaptSchema(schema, schemaMaxDepth)
+
+	if ds.Type != domain.SchemaTypeInteger || !ds.Nullable {
+		t.Errorf("expected nullable integer, got %q nullable=%v", ds.Type, ds.Nullable)
+	}
+	if ds.Minimum == nil || *ds.Minimum != 0 || !ds.ExclusiveMinimum {
+		t.Errorf("expected exclusive minimum 0, got %v exclusive=%v", ds.Minimum, ds.ExclusiveMinimum)
+	}
+}
+
 func TestAdaptSchemaRef_Nil(t *testing.T) {
 	if adaptSchemaRef(nil) != nil {
 		t.Error("expected nil for nil SchemaRef")
@@ -527,3 +574,35 @@ func TestAdaptResponses_Nil(t *testing.T) {
 		t.Error("expected nil for nil Responses")
 	}
 }
+
+func TestAdaptSchema_ExampleAndDefault(t *testing.T) {
+	schema := &oas.Schema{Type: &oas.Types{"string"}, Example: "Rex", Default: "Fido"}
+
+	ds := adaptSchema(schema, schemaMaxDepth)
+
+	if ds.Example != "Rex" || ds.Default != "Fido" {
+		t.Errorf("expected example Rex and default Fido, got %v and %v", ds.Example, ds.Default)
+	}
+}
+
+func TestAdaptSchemaRef_KeepsReferences(t *testing.T) {
+	strType := oas.Types{"string"}
+	objType := oas.Types{"object"}
+	owner := &oas.SchemaRef{Ref: "#/components/schemas/Owner", Value: &oas.Schema{Type: &objType}}
+	ref := &oas.SchemaRef{
+		Ref: "#/components/schemas/Pet",
+		Value: &oas.Schema{
+			Type: &objType,
+			Properties: oas.Schemas{
+				"name":  &oas.SchemaRef{Value: &oas.Schema{Type: &strType}},
+				"owner": owner,
+			},
+		},
+	}
+
+	ds := adaptSchemaRef(ref)
+
+	if ds.Ref != "#/components/schemas/Pet" || ds.Properties["owner"].Ref != "#/components/schemas/Owner" || ds.Properties["name"].Ref != "" {
+		t.Errorf("unexpected references %q %q %q", ds.Ref, ds.Properties["owner"].Ref, ds.Properties["name"].Ref)
+	}
+}
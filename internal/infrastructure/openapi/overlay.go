@@ -0,0 +1,91 @@
+package openapi
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+
+	"dazzle/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// overlayVersion is the OpenAPI Overlay specification version written.
+const overlayVersion = "1.0.0"
+
+// keyOrder is the order fields are written in, as OpenAPI documents are
+// usually laid out. Other keys, such as paths and property names, follow in
+// alphabetical order.
+var keyOrder = []string{
+	"overlay", "info", "title", "version", "actions", "target", "description", "update",
+	"$ref", "summary", "name", "in", "type", "format", "nullable", "required",
+	"parameters", "requestBody", "responses", "properties", "items", "content", "schema",
+}
+
+// WriteOverlay writes an overlay as an OpenAPI Overlay document in YAML,
+// which tools such as Speakeasy and Redocly apply to a spec.
+func WriteOverlay(w io.Writer, o domain.Overlay) error {
+	actions := make([]any, len(o.Actions))
+	for i, a := range o.Actions {
+		action := map[string]any{"target": a.Target, "update": a.Update}
+		if a.Description != "" {
+			action["description"] = a.Description
+		}
+		actions[i] = action
+	}
+	doc := map[string]any{
+		"overlay": overlayVersion,
+		"info":    map[string]any{"title": o.Title, "version": o.Version},
+		"actions": actions,
+	}
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(orderedNode(doc)); err != nil {
+		return fmt.Errorf("writing overlay: %w", err)
+	}
+	return enc.Close()
+}
+
+// orderedNode converts a document tree to a YAML node with its mapping keys
+// in keyOrder.
+func orderedNode(v any) *yaml.Node {
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			a, b := rank(keys[i]), rank(keys[j])
+			if a != b {
+				return a < b
+			}
+			return keys[i] < keys[j]
+		})
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, k := range keys {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: k}, orderedNode(v[k]))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, item := range v {
+			n.Content = append(n.Content, orderedNode(item))
+		}
+		return n
+	}
+	var n yaml.Node
+	if err := n.Encode(v); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	return &n
+}
+
+func rank(key string) int {
+	if i := slices.Index(keyOrder, key); i >= 0 {
+		return i
+	}
+	return len(keyOrder)
+}
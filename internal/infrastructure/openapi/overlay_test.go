@@ -0,0 +1,42 @@
+package openapi_test
+
+import (
+	"bytes"
+	"testing"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/openapi"
+)
+
+func TestWriteOverlay(t *testing.T) {
+	var out bytes.Buffer
+	err := openapi.WriteOverlay(&out, domain.Overlay{Title: "Additions", Version: "1.0.0", Actions: []domain.OverlayAction{{
+		Target:      "$.paths['/pets/{petId}']",
+		Description: "PUT /pets/{petId}, seen once",
+		Update: map[string]any{"put": map[string]any{
+			"responses": map[string]any{"204": map[string]any{"description": "No Content"}},
+			"summary":   "Seen once in recorded traffic",
+		}},
+	}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `overlay: 1.0.0
+info:
+  title: Additions
+  version: 1.0.0
+actions:
+  - target: $.paths['/pets/{petId}']
+    description: PUT /pets/{petId}, seen once
+    update:
+      put:
+        summary: Seen once in recorded traffic
+        responses:
+          "204":
+            description: No Content
+`
+	if got := out.String(); got != want {
+		t.Errorf("unexpected overlay:\n%s", got)
+	}
+}
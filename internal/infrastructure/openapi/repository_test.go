@@ -64,6 +64,16 @@ func TestRepository_Load(t *testing.T) {
 		}
 	})
 
+	t.Run("response links", func(t *testing.T) {
+		link, ok := indexByID(spec.Operations)["createPet"].Responses["201"].Links["GetPetById"]
+		if !ok {
+			t.Fatal("missing GetPetById link")
+		}
+		if link.OperationID != "getPet" || link.Parameters["petId"] != "$response.body#/id" || link.Description != "The pet just created" {
+			t.Errorf("unexpected link %+v", link)
+		}
+	})
+
 	t.Run("operation-level parameters", func(t *testing.T) {
 		ops := indexByID(spec.Operations)
 
@@ -212,6 +222,9 @@ func TestRepository_Load(t *testing.T) {
 		if h.Description != "Total number of pets" {
 			t.Errorf("unexpected header description: %q", h.Description)
 		}
+		if !h.Required {
+			t.Error("expected header to be required")
+		}
 		if h.Schema == nil {
 			t.Fatal("expected header to have schema")
 		}
@@ -237,6 +250,71 @@ func TestRepository_Load(t *testing.T) {
 		}
 	})
 
+	t.Run("security schemes", func(t *testing.T) {
+		if len(spec.SecuritySchemes) != 2 {
+			t.Fatalf("expected 2 security schemes, got %d", len(spec.SecuritySchemes))
+		}
+		apiKey := spec.SecuritySchemes["apiKey"]
+		if apiKey.Type != domain.SecuritySchemeAPIKey || apiKey.In != domain.ParameterInHeader || apiKey.Name != "X-API-Key" {
+			t.Errorf("unexpected apiKey scheme: %+v", apiKey)
+		}
+		bearer := spec.SecuritySchemes["bearerAuth"]
+		if bearer.Type != domain.SecuritySchemeHTTP || bearer.Scheme != "bearer" || bearer.BearerFormat != "JWT" {
+			t.Errorf("unexpected bearerAuth scheme: %+v", bearer)
+		}
+	})
+
+	t.Run("security requirements", func(t *testing.T) {
+		ops := indexByID(spec.Operations)
+
+		// getPet inherits the spec-wide default.
+		getPet := ops["getPet"]
+		if len(getPet.Security) != 1 {
+			t.Fatalf("expected inherited requirement, got %v", getPet.Security)
+		}
+		if _, ok := getPet.Security[0]["apiKey"]; !ok {
+			t.Errorf("expected apiKey requirement, got %v", getPet.Security)
+		}
+
+		// createPet overrides it.
+		if _, ok := ops["createPet"].Security[0]["bearerAuth"]; !ok {
+			t.Errorf("expected bearerAuth override, got %v", ops["createPet"].Security)
+		}
+
+		// listPets opts out with an empty list, distinct from nil.
+		listPets := ops["listPets"]
+		if listPets.Security == nil || len(listPets.Security) != 0 {
+			t.Errorf("expected explicit empty security, got %#v", listPets.Security)
+		}
+	})
+
+	t.Run("media type examples", func(t *testing.T) {
+		ops := indexByID(spec.Operations)
+
+		example, ok := ops["createPet"].RequestBody.Content["application/json"].Example.(map[string]any)
+		if !ok {
+			t.Fatalf("expected object example, got %T", ops["createPet"].RequestBody.Content["application/json"].Example)
+		}
+		if example["name"] != "Rex" {
+			t.Errorf("unexpected example: %v", example)
+		}
+	})
+
+	t.Run("deprecation and extensions", func(t *testing.T) {
+		ops := indexByID(spec.Operations)
+
+		deletePet := ops["deletePet"]
+		if !deletePet.Deprecated {
+			t.Error("expected deletePet to be deprecated")
+		}
+		if deletePet.Extensions["x-audience"] != "internal" {
+			t.Errorf("expected x-audience extension, got %v", deletePet.Extensions)
+		}
+		if ops["listPets"].Deprecated {
+			t.Error("expected listPets not to be deprecated")
+		}
+	})
+
 	t.Run("nil request body for GET operations", func(t *testing.T) {
 		ops := indexByID(spec.Operations)
 
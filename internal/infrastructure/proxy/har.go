@@ -0,0 +1,111 @@
+package proxy
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// harFile is the part of an HTTP Archive (HAR 1.2) dazzle reads.
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Time            float64   `json:"time"` // milliseconds
+	Request         struct {
+		Method   string      `json:"method"`
+		URL      string      `json:"url"`
+		Headers  []harHeader `json:"headers"`
+		PostData *struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status  int         `json:"status"`
+		Headers []harHeader `json:"headers"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+			Encoding string `json:"encoding"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ReadHAR reads the exchanges in an HTTP Archive, as browsers' developer
+// tools export them. Entries without a response (status 0) become
+// exchanges with an error; HTTP/2 pseudo-headers are dropped.
+func ReadHAR(r io.Reader) ([]domain.RecordedExchange, error) {
+	var har harFile
+	if err := json.NewDecoder(r).Decode(&har); err != nil {
+		return nil, fmt.Errorf("invalid HAR: %w", err)
+	}
+	exchanges := make([]domain.RecordedExchange, 0, len(har.Log.Entries))
+	for i, e := range har.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: %w", i+1, err)
+		}
+		ex := domain.RecordedExchange{
+			ID:            i + 1,
+			Time:          e.StartedDateTime,
+			Duration:      time.Duration(e.Time * float64(time.Millisecond)),
+			Method:        domain.HTTPMethod(e.Request.Method),
+			URL:           u.RequestURI(),
+			RequestHeader: harHeaders(e.Request.Headers),
+		}
+		if pd := e.Request.PostData; pd != nil {
+			ex.RequestBody, ex.RequestTruncated = clip([]byte(pd.Text))
+			if ex.RequestHeader.Get("Content-Type") == "" && pd.MimeType != "" {
+				ex.RequestHeader.Set("Content-Type", pd.MimeType)
+			}
+		}
+		if e.Response.Status == 0 {
+			ex.Error = "no response was recorded"
+			exchanges = append(exchanges, ex)
+			continue
+		}
+		ex.Status = e.Response.Status
+		ex.ResponseHeader = harHeaders(e.Response.Headers)
+		// The recorded text is already decoded from any content encoding.
+		ex.ResponseHeader.Del("Content-Encoding")
+		if ex.ResponseHeader.Get("Content-Type") == "" && e.Response.Content.MimeType != "" {
+			ex.ResponseHeader.Set("Content-Type", e.Response.Content.MimeType)
+		}
+		body := []byte(e.Response.Content.Text)
+		if e.Response.Content.Encoding == "base64" {
+			if body, err = base64.StdEncoding.DecodeString(e.Response.Content.Text); err != nil {
+				return nil, fmt.Errorf("entry %d: response body: %w", i+1, err)
+			}
+		}
+		ex.ResponseBody, ex.ResponseTruncated = clip(body)
+		exchanges = append(exchanges, ex)
+	}
+	return exchanges, nil
+}
+
+func harHeaders(headers []harHeader) http.Header {
+	h := make(http.Header, len(headers))
+	for _, hd := range headers {
+		if hd.Name == "" || hd.Name[0] == ':' {
+			continue
+		}
+		h.Add(hd.Name, hd.Value)
+	}
+	return h
+}
@@ -0,0 +1,154 @@
+// Package proxy is a reverse proxy that records the exchanges passing
+// through it.
+package proxy
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// maxRecordedBody caps the bodies kept in a recorded exchange. Bodies are
+// forwarded whole regardless.
+const maxRecordedBody = 1 << 20
+
+type exchangeKey struct{}
+
+// recording is an exchange on its way through the proxy, with the bodies
+// seen so far.
+type recording struct {
+	ex       domain.RecordedExchange
+	request  *teeBody
+	response *teeBody
+}
+
+// teeBody passes a body through while keeping its first maxRecordedBody
+// bytes for the recording.
+type teeBody struct {
+	io.ReadCloser
+	kept      bytes.Buffer
+	truncated bool
+}
+
+func (b *teeBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	kept := min(n, maxRecordedBody-b.kept.Len())
+	b.kept.Write(p[:kept])
+	b.truncated = b.truncated || kept < n
+	return n, err
+}
+
+// recorded is the body as kept, decoded, and whether it was cut short.
+func (b *teeBody) recorded(encoding string) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	body, truncated := clip(decode(b.kept.Bytes(), encoding, b.truncated))
+	return body, truncated || b.truncated
+}
+
+// Proxy forwards requests to an upstream and hands every exchange to a
+// record function once the response has been sent. Bodies stream through
+// as they arrive.
+type Proxy struct {
+	rp     *httputil.ReverseProxy
+	record func(domain.RecordedExchange)
+	seq    atomic.Int64
+	now    func() time.Time
+}
+
+// New proxies to upstream, whose path prefixes every request's path.
+func New(upstream *url.URL, record func(domain.RecordedExchange)) *Proxy {
+	p := &Proxy{record: record, now: time.Now}
+	p.rp = &httputil.ReverseProxy{
+		Rewrite: func(r *httputil.ProxyRequest) {
+			r.SetURL(upstream)
+			r.SetXForwarded()
+		},
+		ModifyResponse: p.captureResponse,
+		ErrorHandler:   p.upstreamFailed,
+	}
+	return p
+}
+
+func (p *Proxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := p.now()
+	rec := &recording{ex: domain.RecordedExchange{
+		ID:            int(p.seq.Add(1)),
+		Time:          start,
+		Method:        domain.HTTPMethod(r.Method),
+		URL:           r.URL.RequestURI(),
+		RequestHeader: r.Header.Clone(),
+	}}
+	if r.Body != nil {
+		rec.request = &teeBody{ReadCloser: r.Body}
+		r.Body = rec.request
+	}
+	p.rp.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), exchangeKey{}, rec)))
+
+	ex := rec.ex
+	ex.Duration = p.now().Sub(start)
+	ex.RequestBody, ex.RequestTruncated = rec.request.recorded(r.Header.Get("Content-Encoding"))
+	if rec.response != nil {
+		ex.ResponseBody, ex.ResponseTruncated = rec.response.recorded(ex.ResponseHeader.Get("Content-Encoding"))
+	}
+	p.record(ex)
+}
+
+func (p *Proxy) captureResponse(resp *http.Response) error {
+	rec := resp.Request.Context().Value(exchangeKey{}).(*recording)
+	rec.ex.Status = resp.StatusCode
+	rec.ex.ResponseHeader = resp.Header.Clone()
+	rec.response = &teeBody{ReadCloser: resp.Body}
+	resp.Body = rec.response
+	return nil
+}
+
+func (p *Proxy) upstreamFailed(w http.ResponseWriter, r *http.Request, err error) {
+	if rec, ok := r.Context().Value(exchangeKey{}).(*recording); ok {
+		rec.ex.Error = err.Error()
+	}
+	http.Error(w, "dazzle proxy: "+err.Error(), http.StatusBadGateway)
+}
+
+// decode undoes gzip and deflate content encodings, returning other bodies
+// as they are. A partial body, cut short for the recording, decodes as far
+// as it goes.
+func decode(body []byte, encoding string, partial bool) []byte {
+	var r io.Reader
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "gzip":
+		zr, err := gzip.NewReader(bytes.NewReader(body))
+		if err != nil {
+			return body
+		}
+		r = zr
+	case "deflate":
+		r = flate.NewReader(bytes.NewReader(body))
+	default:
+		return body
+	}
+	decoded, err := io.ReadAll(io.LimitReader(r, maxRecordedBody+1))
+	if err != nil && !(partial && errors.Is(err, io.ErrUnexpectedEOF)) {
+		return body
+	}
+	return decoded
+}
+
+func clip(body []byte) (string, bool) {
+	if len(body) > maxRecordedBody {
+		return string(body[:maxRecordedBody]), true
+	}
+	return string(body), false
+}
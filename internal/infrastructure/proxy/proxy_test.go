@@ -0,0 +1,265 @@
+package proxy_test
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/proxy"
+)
+
+// exchanges collects what a proxy records.
+type exchanges struct {
+	mu  sync.Mutex
+	got []domain.RecordedExchange
+}
+
+func (e *exchanges) record(ex domain.RecordedExchange) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.got = append(e.got, ex)
+}
+
+func (e *exchanges) all() []domain.RecordedExchange {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.got
+}
+
+func TestProxy_ForwardsAndRecords(t *testing.T) {
+	var seen *http.Request
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.WriteHeader(http.StatusCreated)
+		zw := gzip.NewWriter(w)
+		_, _ = zw.Write([]byte(`{"id":7}`))
+		_ = zw.Close()
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL + "/api")
+	rec := &exchanges{}
+	srv := httptest.NewServer(proxy.New(target, rec.record))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL+"/pets?dry=1", strings.NewReader(`{"name":"Rex"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if seen.URL.Path != "/api/pets" || seen.URL.RawQuery != "dry=1" || seen.Header.Get("X-Forwarded-Host") == "" {
+		t.Errorf("unexpected upstream request %s %v", seen.URL, seen.Header)
+	}
+	if resp.StatusCode != http.StatusCreated || string(body) != `{"id":7}` {
+		t.Errorf("unexpected response %d %s", resp.StatusCode, body)
+	}
+	got := rec.all()
+	if len(got) != 1 {
+		t.Fatalf("expected one exchange, got %d", len(got))
+	}
+	ex := got[0]
+	if ex.ID != 1 || ex.Method != domain.POST || ex.URL != "/pets?dry=1" || ex.RequestBody != `{"name":"Rex"}` ||
+		ex.Status != http.StatusCreated || ex.ResponseBody != `{"id":7}` || ex.Error != "" {
+		t.Errorf("unexpected exchange %+v", ex)
+	}
+}
+
+func TestProxy_ClipsLargeRequestBodies(t *testing.T) {
+	var forwarded int
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		forwarded = len(data)
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+	rec := &exchanges{}
+	srv := httptest.NewServer(proxy.New(target, rec.record))
+	defer srv.Close()
+
+	body := strings.Repeat("x", 2<<20)
+	resp, err := http.Post(srv.URL+"/pets", "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+
+	if forwarded != len(body) {
+		t.Errorf("expected the whole body forwarded, got %d bytes", forwarded)
+	}
+	ex := rec.all()[0]
+	if !ex.RequestTruncated || len(ex.RequestBody) != 1<<20 {
+		t.Errorf("expected the recorded body cut to 1 MiB, got %d bytes, truncated %v", len(ex.RequestBody), ex.RequestTruncated)
+	}
+}
+
+func TestProxy_StreamsResponses(t *testing.T) {
+	release := make(chan struct{})
+	var once sync.Once
+	unblock := func() { once.Do(func() { close(release) }) }
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("first\n"))
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = w.Write([]byte(strings.Repeat("x", 2<<20)))
+	}))
+	defer upstream.Close()
+	target, _ := url.Parse(upstream.URL)
+	rec := &exchanges{}
+	srv := httptest.NewServer(proxy.New(target, rec.record))
+	defer srv.Close()
+	defer unblock()
+
+	var resp *http.Response
+	first := make(chan string, 1)
+	go func() {
+		defer close(first)
+		r, err := http.Get(srv.URL + "/events")
+		if err != nil {
+			return
+		}
+		resp = r
+		line, _ := bufio.NewReader(io.LimitReader(resp.Body, 6)).ReadString('\n')
+		first <- line
+	}()
+	select {
+	case line, ok := <-first:
+		if !ok || line != "first\n" {
+			t.Fatalf("unexpected first line %q", line)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the first line before the response ended")
+	}
+	unblock()
+	rest, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if len(rest) != 2<<20 {
+		t.Errorf("expected the whole body forwarded, got %d more bytes", len(rest))
+	}
+	ex := rec.all()[0]
+	if !ex.ResponseTruncated || len(ex.ResponseBody) != 1<<20 || !strings.HasPrefix(ex.ResponseBody, "first\nx") {
+		t.Errorf("expected the recorded body cut to 1 MiB, got %d bytes, truncated %v", len(ex.ResponseBody), ex.ResponseTruncated)
+	}
+}
+
+func TestProxy_UnreachableUpstream(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	target, _ := url.Parse(upstream.URL)
+	upstream.Close()
+	rec := &exchanges{}
+	srv := httptest.NewServer(proxy.New(target, rec.record))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/pets")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadGateway || !strings.HasPrefix(string(body), "dazzle proxy: ") {
+		t.Errorf("unexpected response %d %s", resp.StatusCode, body)
+	}
+	if got := rec.all(); len(got) != 1 || got[0].Error == "" || got[0].Status != 0 {
+		t.Errorf("expected a failed exchange, got %+v", got)
+	}
+}
+
+func TestRecorder_WritesJSONLines(t *testing.T) {
+	var out bytes.Buffer
+	r := proxy.NewRecorder(&out)
+	for _, ex := range []domain.RecordedExchange{
+		{ID: 1, Method: domain.GET, URL: "/pets?a=<b>", Status: 200},
+		{ID: 2, Method: domain.DELETE, URL: "/pets/1", Status: 500,
+			ResponseViolations: []domain.Violation{{Path: "status", Message: "500 is not a documented response"}}},
+	} {
+		if err := r.Record(ex); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"url":"/pets?a=<b>"`) {
+		t.Fatalf("unexpected recording:\n%s", out.String())
+	}
+	var ex domain.RecordedExchange
+	if err := json.Unmarshal([]byte(lines[1]), &ex); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ex.ID != 2 || len(ex.ResponseViolations) != 1 || ex.ResponseViolations[0].Path != "status" {
+		t.Errorf("unexpected round trip %+v", ex)
+	}
+}
+
+func TestReadRecording_RoundTrips(t *testing.T) {
+	var out bytes.Buffer
+	r := proxy.NewRecorder(&out)
+	_ = r.Record(domain.RecordedExchange{ID: 1, Method: domain.GET, URL: "/pets", Status: 200})
+	_ = r.Record(domain.RecordedExchange{ID: 2, Method: domain.POST, URL: "/pets", RequestBody: `{"name":"Rex"}`, Status: 201})
+
+	got, err := proxy.ReadRecording(&out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[1].RequestBody != `{"name":"Rex"}` || got[1].Status != 201 {
+		t.Errorf("unexpected exchanges %+v", got)
+	}
+	if _, err := proxy.ReadRecording(strings.NewReader("{\"id\": 1}\n{oops\n")); err == nil || !strings.Contains(err.Error(), "exchange 2") {
+		t.Errorf("expected the broken exchange to be reported, got %v", err)
+	}
+}
+
+func TestLoadRecording_ReadsHAR(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "session.har")
+	har := `{"log": {"entries": [
+		{"startedDateTime": "2024-05-01T10:00:00Z", "time": 12.5,
+		 "request": {"method": "POST", "url": "https://api.example.com/v1/pets?dry=1",
+		   "headers": [{"name": ":authority", "value": "api.example.com"}, {"name": "Accept", "value": "application/json"}],
+		   "postData": {"mimeType": "application/json", "text": "{\"name\":\"Rex\"}"}},
+		 "response": {"status": 201, "headers": [{"name": "Content-Encoding", "value": "gzip"}],
+		   "content": {"mimeType": "application/json", "encoding": "base64", "text": "eyJpZCI6N30="}}},
+		{"startedDateTime": "2024-05-01T10:00:01Z", "time": 0,
+		 "request": {"method": "GET", "url": "https://api.example.com/v1/pets", "headers": []},
+		 "response": {"status": 0, "headers": [], "content": {}}}
+	]}}`
+	if err := os.WriteFile(path, []byte(har), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := proxy.LoadRecording(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected two exchanges, got %d", len(got))
+	}
+	ex := got[0]
+	if ex.Method != domain.POST || ex.URL != "/v1/pets?dry=1" || ex.Duration != 12500*time.Microsecond ||
+		ex.RequestHeader.Get(":authority") != "" || ex.RequestHeader.Get("Content-Type") != "application/json" ||
+		ex.RequestBody != `{"name":"Rex"}` {
+		t.Errorf("unexpected request %+v", ex)
+	}
+	if ex.Status != 201 || ex.ResponseBody != `{"id":7}` || ex.ResponseHeader.Get("Content-Encoding") != "" ||
+		ex.ResponseHeader.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected response %+v", ex)
+	}
+	if got[1].Error == "" {
+		t.Errorf("expected an entry without a response to fail, got %+v", got[1])
+	}
+}
@@ -0,0 +1,81 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"dazzle/internal/domain"
+)
+
+// Recorder writes exchanges as JSON lines, one exchange per line. It
+// stops at the first error, which Err reports.
+type Recorder struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	err error
+}
+
+func NewRecorder(w io.Writer) *Recorder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &Recorder{enc: enc}
+}
+
+func (r *Recorder) Record(ex domain.RecordedExchange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	if err := r.enc.Encode(ex); err != nil {
+		r.err = fmt.Errorf("recording exchange %d: %w", ex.ID, err)
+	}
+	return r.err
+}
+
+func (r *Recorder) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// LoadRecording reads the exchanges in a file written by a Recorder, or in
+// a HAR file when its name ends in .har.
+func LoadRecording(path string) ([]domain.RecordedExchange, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading recording: %w", err)
+	}
+	defer f.Close()
+	read := ReadRecording
+	if strings.EqualFold(filepath.Ext(path), ".har") {
+		read = ReadHAR
+	}
+	exchanges, err := read(f)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return exchanges, nil
+}
+
+// ReadRecording reads exchanges written by a Recorder.
+func ReadRecording(r io.Reader) ([]domain.RecordedExchange, error) {
+	var exchanges []domain.RecordedExchange
+	dec := json.NewDecoder(r)
+	for {
+		var ex domain.RecordedExchange
+		err := dec.Decode(&ex)
+		if err == io.EOF {
+			return exchanges, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("exchange %d: %w", len(exchanges)+1, err)
+		}
+		exchanges = append(exchanges, ex)
+	}
+}
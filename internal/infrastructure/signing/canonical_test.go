@@ -0,0 +1,55 @@
+package signing
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// The suite's get-space and get-utf8 write their paths unescaped on the
+// request line, so their canonical paths are encoded once. A client sends
+// the path escaped and most services encode it again, so these requests
+// are checked by their canonical form, and the signing step by the suite's.
+func TestSigV4_CanonicalPaths(t *testing.T) {
+	const emptyHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	for _, tc := range []struct {
+		name, service, url, path string
+	}{
+		{"get-space", "service", "https://example.amazonaws.com/example space/", "/example%2520space/"},
+		{"get-space escaped", "service", "https://example.amazonaws.com/example%20space/", "/example%2520space/"},
+		{"get-utf8", "service", "https://example.amazonaws.com/ሴ", "/%25E1%2588%25B4"},
+		// S3 paths are encoded once and neither normalised nor split at an
+		// escaped slash.
+		{"s3", "s3", "https://example.amazonaws.com/a%2Fb/./c//example%20space", "/a%2Fb/./c//example%20space"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			u, err := url.Parse(tc.url)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			req := &domain.HTTPRequest{Method: domain.GET, URL: tc.url, Header: http.Header{"X-Amz-Date": {"20150830T123600Z"}}}
+			got, _ := SigV4{Service: tc.service}.canonicalRequest(req, u, emptyHash)
+			want := "GET\n" + tc.path + "\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" + emptyHash
+			if got != want {
+				t.Errorf("canonical request:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestSigV4_SuiteSignatures(t *testing.T) {
+	signer := SigV4{SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", Region: "us-east-1", Service: "service"}
+	for _, tc := range []struct{ name, path, want string }{
+		{"get-space", "/example%20space/", "652487583200325589f1fba4c7e578f72c47cb61beeca81406b39ddec1366741"},
+		{"get-utf8", "/%E1%88%B4", "8318018e0b0f223aa2bbf98705b62bb787dc9c0e678f255a891fd03141be5d85"},
+	} {
+		canonical := "GET\n" + tc.path + "\n\nhost:example.amazonaws.com\nx-amz-date:20150830T123600Z\n\nhost;x-amz-date\n" +
+			"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+		if _, got := signer.signature(canonical, time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)); got != tc.want {
+			t.Errorf("%s: signature = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package signing
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"dazzle/internal/domain"
+)
+
+// RequestTarget is the pseudo-header naming the method and path, as in
+// HTTP Signatures.
+const RequestTarget = "(request-target)"
+
+// Defaults for the HMAC signer, following the HTTP Signatures draft.
+const (
+	defaultHMACAlgorithm = "hmac-sha256"
+	defaultHMACHeader    = "Authorization"
+	defaultHMACFormat    = `Signature keyId="{keyId}",algorithm="{algorithm}",headers="{headers}",signature="{signature}"`
+)
+
+// HMAC signs requests with a shared secret. By default the signed string is
+// one "name: value" line per entry of Headers, as in HTTP Signatures;
+// Canonical replaces it with a template. Templates use placeholders:
+//
+//	{method}       upper-case method
+//	{path}         escaped path
+//	{query}        raw query string
+//	{target}       lower-case method, space, path and query
+//	{host}         host
+//	{date}         the Date header
+//	{timestamp}    Unix seconds
+//	{body-sha256}  hex SHA-256 of the body
+//	{header:Name}  any request header
+//
+// Format builds the signature header's value and may also use {keyId},
+// {algorithm}, {headers} (the signed header names) and {signature}.
+type HMAC struct {
+	KeyID           string
+	Secret          string
+	Algorithm       string   // hmac-sha1, hmac-sha256 (default) or hmac-sha512
+	Headers         []string // signed headers; default (request-target), host, date
+	Canonical       string   // template for the signed string
+	Header          string   // header receiving the signature; default Authorization
+	Format          string   // template for that header's value
+	Encoding        string   // base64 (default) or hex
+	TimestampHeader string   // also send {timestamp} in this header
+}
+
+// Sign adds the signature header. Date, Digest and Host are filled in when
+// they are signed but not yet set.
+func (s HMAC) Sign(req *domain.HTTPRequest, now time.Time) error {
+	if s.Secret == "" {
+		return errors.New("hmac: no secret")
+	}
+	newHash, err := hmacHash(s.algorithm())
+	if err != nil {
+		return err
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil {
+		return fmt.Errorf("hmac: parsing URL: %w", err)
+	}
+	if req.Header == nil {
+		req.Header = make(http.Header)
+	}
+
+	now = now.UTC()
+	if s.TimestampHeader != "" {
+		req.Header.Set(s.TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	}
+	headers := s.headers()
+	for _, name := range headers {
+		s.fillHeader(req, strings.ToLower(name), now)
+	}
+
+	vars := requestVars(req, u, now)
+	var signed string
+	if s.Canonical != "" {
+		signed, err = expand(s.Canonical, vars, req.Header)
+	} else {
+		signed, err = headerLines(headers, vars, req.Header)
+	}
+	if err != nil {
+		return fmt.Errorf("hmac: %w", err)
+	}
+
+	mac := hmac.New(newHash, []byte(s.Secret))
+	mac.Write([]byte(signed))
+	sum := mac.Sum(nil)
+	sig := base64.StdEncoding.EncodeToString(sum)
+	if strings.EqualFold(s.Encoding, "hex") {
+		sig = hex.EncodeToString(sum)
+	}
+
+	vars["keyId"] = s.KeyID
+	vars["algorithm"] = s.algorithm()
+	vars["headers"] = strings.ToLower(strings.Join(headers, " "))
+	vars["signature"] = sig
+	format := s.Format
+	if format == "" {
+		format = defaultHMACFormat
+	}
+	value, err := expand(format, vars, req.Header)
+	if err != nil {
+		return fmt.Errorf("hmac: %w", err)
+	}
+
+	header := s.Header
+	if header == "" {
+		header = defaultHMACHeader
+	}
+	req.Header.Set(header, value)
+	return nil
+}
+
+func (s HMAC) algorithm() string {
+	if s.Algorithm == "" {
+		return defaultHMACAlgorithm
+	}
+	return strings.ToLower(s.Algorithm)
+}
+
+func (s HMAC) headers() []string {
+	if len(s.Headers) == 0 {
+		return []string{RequestTarget, "host", "date"}
+	}
+	return s.Headers
+}
+
+func hmacHash(algorithm string) (func() hash.Hash, error) {
+	switch algorithm {
+	case "hmac-sha1":
+		return sha1.New, nil
+	case "hmac-sha256":
+		return sha256.New, nil
+	case "hmac-sha512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("hmac: unsupported algorithm %q", algorithm)
+}
+
+// fillHeader sets the headers the signer can compute when they are signed
+// but missing.
+func (s HMAC) fillHeader(req *domain.HTTPRequest, name string, now time.Time) {
+	if req.Header.Get(name) != "" {
+		return
+	}
+	switch name {
+	case "date":
+		req.Header.Set("Date", now.Format(http.TimeFormat))
+	case "digest":
+		sum := sha256.Sum256(req.Body)
+		req.Header.Set("Digest", "SHA-256="+base64.StdEncoding.EncodeToString(sum[:]))
+	}
+}
+
+func requestVars(req *domain.HTTPRequest, u *url.URL, now time.Time) map[string]string {
+	target := u.EscapedPath()
+	if target == "" {
+		target = "/"
+	}
+	if u.RawQuery != "" {
+		target += "?" + u.RawQuery
+	}
+	return map[string]string{
+		"method":      strings.ToUpper(string(req.Method)),
+		"path":        u.EscapedPath(),
+		"query":       u.RawQuery,
+		"target":      strings.ToLower(string(req.Method)) + " " + target,
+		"host":        hostOf(u, req.Header),
+		"date":        req.Header.Get("Date"),
+		"timestamp":   strconv.FormatInt(now.Unix(), 10),
+		"body-sha256": hashHex(req.Body),
+	}
+}
+
+// headerLines builds the HTTP Signatures signing string.
+func headerLines(headers []string, vars map[string]string, h http.Header) (string, error) {
+	lines := make([]string, len(headers))
+	for i, name := range headers {
+		lower := strings.ToLower(name)
+		var value string
+		switch lower {
+		case RequestTarget:
+			value = vars["target"]
+		case "host":
+			value = vars["host"]
+		default:
+			values := h.Values(name)
+			if len(values) == 0 {
+				return "", fmt.Errorf("signed header %s is not set", name)
+			}
+			value = strings.Join(values, ", ")
+		}
+		lines[i] = lower + ": " + strings.TrimSpace(value)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9-]+)(?::([^}]+))?\}`)
+
+// expand fills a template's placeholders; an unknown one is an error.
+// Escape sequences \n and \t in the template become newline and tab, so
+// templates can be written on one line in YAML.
+func expand(template string, vars map[string]string, h http.Header) (string, error) {
+	template = strings.NewReplacer(`\n`, "\n", `\t`, "\t").Replace(template)
+	var unknown []string
+	out := placeholder.ReplaceAllStringFunc(template, func(m string) string {
+		parts := placeholder.FindStringSubmatch(m)
+		if parts[1] == "header" && parts[2] != "" {
+			return h.Get(parts[2])
+		}
+		if v, ok := vars[parts[1]]; ok && parts[2] == "" {
+			return v
+		}
+		unknown = append(unknown, m)
+		return m
+	})
+	if len(unknown) > 0 {
+		return "", fmt.Errorf("unknown placeholder %s", strings.Join(unknown, ", "))
+	}
+	return out, nil
+}
@@ -0,0 +1,96 @@
+package signing_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/signing"
+)
+
+var hmacTime = time.Date(2014, 6, 9, 21, 0, 0, 0, time.UTC)
+
+func TestHMAC_HTTPSignaturesDefaults(t *testing.T) {
+	req := &domain.HTTPRequest{Method: domain.POST, URL: "https://example.com/foo?param=value&pet=dog", Header: http.Header{}, Body: []byte(`{"hello": "world"}`)}
+	signer := signing.HMAC{KeyID: "test", Secret: "s3cret", Headers: []string{signing.RequestTarget, "host", "date", "digest"}}
+	if err := signer.Sign(req, hmacTime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := req.Header.Get("Date"); got != "Mon, 09 Jun 2014 21:00:00 GMT" {
+		t.Errorf("expected Date to be filled in, got %q", got)
+	}
+	if got := req.Header.Get("Digest"); got != "SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=" {
+		t.Errorf("expected Digest to be filled in, got %q", got)
+	}
+
+	signed := strings.Join([]string{
+		"(request-target): post /foo?param=value&pet=dog",
+		"host: example.com",
+		"date: Mon, 09 Jun 2014 21:00:00 GMT",
+		"digest: SHA-256=X48E9qOokqqrvdts8nOJRJN3OWDUoyWxBf7kbu9DBPE=",
+	}, "\n")
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(signed))
+	want := `Signature keyId="test",algorithm="hmac-sha256",headers="(request-target) host date digest",signature="` +
+		base64.StdEncoding.EncodeToString(mac.Sum(nil)) + `"`
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHMAC_CustomCanonicalForm(t *testing.T) {
+	req := &domain.HTTPRequest{Method: domain.GET, URL: "https://api.partner.example.com/v1/orders?status=open", Header: http.Header{"X-Tenant": {"acme"}}}
+	signer := signing.HMAC{
+		KeyID:           "partner-1",
+		Secret:          "k",
+		Algorithm:       "HMAC-SHA512",
+		Canonical:       `{method}\n{path}\n{query}\n{timestamp}\n{header:X-Tenant}\n{body-sha256}`,
+		Header:          "X-Signature",
+		Format:          "{keyId}:{signature}",
+		Encoding:        "hex",
+		TimestampHeader: "X-Timestamp",
+	}
+	if err := signer.Sign(req, hmacTime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := req.Header.Get("X-Timestamp"); got != "1402347600" {
+		t.Errorf("X-Timestamp = %q", got)
+	}
+	signed := "GET\n/v1/orders\nstatus=open\n1402347600\nacme\ne3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	mac := hmac.New(sha512.New, []byte("k"))
+	mac.Write([]byte(signed))
+	if got, want := req.Header.Get("X-Signature"), "partner-1:"+hex.EncodeToString(mac.Sum(nil)); got != want {
+		t.Errorf("X-Signature = %s, want %s", got, want)
+	}
+	if req.Header.Get("Authorization") != "" {
+		t.Error("expected Authorization to be left alone")
+	}
+}
+
+func TestHMAC_Errors(t *testing.T) {
+	req := func() *domain.HTTPRequest {
+		return &domain.HTTPRequest{Method: domain.GET, URL: "https://example.com/", Header: http.Header{}}
+	}
+	for _, tc := range []struct {
+		signer signing.HMAC
+		want   string
+	}{
+		{signing.HMAC{}, "hmac: no secret"},
+		{signing.HMAC{Secret: "k", Algorithm: "hmac-md5"}, `hmac: unsupported algorithm "hmac-md5"`},
+		{signing.HMAC{Secret: "k", Headers: []string{"x-missing"}}, "hmac: signed header x-missing is not set"},
+		{signing.HMAC{Secret: "k", Canonical: "{method}{nope}"}, "hmac: unknown placeholder {nope}"},
+	} {
+		if err := tc.signer.Sign(req(), hmacTime); err == nil || err.Error() != tc.want {
+			t.Errorf("expected %q, got %v", tc.want, err)
+		}
+	}
+}
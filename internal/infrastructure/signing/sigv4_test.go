@@ -0,0 +1,117 @@
+package signing_test
+
+import (
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/signing"
+)
+
+// Credentials and time shared by the AWS Signature Version 4 test suite.
+var (
+	vectorTime   = time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	vectorSigner = signing.SigV4{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         "service",
+	}
+)
+
+func signature(t *testing.T, req *domain.HTTPRequest) string {
+	t.Helper()
+	auth := req.Header.Get("Authorization")
+	_, sig, ok := strings.Cut(auth, "Signature=")
+	if !ok {
+		t.Fatalf("no signature in %q", auth)
+	}
+	return sig
+}
+
+func TestSigV4_TestSuite(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		method domain.HTTPMethod
+		url    string
+		want   string
+	}{
+		{"get-vanilla", domain.GET, "https://example.amazonaws.com/", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"get-vanilla-query-order-key-case", domain.GET, "https://example.amazonaws.com/?Param2=value2&Param1=value1", "b97d918cfa904a5beff61c982a1b6f458b799221646efd99d3219ec94cdf2500"},
+		{"post-vanilla", domain.POST, "https://example.amazonaws.com/", "5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b"},
+		{"normalize-path/get-relative", domain.GET, "https://example.amazonaws.com/example/..", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"normalize-path/get-relative-relative", domain.GET, "https://example.amazonaws.com/example1/example2/../..", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"normalize-path/get-slash", domain.GET, "https://example.amazonaws.com//", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"normalize-path/get-slash-dot-slash", domain.GET, "https://example.amazonaws.com/./", "5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"},
+		{"normalize-path/get-slash-pointless-dot", domain.GET, "https://example.amazonaws.com/./example", "ef75d96142cf21edca26f06005da7988e4f8dc83a165a80865db7089db637ec5"},
+		{"normalize-path/get-slashes", domain.GET, "https://example.amazonaws.com//example//", "9a624bd73a37c9a373b5312afbebe7a714a789de108f0bdfe846570885f57e84"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := &domain.HTTPRequest{Method: tc.method, URL: tc.url, Header: http.Header{}}
+			if err := vectorSigner.Sign(req, vectorTime); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := signature(t, req); got != tc.want {
+				t.Errorf("signature = %s, want %s", got, tc.want)
+			}
+			want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=" + tc.want
+			if got := req.Header.Get("Authorization"); got != want {
+				t.Errorf("Authorization = %s", got)
+			}
+			if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+				t.Errorf("X-Amz-Date = %s", got)
+			}
+		})
+	}
+}
+
+// TestSigV4_IAMListUsers follows the worked example in the AWS general
+// reference ("Signature Version 4 signing process").
+func TestSigV4_IAMListUsers(t *testing.T) {
+	key := signing.SigningKey(vectorSigner.SecretAccessKey, vectorTime, "us-east-1", "iam")
+	if got := hex.EncodeToString(key); got != "c4afb1cc5771d871763a393e44b703571b55cc28424d1a5e86da6ed3c154a4b9" {
+		t.Errorf("signing key = %s", got)
+	}
+
+	signer := vectorSigner
+	signer.Service = "iam"
+	req := &domain.HTTPRequest{
+		Method: domain.GET,
+		URL:    "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08",
+		Header: http.Header{"Content-Type": {"application/x-www-form-urlencoded; charset=utf-8"}},
+	}
+	if err := signer.Sign(req, vectorTime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := signature(t, req); got != "5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7" {
+		t.Errorf("signature = %s", got)
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "SignedHeaders=content-type;host;x-amz-date,") {
+		t.Errorf("unexpected signed headers in %s", req.Header.Get("Authorization"))
+	}
+}
+
+func TestSigV4_SessionToken(t *testing.T) {
+	signer := vectorSigner
+	signer.SessionToken = "session"
+	req := &domain.HTTPRequest{Method: domain.GET, URL: "https://example.amazonaws.com/", Header: http.Header{"User-Agent": {"dazzle"}}}
+	if err := signer.Sign(req, vectorTime); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if req.Header.Get("X-Amz-Security-Token") != "session" {
+		t.Error("expected the session token header")
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "SignedHeaders=host;x-amz-date;x-amz-security-token,") {
+		t.Errorf("expected the token signed and User-Agent not, got %s", req.Header.Get("Authorization"))
+	}
+}
+
+func TestSigV4_MissingCredentials(t *testing.T) {
+	err := signing.SigV4{Region: "us-east-1", Service: "execute-api"}.Sign(&domain.HTTPRequest{URL: "https://x"}, vectorTime)
+	if err == nil {
+		t.Error("expected an error without credentials")
+	}
+}
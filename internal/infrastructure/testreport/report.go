@@ -0,0 +1,214 @@
+// Package testreport writes the results of a contract test run for people
+// and for CI systems.
+package testreport
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"dazzle/internal/domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats lists the formats Write supports.
+var Formats = []string{"text", "junit", "json", "tap"}
+
+// Write writes run in the named format.
+func Write(w io.Writer, format string, run domain.TestRun) error {
+	switch format {
+	case "text":
+		return writeText(w, run)
+	case "junit":
+		return writeJUnit(w, run)
+	case "json":
+		return writeJSON(w, run)
+	case "tap":
+		return writeTAP(w, run)
+	}
+	return fmt.Errorf("unknown report format %q (have: %s)", format, strings.Join(Formats, ", "))
+}
+
+func title(r domain.TestResult) string {
+	return r.Collection + " › " + r.Name
+}
+
+func writeText(w io.Writer, run domain.TestRun) error {
+	for _, r := range run.Results {
+		switch {
+		case r.Skipped != "":
+			fmt.Fprintf(w, "- %s skipped: %s\n", title(r), r.Skipped)
+			continue
+		case r.Error != "":
+			fmt.Fprintf(w, "✗ %s\n    %s\n", title(r), r.Error)
+			continue
+		case r.Passed():
+			fmt.Fprintf(w, "✓ %s", title(r))
+		default:
+			fmt.Fprintf(w, "✗ %s", title(r))
+		}
+		fmt.Fprintf(w, "  %s %s → %d (%s)\n", r.Method, r.URL, r.Status, r.Duration.Round(time.Millisecond))
+		for _, f := range r.Failures {
+			fmt.Fprintf(w, "    %s\n", f)
+		}
+	}
+	passed, failed, skipped := run.Counts()
+	_, err := fmt.Fprintf(w, "\n%d passed, %d failed, %d skipped in %s\n", passed, failed, skipped, run.Duration.Round(time.Millisecond))
+	return err
+}
+
+type jsonReport struct {
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+	domain.TestRun
+}
+
+func writeJSON(w io.Writer, run domain.TestRun) error {
+	report := jsonReport{TestRun: run}
+	report.Passed, report.Failed, report.Skipped = run.Counts()
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+// JUnit XML, in the form CI systems such as Jenkins, GitLab and GitHub
+// Actions reporters read. Each collection is a test suite.
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Time     string       `xml:"time,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Skipped   int         `xml:"skipped,attr"`
+	Time      string      `xml:"time,attr"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+	duration  time.Duration
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func writeJUnit(w io.Writer, run domain.TestRun) error {
+	doc := junitSuites{Name: "dazzle", Time: seconds(run.Duration)}
+	index := map[string]int{}
+	for _, r := range run.Results {
+		i, ok := index[r.Collection]
+		if !ok {
+			i = len(doc.Suites)
+			index[r.Collection] = i
+			doc.Suites = append(doc.Suites, junitSuite{Name: r.Collection, Timestamp: run.Started.UTC().Format("2006-01-02T15:04:05")})
+		}
+		suite := &doc.Suites[i]
+		c := junitCase{Name: r.Name, ClassName: r.Collection, Time: seconds(r.Duration)}
+		if r.URL != "" {
+			c.SystemOut = fmt.Sprintf("%s %s → %d", r.Method, r.URL, r.Status)
+		}
+		switch {
+		case r.Skipped != "":
+			c.Skipped = &junitMessage{Message: r.Skipped}
+			suite.Skipped++
+		case r.Error != "":
+			c.Error = &junitMessage{Message: r.Error}
+			suite.Errors++
+		case len(r.Failures) > 0:
+			c.Failure = &junitMessage{Message: r.Failures[0], Text: strings.Join(r.Failures, "\n")}
+			suite.Failures++
+		}
+		suite.Tests++
+		suite.duration += r.Duration
+		suite.Cases = append(suite.Cases, c)
+	}
+	for i := range doc.Suites {
+		s := &doc.Suites[i]
+		s.Time = seconds(s.duration)
+		doc.Tests += s.Tests
+		doc.Failures += s.Failures
+		doc.Errors += s.Errors
+		doc.Skipped += s.Skipped
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// tapDiagnostic is the YAML block under a failing TAP 13 test point.
+type tapDiagnostic struct {
+	Message  string   `yaml:"message"`
+	Failures []string `yaml:"failures,omitempty"`
+	Request  string   `yaml:"request,omitempty"`
+	Status   int      `yaml:"status,omitempty"`
+	Duration string   `yaml:"duration,omitempty"`
+}
+
+func writeTAP(w io.Writer, run domain.TestRun) error {
+	fmt.Fprintf(w, "TAP version 13\n1..%d\n", len(run.Results))
+	for i, r := range run.Results {
+		switch {
+		case r.Skipped != "":
+			fmt.Fprintf(w, "ok %d - %s # SKIP %s\n", i+1, title(r), r.Skipped)
+			continue
+		case r.Passed():
+			fmt.Fprintf(w, "ok %d - %s\n", i+1, title(r))
+			continue
+		}
+		fmt.Fprintf(w, "not ok %d - %s\n", i+1, title(r))
+		diag := tapDiagnostic{Message: r.Error, Status: r.Status}
+		if r.Error == "" {
+			diag.Message, diag.Failures = r.Failures[0], r.Failures
+			diag.Duration = r.Duration.Round(time.Millisecond).String()
+		}
+		if r.URL != "" {
+			diag.Request = string(r.Method) + " " + r.URL
+		}
+		data, err := yaml.Marshal(diag)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(w, "  ---")
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			fmt.Fprintln(w, "  "+line)
+		}
+		fmt.Fprintln(w, "  ...")
+	}
+	return nil
+}
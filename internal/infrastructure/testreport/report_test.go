@@ -0,0 +1,160 @@
+package testreport_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/testreport"
+)
+
+func sampleRun() domain.TestRun {
+	return domain.TestRun{
+		Environment: "local",
+		Started:     time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Duration:    1500 * time.Millisecond,
+		Results: []domain.TestResult{
+			{Name: "create", Collection: "pets", OperationID: "createPet", Method: domain.POST,
+				URL: "http://localhost/pets", Status: 201, Duration: 120 * time.Millisecond,
+				Captured: map[string]string{"petId": "7"}},
+			{Name: "fetch", Collection: "pets", OperationID: "getPet", Method: domain.GET,
+				URL: "http://localhost/pets/7", Status: 404, Duration: 30 * time.Millisecond,
+				Failures: []string{"status 404, expected 200", "$.name matched nothing"}},
+			{Name: "owner", Collection: "pets", OperationID: "getOwner", Skipped: `owner was not captured by "fetch"`},
+			{Name: "ping", Collection: "health", OperationID: "ping", Method: domain.GET,
+				URL: "http://localhost/ping", Error: "connection refused"},
+		},
+	}
+}
+
+func write(t *testing.T, format string) string {
+	t.Helper()
+	var b bytes.Buffer
+	if err := testreport.Write(&b, format, sampleRun()); err != nil {
+		t.Fatalf("Write(%s): %v", format, err)
+	}
+	return b.String()
+}
+
+func TestWrite_Text(t *testing.T) {
+	got := write(t, "text")
+	for _, want := range []string{
+		"✓ pets › create  POST http://localhost/pets → 201 (120ms)\n",
+		"✗ pets › fetch  GET http://localhost/pets/7 → 404 (30ms)\n    status 404, expected 200\n    $.name matched nothing\n",
+		"- pets › owner skipped: owner was not captured by \"fetch\"\n",
+		"✗ health › ping\n    connection refused\n",
+		"\n1 passed, 2 failed, 1 skipped in 1.5s\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
+
+func TestWrite_JUnit(t *testing.T) {
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Tests int    `xml:"tests,attr"`
+			Time  string `xml:"time,attr"`
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+					Text    string `xml:",chardata"`
+				} `xml:"failure"`
+				Error   *struct{} `xml:"error"`
+				Skipped *struct{} `xml:"skipped"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	out := write(t, "junit")
+	if !strings.HasPrefix(out, "<?xml") {
+		t.Errorf("expected an XML header, got %q", out[:min(len(out), 20)])
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Errors != 1 || doc.Skipped != 1 {
+		t.Errorf("unexpected totals %+v", doc)
+	}
+	if len(doc.Suites) != 2 || doc.Suites[0].Name != "pets" || doc.Suites[0].Tests != 3 || doc.Suites[1].Name != "health" {
+		t.Fatalf("expected a suite per collection, got %+v", doc.Suites)
+	}
+	if doc.Suites[0].Time != "0.150" {
+		t.Errorf("expected the suite time to add up its tests, got %s", doc.Suites[0].Time)
+	}
+	cases := doc.Suites[0].Cases
+	if cases[0].Failure != nil || cases[0].Skipped != nil {
+		t.Errorf("expected the first case to pass, got %+v", cases[0])
+	}
+	if f := cases[1].Failure; f == nil || f.Message != "status 404, expected 200" || !strings.Contains(f.Text, "$.name matched nothing") {
+		t.Errorf("unexpected failure %+v", f)
+	}
+	if cases[2].Skipped == nil || doc.Suites[1].Cases[0].Error == nil {
+		t.Errorf("expected a skipped case and an error case, got %+v", doc.Suites)
+	}
+}
+
+func TestWrite_JSON(t *testing.T) {
+	var doc struct {
+		Passed, Failed, Skipped int
+		Environment             string
+		Results                 []domain.TestResult
+	}
+	out := write(t, "json")
+	if err := json.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Passed != 1 || doc.Failed != 2 || doc.Skipped != 1 || doc.Environment != "local" {
+		t.Errorf("unexpected summary %+v", doc)
+	}
+	if len(doc.Results) != 4 || doc.Results[1].Failures[1] != "$.name matched nothing" {
+		t.Errorf("unexpected results %+v", doc.Results)
+	}
+	if strings.Contains(out, "petId") {
+		t.Errorf("expected captured values left out, got %s", out)
+	}
+}
+
+func TestWrite_TAP(t *testing.T) {
+	got := write(t, "tap")
+	want := `TAP version 13
+1..4
+ok 1 - pets › create
+not ok 2 - pets › fetch
+  ---
+  message: status 404, expected 200
+  failures:
+      - status 404, expected 200
+      - $.name matched nothing
+  request: GET http://localhost/pets/7
+  status: 404
+  duration: 30ms
+  ...
+ok 3 - pets › owner # SKIP owner was not captured by "fetch"
+not ok 4 - health › ping
+  ---
+  message: connection refused
+  request: GET http://localhost/ping
+  ...
+`
+	if got != want {
+		t.Errorf("unexpected TAP:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWrite_UnknownFormat(t *testing.T) {
+	err := testreport.Write(&bytes.Buffer{}, "html", sampleRun())
+	if err == nil || !strings.Contains(err.Error(), "text, junit, json, tap") {
+		t.Errorf("expected the formats to be listed, got %v", err)
+	}
+}
@@ -16,6 +16,11 @@ type AppModel struct {
 	opSvc      domain.OperationService
 	specSource string
 
+	services  screens.Services
+	envSvc    domain.EnvironmentService
+	envs      *domain.EnvironmentSet
+	activeEnv string
+
 	spec   *domain.Spec
 	screen Screen
 	width  int
@@ -37,6 +42,19 @@ func NewAppModel(
 	}
 }
 
+// SetEnvironments makes the environments available to the operations
+// screen, starting with active ("" for none).
+func (m *AppModel) SetEnvironments(svc domain.EnvironmentService, set *domain.EnvironmentSet, active string) {
+	m.envSvc = svc
+	m.envs = set
+	m.activeEnv = active
+}
+
+// SetServices enables the operations screen's optional features.
+func (m *AppModel) SetServices(svc screens.Services) {
+	m.services = svc
+}
+
 func (m *AppModel) Init() tea.Cmd {
 	return m.loadSpec()
 }
@@ -82,6 +100,10 @@ func (m *AppModel) handleSpecLoaded(msg SpecLoadedMsg) (tea.Model, tea.Cmd) {
 	m.spec = msg.Spec
 
 	opsScreen := screens.NewOperationsScreen(m.spec, m.opSvc)
+	opsScreen.SetServices(m.ctx, m.services)
+	if m.envSvc != nil {
+		opsScreen.SetEnvironments(m.envSvc, m.envs, m.activeEnv)
+	}
 	m.screen = opsScreen
 
 	// Send the current window size to the new screen
@@ -38,6 +38,14 @@ func (s *stubOperationService) SortOperations(ops []domain.Operation) []domain.O
 	return ops
 }
 
+func (s *stubOperationService) ParseQuery(q string) domain.OperationFilter {
+	return domain.OperationFilter{Query: q}
+}
+
+func (s *stubOperationService) SearchOperations(ops []domain.Operation, _ domain.OperationFilter) []domain.Operation {
+	return ops
+}
+
 func TestAppModel_Init(t *testing.T) {
 	svc := &stubSpecService{spec: &domain.Spec{}}
 	app := ui.NewAppModel(context.Background(), svc, &stubOperationService{}, "test.yaml")
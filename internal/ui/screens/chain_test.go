@@ -0,0 +1,100 @@
+package screens_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+func createdResponse() *domain.HTTPResponse {
+	return &domain.HTTPResponse{
+		StatusCode: http.StatusCreated,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(`{"id": 7, "name": "Rex"}`),
+	}
+}
+
+func TestOperationsScreen_CapturesAndFollowsLinks(t *testing.T) {
+	store := &collectionStore{saved: []domain.SavedRequest{{
+		Name: "New pet", Collection: "pets", OperationID: "createPet",
+		Values:   domain.RequestValues{Server: "{{baseUrl}}", ContentType: "application/json", Body: `{"name": "Rex"}`},
+		Captures: []domain.Capture{{Variable: "petId", Path: "$.id"}},
+	}}}
+	envs := testEnvironments()
+	svc := &stubRequestService{resp: createdResponse()}
+	s := newScreen(testSpec(), screens.Services{
+		Requests:    svc,
+		Collections: application.NewCollectionService(store),
+		Chain:       application.NewChainService(),
+	})
+	s.SetEnvironments(application.NewEnvironmentService(nil), envs, "local")
+
+	selectOperation(s, "createPet")
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("enter"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "captures petId ← $.id") {
+		t.Fatalf("expected the saved request's captures listed, got:\n%s", plain)
+	}
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	drainCmd(s, cmd)
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "✓ petId = 7 → @local") || envs.Environments["local"].Variables["petId"] != "7" {
+		t.Errorf("expected petId captured into local, got:\n%s", plain)
+	}
+	if !strings.Contains(plain, "→ 1 GetPetById getPet") || !strings.Contains(plain, "1 follow link") {
+		t.Fatalf("expected the response's link listed, got:\n%s", plain)
+	}
+
+	_, cmd = s.Update(keyMsg("1"))
+	drainCmd(s, cmd)
+	plain = ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "GET /pets/{petId}") || !strings.Contains(plain, "✓ Filled in from GetPetById") {
+		t.Fatalf("expected the getPet builder, got:\n%s", plain)
+	}
+	_, cmd = s.Update(keyMsg("ctrl+s"))
+	drainCmd(s, cmd)
+	if svc.values.Server != "http://localhost:8080" || svc.values.Path["petId"] != "7" {
+		t.Errorf("expected the link's petId sent to the same server, got %q %v", svc.values.Server, svc.values.Path)
+	}
+}
+
+func TestOperationsScreen_CapturesValueUnderCursor(t *testing.T) {
+	store := &collectionStore{}
+	s := newScreen(testSpec(), screens.Services{
+		Requests:    &stubRequestService{resp: createdResponse()},
+		Collections: application.NewCollectionService(store),
+		Chain:       application.NewChainService(),
+	})
+	s.SetEnvironments(application.NewEnvironmentService(nil), nil, "")
+	selectOperation(s, "createPet")
+	s.Update(keyMsg("enter"))
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	drainCmd(s, cmd)
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("c"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "capture $.id as: id") {
+		t.Fatalf("expected the capture prompt suggesting id, got:\n%s", plain)
+	}
+	typeRunes(s, "X")
+	_, cmd = s.Update(keyMsg("enter"))
+	drainCmd(s, cmd)
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "✓ idX = 7 → @session") || !strings.Contains(plain, "@session") {
+		t.Errorf("expected idX captured into a session environment, got:\n%s", plain)
+	}
+
+	// The capture is saved with the request.
+	s.Update(keyMsg("esc"))
+	s.Update(keyMsg("ctrl+r"))
+	typeRunes(s, "New pet")
+	s.Update(keyMsg("enter"))
+	if len(store.saved) != 1 || len(store.saved[0].Captures) != 1 || store.saved[0].Captures[0] != (domain.Capture{Variable: "idX", Path: "$.id"}) {
+		t.Errorf("expected the capture saved, got %+v", store.saved)
+	}
+}
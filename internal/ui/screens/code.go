@@ -0,0 +1,95 @@
+package screens
+
+import (
+	"context"
+	"fmt"
+
+	"dazzle/internal/domain"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// snippetMsg carries the Code tab's snippets. key identifies the inputs
+// they were built from so stale results are dropped.
+type snippetMsg struct {
+	key      string
+	snippets []domain.Snippet
+	source   string
+	err      error
+}
+
+// copiedMsg reports the end of a copy to the clipboard.
+type copiedMsg struct {
+	label string
+	err   error
+}
+
+// refreshSnippets rebuilds the Code tab's snippets when it is on screen and
+// the operation, environment or builder values changed. The request is
+// authenticated and signed like one being sent, which may fetch a token,
+// so it is prepared asynchronously.
+func (s *OperationsScreen) refreshSnippets() tea.Cmd {
+	if s.svc.Snippets == nil || s.svc.Requests == nil || s.detail.op == nil ||
+		s.pane != paneDetail || s.detail.Tab() != TabCode {
+		return nil
+	}
+	op := *s.detail.op
+	values, source := s.snippetValues(op)
+	key := fmt.Sprintf("%s|%s|%v", op.ID, s.activeEnv, values)
+	if key == s.snippetKey {
+		return nil
+	}
+	s.snippetKey = key
+
+	env, _ := s.envs.Get(s.activeEnv)
+	env = env.Clone()
+	if s.activeEnv != "" {
+		source += " · @" + s.activeEnv
+	}
+	if s.envSvc != nil {
+		var err error
+		if values, err = s.envSvc.SubstituteValues(env, values); err != nil {
+			s.detail.SetSnippets(nil, source, err)
+			return nil
+		}
+	}
+	req, err := s.svc.Requests.BuildRequest(op, values)
+	if err != nil {
+		s.detail.SetSnippets(nil, source, err)
+		return nil
+	}
+
+	s.detail.SetSnippetsPending()
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	authz, svc, schemes := domain.Authorizer{Auth: s.svc.Auth, Signer: s.svc.Signer}, s.svc.Snippets, s.schemes
+	return func() tea.Msg {
+		if err := authz.Authorize(ctx, schemes, op, env, req); err != nil {
+			return snippetMsg{key: key, source: source, err: err}
+		}
+		return snippetMsg{key: key, snippets: svc.Snippets(req), source: source}
+	}
+}
+
+// snippetValues returns the builder's values when it is open on op,
+// otherwise the defaults a new builder would start with.
+func (s *OperationsScreen) snippetValues(op domain.Operation) (domain.RequestValues, string) {
+	if s.builder != nil && s.builder.Operation().ID == op.ID {
+		return s.builder.Values(), "Values from the request builder"
+	}
+	return s.newBuilder(op).Values(), "Default values"
+}
+
+// copySnippet copies the Code tab's snippet to the clipboard.
+func (s *OperationsScreen) copySnippet() tea.Cmd {
+	snippet, ok := s.detail.Snippet()
+	if !ok || s.svc.Clipboard == nil {
+		return nil
+	}
+	clipboard := s.svc.Clipboard
+	return func() tea.Msg {
+		return copiedMsg{label: snippet.Label, err: clipboard.Copy(snippet.Code)}
+	}
+}
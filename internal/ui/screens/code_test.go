@@ -0,0 +1,55 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/ui/screens"
+)
+
+// clipboardLog records copied text.
+type clipboardLog struct {
+	copied []string
+}
+
+func (c *clipboardLog) Copy(text string) error {
+	c.copied = append(c.copied, text)
+	return nil
+}
+
+func TestOperationsScreen_CodeTabShowsAndCopiesSnippets(t *testing.T) {
+	clip := &clipboardLog{}
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{}, Snippets: application.NewSnippetService(), Clipboard: clip})
+
+	s.Update(keyMsg("esc"))
+	_, cmd := s.Update(keyMsg("7"))
+	drainCmd(s, cmd)
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	for _, want := range []string{"cURL · HTTPie · Go · Python · JavaScript", "Values from the request builder", "curl 'https://petstore.example.com/pets/{petId}'", "c language · y copy"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q in the Code tab, got:\n%s", want, plain)
+		}
+	}
+
+	s.Update(keyMsg("c"))
+	_, cmd = s.Update(keyMsg("y"))
+	drainCmd(s, cmd)
+	if len(clip.copied) != 1 || !strings.HasPrefix(clip.copied[0], "http GET 'https://petstore.example.com/pets/{petId}'") {
+		t.Fatalf("expected the HTTPie snippet to be copied, got %q", clip.copied)
+	}
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "✓ Copied HTTPie to the clipboard") {
+		t.Errorf("expected a copy confirmation, got:\n%s", plain)
+	}
+}
+
+func TestOperationsScreen_CodeTabReportsAuthErrors(t *testing.T) {
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{}, Auth: failingAuth{}, Snippets: application.NewSnippetService()})
+
+	s.Update(keyMsg("esc"))
+	_, cmd := s.Update(keyMsg("7"))
+	drainCmd(s, cmd)
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "authenticating:") {
+		t.Errorf("expected the authentication error in the Code tab, got:\n%s", plain)
+	}
+}
@@ -0,0 +1,105 @@
+package screens
+
+import (
+	"fmt"
+	"io"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// savedRequestItem is a saved request listed under its operation.
+type savedRequestItem struct {
+	op    domain.Operation
+	saved domain.SavedRequest
+}
+
+func (i savedRequestItem) Title() string       { return i.saved.Name }
+func (i savedRequestItem) Description() string { return i.saved.Collection }
+func (i savedRequestItem) FilterValue() string {
+	return string(i.op.Method) + " " + i.op.Path + " " + i.saved.Name + " " + i.saved.Collection
+}
+
+func renderSavedRequestItem(w io.Writer, m list.Model, index int, item savedRequestItem) {
+	name := "  ↳ " + item.saved.Name
+	collection := "      " + item.saved.Collection
+	if index == m.Index() {
+		fmt.Fprintf(w, "%s\n%s", lipgloss.NewStyle().Bold(true).Render(">"+name),
+			lipgloss.NewStyle().Foreground(styles.Subtext1).Render(collection))
+		return
+	}
+	fmt.Fprintf(w, " %s\n%s", name, lipgloss.NewStyle().Foreground(styles.Overlay1).Render(collection))
+}
+
+// listItems lists each operation followed by its saved requests.
+func listItems(ops []domain.Operation, saved map[string][]domain.SavedRequest) []list.Item {
+	items := make([]list.Item, 0, len(ops))
+	for _, op := range ops {
+		items = append(items, operationItem{op: op})
+		for _, r := range saved[op.ID] {
+			items = append(items, savedRequestItem{op: op, saved: r})
+		}
+	}
+	return items
+}
+
+// savePrompt asks for the name and collection to save the builder's
+// request under.
+type savePrompt struct {
+	name       textinput.Model
+	collection textinput.Model
+}
+
+func newSavePrompt(name, collection string) *savePrompt {
+	p := &savePrompt{name: textinput.New(), collection: textinput.New()}
+	p.name.Prompt, p.collection.Prompt = "", ""
+	p.name.Placeholder, p.collection.Placeholder = "name", "default"
+	p.name.Width, p.collection.Width = 24, 16
+	p.name.SetValue(name)
+	p.collection.SetValue(collection)
+	p.name.Focus()
+	return p
+}
+
+// update handles a key press; done reports that the prompt closed, and ok
+// that it should be saved.
+func (p *savePrompt) update(msg tea.KeyMsg) (cmd tea.Cmd, ok, done bool) {
+	switch msg.String() {
+	case "enter":
+		return nil, true, true
+	case "esc":
+		return nil, false, true
+	case "tab", "shift+tab":
+		if p.name.Focused() {
+			p.name.Blur()
+			return p.collection.Focus(), false, false
+		}
+		p.collection.Blur()
+		return p.name.Focus(), false, false
+	}
+	if p.name.Focused() {
+		p.name, cmd = p.name.Update(msg)
+	} else {
+		p.collection, cmd = p.collection.Update(msg)
+	}
+	return cmd, false, false
+}
+
+func (p *savePrompt) request(op domain.Operation, values domain.RequestValues) domain.SavedRequest {
+	return domain.SavedRequest{
+		Name:        p.name.Value(),
+		Collection:  p.collection.Value(),
+		OperationID: op.ID,
+		Values:      values,
+	}
+}
+
+func (p *savePrompt) view() string {
+	label := lipgloss.NewStyle().Foreground(styles.Blue)
+	return label.Render("save as ") + p.name.View() + label.Render(" in ") + p.collection.View()
+}
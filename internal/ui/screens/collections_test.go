@@ -0,0 +1,85 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+// collectionStore is an in-memory CollectionRepository with one
+// collection per saved request's collection name.
+type collectionStore struct {
+	saved []domain.SavedRequest
+}
+
+func (c *collectionStore) LoadCollections() ([]domain.Collection, error) {
+	var out []domain.Collection
+	index := map[string]int{}
+	for _, r := range c.saved {
+		i, ok := index[r.Collection]
+		if !ok {
+			i = len(out)
+			index[r.Collection] = i
+			out = append(out, domain.Collection{Name: r.Collection})
+		}
+		out[i].Requests = append(out[i].Requests, r)
+	}
+	return out, nil
+}
+
+func (c *collectionStore) SaveRequest(r domain.SavedRequest) error {
+	c.saved = append(c.saved, r)
+	return nil
+}
+
+func TestOperationsScreen_OpensSavedRequest(t *testing.T) {
+	store := &collectionStore{saved: []domain.SavedRequest{
+		{Name: "Rex", Collection: "pets", OperationID: "getPet", Values: domain.RequestValues{Path: map[string]string{"petId": "42"}}},
+		{Name: "Gone", Collection: "pets", OperationID: "removedFromSpec"},
+	}}
+	s := newScreen(testSpec(), screens.Services{Requests: &stubRequestService{}, Collections: application.NewCollectionService(store)})
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "↳ Rex") || strings.Contains(plain, "Gone") {
+		t.Fatalf("expected Rex under its operation and no orphans, got:\n%s", plain)
+	}
+
+	selectOperation(s, "getPet")
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("enter"))
+	plain = ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "★ pets/Rex") || !strings.Contains(plain, "42") {
+		t.Errorf("expected the builder filled from the saved request, got:\n%s", plain)
+	}
+}
+
+func TestOperationsScreen_SavesRequest(t *testing.T) {
+	store := &collectionStore{}
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{}, Collections: application.NewCollectionService(store)})
+	s.Update(keyMsg("esc"))
+	s.Update(keyMsg("enter"))
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("7"))
+	s.Update(keyMsg("ctrl+r"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "save as") {
+		t.Fatalf("expected the save prompt, got:\n%s", plain)
+	}
+	typeRunes(s, "Tom")
+	s.Update(keyMsg("enter"))
+
+	if len(store.saved) != 1 {
+		t.Fatalf("expected one saved request, got %+v", store.saved)
+	}
+	got := store.saved[0]
+	if got.Name != "Tom" || got.Collection != application.DefaultCollection || got.OperationID != "getPet" || got.Values.Path["petId"] != "7" {
+		t.Errorf("unexpected saved request %+v", got)
+	}
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "✓ Saved to default") || !strings.Contains(plain, "↳ Tom") {
+		t.Errorf("expected a confirmation and the request listed, got:\n%s", plain)
+	}
+}
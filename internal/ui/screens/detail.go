@@ -1,6 +1,7 @@
 package screens
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
@@ -14,16 +15,58 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// DetailPanel renders operation metadata in a scrollable viewport.
+// DetailTab identifies a tab of the detail panel.
+type DetailTab int
+
+const (
+	TabOverview DetailTab = iota
+	TabParameters
+	TabRequest
+	TabResponses
+	TabExamples
+	TabSecurity
+	TabCode
+	detailTabCount
+)
+
+var detailTabNames = [detailTabCount]struct{ long, short string }{
+	{"Overview", "Info"},
+	{"Parameters", "Params"},
+	{"Request", "Req"},
+	{"Responses", "Resp"},
+	{"Examples", "Ex"},
+	{"Security", "Sec"},
+	{"Code", "Code"},
+}
+
+// DetailPanel renders operation metadata as tabs, each in a scrollable
+// viewport. The active tab is kept when the operation changes; scroll
+// positions are remembered per tab until then.
 type DetailPanel struct {
 	viewport viewport.Model
 	op       *domain.Operation
+	shared   map[string]int
+	schemes  map[string]domain.SecurityScheme
+	examples domain.ExampleService
+	tab      DetailTab
+	offsets  [detailTabCount]int
 	width    int
 	height   int
+
+	snippets       []domain.Snippet
+	snippetSource  string
+	snippetErr     error
+	snippetPending bool
+	snippetLang    int
+	codeNotice     string
 }
 
+// detailChromeHeight is the number of lines above the viewport: the
+// method/path header and the tab bar.
+const detailChromeHeight = 2
+
 func NewDetailPanel(width, height int) *DetailPanel {
-	vp := viewport.New(max(1, width-1), height)
+	vp := viewport.New(max(1, width-1), max(1, height-detailChromeHeight))
 	return &DetailPanel{
 		viewport: vp,
 		width:    width,
@@ -33,10 +76,79 @@ func NewDetailPanel(width, height int) *DetailPanel {
 
 func (d *DetailPanel) SetOperation(op domain.Operation) {
 	d.op = &op
+	d.offsets = [detailTabCount]int{}
+	d.snippets, d.snippetErr, d.snippetPending, d.codeNotice = nil, nil, false, ""
 	d.viewport.SetContent(d.renderContent())
 	d.viewport.GotoTop()
 }
 
+// SetSharedResponses records how many operations declare each error
+// response, keyed by responseFingerprint. Error responses shared by several
+// operations are collapsed into a single summary line.
+func (d *DetailPanel) SetSharedResponses(shared map[string]int) {
+	d.shared = shared
+	d.refresh()
+}
+
+// SetSecuritySchemes provides the spec's schemes so the Security tab can
+// describe the schemes an operation's requirements refer to.
+func (d *DetailPanel) SetSecuritySchemes(schemes map[string]domain.SecurityScheme) {
+	d.schemes = schemes
+	d.refresh()
+}
+
+// SetExampleService makes the Examples tab show a generated example for
+// each media type the spec gives none for.
+func (d *DetailPanel) SetExampleService(svc domain.ExampleService) {
+	d.examples = svc
+	d.refresh()
+}
+
+// SetSnippetsPending shows that the Code tab's snippets are being prepared.
+func (d *DetailPanel) SetSnippetsPending() {
+	d.snippetPending = true
+	d.refresh()
+}
+
+// SetSnippets shows the request as code in the Code tab. source says where
+// the values came from; err replaces the snippets when the request could
+// not be built.
+func (d *DetailPanel) SetSnippets(snippets []domain.Snippet, source string, err error) {
+	d.snippets, d.snippetSource, d.snippetErr = snippets, source, err
+	d.snippetPending = false
+	d.codeNotice = ""
+	d.refresh()
+}
+
+// Snippet returns the snippet in the language selected on the Code tab.
+func (d *DetailPanel) Snippet() (domain.Snippet, bool) {
+	if len(d.snippets) == 0 {
+		return domain.Snippet{}, false
+	}
+	return d.snippets[d.snippetLang%len(d.snippets)], true
+}
+
+// SetCodeNotice shows a one-line notice, such as a copy confirmation, above
+// the snippet until the language or snippets change.
+func (d *DetailPanel) SetCodeNotice(notice string) {
+	d.codeNotice = notice
+	d.refresh()
+}
+
+// Tab returns the active tab.
+func (d *DetailPanel) Tab() DetailTab { return d.tab }
+
+// SetTab switches to the given tab, restoring its scroll position.
+func (d *DetailPanel) SetTab(tab DetailTab) {
+	if tab < 0 || tab >= detailTabCount || tab == d.tab {
+		return
+	}
+	d.offsets[d.tab] = d.viewport.YOffset
+	d.tab = tab
+	d.viewport.SetContent(d.renderContent())
+	d.viewport.SetYOffset(d.offsets[tab])
+}
+
 func (d *DetailPanel) Clear() {
 	d.op = nil
 	d.viewport.SetContent("")
@@ -47,13 +159,38 @@ func (d *DetailPanel) SetSize(width, height int) {
 	d.width = width
 	d.height = height
 	d.viewport.Width = max(1, width-1) // reserve 1 col for scrollbar
-	d.viewport.Height = height
+	d.viewport.Height = max(1, height-detailChromeHeight)
+	d.refresh()
+}
+
+func (d *DetailPanel) refresh() {
 	if d.op != nil {
 		d.viewport.SetContent(d.renderContent())
 	}
 }
 
+// Update switches tabs with ←/→ (h/l, [/]) or 1–7, and the Code tab's
+// language with c, and passes other keys to the active tab's viewport.
 func (d *DetailPanel) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch k := msg.String(); {
+		case k == "c" && d.tab == TabCode && len(d.snippets) > 0:
+			d.snippetLang = (d.snippetLang + 1) % len(d.snippets)
+			d.codeNotice = ""
+			d.refresh()
+			return nil
+		case k == "left" || k == "h" || k == "[":
+			d.SetTab((d.tab + detailTabCount - 1) % detailTabCount)
+			return nil
+		case k == "right" || k == "l" || k == "]":
+			d.SetTab((d.tab + 1) % detailTabCount)
+			return nil
+		case len(k) == 1 && k[0] >= '1' && int(k[0]-'1') < int(detailTabCount):
+			d.SetTab(DetailTab(k[0] - '1'))
+			return nil
+		}
+	}
+
 	var cmd tea.Cmd
 	d.viewport, cmd = d.viewport.Update(msg)
 	return cmd
@@ -63,22 +200,56 @@ func (d *DetailPanel) View() string {
 	if d.op == nil {
 		return styles.Muted.Render("Select an operation to view details")
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Top, d.viewport.View(), d.renderScrollbar())
+	header := styles.Method(string(d.op.Method)) + " " + lipgloss.NewStyle().Bold(true).Render(d.op.Path)
+	if d.op.Deprecated {
+		header += "  " + lipgloss.NewStyle().Foreground(styles.Yellow).Render("deprecated")
+	}
+	header = lipgloss.NewStyle().MaxWidth(max(1, d.width)).Render(header)
+	body := lipgloss.JoinHorizontal(lipgloss.Top, d.viewport.View(), renderViewportScrollbar(d.viewport))
+	return header + "\n" + d.renderTabBar() + "\n" + body
 }
 
-func (d *DetailPanel) renderScrollbar() string {
-	total := d.viewport.TotalLineCount()
-	visible := d.viewport.VisibleLineCount()
+// renderTabBar shows every tab, falling back to short labels when the full
+// names do not fit.
+func (d *DetailPanel) renderTabBar() string {
+	render := func(short bool) string {
+		labels := make([]string, detailTabCount)
+		for i, n := range detailTabNames {
+			label := n.long
+			if short {
+				label = n.short
+			}
+			if DetailTab(i) == d.tab {
+				labels[i] = lipgloss.NewStyle().Bold(true).Underline(true).Foreground(styles.Blue).Render(label)
+			} else {
+				labels[i] = styles.Muted.Render(label)
+			}
+		}
+		return strings.Join(labels, styles.Muted.Render(" │ "))
+	}
+
+	bar := render(false)
+	if lipgloss.Width(bar) > d.width {
+		bar = render(true)
+	}
+	return lipgloss.NewStyle().MaxWidth(max(1, d.width)).Render(bar)
+}
+
+// renderViewportScrollbar draws a one-column scrollbar for vp.
+func renderViewportScrollbar(vp viewport.Model) string {
+	height := vp.Height
+	total := vp.TotalLineCount()
+	visible := vp.VisibleLineCount()
 	showThumb := total > visible
 	thumbH := 0
 	thumbTop := 0
 	if showThumb {
-		thumbH = max(1, d.height*visible/total)
-		thumbTop = int(float64(d.height-thumbH) * d.viewport.ScrollPercent())
+		thumbH = max(1, height*visible/total)
+		thumbTop = int(float64(height-thumbH) * vp.ScrollPercent())
 	}
 
 	var b strings.Builder
-	for i := range d.height {
+	for i := range height {
 		if i > 0 {
 			b.WriteByte('\n')
 		}
@@ -91,19 +262,34 @@ func (d *DetailPanel) renderScrollbar() string {
 	return b.String()
 }
 
+// renderContent renders the active tab for the current operation.
 func (d *DetailPanel) renderContent() string {
 	if d.op == nil {
 		return ""
 	}
 
+	switch d.tab {
+	case TabParameters:
+		return d.renderParametersTab()
+	case TabRequest:
+		return d.renderRequestTab()
+	case TabResponses:
+		return d.renderResponsesTab()
+	case TabExamples:
+		return d.renderExamplesTab()
+	case TabSecurity:
+		return d.renderSecurityTab()
+	case TabCode:
+		return d.renderCodeTab()
+	default:
+		return d.renderOverviewTab()
+	}
+}
+
+func (d *DetailPanel) renderOverviewTab() string {
 	var b strings.Builder
 	op := d.op
 
-	method := styles.Method(string(op.Method))
-	path := lipgloss.NewStyle().Bold(true).Render(op.Path)
-	b.WriteString(method + " " + path)
-	b.WriteString("\n")
-
 	if op.Summary != "" {
 		b.WriteString("\n")
 		b.WriteString(op.Summary)
@@ -117,37 +303,311 @@ func (d *DetailPanel) renderContent() string {
 	}
 
 	b.WriteString("\n")
+	rows := [][2]string{
+		{"Operation", op.ID},
+		{"Tags", strings.Join(op.Tags, ", ")},
+		{"Parameters", summarizeParameters(op.Parameters)},
+		{"Request", summarizeRequestBody(op.RequestBody)},
+		{"Responses", summarizeResponses(op.Responses)},
+		{"Security", summarizeSecurity(op.Security)},
+	}
+	for _, r := range rows {
+		value := r[1]
+		if value == "" {
+			value = styles.Muted.Render("none")
+		}
+		b.WriteString("  " + styles.Muted.Render(fmt.Sprintf("%-11s", r[0])) + " " + value + "\n")
+	}
+	return b.String()
+}
+
+func (d *DetailPanel) renderParametersTab() string {
+	var b strings.Builder
 	b.WriteString(sectionHeader("Parameters"))
-	if len(op.Parameters) == 0 {
+	if len(d.op.Parameters) == 0 {
 		b.WriteString(styles.Muted.Render("  None"))
 		b.WriteString("\n")
-	} else {
-		for _, p := range op.Parameters {
-			b.WriteString(renderParameter(p, d.viewport.Width))
-		}
+		return b.String()
+	}
+	for _, p := range d.op.Parameters {
+		b.WriteString(renderParameter(p, d.viewport.Width))
 	}
+	return b.String()
+}
 
-	b.WriteString("\n")
+func (d *DetailPanel) renderRequestTab() string {
+	var b strings.Builder
 	b.WriteString(sectionHeader("Request Body"))
-	if op.RequestBody == nil {
+	if d.op.RequestBody == nil {
 		b.WriteString(styles.Muted.Render("  None"))
 		b.WriteString("\n")
-	} else {
-		b.WriteString(renderRequestBody(op.RequestBody, d.viewport.Width))
+		return b.String()
 	}
+	b.WriteString(renderRequestBody(d.op.RequestBody, d.viewport.Width))
+	return b.String()
+}
 
-	b.WriteString("\n")
+func (d *DetailPanel) renderResponsesTab() string {
+	var b strings.Builder
 	b.WriteString(sectionHeader("Responses"))
-	if len(op.Responses) == 0 {
+	if len(d.op.Responses) == 0 {
 		b.WriteString(styles.Muted.Render("  None"))
 		b.WriteString("\n")
-	} else {
-		b.WriteString(renderResponses(op.Responses))
+		return b.String()
+	}
+	b.WriteString(renderResponses(d.op.Responses, d.shared, d.viewport.Width))
+	return b.String()
+}
+
+func (d *DetailPanel) renderExamplesTab() string {
+	var b strings.Builder
+	b.WriteString(sectionHeader("Examples"))
+
+	var blocks []string
+	if d.op.RequestBody != nil {
+		for _, ct := range sortedKeys(d.op.RequestBody.Content) {
+			blocks = append(blocks, d.mediaExamples("Request", ct, d.op.RequestBody.Content[ct])...)
+		}
+	}
+	for _, code := range sortedKeys(d.op.Responses) {
+		resp := d.op.Responses[code]
+		for _, ct := range sortedKeys(resp.Content) {
+			blocks = append(blocks, d.mediaExamples(renderStatus(code), ct, resp.Content[ct])...)
+		}
 	}
 
+	if len(blocks) == 0 {
+		b.WriteString(styles.Muted.Render("  None"))
+		b.WriteString("\n")
+		return b.String()
+	}
+	b.WriteString(strings.Join(blocks, "\n"))
 	return b.String()
 }
 
+func (d *DetailPanel) renderSecurityTab() string {
+	var b strings.Builder
+	b.WriteString(sectionHeader("Security"))
+
+	if len(d.op.Security) == 0 {
+		if d.op.Security != nil {
+			b.WriteString("  Anonymous access\n")
+		} else {
+			b.WriteString(styles.Muted.Render("  None"))
+			b.WriteString("\n")
+		}
+		return b.String()
+	}
+
+	for i, req := range d.op.Security {
+		if i > 0 {
+			b.WriteString("  " + styles.Muted.Render("or") + "\n")
+		}
+		if len(req) == 0 {
+			b.WriteString("  Anonymous access\n")
+			continue
+		}
+		for _, name := range sortedKeys(req) {
+			line := "  " + lipgloss.NewStyle().Bold(true).Render(name)
+			if scheme, ok := d.schemes[name]; ok {
+				line += "  " + describeSecurityScheme(scheme)
+			}
+			if scopes := req[name]; len(scopes) > 0 {
+				line += "  " + styles.Muted.Render("scopes: "+strings.Join(scopes, ", "))
+			}
+			b.WriteString(line + "\n")
+			if scheme, ok := d.schemes[name]; ok && scheme.Description != "" {
+				desc := renderMarkdown(scheme.Description, max(1, d.viewport.Width-6))
+				b.WriteString(indent(desc, "    ") + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// renderCodeTab shows the request in the selected language, with a bar to
+// pick another.
+func (d *DetailPanel) renderCodeTab() string {
+	var b strings.Builder
+	b.WriteString(sectionHeader("Code"))
+	switch {
+	case d.snippetErr != nil:
+		b.WriteString(lipgloss.NewStyle().Width(max(1, d.width-3)).Inherit(styles.Error).Render("  " + d.snippetErr.Error()))
+		b.WriteString("\n")
+		return b.String()
+	case d.snippetPending && len(d.snippets) == 0:
+		b.WriteString(styles.Muted.Render("  Preparing request…"))
+		b.WriteString("\n")
+		return b.String()
+	case len(d.snippets) == 0:
+		b.WriteString(styles.Muted.Render("  None"))
+		b.WriteString("\n")
+		return b.String()
+	}
+
+	current, _ := d.Snippet()
+	labels := make([]string, len(d.snippets))
+	for i, sn := range d.snippets {
+		if sn.Language == current.Language {
+			labels[i] = lipgloss.NewStyle().Bold(true).Foreground(styles.Blue).Render(sn.Label)
+		} else {
+			labels[i] = styles.Muted.Render(sn.Label)
+		}
+	}
+	b.WriteString("  " + strings.Join(labels, styles.Muted.Render(" · ")) + "\n")
+	if d.snippetSource != "" {
+		b.WriteString(styles.Muted.Render("  "+d.snippetSource) + "\n")
+	}
+	if d.codeNotice != "" {
+		b.WriteString(lipgloss.NewStyle().Foreground(styles.Green).Render("  "+d.codeNotice) + "\n")
+	}
+	b.WriteString("\n")
+	b.WriteString(indent(styles.Highlight(current.Code, current.Syntax), "  "))
+	b.WriteString("\n\n")
+	b.WriteString(styles.Muted.Render("  c language · y copy"))
+	b.WriteString("\n")
+	return b.String()
+}
+
+func summarizeParameters(params []domain.Parameter) string {
+	if len(params) == 0 {
+		return ""
+	}
+	counts := make(map[domain.ParameterIn]int)
+	for _, p := range params {
+		counts[p.In]++
+	}
+	var parts []string
+	for _, in := range []domain.ParameterIn{domain.ParameterInPath, domain.ParameterInQuery, domain.ParameterInHeader, domain.ParameterInCookie} {
+		if n := counts[in]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, in))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func summarizeRequestBody(rb *domain.RequestBody) string {
+	if rb == nil {
+		return ""
+	}
+	summary := strings.Join(sortedKeys(rb.Content), ", ")
+	if rb.Required {
+		summary += "  " + lipgloss.NewStyle().Foreground(styles.Red).Render("required")
+	}
+	return summary
+}
+
+func summarizeResponses(responses map[string]domain.Response) string {
+	codes := sortedKeys(responses)
+	for i, c := range codes {
+		codes[i] = renderStatus(c)
+	}
+	return strings.Join(codes, " ")
+}
+
+func summarizeSecurity(reqs []domain.SecurityRequirement) string {
+	if reqs != nil && len(reqs) == 0 {
+		return "anonymous"
+	}
+	alternatives := make([]string, 0, len(reqs))
+	for _, req := range reqs {
+		if len(req) == 0 {
+			alternatives = append(alternatives, "anonymous")
+			continue
+		}
+		alternatives = append(alternatives, strings.Join(sortedKeys(req), " + "))
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+// describeSecurityScheme summarises a scheme in a few words, e.g.
+// "API key in header X-API-Key" or "HTTP bearer (JWT)".
+func describeSecurityScheme(s domain.SecurityScheme) string {
+	switch s.Type {
+	case domain.SecuritySchemeAPIKey:
+		return fmt.Sprintf("API key in %s %s", s.In, s.Name)
+	case domain.SecuritySchemeHTTP:
+		desc := "HTTP " + s.Scheme
+		if s.BearerFormat != "" {
+			desc += " (" + s.BearerFormat + ")"
+		}
+		return desc
+	case domain.SecuritySchemeOAuth2:
+		var flows []string
+		if s.Flows != nil {
+			if s.Flows.AuthorizationCode != nil {
+				flows = append(flows, "authorization code")
+			}
+			if s.Flows.ClientCredentials != nil {
+				flows = append(flows, "client credentials")
+			}
+			if s.Flows.Password != nil {
+				flows = append(flows, "password")
+			}
+			if s.Flows.Implicit != nil {
+				flows = append(flows, "implicit")
+			}
+		}
+		if len(flows) == 0 {
+			return "OAuth2"
+		}
+		return "OAuth2 " + strings.Join(flows, ", ")
+	case domain.SecuritySchemeOpenIDConnect:
+		return "OpenID Connect " + s.OpenIDConnectURL
+	case domain.SecuritySchemeMutualTLS:
+		return "mutual TLS"
+	default:
+		return string(s.Type)
+	}
+}
+
+// renderMediaExamples renders a media type's inline and named examples,
+// one block each, labelled with where they apply.
+// mediaExamples renders the spec's examples for a media type, or a
+// generated one when it has none.
+func (d *DetailPanel) mediaExamples(label, contentType string, mt domain.MediaType) []string {
+	if blocks := renderMediaExamples(label, contentType, mt); len(blocks) > 0 || d.examples == nil {
+		return blocks
+	}
+	example, ok := generatedExample(d.examples, contentType, mt)
+	if !ok {
+		return nil
+	}
+	heading := "  " + label + "  " + lipgloss.NewStyle().Foreground(styles.Blue).Render(contentType) + "  " + styles.Muted.Render("generated example")
+	return []string{heading + "\n" + indent(example, "    ") + "\n"}
+}
+
+func renderMediaExamples(label, contentType string, mt domain.MediaType) []string {
+	heading := "  " + label + "  " + lipgloss.NewStyle().Foreground(styles.Blue).Render(contentType)
+
+	var blocks []string
+	if mt.Example != nil {
+		blocks = append(blocks, heading+"\n"+indent(formatExample(mt.Example), "    ")+"\n")
+	}
+	for _, name := range sortedKeys(mt.Examples) {
+		ex := mt.Examples[name]
+		title := heading + "  " + lipgloss.NewStyle().Bold(true).Render(name)
+		if ex.Summary != "" {
+			title += "  " + styles.Muted.Render(ex.Summary)
+		}
+		blocks = append(blocks, title+"\n"+indent(formatExample(ex.Value), "    ")+"\n")
+	}
+	return blocks
+}
+
+// formatExample renders an example value as indented JSON; strings are shown
+// verbatim since they are often non-JSON payloads.
+func formatExample(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(out)
+}
+
 func sectionHeader(title string) string {
 	return styles.Title.Render(title) + "\n"
 }
@@ -193,17 +653,26 @@ func renderRequestBody(rb *domain.RequestBody, width int) string {
 	return b.String()
 }
 
-func renderResponses(responses map[string]domain.Response) string {
+func renderResponses(responses map[string]domain.Response, shared map[string]int, width int) string {
 	var b strings.Builder
+	var collapsed []string
 	for _, code := range sortedKeys(responses) {
 		resp := responses[code]
-		status := lipgloss.NewStyle().Bold(true).Foreground(styles.Green).Render(code)
-		b.WriteString("  " + status)
+		if n := shared[responseFingerprint(code, resp)]; n >= sharedResponseMin && isErrorStatus(code) {
+			collapsed = append(collapsed, renderStatus(code)+styles.Muted.Render(fmt.Sprintf(" ×%d", n)))
+			continue
+		}
+
+		b.WriteString("  " + renderStatus(code))
 		if resp.Description != "" {
 			b.WriteString("  " + resp.Description)
 		}
 		b.WriteString("\n")
 
+		if len(resp.Headers) > 0 {
+			b.WriteString(renderHeaders(resp.Headers, width))
+		}
+
 		for _, contentType := range sortedKeys(resp.Content) {
 			mt := resp.Content[contentType]
 			b.WriteString("    " + lipgloss.NewStyle().Foreground(styles.Blue).Render(contentType) + "\n")
@@ -212,6 +681,97 @@ func renderResponses(responses map[string]domain.Response) string {
 			}
 		}
 	}
+
+	if len(collapsed) > 0 {
+		b.WriteString("  " + styles.Muted.Render("Shared errors:") + " " + strings.Join(collapsed, "  ") + "\n")
+	}
+	return b.String()
+}
+
+func renderStatus(code string) string {
+	return lipgloss.NewStyle().Bold(true).Foreground(styles.StatusColor(code)).Render(code)
+}
+
+func renderHeaders(headers map[string]domain.Header, width int) string {
+	var b strings.Builder
+	b.WriteString("    " + styles.Muted.Render("headers") + "\n")
+	for _, name := range sortedKeys(headers) {
+		h := headers[name]
+		parts := []string{lipgloss.NewStyle().Bold(true).Render(name)}
+		if h.Schema != nil {
+			if t := renderSchemaType(h.Schema); t != "" {
+				parts = append(parts, t)
+			}
+		}
+		if h.Required {
+			parts = append(parts, lipgloss.NewStyle().Foreground(styles.Red).Render("required"))
+		}
+		b.WriteString("      " + strings.Join(parts, "  ") + "\n")
+		if h.Description != "" {
+			desc := renderMarkdown(h.Description, max(1, width-10))
+			b.WriteString(indent(desc, "        ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// sharedResponseMin is how many operations must declare an identical error
+// response before it is collapsed into the shared summary line.
+const sharedResponseMin = 2
+
+func isErrorStatus(code string) bool {
+	return code == "default" || strings.HasPrefix(code, "4") || strings.HasPrefix(code, "5")
+}
+
+// sharedResponses counts, across all operations, how many declare each
+// error response, keyed by its fingerprint.
+func sharedResponses(ops []domain.Operation) map[string]int {
+	counts := make(map[string]int)
+	for _, op := range ops {
+		for code, resp := range op.Responses {
+			if isErrorStatus(code) {
+				counts[responseFingerprint(code, resp)]++
+			}
+		}
+	}
+	return counts
+}
+
+// responseFingerprint identifies a response by status code, description,
+// headers and body schema shape, so identical responses declared on
+// different operations compare equal.
+func responseFingerprint(code string, r domain.Response) string {
+	var b strings.Builder
+	b.WriteString(code + "|" + r.Description)
+	for _, name := range sortedKeys(r.Headers) {
+		b.WriteString("|h:" + name + ":" + schemaSignature(r.Headers[name].Schema))
+	}
+	for _, ct := range sortedKeys(r.Content) {
+		b.WriteString("|c:" + ct + ":" + schemaSignature(r.Content[ct].Schema))
+	}
+	return b.String()
+}
+
+// schemaSignature renders a schema's structure as a compact string.
+func schemaSignature(s *domain.Schema) string {
+	if s == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(string(s.Type))
+	if s.Format != "" {
+		b.WriteString("(" + s.Format + ")")
+	}
+	if s.Items != nil {
+		b.WriteString("[" + schemaSignature(s.Items) + "]")
+	}
+	if len(s.Properties) > 0 {
+		b.WriteString("{")
+		for _, name := range sortedKeys(s.Properties) {
+			b.WriteString(name + ":" + schemaSignature(s.Properties[name]) + ",")
+		}
+		b.WriteString("}")
+	}
 	return b.String()
 }
 
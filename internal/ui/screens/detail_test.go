@@ -1,12 +1,18 @@
 package screens_test
 
 import (
+	"fmt"
 	"regexp"
 	"strings"
 	"testing"
 
+	"dazzle/internal/application"
 	"dazzle/internal/domain"
 	"dazzle/internal/ui/screens"
+	"dazzle/internal/ui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 )
 
 var ansiRe = regexp.MustCompile(`\x1b\[[0-9;]*m`)
@@ -69,8 +75,13 @@ func minimalOperation() domain.Operation {
 }
 
 func renderDetail(op domain.Operation) string {
+	return renderDetailTab(op, screens.TabOverview)
+}
+
+func renderDetailTab(op domain.Operation, tab screens.DetailTab) string {
 	d := screens.NewDetailPanel(80, 40)
 	d.SetOperation(op)
+	d.SetTab(tab)
 	return d.View()
 }
 
@@ -99,7 +110,7 @@ func TestDetailPanel_RendersSummaryAndDescription(t *testing.T) {
 }
 
 func TestDetailPanel_RendersParameters(t *testing.T) {
-	view := renderDetail(fullOperation())
+	view := renderDetailTab(fullOperation(), screens.TabParameters)
 
 	if !strings.Contains(view, "X-Request-ID") {
 		t.Error("expected parameter name in view")
@@ -118,7 +129,7 @@ func TestDetailPanel_RendersParameters(t *testing.T) {
 }
 
 func TestDetailPanel_RendersRequestBody(t *testing.T) {
-	view := renderDetail(fullOperation())
+	view := renderDetailTab(fullOperation(), screens.TabRequest)
 
 	if !strings.Contains(view, "Request Body") {
 		t.Error("expected Request Body section in view")
@@ -135,7 +146,7 @@ func TestDetailPanel_RendersRequestBody(t *testing.T) {
 }
 
 func TestDetailPanel_RendersResponses(t *testing.T) {
-	view := renderDetail(fullOperation())
+	view := renderDetailTab(fullOperation(), screens.TabResponses)
 
 	if !strings.Contains(view, "201") {
 		t.Error("expected 201 status code in view")
@@ -152,7 +163,7 @@ func TestDetailPanel_RendersResponses(t *testing.T) {
 }
 
 func TestDetailPanel_MinimalOperation(t *testing.T) {
-	view := renderDetail(minimalOperation())
+	view := renderDetailTab(minimalOperation(), screens.TabParameters)
 
 	if !strings.Contains(view, "GET") {
 		t.Error("expected GET in view")
@@ -219,7 +230,7 @@ func TestDetailPanel_RequiredParameter(t *testing.T) {
 		},
 	}
 
-	view := renderDetail(op)
+	view := renderDetailTab(op, screens.TabParameters)
 
 	if !strings.Contains(view, "required") {
 		t.Error("expected required indicator for path param")
@@ -255,7 +266,7 @@ func TestDetailPanel_ArrayResponseSchema(t *testing.T) {
 		},
 	}
 
-	view := renderDetail(op)
+	view := renderDetailTab(op, screens.TabResponses)
 
 	if !strings.Contains(view, "array[object]") {
 		t.Error("expected array[object] type in view")
@@ -264,3 +275,213 @@ func TestDetailPanel_ArrayResponseSchema(t *testing.T) {
 		t.Error("expected id property in view")
 	}
 }
+
+func TestDetailPanel_RendersResponseHeaders(t *testing.T) {
+	op := domain.Operation{
+		ID:     "listPets",
+		Path:   "/pets",
+		Method: domain.GET,
+		Responses: map[string]domain.Response{
+			"200": {
+				Description: "A list of pets",
+				Headers: map[string]domain.Header{
+					"X-Total-Count": {
+						Description: "Total number of pets",
+						Required:    true,
+						Schema:      &domain.Schema{Type: domain.SchemaTypeInteger},
+					},
+				},
+			},
+		},
+	}
+
+	plain := ansiRe.ReplaceAllString(renderDetailTab(op, screens.TabResponses), "")
+
+	if !strings.Contains(plain, "X-Total-Count  integer  required") {
+		t.Error("expected header name, type and required marker")
+	}
+	if !strings.Contains(plain, "Total number of pets") {
+		t.Error("expected header description")
+	}
+}
+
+func TestDetailPanel_StatusClassColours(t *testing.T) {
+	op := domain.Operation{
+		ID:     "getPet",
+		Path:   "/pets/{id}",
+		Method: domain.GET,
+		Responses: map[string]domain.Response{
+			"200": {Description: "OK"},
+			"404": {Description: "Not found"},
+		},
+	}
+
+	view := renderDetailTab(op, screens.TabResponses)
+
+	ok := lipgloss.NewStyle().Bold(true).Foreground(styles.StatusColor("200")).Render("200")
+	notFound := lipgloss.NewStyle().Bold(true).Foreground(styles.StatusColor("404")).Render("404")
+	if !strings.Contains(view, ok) || !strings.Contains(view, notFound) {
+		t.Error("expected status codes rendered in their class colours")
+	}
+}
+
+func TestDetailPanel_TabKeysSwitchTabs(t *testing.T) {
+	d := screens.NewDetailPanel(80, 40)
+	d.SetOperation(fullOperation())
+
+	d.Update(tea.KeyMsg{Type: tea.KeyRight})
+	if d.Tab() != screens.TabParameters {
+		t.Errorf("expected right arrow to select Parameters, got %d", d.Tab())
+	}
+	if !strings.Contains(d.View(), "X-Request-ID") {
+		t.Error("expected Parameters content after switching tab")
+	}
+
+	d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'4'}})
+	if d.Tab() != screens.TabResponses {
+		t.Errorf("expected 4 to select Responses, got %d", d.Tab())
+	}
+
+	// Left from the first tab wraps to the last.
+	d.SetTab(screens.TabOverview)
+	d.Update(tea.KeyMsg{Type: tea.KeyLeft})
+	if d.Tab() != screens.TabCode {
+		t.Errorf("expected left to wrap to Code, got %d", d.Tab())
+	}
+}
+
+func TestDetailPanel_TabKeptAcrossOperations(t *testing.T) {
+	d := screens.NewDetailPanel(80, 40)
+	d.SetOperation(fullOperation())
+	d.SetTab(screens.TabResponses)
+
+	d.SetOperation(minimalOperation())
+	if d.Tab() != screens.TabResponses {
+		t.Errorf("expected Responses tab to be kept, got %d", d.Tab())
+	}
+}
+
+func TestDetailPanel_ScrollRememberedPerTab(t *testing.T) {
+	op := fullOperation()
+	for i := range 30 {
+		op.Parameters = append(op.Parameters, domain.Parameter{Name: fmt.Sprintf("p%02d", i), In: domain.ParameterInQuery})
+	}
+
+	d := screens.NewDetailPanel(80, 10)
+	d.SetOperation(op)
+	d.SetTab(screens.TabParameters)
+	for range 5 {
+		d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	}
+	scrolled := d.View()
+
+	d.SetTab(screens.TabOverview)
+	d.SetTab(screens.TabParameters)
+	if d.View() != scrolled {
+		t.Error("expected Parameters scroll position restored after switching back")
+	}
+
+	// A new operation starts every tab at the top.
+	d.SetOperation(op)
+	if d.View() == scrolled {
+		t.Error("expected scroll position reset for a new operation")
+	}
+}
+
+func TestDetailPanel_OverviewSummarisesSections(t *testing.T) {
+	plain := ansiRe.ReplaceAllString(renderDetail(fullOperation()), "")
+
+	for _, want := range []string{"createPet", "1 header", "application/json  required", "201 400"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q in overview", want)
+		}
+	}
+}
+
+func TestDetailPanel_ExamplesTab(t *testing.T) {
+	op := fullOperation()
+	op.RequestBody.Content["application/json"] = domain.MediaType{
+		Example: map[string]any{"name": "Rex"},
+		Examples: map[string]domain.Example{
+			"cat": {Summary: "A cat", Value: map[string]any{"name": "Tom"}},
+		},
+	}
+
+	plain := ansiRe.ReplaceAllString(renderDetailTab(op, screens.TabExamples), "")
+
+	if !strings.Contains(plain, `"name": "Rex"`) {
+		t.Error("expected inline example rendered as JSON")
+	}
+	if !strings.Contains(plain, "cat  A cat") || !strings.Contains(plain, `"name": "Tom"`) {
+		t.Error("expected named example with its summary")
+	}
+}
+
+func TestDetailPanel_GeneratedExamples(t *testing.T) {
+	op := fullOperation()
+	op.RequestBody.Content["application/json"] = domain.MediaType{Schema: &domain.Schema{
+		Type:       domain.SchemaTypeObject,
+		Properties: map[string]*domain.Schema{"id": {Type: domain.SchemaTypeString, Format: "uuid"}},
+	}}
+
+	d := screens.NewDetailPanel(100, 60)
+	d.SetExampleService(application.NewExampleService(1))
+	d.SetOperation(op)
+	d.SetTab(screens.TabExamples)
+	plain := ansiRe.ReplaceAllString(d.View(), "")
+
+	if !strings.Contains(plain, "Request  application/json  generated example") {
+		t.Errorf("expected a generated request example, got:\n%s", plain)
+	}
+	if !regexp.MustCompile(`"id": "[0-9a-f-]{36}"`).MatchString(plain) {
+		t.Errorf("expected a generated UUID, got:\n%s", plain)
+	}
+}
+
+func TestDetailPanel_SecurityTab(t *testing.T) {
+	op := fullOperation()
+	op.Security = []domain.SecurityRequirement{
+		{"oauth": {"pets:write"}},
+		{"apiKey": nil},
+	}
+
+	d := screens.NewDetailPanel(100, 40)
+	d.SetSecuritySchemes(map[string]domain.SecurityScheme{
+		"apiKey": {Type: domain.SecuritySchemeAPIKey, In: domain.ParameterInHeader, Name: "X-API-Key"},
+		"oauth": {Type: domain.SecuritySchemeOAuth2, Flows: &domain.OAuthFlows{
+			ClientCredentials: &domain.OAuthFlow{TokenURL: "https://auth.example/token"},
+		}},
+	})
+	d.SetOperation(op)
+	d.SetTab(screens.TabSecurity)
+	plain := ansiRe.ReplaceAllString(d.View(), "")
+
+	if !strings.Contains(plain, "oauth  OAuth2 client credentials  scopes: pets:write") {
+		t.Error("expected OAuth2 requirement with scopes")
+	}
+	if !strings.Contains(plain, "apiKey  API key in header X-API-Key") {
+		t.Error("expected API key requirement")
+	}
+	if !strings.Contains(plain, "or") {
+		t.Error("expected alternatives separated by 'or'")
+	}
+}
+
+func TestDetailPanel_SecurityTabAnonymous(t *testing.T) {
+	op := minimalOperation()
+	op.Security = []domain.SecurityRequirement{}
+
+	if !strings.Contains(renderDetailTab(op, screens.TabSecurity), "Anonymous access") {
+		t.Error("expected explicit empty security to read as anonymous access")
+	}
+}
+
+func TestDetailPanel_NarrowTabBarUsesShortLabels(t *testing.T) {
+	d := screens.NewDetailPanel(40, 20)
+	d.SetOperation(fullOperation())
+	plain := ansiRe.ReplaceAllString(d.View(), "")
+
+	if !strings.Contains(plain, "Info") || strings.Contains(plain, "Overview") {
+		t.Error("expected short tab labels when the panel is narrow")
+	}
+}
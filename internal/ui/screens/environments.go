@@ -0,0 +1,99 @@
+package screens
+
+import (
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// noEnvironment is the picker entry for sending without an environment.
+const noEnvironment = "(none)"
+
+// envPicker is a single-select list of environments shown in place of the
+// operation list.
+type envPicker struct {
+	names  []string // noEnvironment first, then the environments in order
+	cursor int
+	offset int
+	height int
+}
+
+func newEnvPicker(set *domain.EnvironmentSet, active string, height int) *envPicker {
+	p := &envPicker{names: append([]string{noEnvironment}, set.Names()...), height: max(1, height)}
+	for i, name := range p.names {
+		if name == active {
+			p.cursor = i
+		}
+	}
+	p.scrollToCursor()
+	return p
+}
+
+// update handles a key press. chosen is the selected environment ("" for
+// none) and is only meaningful when done is true and ok is set.
+func (p *envPicker) update(msg tea.KeyMsg) (chosen string, ok, done bool) {
+	switch msg.String() {
+	case "up", "k":
+		p.cursor = max(0, p.cursor-1)
+	case "down", "j":
+		p.cursor = min(len(p.names)-1, p.cursor+1)
+	case "enter", " ":
+		chosen = p.names[p.cursor]
+		if chosen == noEnvironment {
+			chosen = ""
+		}
+		return chosen, true, true
+	case "esc", "e":
+		return "", false, true
+	}
+	p.scrollToCursor()
+	return "", false, false
+}
+
+func (p *envPicker) setHeight(height int) {
+	p.height = max(1, height)
+	p.scrollToCursor()
+}
+
+func (p *envPicker) scrollToCursor() {
+	visible := p.visibleRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+}
+
+// visibleRows excludes the title and hint lines.
+func (p *envPicker) visibleRows() int {
+	return max(1, p.height-3)
+}
+
+func (p *envPicker) view(active string) string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Environments"))
+	b.WriteString("\n\n")
+
+	end := min(len(p.names), p.offset+p.visibleRows())
+	for i := p.offset; i < end; i++ {
+		name := p.names[i]
+		mark := "( )"
+		if name == active || (name == noEnvironment && active == "") {
+			mark = "(•)"
+		}
+		line := mark + " " + name
+		if i == p.cursor {
+			line = lipgloss.NewStyle().Bold(true).Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(styles.Muted.Render("enter select · esc cancel"))
+	return b.String()
+}
@@ -0,0 +1,75 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+func testEnvironments() *domain.EnvironmentSet {
+	return &domain.EnvironmentSet{Environments: map[string]domain.Environment{
+		"local":   {Name: "local", Variables: map[string]string{"baseUrl": "http://localhost:8080", "pet": "1"}},
+		"staging": {Name: "staging", Variables: map[string]string{"baseUrl": "https://staging.example.com", "pet": "2"}},
+	}}
+}
+
+func TestOperationsScreen_SubstitutesEnvironmentVariables(t *testing.T) {
+	svc := &stubRequestService{resp: jsonResponse(`{}`)}
+	s := newScreen(testSpec(), screens.Services{Requests: svc, Validation: application.NewValidationService()})
+	s.SetEnvironments(application.NewEnvironmentService(nil), testEnvironments(), "local")
+	selectOperation(s, "getPet")
+	s.Update(keyMsg("enter"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "{{baseUrl}}") || !strings.Contains(plain, "@local") {
+		t.Fatal("expected the builder to target the environment's baseUrl")
+	}
+
+	s.Update(keyMsg("down"))
+	typeRunes(s, "{{pet}}")
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	if cmd == nil {
+		t.Fatal("expected the substituted request to be valid and sent")
+	}
+	drainCmd(s, cmd)
+	if svc.values.Server != "http://localhost:8080" || svc.values.Path["petId"] != "1" {
+		t.Errorf("expected substituted values, got %q %v", svc.values.Server, svc.values.Path)
+	}
+}
+
+func TestOperationsScreen_UndefinedVariableBlocksSend(t *testing.T) {
+	svc := &stubRequestService{resp: jsonResponse(`{}`)}
+	s := newBuilderScreen(screens.Services{Requests: svc})
+	s.SetEnvironments(application.NewEnvironmentService(nil), testEnvironments(), "local")
+
+	s.Update(keyMsg("down"))
+	typeRunes(s, "{{nope}}")
+	s.Update(keyMsg("ctrl+o"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "path petId: undefined variable nope (local)") {
+		t.Errorf("expected the substitution error, got:\n%s", plain)
+	}
+}
+
+func TestOperationsScreen_SwitchEnvironment(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.SetEnvironments(application.NewEnvironmentService(nil), testEnvironments(), "")
+
+	s.Update(keyMsg("e"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "Environments") || !strings.Contains(plain, "(•) (none)") {
+		t.Fatalf("expected the environment picker, got:\n%s", plain)
+	}
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("enter"))
+	if got := s.ActiveEnvironment(); got != "staging" {
+		t.Errorf("expected staging to be active, got %q", got)
+	}
+	if !strings.Contains(ansiRe.ReplaceAllString(s.View(), ""), "@staging") {
+		t.Error("expected the active environment in the list title")
+	}
+}
@@ -0,0 +1,262 @@
+package screens
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// facetState tracks the method toggles and tag selections that narrow the
+// operation list before the text filter is applied.
+type facetState struct {
+	methods map[domain.HTTPMethod]bool
+	tags    map[string]bool
+}
+
+func newFacetState() facetState {
+	return facetState{
+		methods: make(map[domain.HTTPMethod]bool),
+		tags:    make(map[string]bool),
+	}
+}
+
+func (f facetState) active() bool {
+	return len(f.methods) > 0 || len(f.tags) > 0
+}
+
+func (f facetState) toggleMethod(m domain.HTTPMethod) {
+	if f.methods[m] {
+		delete(f.methods, m)
+	} else {
+		f.methods[m] = true
+	}
+}
+
+func (f facetState) toggleTag(tag string) {
+	if f.tags[tag] {
+		delete(f.tags, tag)
+	} else {
+		f.tags[tag] = true
+	}
+}
+
+func (f facetState) clear() {
+	clear(f.methods)
+	clear(f.tags)
+}
+
+// activeMethods returns the toggled methods in display order.
+func (f facetState) activeMethods() []domain.HTTPMethod {
+	var methods []domain.HTTPMethod
+	for _, m := range domain.HTTPMethods {
+		if f.methods[m] {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+func (f facetState) activeTags() []string {
+	return sortedKeys(f.tags)
+}
+
+// filter converts the facets into a domain.OperationFilter. When ignoreMethods
+// or ignoreTags is set that facet is left out, which is how per-facet counts
+// are computed against the other active facets.
+func (f facetState) filter(ignoreMethods, ignoreTags bool) domain.OperationFilter {
+	var filter domain.OperationFilter
+	if !ignoreMethods {
+		filter.Methods = f.activeMethods()
+	}
+	if !ignoreTags {
+		filter.Tags = f.activeTags()
+	}
+	return filter
+}
+
+// methodFacets returns the methods used by at least one operation, in
+// display order. Their position determines the number key that toggles them.
+func methodFacets(ops []domain.Operation) []domain.HTTPMethod {
+	present := make(map[domain.HTTPMethod]bool)
+	for _, op := range ops {
+		present[op.Method] = true
+	}
+	var methods []domain.HTTPMethod
+	for _, m := range domain.HTTPMethods {
+		if present[m] {
+			methods = append(methods, m)
+		}
+	}
+	return methods
+}
+
+// tagFacets returns every tag used by at least one operation, sorted.
+func tagFacets(ops []domain.Operation) []string {
+	seen := make(map[string]struct{})
+	for _, op := range ops {
+		for _, t := range op.Tags {
+			seen[t] = struct{}{}
+		}
+	}
+	tags := make([]string, 0, len(seen))
+	for t := range seen {
+		tags = append(tags, t)
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// facetTitle appends the active facets to the list title, e.g.
+// "Petstore API · GET POST · #pets".
+func facetTitle(base string, f facetState) string {
+	parts := []string{base}
+	if methods := f.activeMethods(); len(methods) > 0 {
+		names := make([]string, len(methods))
+		for i, m := range methods {
+			names[i] = string(m)
+		}
+		parts = append(parts, strings.Join(names, " "))
+	}
+	if tags := f.activeTags(); len(tags) > 0 {
+		parts = append(parts, "#"+strings.Join(tags, " #"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderFacetBar renders the method toggles with their counts and a summary
+// of the selected tags, wrapped to width.
+func renderFacetBar(methods []domain.HTTPMethod, counts map[domain.HTTPMethod]int, f facetState, hasTags bool, width int) string {
+	chips := make([]string, 0, len(methods))
+	for i, m := range methods {
+		label := fmt.Sprintf("%d %s %d", i+1, m, counts[m])
+		if f.methods[m] {
+			chips = append(chips, lipgloss.NewStyle().Bold(true).Underline(true).
+				Foreground(styles.MethodColor(string(m))).Render(label))
+		} else {
+			chips = append(chips, styles.Muted.Render(label))
+		}
+	}
+	lines := []string{strings.Join(chips, "  ")}
+
+	if hasTags {
+		if tags := f.activeTags(); len(tags) > 0 {
+			lines = append(lines, lipgloss.NewStyle().Foreground(styles.Blue).
+				Render("#"+strings.Join(tags, " #")))
+		} else {
+			lines = append(lines, styles.Muted.Render("t tags · x clear"))
+		}
+	}
+
+	return lipgloss.NewStyle().Width(max(1, width)).Render(strings.Join(lines, "\n"))
+}
+
+// tagPicker is a multi-select list of tags shown in place of the operation
+// list while the user chooses tag facets.
+type tagPicker struct {
+	tags   []string
+	counts map[string]int
+	facets facetState
+	cursor int
+	offset int
+	height int
+}
+
+func newTagPicker(tags []string, counts map[string]int, f facetState, height int) *tagPicker {
+	return &tagPicker{tags: tags, counts: counts, facets: f, height: max(1, height)}
+}
+
+// update handles a key press and reports whether the selection changed and
+// whether the picker should close.
+func (p *tagPicker) update(msg tea.KeyMsg) (changed, done bool) {
+	switch msg.String() {
+	case "up", "k":
+		p.cursor = max(0, p.cursor-1)
+	case "down", "j":
+		p.cursor = min(len(p.tags)-1, p.cursor+1)
+	case " ", "x":
+		if len(p.tags) > 0 {
+			p.facets.toggleTag(p.tags[p.cursor])
+			changed = true
+		}
+	case "enter", "esc", "t":
+		done = true
+	}
+	p.scrollToCursor()
+	return changed, done
+}
+
+func (p *tagPicker) setHeight(height int) {
+	p.height = max(1, height)
+	p.scrollToCursor()
+}
+
+func (p *tagPicker) scrollToCursor() {
+	visible := p.visibleRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+}
+
+// visibleRows excludes the title and hint lines.
+func (p *tagPicker) visibleRows() int {
+	return max(1, p.height-3)
+}
+
+func (p *tagPicker) view() string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("Tags"))
+	b.WriteString("\n\n")
+
+	if len(p.tags) == 0 {
+		b.WriteString(styles.Muted.Render("No tags in this spec"))
+		return b.String()
+	}
+
+	end := min(len(p.tags), p.offset+p.visibleRows())
+	for i := p.offset; i < end; i++ {
+		tag := p.tags[i]
+		box := "[ ]"
+		if p.facets.tags[tag] {
+			box = "[x]"
+		}
+		line := fmt.Sprintf("%s %s %s", box, tag, styles.Muted.Render(fmt.Sprint(p.counts[tag])))
+		if i == p.cursor {
+			line = lipgloss.NewStyle().Bold(true).Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(line + "\n")
+	}
+	b.WriteString(styles.Muted.Render("space toggle · enter done"))
+	return b.String()
+}
+
+// countByMethod counts operations per method.
+func countByMethod(ops []domain.Operation) map[domain.HTTPMethod]int {
+	counts := make(map[domain.HTTPMethod]int)
+	for _, op := range ops {
+		counts[op.Method]++
+	}
+	return counts
+}
+
+// countByTag counts operations per tag.
+func countByTag(ops []domain.Operation) map[string]int {
+	counts := make(map[string]int)
+	for _, op := range ops {
+		for _, t := range slices.Compact(slices.Sorted(slices.Values(op.Tags))) {
+			counts[t]++
+		}
+	}
+	return counts
+}
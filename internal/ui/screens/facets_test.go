@@ -0,0 +1,114 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/ui/screens"
+)
+
+func TestFacets_BarShowsMethodCounts(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+
+	if !strings.Contains(plain, "1 GET 3") {
+		t.Error("expected GET facet with count 3")
+	}
+	if !strings.Contains(plain, "2 POST 2") {
+		t.Error("expected POST facet with count 2")
+	}
+}
+
+func TestFacets_MethodToggleNarrowsList(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+
+	// Facets are numbered in method order: 1 = GET, 2 = POST.
+	s.Update(keyMsg("2"))
+	view := s.View()
+
+	if !strings.Contains(view, "Create a pet") || !strings.Contains(view, "Refund an order") {
+		t.Error("expected POST operations after toggling POST")
+	}
+	if strings.Contains(view, "List orders") {
+		t.Error("expected GET operations to be hidden")
+	}
+	if !strings.Contains(view, "Petstore API · POST") {
+		t.Error("expected active method facet in list title")
+	}
+
+	// Toggling a second method widens the selection again.
+	s.Update(keyMsg("1"))
+	if !strings.Contains(s.View(), "List orders") {
+		t.Error("expected GET operations after toggling GET too")
+	}
+}
+
+func TestFacets_TagPickerSelectsTags(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+
+	s.Update(keyMsg("t"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "[ ] billing 2") {
+		t.Fatal("expected tag picker listing billing with its count")
+	}
+
+	// billing sorts first; select it and close the picker.
+	s.Update(keyMsg(" "))
+	s.Update(keyMsg("enter"))
+
+	view := s.View()
+	if !strings.Contains(view, "#billing") {
+		t.Error("expected active tag in title")
+	}
+	if strings.Contains(view, "List all pets") {
+		t.Error("expected pets operations to be hidden by the billing tag facet")
+	}
+
+	// Method counts are computed within the active tag facet.
+	plain = ansiRe.ReplaceAllString(view, "")
+	if !strings.Contains(plain, "1 GET 1") {
+		t.Error("expected GET count of 1 within billing")
+	}
+}
+
+func TestFacets_CombineWithTextFilter(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.Update(keyMsg("1"))
+
+	typeFilter(s, "orders")
+
+	view := s.View()
+	if !strings.Contains(view, "List orders") {
+		t.Error("expected GET /orders to match both facet and text filter")
+	}
+	if strings.Contains(view, "Refund an order") {
+		t.Error("expected POST operation to be excluded by the method facet")
+	}
+}
+
+func TestFacets_ClearResetsList(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.Update(keyMsg("2"))
+	s.Update(keyMsg("x"))
+
+	view := s.View()
+	if !strings.Contains(view, "List all pets") {
+		t.Error("expected all operations after clearing facets")
+	}
+	if strings.Contains(view, "Petstore API ·") {
+		t.Error("expected title without facets after clearing")
+	}
+}
+
+func TestFacets_IgnoredWhileTyping(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+
+	// "2" typed into the filter box must not toggle POST.
+	s.Update(keyMsg("/"))
+	s.Update(keyMsg("2"))
+	s.Update(keyMsg("esc"))
+
+	if strings.Contains(s.View(), "Petstore API · POST") {
+		t.Error("expected digits to reach the filter input while filtering")
+	}
+}
@@ -0,0 +1,237 @@
+package screens
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// historyStatusClasses is the order s cycles the status filter through.
+var historyStatusClasses = []string{"", "2xx", "3xx", "4xx", "5xx", domain.StatusClassError}
+
+// historyAction is what a key press in the history panel asks the screen
+// to do.
+type historyAction int
+
+const (
+	historyNone historyAction = iota
+	historyMoved
+	historyReload
+	historyOpen
+	historyResend
+	historyClose
+)
+
+// historyPanel lists sent requests, newest first, in place of the
+// operation list. The right pane shows the selected entry.
+type historyPanel struct {
+	entries []domain.HistoryEntry
+	filter  domain.HistoryFilter
+	err     error
+	cursor  int
+	offset  int
+	height  int
+}
+
+func newHistoryPanel(height int) *historyPanel {
+	return &historyPanel{height: max(1, height)}
+}
+
+// setEntries replaces the listed entries, keeping the cursor in range.
+func (p *historyPanel) setEntries(entries []domain.HistoryEntry, err error) {
+	p.entries, p.err = entries, err
+	p.cursor = min(p.cursor, max(0, len(entries)-1))
+	p.scrollToCursor()
+}
+
+func (p *historyPanel) selected() (domain.HistoryEntry, bool) {
+	if p.cursor >= len(p.entries) {
+		return domain.HistoryEntry{}, false
+	}
+	return p.entries[p.cursor], true
+}
+
+func (p *historyPanel) update(msg tea.KeyMsg) historyAction {
+	switch msg.String() {
+	case "up", "k":
+		p.cursor = max(0, p.cursor-1)
+		p.scrollToCursor()
+		return historyMoved
+	case "down", "j":
+		p.cursor = max(0, min(len(p.entries)-1, p.cursor+1))
+		p.scrollToCursor()
+		return historyMoved
+	case "o":
+		if p.filter.OperationID != "" {
+			p.filter.OperationID = ""
+		} else if e, ok := p.selected(); ok {
+			p.filter.OperationID = e.OperationID
+		}
+		return historyReload
+	case "s":
+		for i, class := range historyStatusClasses {
+			if class == p.filter.StatusClass {
+				p.filter.StatusClass = historyStatusClasses[(i+1)%len(historyStatusClasses)]
+				break
+			}
+		}
+		return historyReload
+	case "enter":
+		return historyOpen
+	case "r":
+		return historyResend
+	case "esc", "H":
+		return historyClose
+	}
+	return historyNone
+}
+
+func (p *historyPanel) setHeight(height int) {
+	p.height = max(1, height)
+	p.scrollToCursor()
+}
+
+func (p *historyPanel) scrollToCursor() {
+	visible := p.visibleRows()
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+visible {
+		p.offset = p.cursor - visible + 1
+	}
+}
+
+// visibleRows excludes the title, filter and hint lines; each entry takes
+// one row.
+func (p *historyPanel) visibleRows() int {
+	return max(1, p.height-4)
+}
+
+func (p *historyPanel) view(width int) string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render("History"))
+	b.WriteString("\n")
+	b.WriteString(styles.Muted.Render(p.filterLine()))
+	b.WriteString("\n\n")
+
+	row := lipgloss.NewStyle().MaxWidth(max(1, width))
+	switch {
+	case p.err != nil:
+		b.WriteString(row.Inherit(styles.Error).Render(p.err.Error()) + "\n")
+	case len(p.entries) == 0:
+		b.WriteString(styles.Muted.Render("No requests yet.") + "\n")
+	}
+	end := min(len(p.entries), p.offset+p.visibleRows())
+	for i := p.offset; i < end; i++ {
+		e := p.entries[i]
+		line := fmt.Sprintf("%s %s %s %s", e.Time.Local().Format("Jan 02 15:04:05"), historyStatus(e), styles.Method(string(e.Method)), e.Path)
+		if i == p.cursor {
+			line = lipgloss.NewStyle().Bold(true).Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(row.Render(line) + "\n")
+	}
+	b.WriteString(styles.Muted.Render("enter open · r resend · o operation · s status · esc close"))
+	return b.String()
+}
+
+func (p *historyPanel) filterLine() string {
+	op, status := "all operations", "any status"
+	if p.filter.OperationID != "" {
+		op = p.filter.OperationID
+	}
+	if p.filter.StatusClass != "" {
+		status = p.filter.StatusClass
+	}
+	return op + " · " + status
+}
+
+// historyStatus renders the entry's status code, or ERR without a response.
+func historyStatus(e domain.HistoryEntry) string {
+	if e.Status == 0 {
+		return styles.Error.Render("ERR")
+	}
+	code := fmt.Sprint(e.Status)
+	return lipgloss.NewStyle().Foreground(styles.StatusColor(code)).Render(code)
+}
+
+// historyEntryView renders one entry in full for the right pane, cut to
+// height lines.
+func historyEntryView(e domain.HistoryEntry, width, height int) string {
+	var b strings.Builder
+	header := string(e.Method) + " " + e.OperationID
+	if e.Environment != "" {
+		header += "  " + styles.Muted.Render("@"+e.Environment)
+	}
+	b.WriteString(styles.Title.Render(header) + "\n")
+	b.WriteString(styles.Muted.Render(e.Time.Local().Format(time.DateTime)) + "\n\n")
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(string(e.Method)+" "+e.URL) + "\n")
+	writeHeaders(&b, e.RequestHeader)
+	if e.RequestBody != "" {
+		b.WriteString("\n" + e.RequestBody + "\n")
+	}
+	b.WriteString("\n")
+
+	if e.Error != "" {
+		b.WriteString(styles.Error.Render(e.Error) + "\n")
+	} else {
+		code := fmt.Sprint(e.Status)
+		status := lipgloss.NewStyle().Bold(true).Foreground(styles.StatusColor(code)).
+			Render(strings.TrimSpace(code + " " + http.StatusText(e.Status)))
+		b.WriteString(status + styles.Muted.Render(" · "+timingsLine(e.Timings)) + "\n")
+		writeHeaders(&b, e.ResponseHeader)
+		if e.ResponseBody != "" {
+			b.WriteString("\n" + e.ResponseBody)
+			if e.ResponseTruncated {
+				b.WriteString(styles.Muted.Render("…"))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	if len(lines) > height {
+		lines = lines[:max(1, height)]
+	}
+	clip := lipgloss.NewStyle().MaxWidth(max(1, width))
+	for i, l := range lines {
+		lines[i] = clip.Render(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func writeHeaders(b *strings.Builder, h http.Header) {
+	for _, name := range sortedKeys(h) {
+		for _, v := range h[name] {
+			b.WriteString(styles.Muted.Render(name+": ") + v + "\n")
+		}
+	}
+}
+
+// timingsLine summarises the phases that happened, e.g.
+// "120ms (dns 2ms, connect 10ms, first byte 90ms)".
+func timingsLine(t domain.Timings) string {
+	var phases []string
+	for _, p := range []struct {
+		name string
+		d    time.Duration
+	}{{"dns", t.DNS}, {"connect", t.Connect}, {"tls", t.TLS}, {"first byte", t.FirstByte}} {
+		if p.d > 0 {
+			phases = append(phases, p.name+" "+p.d.Round(time.Millisecond).String())
+		}
+	}
+	total := t.Total.Round(time.Millisecond).String()
+	if len(phases) == 0 {
+		return total
+	}
+	return total + " (" + strings.Join(phases, ", ") + ")"
+}
@@ -0,0 +1,128 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+// historyLog is an in-memory HistoryRepository.
+type historyLog struct {
+	entries []domain.HistoryEntry
+}
+
+func (l *historyLog) Append(e domain.HistoryEntry) error {
+	l.entries = append(l.entries, e)
+	return nil
+}
+
+func (l *historyLog) List() ([]domain.HistoryEntry, error) { return l.entries, nil }
+
+func TestOperationsScreen_HistoryRecordsAndResends(t *testing.T) {
+	svc := &stubRequestService{resp: jsonResponse(`{"id":7}`)}
+	log := &historyLog{}
+	s := newBuilderScreen(screens.Services{Requests: svc, History: application.NewHistoryService(log)})
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("7"))
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	drainCmd(s, cmd)
+	if len(log.entries) != 1 || log.entries[0].OperationID != "getPet" || log.entries[0].Values.Path["petId"] != "7" {
+		t.Fatalf("expected the request to be recorded, got %+v", log.entries)
+	}
+
+	// Back out to the list and open the history.
+	s.Update(keyMsg("esc"))
+	s.Update(keyMsg("esc"))
+	s.Update(keyMsg("tab"))
+	s.Update(keyMsg("H"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	for _, want := range []string{"History", "all operations · any status", "200 GET /pets/{petId}", "https://petstore.example.com/pets/{petId}", `{"id":7}`} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q in the history view, got:\n%s", want, plain)
+		}
+	}
+
+	s.Update(keyMsg("s"))
+	s.Update(keyMsg("s"))
+	s.Update(keyMsg("s"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "all operations · 4xx") || !strings.Contains(plain, "No requests yet.") {
+		t.Errorf("expected the 4xx filter to hide the entry, got:\n%s", plain)
+	}
+	for range 3 {
+		s.Update(keyMsg("s"))
+	}
+
+	svc.values = domain.RequestValues{}
+	_, cmd = s.Update(keyMsg("r"))
+	if cmd == nil {
+		t.Fatal("expected r to resend the entry")
+	}
+	drainCmd(s, cmd)
+	if svc.values.Path["petId"] != "7" {
+		t.Errorf("expected the recorded values to be resent, got %v", svc.values.Path)
+	}
+	if len(log.entries) != 2 {
+		t.Errorf("expected the resend to be recorded too, got %d entries", len(log.entries))
+	}
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "200 OK") {
+		t.Errorf("expected the response pane, got:\n%s", plain)
+	}
+}
+
+// credentialMap is an in-memory CredentialRepository.
+type credentialMap map[string]domain.Credentials
+
+func (m credentialMap) Load() (map[string]domain.Credentials, error) { return m, nil }
+
+func TestOperationsScreen_HistoryLeavesOutCredentials(t *testing.T) {
+	spec := testSpec()
+	spec.SecuritySchemes = map[string]domain.SecurityScheme{
+		"key":          {Type: domain.SecuritySchemeAPIKey, In: domain.ParameterInQuery, Name: "key"},
+		"subscription": {Type: domain.SecuritySchemeAPIKey, In: domain.ParameterInHeader, Name: "Ocp-Apim-Subscription-Key"},
+	}
+	spec.Operations[2].Security = []domain.SecurityRequirement{{"key": nil, "subscription": nil}}
+	svc := &stubRequestService{resp: jsonResponse(`{}`)}
+	log := &historyLog{}
+	s := newScreen(spec, screens.Services{
+		Requests: svc,
+		Auth:     application.NewAuthService(credentialMap{"key": {APIKey: "s3cret"}, "subscription": {APIKey: "s3cret"}}, nil, nil),
+		History:  application.NewHistoryService(log),
+	})
+	selectOperation(s, "getPet")
+	s.Update(keyMsg("enter"))
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("7"))
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	drainCmd(s, cmd)
+	if svc.sent == nil || !strings.Contains(svc.sent.URL, "key=s3cret") || svc.sent.Header.Get("Ocp-Apim-Subscription-Key") != "s3cret" {
+		t.Fatalf("expected the keys sent, got %+v", svc.sent)
+	}
+	if len(log.entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(log.entries))
+	}
+	if e := log.entries[0]; strings.Contains(e.URL, "s3cret") || len(e.RequestHeader.Values("Ocp-Apim-Subscription-Key")) > 0 {
+		t.Errorf("expected no credentials in the history, got %s %v", e.URL, e.RequestHeader)
+	}
+}
+
+func TestOperationsScreen_HistoryReopensInBuilder(t *testing.T) {
+	log := &historyLog{entries: []domain.HistoryEntry{{
+		ID: "1", OperationID: "getPet", Method: domain.GET, Path: "/pets/{petId}", Status: 404,
+		Values: domain.RequestValues{Server: "https://petstore.example.com", Path: map[string]string{"petId": "42"}, Query: map[string]string{"fields": "basic"}},
+	}}}
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{}, History: application.NewHistoryService(log)})
+	s.Update(keyMsg("esc"))
+	s.Update(keyMsg("tab"))
+
+	s.Update(keyMsg("H"))
+	s.Update(keyMsg("enter"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "42") || !strings.Contains(plain, "basic") || !strings.Contains(plain, "ctrl+s") {
+		t.Errorf("expected the builder filled from the entry, got:\n%s", plain)
+	}
+}
@@ -0,0 +1,64 @@
+package screens
+
+import (
+	"strings"
+
+	"dazzle/internal/ui/styles"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// importPanel takes a pasted curl command in the right pane.
+type importPanel struct {
+	input textarea.Model
+	err   error
+	width int
+}
+
+func newImportPanel(width, height int) *importPanel {
+	p := &importPanel{input: textarea.New(), width: width}
+	p.input.ShowLineNumbers = false
+	p.input.CharLimit = 0
+	p.input.Placeholder = "curl https://api.example.com/pets -H 'Accept: application/json'"
+	p.setSize(width, height)
+	p.input.Focus()
+	return p
+}
+
+func (p *importPanel) setSize(width, height int) {
+	p.width = width
+	p.input.SetWidth(max(1, width))
+	p.input.SetHeight(max(3, height-6))
+}
+
+// update handles a key press; done reports that the panel closed, and ok
+// that the command should be imported.
+func (p *importPanel) update(msg tea.KeyMsg) (cmd tea.Cmd, ok, done bool) {
+	switch msg.String() {
+	case "ctrl+s":
+		return nil, true, true
+	case "esc":
+		return nil, false, true
+	}
+	p.err = nil
+	p.input, cmd = p.input.Update(msg)
+	return cmd, false, false
+}
+
+func (p *importPanel) command() string { return strings.TrimSpace(p.input.Value()) }
+
+func (p *importPanel) view() string {
+	var sb strings.Builder
+	sb.WriteString(styles.Title.Render("Import cURL"))
+	sb.WriteString("\n\n")
+	sb.WriteString(p.input.View())
+	sb.WriteString("\n\n")
+	if p.err != nil {
+		sb.WriteString(lipgloss.NewStyle().Width(max(1, p.width)).Inherit(styles.Error).Render(p.err.Error()))
+		sb.WriteString("\n")
+	}
+	sb.WriteString(styles.Muted.Render("paste a curl command · ctrl+s import · esc cancel"))
+	return sb.String()
+}
@@ -0,0 +1,48 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dazzle/internal/application"
+	"dazzle/internal/ui/screens"
+)
+
+func paste(s *screens.OperationsScreen, text string) {
+	s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text), Paste: true})
+}
+
+func TestOperationsScreen_ImportCurlOpensBuilder(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{Requests: &stubRequestService{}, Import: application.NewImportService()})
+	s.Update(keyMsg("I"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "Import cURL") {
+		t.Fatalf("expected the import panel, got:\n%s", plain)
+	}
+
+	paste(s, "curl 'https://petstore.example.com/pets/42?fields=basic&debug=1' \\\n  -H 'Accept: application/json'")
+	s.Update(keyMsg("ctrl+s"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	for _, want := range []string{"GET /pets/{petId}", "42", "basic", "! query debug: not in the spec; dropped", "✓ Imported from cURL"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q in the builder, got:\n%s", want, plain)
+		}
+	}
+}
+
+func TestOperationsScreen_ImportCurlShowsErrors(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{Requests: &stubRequestService{}, Import: application.NewImportService()})
+	s.Update(keyMsg("I"))
+	paste(s, "curl https://petstore.example.com/owners")
+	s.Update(keyMsg("ctrl+s"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "Import cURL") || !strings.Contains(plain, "no operation matches GET /owners") {
+		t.Errorf("expected the error in the import panel, got:\n%s", plain)
+	}
+
+	s.Update(keyMsg("esc"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); strings.Contains(plain, "Import cURL") {
+		t.Errorf("expected esc to close the import panel, got:\n%s", plain)
+	}
+}
@@ -0,0 +1,207 @@
+package screens
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"dazzle/internal/infrastructure/jsonpath"
+)
+
+type jsonKind int
+
+const (
+	jsonScalar jsonKind = iota
+	jsonObject
+	jsonArray
+)
+
+// jsonNode is a node of a decoded JSON document that keeps object members
+// in document order, which map[string]any does not.
+type jsonNode struct {
+	loc      string // jsonpath location, e.g. "$.items[3].price"; unique per node
+	key      string // member name when the parent is an object
+	inArray  bool
+	kind     jsonKind
+	value    any // scalars only
+	children []*jsonNode
+	parent   *jsonNode
+}
+
+// parseJSONTree decodes body into an ordered tree.
+func parseJSONTree(body []byte) (*jsonNode, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	root, err := decodeNode(dec, jsonpath.Location{}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("unexpected data after JSON value")
+	}
+	return root, nil
+}
+
+func decodeNode(dec *json.Decoder, loc jsonpath.Location, parent *jsonNode) (*jsonNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	n := &jsonNode{loc: loc.String(), parent: parent}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		n.value = tok
+		return n, nil
+	}
+
+	switch delim {
+	case '{':
+		n.kind = jsonObject
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key, _ := keyTok.(string)
+			child, err := decodeNode(dec, loc.Key(key), n)
+			if err != nil {
+				return nil, err
+			}
+			child.key = key
+			n.children = append(n.children, child)
+		}
+	case '[':
+		n.kind = jsonArray
+		for i := 0; dec.More(); i++ {
+			child, err := decodeNode(dec, loc.Index(i), n)
+			if err != nil {
+				return nil, err
+			}
+			child.inArray = true
+			n.children = append(n.children, child)
+		}
+	}
+	// Consume the closing delimiter.
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// valueTree builds a tree from an already decoded value, as returned by a
+// jsonpath filter. Object members are ordered by name.
+func valueTree(v any, loc jsonpath.Location, parent *jsonNode) *jsonNode {
+	n := &jsonNode{loc: loc.String(), parent: parent}
+	switch t := v.(type) {
+	case map[string]any:
+		n.kind = jsonObject
+		for _, k := range sortedKeys(t) {
+			child := valueTree(t[k], loc.Key(k), n)
+			child.key = k
+			n.children = append(n.children, child)
+		}
+	case []any:
+		n.kind = jsonArray
+		for i, e := range t {
+			child := valueTree(e, loc.Index(i), n)
+			child.inArray = true
+			n.children = append(n.children, child)
+		}
+	default:
+		n.value = v
+	}
+	return n
+}
+
+// walk visits n and its descendants depth-first.
+func (n *jsonNode) walk(fn func(*jsonNode)) {
+	fn(n)
+	for _, c := range n.children {
+		c.walk(fn)
+	}
+}
+
+// jsonLine is a rendered line of the tree. Expanded containers produce an
+// opening and a closing line; both refer to the same node.
+type jsonLine struct {
+	node    *jsonNode
+	closing bool
+	text    string
+	note    string // shown muted after the highlighted text
+}
+
+// flattenJSON renders the visible lines of the tree as indented JSON,
+// replacing collapsed containers with a one-line summary.
+func flattenJSON(root *jsonNode, collapsed map[string]bool) []jsonLine {
+	var lines []jsonLine
+	var visit func(n *jsonNode, depth int, last bool)
+	visit = func(n *jsonNode, depth int, last bool) {
+		pad := strings.Repeat("  ", depth)
+		prefix := pad
+		if n.parent != nil && !n.inArray {
+			prefix += scalarText(n.key) + ": "
+		}
+		comma := ","
+		if last {
+			comma = ""
+		}
+
+		switch {
+		case n.kind == jsonScalar:
+			lines = append(lines, jsonLine{node: n, text: prefix + scalarText(n.value) + comma})
+		case len(n.children) == 0:
+			lines = append(lines, jsonLine{node: n, text: prefix + emptyContainer(n.kind) + comma})
+		case collapsed[n.loc]:
+			open, end := containerDelims(n.kind)
+			lines = append(lines, jsonLine{node: n, text: prefix + open + "…" + end + comma, note: containerSize(n)})
+		default:
+			open, end := containerDelims(n.kind)
+			lines = append(lines, jsonLine{node: n, text: prefix + open})
+			for i, c := range n.children {
+				visit(c, depth+1, i == len(n.children)-1)
+			}
+			lines = append(lines, jsonLine{node: n, closing: true, text: pad + end + comma})
+		}
+	}
+	if root != nil {
+		visit(root, 0, true)
+	}
+	return lines
+}
+
+func containerDelims(k jsonKind) (string, string) {
+	if k == jsonArray {
+		return "[", "]"
+	}
+	return "{", "}"
+}
+
+func emptyContainer(k jsonKind) string {
+	open, end := containerDelims(k)
+	return open + end
+}
+
+func containerSize(n *jsonNode) string {
+	unit := "keys"
+	if n.kind == jsonArray {
+		unit = "items"
+	}
+	if len(n.children) == 1 {
+		unit = strings.TrimSuffix(unit, "s")
+	}
+	return fmt.Sprintf("%d %s", len(n.children), unit)
+}
+
+// scalarText encodes a scalar as JSON without HTML escaping.
+func scalarText(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprint(v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -0,0 +1,75 @@
+package screens
+
+import (
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+type loginState int
+
+const (
+	loginStarting loginState = iota
+	loginWaiting
+	loginDone
+	loginFailed
+)
+
+// loginStartedMsg carries the session once the login page is ready. seq
+// identifies the login so a cancelled one is ignored.
+type loginStartedMsg struct {
+	seq     int
+	session domain.LoginSession
+	err     error
+}
+
+// loginDoneMsg reports the end of a login.
+type loginDoneMsg struct {
+	seq int
+	err error
+}
+
+// loginPanel shows the progress of an interactive login in the right pane.
+type loginPanel struct {
+	scheme string
+	env    string
+	state  loginState
+	url    string
+	err    error
+	width  int
+}
+
+func (p *loginPanel) view() string {
+	var sb strings.Builder
+	header := "Log in · " + p.scheme
+	if p.env != "" {
+		header += "  " + styles.Muted.Render("@"+p.env)
+	}
+	sb.WriteString(styles.Title.Render(header))
+	sb.WriteString("\n\n")
+
+	wrap := lipgloss.NewStyle().Width(max(1, p.width))
+	switch p.state {
+	case loginStarting:
+		sb.WriteString(styles.Muted.Render("Preparing login…"))
+	case loginWaiting:
+		sb.WriteString(wrap.Render("Waiting for you to sign in with your browser. If it did not open, visit:"))
+		sb.WriteString("\n\n")
+		sb.WriteString(wrap.Foreground(styles.Blue).Render(p.url))
+	case loginDone:
+		sb.WriteString(wrap.Foreground(styles.Green).Render("✓ Logged in. Requests using " + p.scheme + " now carry your token."))
+	case loginFailed:
+		sb.WriteString(wrap.Inherit(styles.Error).Render(p.err.Error()))
+	}
+
+	sb.WriteString("\n\n")
+	if p.state == loginStarting || p.state == loginWaiting {
+		sb.WriteString(styles.Muted.Render("esc cancel"))
+	} else {
+		sb.WriteString(styles.Muted.Render("esc back"))
+	}
+	return sb.String()
+}
@@ -0,0 +1,75 @@
+package screens_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+// stubSession is a LoginSession whose sign-in completes with err.
+type stubSession struct{ err error }
+
+func (s stubSession) AuthURL() string            { return "https://idp.example.com/authorize?client_id=dazzle" }
+func (s stubSession) Wait(context.Context) error { return s.err }
+func (s stubSession) Close() error               { return nil }
+
+// stubLoginAuth records which scheme a login was started for.
+type stubLoginAuth struct {
+	scheme  string
+	waitErr error
+}
+
+func (a *stubLoginAuth) Authenticate(context.Context, map[string]domain.SecurityScheme, domain.Operation, domain.Environment, *domain.HTTPRequest) error {
+	return nil
+}
+
+func (a *stubLoginAuth) BeginLogin(_ context.Context, _ map[string]domain.SecurityScheme, name string, _ domain.Environment) (domain.LoginSession, error) {
+	a.scheme = name
+	return stubSession{err: a.waitErr}, nil
+}
+
+// runLogin presses L on the operation selected and feeds each step of the login back to the screen.
+func runLogin(s *screens.OperationsScreen) (waiting string) {
+	_, cmd := s.Update(keyMsg("L"))
+	_, cmd = s.Update(cmd())
+	waiting = ansiRe.ReplaceAllString(s.View(), "")
+	s.Update(cmd())
+	return waiting
+}
+
+func TestOperationsScreen_Login(t *testing.T) {
+	auth := &stubLoginAuth{}
+	s := newScreen(testSpec(), screens.Services{Auth: auth})
+	selectOperation(s, "getPet")
+
+	waiting := runLogin(s)
+	if auth.scheme != "oidc" {
+		t.Errorf("expected a login to the operation's OIDC scheme, got %q", auth.scheme)
+	}
+	if !strings.Contains(waiting, "Waiting for you to sign in") || !strings.Contains(waiting, "https://idp.example.com/authorize?client_id=dazzle") {
+		t.Errorf("expected the login URL while waiting, got:\n%s", waiting)
+	}
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "✓ Logged in") {
+		t.Errorf("expected the login to complete, got:\n%s", plain)
+	}
+
+	s.Update(keyMsg("esc"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); strings.Contains(plain, "Logged in") {
+		t.Error("expected esc to leave the login pane")
+	}
+}
+
+func TestOperationsScreen_LoginFailure(t *testing.T) {
+	auth := &stubLoginAuth{waitErr: errors.New("login refused: access_denied")}
+	s := newScreen(testSpec(), screens.Services{Auth: auth})
+	selectOperation(s, "getPet")
+
+	runLogin(s)
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "login refused: access_denied") {
+		t.Errorf("expected the login error, got:\n%s", plain)
+	}
+}
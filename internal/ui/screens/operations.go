@@ -1,14 +1,23 @@
 package screens
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"io"
+	"maps"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"dazzle/internal/domain"
 	"dazzle/internal/ui/styles"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -35,6 +44,10 @@ func (d operationDelegate) Spacing() int                            { return 1 }
 func (d operationDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 
 func (d operationDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if saved, ok := item.(savedRequestItem); ok {
+		renderSavedRequestItem(w, m, index, saved)
+		return
+	}
 	op, ok := item.(operationItem)
 	if !ok {
 		return
@@ -69,50 +82,188 @@ const (
 	focusDetail
 )
 
+// rightPane is what the right-hand panel shows.
+type rightPane int
+
+const (
+	paneDetail rightPane = iota
+	paneRequest
+	paneResponse
+	paneLogin
+	paneHistory
+	paneImport
+)
+
+// loginTimeout bounds how long a login waits for the user to sign in.
+const loginTimeout = 5 * time.Minute
+
+// responseMsg carries the result of a request sent from the builder. seq
+// identifies the send so a slow response cannot replace a newer one.
+// historyErr reports a failure to record the request. values and req are
+// what was sent, and captures what to capture from the response.
+type responseMsg struct {
+	seq        int
+	op         domain.Operation
+	values     domain.RequestValues
+	req        *domain.HTTPRequest
+	captures   []domain.Capture
+	resp       *domain.HTTPResponse
+	err        error
+	historyErr error
+}
+
+// sessionEnvironment receives captured values when no environment is
+// active.
+const sessionEnvironment = "session"
+
 // OperationsScreen displays a split-pane view: filterable operation list
-// on the left, operation detail on the right.
+// on the left, operation detail on the right. Its Services add sending,
+// history, collections and the other features around an operation.
 type OperationsScreen struct {
-	list   list.Model
-	detail *DetailPanel
-	focus  panelFocus
-	lastID string
-	width  int
-	height int
+	list      list.Model
+	tree      *operationTree
+	treeView  bool
+	detail    *DetailPanel
+	opSvc     domain.OperationService
+	ops       []domain.Operation
+	title     string
+	facets    facetState
+	methods   []domain.HTTPMethod
+	tags      []string
+	tagPicker *tagPicker
+	envPicker *envPicker
+	history   *historyPanel
+	byValue   map[string]domain.Operation
+	focus     panelFocus
+	lastID    string
+	width     int
+	height    int
+
+	ctx      context.Context
+	svc      Services
+	servers  []domain.Server
+	pane     rightPane
+	builder  *RequestBuilder
+	response *ResponseView
+	seq      int
+
+	envSvc    domain.EnvironmentService
+	envs      *domain.EnvironmentSet
+	activeEnv string
+
+	schemes     map[string]domain.SecurityScheme
+	login       *loginPanel
+	loginSeq    int
+	loginCtx    context.Context
+	loginCancel context.CancelFunc
+
+	historyErr error
+
+	saved      map[string][]domain.SavedRequest
+	savePrompt *savePrompt
+
+	snippetKey string
+
+	importer *importPanel
+
+	exchange *responseMsg // the response shown, for following its links
+	links    []responseLink
+}
+
+// responseLink is a link the response shown offers.
+type responseLink struct {
+	name string
+	link domain.Link
 }
 
 func NewOperationsScreen(spec *domain.Spec, opSvc domain.OperationService) *OperationsScreen {
 	ops := opSvc.SortOperations(opSvc.ListOperations(spec))
 
-	items := make([]list.Item, len(ops))
-	for i, op := range ops {
-		items[i] = operationItem{op: op}
-	}
-
 	title := spec.Info.Title
 	if title == "" {
 		title = "Endpoints"
 	}
 
-	l := list.New(items, operationDelegate{}, 0, 0)
+	l := list.New(listItems(ops, nil), operationDelegate{}, 0, 0)
 	l.Title = title
 	l.SetShowStatusBar(true)
 	l.SetFilteringEnabled(true)
 	l.Styles.Title = styles.Title
 
 	s := &OperationsScreen{
-		list:   l,
-		detail: NewDetailPanel(0, 0),
+		list:     l,
+		tree:     newOperationTree(),
+		detail:   NewDetailPanel(0, 0),
+		opSvc:    opSvc,
+		ops:      ops,
+		title:    title,
+		facets:   newFacetState(),
+		methods:  methodFacets(ops),
+		tags:     tagFacets(ops),
+		byValue:  make(map[string]domain.Operation, len(ops)),
+		servers:  spec.Servers,
+		schemes:  spec.SecuritySchemes,
+		response: NewResponseView(0, 0),
 	}
+	for _, op := range ops {
+		s.byValue[operationItem{op: op}.FilterValue()] = op
+	}
+	s.list.AdditionalShortHelpKeys = s.facetKeys
+	s.list.Filter = s.searchFilter
+	s.detail.SetSharedResponses(sharedResponses(ops))
+	s.detail.SetSecuritySchemes(spec.SecuritySchemes)
 
 	s.syncDetail()
 	return s
 }
 
+// SetServices enables the features the services provide; ctx bounds every
+// request sent. Collections are loaded straight away.
+func (s *OperationsScreen) SetServices(ctx context.Context, svc Services) {
+	s.ctx = ctx
+	s.svc = svc
+	s.detail.SetExampleService(svc.Examples)
+	if svc.Collections != nil {
+		s.loadCollections()
+	}
+}
+
+// SetEnvironments enables {{var}} substitution and the environment switcher.
+// active names the environment in use; "" means none.
+func (s *OperationsScreen) SetEnvironments(svc domain.EnvironmentService, set *domain.EnvironmentSet, active string) {
+	s.envSvc = svc
+	s.envs = set
+	s.activeEnv = active
+	s.list.Title = s.listTitle()
+}
+
+// loadCollections reloads the saved requests; a failure is shown in the
+// list's status bar.
+func (s *OperationsScreen) loadCollections() tea.Cmd {
+	collections, err := s.svc.Collections.LoadCollections()
+	if err != nil {
+		return s.list.NewStatusMessage(styles.Error.Render("collections: " + err.Error()))
+	}
+	s.saved = s.svc.Collections.ByOperation(collections)
+	s.tree.setSaved(s.saved)
+	return s.applyFacets()
+}
+
+// ActiveEnvironment returns the name of the environment in use.
+func (s *OperationsScreen) ActiveEnvironment() string { return s.activeEnv }
+
 func (s *OperationsScreen) Name() string { return "operations" }
 
 func (s *OperationsScreen) Init() tea.Cmd { return nil }
 
+// Update handles msg, then prepares the Code tab's snippets if what they
+// depend on changed.
 func (s *OperationsScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := s.update(msg)
+	return model, tea.Batch(cmd, s.refreshSnippets())
+}
+
+func (s *OperationsScreen) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		s.width = msg.Width
@@ -120,20 +271,89 @@ func (s *OperationsScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		s.layoutPanels()
 		return s, nil
 
+	case loginStartedMsg:
+		return s, s.handleLoginStarted(msg)
+
+	case loginDoneMsg:
+		if msg.seq == s.loginSeq {
+			s.finishLogin(msg.err)
+		}
+		return s, nil
+
+	case snippetMsg:
+		if msg.key == s.snippetKey {
+			s.detail.SetSnippets(msg.snippets, msg.source, msg.err)
+		}
+		return s, nil
+
+	case copiedMsg:
+		if msg.err != nil {
+			s.detail.SetCodeNotice("✗ " + msg.err.Error())
+		} else {
+			s.detail.SetCodeNotice("✓ Copied " + msg.label + " to the clipboard")
+		}
+		return s, nil
+
+	case responseMsg:
+		s.historyErr = msg.historyErr
+		if msg.seq == s.seq {
+			if msg.err != nil {
+				s.response.SetError(msg.err)
+			} else {
+				s.response.SetResponse(msg.resp)
+				if s.svc.Validation != nil {
+					s.response.SetViolations(s.svc.Validation.ValidateResponse(msg.op, msg.resp))
+				}
+				s.chainResponse(msg)
+			}
+		}
+		return s, nil
+
+	case captureMsg:
+		if s.builder != nil && s.exchange != nil {
+			s.builder.AddCapture(msg.capture)
+			s.capture([]domain.Capture{msg.capture})
+		}
+		return s, nil
+
+	case followLinkMsg:
+		s.followLink(msg.index)
+		return s, textinput.Blink
+
 	case tea.KeyMsg:
+		if s.focus == focusDetail && s.pane != paneDetail {
+			if cmd, handled := s.updateExchangePane(msg); handled {
+				return s, cmd
+			}
+		}
 		if msg.String() == "tab" {
 			s.toggleFocus()
 			return s, nil
 		}
-		// Detail panel has no text input, so 'q' always means quit.
-		if s.focus == focusDetail && msg.String() == "q" {
+		if msg.String() == "enter" && s.canOpenRequest() {
+			s.openRequest()
+			return s, textinput.Blink
+		}
+		// Detail panel and tree have no text input, so 'q' always means quit.
+		if (s.focus == focusDetail || s.treeView) && msg.String() == "q" {
 			return s, tea.Quit
 		}
+		if s.focus == focusList {
+			if cmd, handled := s.handleFacetKey(msg); handled {
+				return s, cmd
+			}
+		}
 		// Route key messages based on which panel is focused.
 		var cmd tea.Cmd
-		if s.focus == focusDetail {
+		switch {
+		case s.focus == focusDetail && msg.String() == "y" && s.detail.Tab() == TabCode:
+			cmd = s.copySnippet()
+		case s.focus == focusDetail:
 			cmd = s.detail.Update(msg)
-		} else {
+		case s.treeView:
+			s.tree.update(msg)
+			s.syncDetail()
+		default:
 			s.list, cmd = s.list.Update(msg)
 			s.syncDetail()
 		}
@@ -142,9 +362,15 @@ func (s *OperationsScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.MouseMsg:
 		// Route mouse scroll to whichever panel the cursor is over.
 		var cmd tea.Cmd
-		if s.panelAt(msg.X) == focusDetail {
+		switch {
+		case s.panelAt(msg.X) == focusDetail && s.pane == paneResponse:
+			cmd = s.response.Update(msg)
+		case s.panelAt(msg.X) == focusDetail:
 			cmd = s.detail.Update(msg)
-		} else {
+		case s.treeView:
+			s.tree.update(msg)
+			s.syncDetail()
+		default:
 			s.list, cmd = s.list.Update(msg)
 			s.syncDetail()
 		}
@@ -155,6 +381,9 @@ func (s *OperationsScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// can process async results regardless of which panel is focused.
 	var cmd tea.Cmd
 	s.list, cmd = s.list.Update(msg)
+	if s.treeView {
+		s.tree.setOperations(s.visibleOperations())
+	}
 	s.syncDetail()
 	return s, cmd
 }
@@ -189,8 +418,8 @@ func (s *OperationsScreen) View() string {
 		detailBorder = activeBorder.Width(detailContentW).PaddingLeft(1).PaddingRight(1)
 	}
 
-	listView := listBorder.Render(s.list.View())
-	detailView := detailBorder.Render(s.detail.View())
+	listView := listBorder.Render(s.listPanelView())
+	detailView := detailBorder.Render(s.rightPaneView())
 
 	return lipgloss.JoinHorizontal(lipgloss.Top, listView, detailView)
 }
@@ -206,8 +435,30 @@ func (s *OperationsScreen) layoutPanels() {
 
 	// Account for border (1 char each side), clamped to avoid negative sizes.
 	// Detail panel also has 1 char horizontal padding on each side.
-	s.list.SetSize(max(1, listWidth-2), contentH)
+	// The facet bar sits above the list and takes its rendered height.
+	barH := lipgloss.Height(s.facetBarView())
+	s.list.SetSize(max(1, listWidth-2), max(1, contentH-barH))
+	s.tree.setSize(max(1, listWidth-2), max(1, contentH-barH))
 	s.detail.SetSize(max(1, detailWidth-4), contentH)
+	s.response.SetSize(max(1, detailWidth-4), contentH)
+	if s.login != nil {
+		s.login.width = max(1, detailWidth-4)
+	}
+	if s.importer != nil {
+		s.importer.setSize(max(1, detailWidth-4), contentH)
+	}
+	if s.builder != nil {
+		s.builder.SetSize(max(1, detailWidth-4), contentH)
+	}
+	if s.tagPicker != nil {
+		s.tagPicker.setHeight(max(1, contentH-barH))
+	}
+	if s.envPicker != nil {
+		s.envPicker.setHeight(max(1, contentH-barH))
+	}
+	if s.history != nil {
+		s.history.setHeight(max(1, contentH-barH))
+	}
 }
 
 // panelAt returns which panel occupies the given x coordinate.
@@ -227,15 +478,806 @@ func (s *OperationsScreen) toggleFocus() {
 }
 
 func (s *OperationsScreen) syncDetail() {
-	item, ok := s.list.SelectedItem().(operationItem)
-	if !ok {
+	if s.treeView {
+		// Segment rows keep showing the last operation; only an empty
+		// tree clears the panel.
+		op := s.tree.selectedOperation()
+		if op == nil {
+			if len(s.tree.rows) == 0 {
+				s.lastID = ""
+				s.detail.Clear()
+			}
+			return
+		}
+		s.showOperation(*op)
+		return
+	}
+
+	switch item := s.list.SelectedItem().(type) {
+	case operationItem:
+		s.showOperation(item.op)
+	case savedRequestItem:
+		s.showOperation(item.op)
+	default:
 		s.lastID = ""
 		s.detail.Clear()
+	}
+}
+
+func (s *OperationsScreen) showOperation(op domain.Operation) {
+	if op.ID == s.lastID {
 		return
 	}
-	if item.op.ID == s.lastID {
+	// Selecting another operation leaves the builder and response.
+	s.pane = paneDetail
+	s.lastID = op.ID
+	s.detail.SetOperation(op)
+}
+
+func (s *OperationsScreen) rightPaneView() string {
+	switch s.pane {
+	case paneRequest:
+		if s.savePrompt != nil {
+			s.builder.SetPrompt(s.savePrompt.view())
+		} else {
+			s.builder.SetPrompt("")
+		}
+		return s.builder.View()
+	case paneResponse:
+		return s.response.View()
+	case paneLogin:
+		return s.login.view()
+	case paneImport:
+		return s.importer.view()
+	case paneHistory:
+		if e, ok := s.history.selected(); ok {
+			return historyEntryView(e, max(1, s.width-s.listWidth()-4), max(1, s.height-2))
+		}
+		return s.detail.View()
+	default:
+		return s.detail.View()
+	}
+}
+
+// canOpenRequest reports whether enter should open the request builder:
+// sending is enabled, an operation is shown and the key is not needed by
+// the list filter, tag picker or a tree segment.
+func (s *OperationsScreen) canOpenRequest() bool {
+	if s.svc.Requests == nil || s.detail.op == nil {
+		return false
+	}
+	if s.focus == focusDetail {
+		return true
+	}
+	if s.tagPicker != nil || s.envPicker != nil || s.history != nil || s.list.FilterState() == list.Filtering {
+		return false
+	}
+	return !s.treeView || s.tree.selectedOperation() != nil
+}
+
+// openRequest shows the builder for the operation in the detail panel,
+// keeping the values already entered when it is the same operation. From
+// a saved request in the list, the builder starts from its values.
+func (s *OperationsScreen) openRequest() {
+	s.savePrompt = nil
+	var saved *domain.SavedRequest
+	if s.focus == focusList {
+		saved = s.selectedSaved()
+	}
+	switch {
+	case saved != nil:
+		s.builder = s.newBuilder(*s.detail.op)
+		s.builder.SetValues(saved.Values)
+		s.builder.SetCaptures(saved.Captures)
+		s.builder.SetSavedAs(saved.Collection, saved.Name)
+	case s.builder == nil || s.builder.Operation().ID != s.detail.op.ID:
+		s.builder = s.newBuilder(*s.detail.op)
+	}
+	s.builder.EnableSaving(s.svc.Collections != nil)
+	s.builder.SetEnvironment(s.activeEnv)
+	s.layoutPanels()
+	s.validateRequest()
+	s.pane = paneRequest
+	s.focus = focusDetail
+}
+
+// selectedSaved returns the saved request under the list or tree cursor.
+func (s *OperationsScreen) selectedSaved() *domain.SavedRequest {
+	if s.treeView {
+		return s.tree.selectedSaved()
+	}
+	if item, ok := s.list.SelectedItem().(savedRequestItem); ok {
+		return &item.saved
+	}
+	return nil
+}
+
+// saveRequest saves the builder's values, as entered, and its captures
+// under the prompt's name and collection, then lists the request under its
+// operation.
+func (s *OperationsScreen) saveRequest() tea.Cmd {
+	req := s.savePrompt.request(s.builder.Operation(), s.builder.Values())
+	req.Captures = s.builder.Captures()
+	if err := s.svc.Collections.SaveRequest(req); err != nil {
+		s.builder.SetError(fmt.Errorf("saving: %w", err))
+		return nil
+	}
+	s.savePrompt = nil
+	collection := req.Collection
+	if collection == "" {
+		collection = "default"
+	}
+	s.builder.SetSavedAs(collection, strings.TrimSpace(req.Name))
+	s.builder.SetNotice("Saved to " + collection)
+	return s.loadCollections()
+}
+
+// newBuilder creates a request builder for op, generating its body when
+// the spec has no example.
+func (s *OperationsScreen) newBuilder(op domain.Operation) *RequestBuilder {
+	b := NewRequestBuilder(op, s.builderServers())
+	if s.svc.Examples != nil {
+		b.GenerateBody(s.svc.Examples)
+	}
+	return b
+}
+
+// builderServers offers the active environment's baseUrl ahead of the
+// spec's servers, so a new builder targets the environment by default.
+func (s *OperationsScreen) builderServers() []domain.Server {
+	env, ok := s.envs.Get(s.activeEnv)
+	if _, hasBase := env.Variables["baseUrl"]; !ok || !hasBase {
+		return s.servers
+	}
+	return append([]domain.Server{{URL: "{{baseUrl}}", Description: "baseUrl of the active environment"}}, s.servers...)
+}
+
+// resolvedValues returns the builder's values with the active environment's
+// variables substituted.
+func (s *OperationsScreen) resolvedValues() (domain.RequestValues, error) {
+	values := s.builder.Values()
+	if s.envSvc == nil {
+		return values, nil
+	}
+	env, _ := s.envs.Get(s.activeEnv)
+	return s.envSvc.SubstituteValues(env, values)
+}
+
+// validateRequest re-checks the builder's values so invalid fields are
+// marked as the user types. Values are checked after substitution; when a
+// variable cannot be resolved the error is shown instead.
+func (s *OperationsScreen) validateRequest() {
+	values, err := s.resolvedValues()
+	s.builder.SetError(err)
+	if err != nil {
+		values = s.builder.Values()
+	}
+	if s.svc.Validation != nil {
+		s.builder.SetViolations(s.svc.Validation.ValidateRequest(s.builder.Operation(), values))
+	}
+}
+
+// updateExchangePane handles keys for the builder and response panes. esc
+// steps back from response to builder to detail. An invalid request is only
+// sent with ctrl+o.
+func (s *OperationsScreen) updateExchangePane(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch s.pane {
+	case paneRequest:
+		if s.savePrompt != nil {
+			cmd, ok, done := s.savePrompt.update(msg)
+			switch {
+			case done && ok:
+				return s.saveRequest(), true
+			case done:
+				s.savePrompt = nil
+			}
+			return cmd, true
+		}
+		switch msg.String() {
+		case "esc":
+			s.pane = paneDetail
+			return nil, true
+		case "ctrl+r":
+			if s.svc.Collections != nil {
+				collection, name := s.builder.SavedAs()
+				s.savePrompt = newSavePrompt(name, collection)
+				return textinput.Blink, true
+			}
+		case "ctrl+s":
+			if !s.builder.Valid() {
+				s.builder.Block()
+				return nil, true
+			}
+			return s.send(), true
+		case "ctrl+o":
+			return s.send(), true
+		}
+		cmd := s.builder.Update(msg)
+		s.validateRequest()
+		return cmd, true
+
+	case paneResponse:
+		if s.response.Inputting() {
+			return s.response.Update(msg), true
+		}
+		switch msg.String() {
+		case "esc":
+			s.pane = paneRequest
+			return nil, true
+		case "tab":
+			return nil, false
+		}
+		return s.response.Update(msg), true
+
+	case paneImport:
+		if msg.String() == "tab" {
+			return nil, false
+		}
+		cmd, ok, done := s.importer.update(msg)
+		switch {
+		case done && ok:
+			s.importRequest()
+		case done:
+			s.importer = nil
+			s.pane = paneDetail
+		}
+		return cmd, true
+
+	case paneLogin:
+		switch msg.String() {
+		case "esc":
+			s.cancelLogin()
+			s.pane = paneDetail
+			return nil, true
+		case "tab":
+			return nil, false
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// loginScheme picks the scheme to log in to: the first one supporting
+// login among the shown operation's requirements, otherwise among all of
+// the spec's schemes.
+func (s *OperationsScreen) loginScheme() string {
+	if s.detail.op != nil {
+		for _, requirement := range s.detail.op.Security {
+			for _, name := range sortedKeys(requirement) {
+				if s.schemes[name].SupportsLogin() {
+					return name
+				}
+			}
+		}
+	}
+	for _, name := range sortedKeys(s.schemes) {
+		if s.schemes[name].SupportsLogin() {
+			return name
+		}
+	}
+	return ""
+}
+
+// startLogin begins logging in to scheme in the active environment and
+// shows its progress in the right pane.
+func (s *OperationsScreen) startLogin(scheme string) tea.Cmd {
+	s.cancelLogin()
+	ctx := s.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+
+	s.loginSeq++
+	s.loginCtx, s.loginCancel = ctx, cancel
+	s.login = &loginPanel{scheme: scheme, env: s.activeEnv}
+	s.layoutPanels()
+	s.pane = paneLogin
+	s.focus = focusDetail
+
+	seq, svc, schemes := s.loginSeq, s.svc.Auth, s.schemes
+	env, _ := s.envs.Get(s.activeEnv)
+	env = env.Clone()
+	return func() tea.Msg {
+		session, err := svc.BeginLogin(ctx, schemes, scheme, env)
+		return loginStartedMsg{seq: seq, session: session, err: err}
+	}
+}
+
+// handleLoginStarted shows the login URL and waits for the redirect.
+func (s *OperationsScreen) handleLoginStarted(msg loginStartedMsg) tea.Cmd {
+	if msg.seq != s.loginSeq {
+		if msg.session != nil {
+			_ = msg.session.Close()
+		}
+		return nil
+	}
+	if msg.err != nil {
+		s.finishLogin(msg.err)
+		return nil
+	}
+
+	s.login.state = loginWaiting
+	s.login.url = msg.session.AuthURL()
+	ctx, seq := s.loginCtx, msg.seq
+	return func() tea.Msg {
+		return loginDoneMsg{seq: seq, err: msg.session.Wait(ctx)}
+	}
+}
+
+func (s *OperationsScreen) finishLogin(err error) {
+	if err != nil {
+		s.login.state, s.login.err = loginFailed, err
+	} else {
+		s.login.state = loginDone
+	}
+	if s.loginCancel != nil {
+		s.loginCancel()
+		s.loginCancel = nil
+	}
+}
+
+// cancelLogin abandons a login in progress.
+func (s *OperationsScreen) cancelLogin() {
+	if s.loginCancel != nil {
+		s.loginCancel()
+		s.loginCancel = nil
+		s.loginSeq++
+	}
+}
+
+// send builds the request from the builder and sends it asynchronously,
+// authenticating it first since that may fetch a token, then signing it.
+// Build errors are shown in the builder.
+func (s *OperationsScreen) send() tea.Cmd {
+	values, err := s.resolvedValues()
+	if err != nil {
+		s.builder.SetError(err)
+		return nil
+	}
+	req, err := s.svc.Requests.BuildRequest(s.builder.Operation(), values)
+	if err != nil {
+		s.builder.SetError(err)
+		return nil
+	}
+
+	s.seq++
+	seq := s.seq
+	s.pane = paneResponse
+	s.response.SetPending()
+
+	ctx, svc, op := s.ctx, s.svc.Requests, s.builder.Operation()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	authz, history, schemes := domain.Authorizer{Auth: s.svc.Auth, Signer: s.svc.Signer}, s.svc.History, s.schemes
+	// Captures change the set while the request is in flight.
+	env, _ := s.envs.Get(s.activeEnv)
+	env = env.Clone()
+	envName, entered, captures := s.activeEnv, s.builder.Values(), s.builder.Captures()
+	// The history gets the request as built, so the credentials added next
+	// are never written to it, whatever they are named.
+	recorded := req.Clone()
+	return func() tea.Msg {
+		if err := authz.Authorize(ctx, schemes, op, env, req); err != nil {
+			return responseMsg{seq: seq, op: op, err: err}
+		}
+		resp, err := svc.Send(ctx, req)
+		msg := responseMsg{seq: seq, op: op, values: values, req: req, captures: captures, resp: resp, err: err}
+		if history != nil {
+			_, msg.historyErr = history.Record(op, envName, entered, recorded, resp, err)
+		}
+		return msg
+	}
+}
+
+// chainResponse captures the request's values from its response and
+// lists the links the response offers.
+func (s *OperationsScreen) chainResponse(msg responseMsg) {
+	if s.svc.Chain == nil {
+		return
+	}
+	s.exchange = &msg
+	if len(msg.captures) > 0 {
+		s.capture(msg.captures)
+	}
+
+	s.links = nil
+	resp, _ := domain.MatchResponse(msg.op.Responses, strconv.Itoa(msg.resp.StatusCode))
+	links := resp.Links
+	labels := make([]string, 0, len(links))
+	for _, name := range slices.Sorted(maps.Keys(links)) {
+		l := links[name]
+		s.links = append(s.links, responseLink{name: name, link: l})
+		label := name + " " + styles.Muted.Render(cmp.Or(l.OperationID, l.OperationRef))
+		if l.Description != "" {
+			label += styles.Muted.Render(" · " + l.Description)
+		}
+		labels = append(labels, label)
+	}
+	s.response.SetLinks(labels)
+}
+
+// capture stores the values captured from the response shown in the
+// active environment, or in a session environment when none is active.
+// They last until dazzle exits; the environments file is not changed.
+func (s *OperationsScreen) capture(captures []domain.Capture) {
+	values, err := s.svc.Chain.Capture(captures, s.exchange.resp)
+	if len(values) > 0 {
+		if s.envs == nil {
+			s.envs = &domain.EnvironmentSet{}
+		}
+		if _, ok := s.envs.Get(s.activeEnv); !ok {
+			s.setActiveEnvironment(sessionEnvironment)
+		}
+		for name, value := range values {
+			s.envs.SetVariable(s.activeEnv, name, value)
+		}
+	}
+	s.response.SetCaptured(s.activeEnv, values, err)
+}
+
+// followLink opens the builder for the operation the response's link at i
+// leads to, filled in from the exchange. The server and body it does not
+// set stay as a new builder has them, except that the server entered for
+// the request that was sent is kept.
+func (s *OperationsScreen) followLink(i int) {
+	if s.exchange == nil || i >= len(s.links) {
+		return
+	}
+	x, l := s.exchange, s.links[i]
+	op, values, err := s.svc.Chain.FollowLink(l.link, s.ops, x.values, x.req, x.resp)
+	if err != nil {
+		s.response.SetLinkError(fmt.Errorf("%s: %w", l.name, err))
+		return
+	}
+
+	b := s.newBuilder(op)
+	defaults := b.Values()
+	if values.Server == "" {
+		values.Server = s.builder.Values().Server
+	}
+	if values.Body == "" {
+		values.Body, values.ContentType = defaults.Body, defaults.ContentType
+	}
+	b.SetValues(values)
+	s.selectOperation(op)
+	s.builder = b
+	s.focus = focusDetail
+	s.openRequest()
+	s.builder.SetNotice("Filled in from " + l.name)
+}
+
+// importRequest matches the pasted curl command to an operation and opens
+// it in the builder, listing what differs from the spec. A command that
+// cannot be imported keeps the panel open with the error.
+func (s *OperationsScreen) importRequest() {
+	imp, err := s.svc.Import.ImportCurl(s.importer.command(), s.ops, s.servers)
+	if err != nil {
+		s.importer.err = err
 		return
 	}
-	s.lastID = item.op.ID
-	s.detail.SetOperation(item.op)
+	s.importer = nil
+	s.selectOperation(imp.Operation)
+	s.builder = s.newBuilder(imp.Operation)
+	s.builder.SetValues(imp.Values)
+	warnings := make([]string, len(imp.Mismatches))
+	for i, m := range imp.Mismatches {
+		warnings[i] = m.String()
+	}
+	s.builder.SetWarnings(warnings)
+	s.openRequest()
+	s.builder.SetNotice("Imported from cURL")
+}
+
+// openHistory shows the history panel in place of the operation list.
+func (s *OperationsScreen) openHistory() {
+	s.history = newHistoryPanel(s.list.Height())
+	s.focus = focusList
+	s.reloadHistory()
+}
+
+// reloadHistory lists the entries matching the panel's filter. A failure
+// to record the last request is shown above them.
+func (s *OperationsScreen) reloadHistory() {
+	entries, err := s.svc.History.List(s.history.filter)
+	if err == nil && s.historyErr != nil {
+		err = s.historyErr
+	}
+	s.history.setEntries(entries, err)
+	s.pane = paneHistory
+}
+
+// handleHistoryKey runs the history panel's actions.
+func (s *OperationsScreen) handleHistoryKey(msg tea.KeyMsg) tea.Cmd {
+	switch s.history.update(msg) {
+	case historyMoved:
+		s.pane = paneHistory
+	case historyReload:
+		s.reloadHistory()
+	case historyOpen:
+		s.reopenEntry()
+	case historyResend:
+		if s.reopenEntry() {
+			return s.send()
+		}
+	case historyClose:
+		s.history = nil
+		s.pane = paneDetail
+	}
+	return nil
+}
+
+// reopenEntry loads the selected entry into the request builder: its
+// operation, environment and values, except masked secrets, which the
+// AuthService supplies again.
+func (s *OperationsScreen) reopenEntry() bool {
+	e, ok := s.history.selected()
+	if !ok || s.svc.Requests == nil {
+		return false
+	}
+	var op *domain.Operation
+	for i := range s.ops {
+		if s.ops[i].ID == e.OperationID {
+			op = &s.ops[i]
+			break
+		}
+	}
+	if op == nil {
+		s.history.err = fmt.Errorf("operation %s is no longer in the spec", e.OperationID)
+		return false
+	}
+	if _, exists := s.envs.Get(e.Environment); exists || e.Environment == "" {
+		s.setActiveEnvironment(e.Environment)
+	}
+
+	s.history = nil
+	s.selectOperation(*op)
+	s.builder = s.newBuilder(*op)
+	s.builder.SetValues(e.ReplayValues())
+	s.openRequest()
+	return true
+}
+
+// selectOperation moves the list or tree cursor to op, when visible, and
+// shows it.
+func (s *OperationsScreen) selectOperation(op domain.Operation) {
+	if s.treeView {
+		s.tree.selectOperation(op.ID)
+	} else {
+		for i, item := range s.list.VisibleItems() {
+			if oi, ok := item.(operationItem); ok && oi.op.ID == op.ID {
+				s.list.Select(i)
+				break
+			}
+		}
+	}
+	s.showOperation(op)
+}
+
+// visibleOperations returns the operations currently shown by the list,
+// after facets and any applied text filter.
+func (s *OperationsScreen) visibleOperations() []domain.Operation {
+	items := s.list.VisibleItems()
+	ops := make([]domain.Operation, 0, len(items))
+	for _, item := range items {
+		if oi, ok := item.(operationItem); ok {
+			ops = append(ops, oi.op)
+		}
+	}
+	return ops
+}
+
+// toggleTreeView switches the left pane between the flat list and the path
+// tree, carrying the selected operation across.
+func (s *OperationsScreen) toggleTreeView() {
+	s.treeView = !s.treeView
+	if s.treeView {
+		s.tree.setOperations(s.visibleOperations())
+		if s.lastID != "" {
+			s.tree.selectOperation(s.lastID)
+		}
+	} else if op := s.tree.selectedOperation(); op != nil {
+		for i, item := range s.list.VisibleItems() {
+			if oi, ok := item.(operationItem); ok && oi.op.ID == op.ID {
+				s.list.Select(i)
+				break
+			}
+		}
+	}
+	s.syncDetail()
+}
+
+// facetKeys lists the facet bindings in the list's short help.
+func (s *OperationsScreen) facetKeys() []key.Binding {
+	keys := []key.Binding{
+		key.NewBinding(key.WithKeys("1"), key.WithHelp(fmt.Sprintf("1-%d", max(1, len(s.methods))), "method")),
+		key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tags")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "clear facets")),
+		key.NewBinding(key.WithKeys("v"), key.WithHelp("v", "tree")),
+	}
+	if s.envSvc != nil {
+		keys = append(keys, key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "environment")))
+	}
+	if s.svc.Auth != nil && s.loginScheme() != "" {
+		keys = append(keys, key.NewBinding(key.WithKeys("L"), key.WithHelp("L", "log in")))
+	}
+	if s.svc.History != nil {
+		keys = append(keys, key.NewBinding(key.WithKeys("H"), key.WithHelp("H", "history")))
+	}
+	if s.svc.Import != nil && s.svc.Requests != nil {
+		keys = append(keys, key.NewBinding(key.WithKeys("I"), key.WithHelp("I", "import curl")))
+	}
+	return keys
+}
+
+// handleFacetKey processes facet keys while the list is focused. Facet keys
+// are ignored while the user is typing a filter so they reach the input.
+func (s *OperationsScreen) handleFacetKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if s.tagPicker != nil {
+		changed, done := s.tagPicker.update(msg)
+		if done {
+			s.tagPicker = nil
+		}
+		if changed || done {
+			return s.applyFacets(), true
+		}
+		return nil, true
+	}
+	if s.history != nil {
+		return s.handleHistoryKey(msg), true
+	}
+	if s.envPicker != nil {
+		if name, ok, done := s.envPicker.update(msg); done {
+			s.envPicker = nil
+			if ok {
+				s.setActiveEnvironment(name)
+			}
+		}
+		return nil, true
+	}
+
+	if s.list.FilterState() == list.Filtering {
+		return nil, false
+	}
+
+	k := msg.String()
+	switch {
+	case len(k) == 1 && k[0] >= '1' && k[0] <= '9':
+		i := int(k[0] - '1')
+		if i >= len(s.methods) {
+			return nil, true
+		}
+		s.facets.toggleMethod(s.methods[i])
+		return s.applyFacets(), true
+	case k == "t":
+		tagCounts := countByTag(s.opSvc.FilterOperations(s.ops, s.facets.filter(false, true)))
+		s.tagPicker = newTagPicker(s.tags, tagCounts, s.facets, s.list.Height())
+		return nil, true
+	case k == "v":
+		s.toggleTreeView()
+		return nil, true
+	case k == "L" && s.svc.Auth != nil:
+		if scheme := s.loginScheme(); scheme != "" {
+			return s.startLogin(scheme), true
+		}
+		return nil, true
+	case k == "e" && s.envSvc != nil:
+		s.envPicker = newEnvPicker(s.envs, s.activeEnv, s.list.Height())
+		return nil, true
+	case k == "H" && s.svc.History != nil:
+		s.openHistory()
+		return nil, true
+	case k == "I" && s.svc.Import != nil && s.svc.Requests != nil:
+		s.importer = newImportPanel(max(1, s.width-s.listWidth()-4), max(1, s.height-2))
+		s.pane = paneImport
+		s.focus = focusDetail
+		return textarea.Blink, true
+	case k == "x":
+		if !s.facets.active() {
+			return nil, true
+		}
+		s.facets.clear()
+		return s.applyFacets(), true
+	}
+	return nil, false
+}
+
+// applyFacets recomputes the list items from the active facets. The list's
+// own text filter is re-run over the new items, so both combine.
+func (s *OperationsScreen) applyFacets() tea.Cmd {
+	ops := s.ops
+	if s.facets.active() {
+		ops = s.opSvc.FilterOperations(s.ops, s.facets.filter(false, false))
+	}
+	if s.tagPicker != nil {
+		s.tagPicker.counts = countByTag(s.opSvc.FilterOperations(s.ops, s.facets.filter(false, true)))
+	}
+
+	s.list.Title = s.listTitle()
+	cmd := s.list.SetItems(listItems(ops, s.saved))
+	if s.treeView {
+		s.tree.setOperations(s.visibleOperations())
+	}
+	s.layoutPanels()
+	s.syncDetail()
+	return cmd
+}
+
+// listTitle is the list title with the active facets and environment.
+func (s *OperationsScreen) listTitle() string {
+	title := facetTitle(s.title, s.facets)
+	if s.activeEnv != "" {
+		title += " · @" + s.activeEnv
+	}
+	return title
+}
+
+// setActiveEnvironment switches environments. An open builder keeps its
+// values; their variables now resolve against the new environment.
+func (s *OperationsScreen) setActiveEnvironment(name string) {
+	s.activeEnv = name
+	s.list.Title = s.listTitle()
+	if s.builder != nil {
+		s.builder.SetEnvironment(name)
+		s.validateRequest()
+	}
+}
+
+// facetBarView renders the facet bar with counts computed against the other
+// active facets, so each count shows what toggling that facet would yield.
+func (s *OperationsScreen) facetBarView() string {
+	methodCounts := countByMethod(s.opSvc.FilterOperations(s.ops, s.facets.filter(true, false)))
+	return renderFacetBar(s.methods, methodCounts, s.facets, len(s.tags) > 0, max(1, s.listWidth()-2))
+}
+
+func (s *OperationsScreen) listPanelView() string {
+	var body string
+	switch {
+	case s.tagPicker != nil:
+		body = s.tagPicker.view()
+	case s.envPicker != nil:
+		body = s.envPicker.view(s.activeEnv)
+	case s.history != nil:
+		body = s.history.view(max(1, s.listWidth()-2))
+	case s.treeView:
+		body = s.tree.view(s.list.Title)
+	default:
+		body = s.list.View()
+	}
+	return s.facetBarView() + "\n" + body
+}
+
+// searchFilter is the list's filter function. Plain text keeps the list's
+// fuzzy matching; queries using qualifiers (method:, tag:, param:, ...) or
+// quoted phrases go through OperationService.SearchOperations, which searches
+// the whole operation and ranks by relevance. It runs off the UI goroutine,
+// so it only reads state that never changes after construction.
+func (s *OperationsScreen) searchFilter(term string, targets []string) []list.Rank {
+	filter := s.opSvc.ParseQuery(term)
+	if !isStructuredQuery(term, filter) {
+		return list.DefaultFilter(term, targets)
+	}
+
+	index := make(map[string]int, len(targets))
+	candidates := make([]domain.Operation, 0, len(targets))
+	for i, t := range targets {
+		if op, ok := s.byValue[t]; ok {
+			index[t] = i
+			candidates = append(candidates, op)
+		}
+	}
+
+	ranked := s.opSvc.SearchOperations(candidates, filter)
+	ranks := make([]list.Rank, len(ranked))
+	for i, op := range ranked {
+		ranks[i] = list.Rank{Index: index[operationItem{op: op}.FilterValue()]}
+	}
+	return ranks
+}
+
+// isStructuredQuery reports whether the parsed query used qualifiers or
+// quotes. Without either, every whitespace-separated word is a plain term.
+func isStructuredQuery(term string, f domain.OperationFilter) bool {
+	return strings.Contains(term, `"`) || len(f.Terms) != len(strings.Fields(term))
 }
@@ -1,11 +1,13 @@
 package screens_test
 
 import (
+	"context"
 	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"dazzle/internal/application"
 	"dazzle/internal/domain"
 	"dazzle/internal/ui/screens"
 )
@@ -17,18 +19,57 @@ func (s *stubOpService) FilterOperations(ops []domain.Operation, _ domain.Operat
 	return ops
 }
 func (s *stubOpService) SortOperations(ops []domain.Operation) []domain.Operation { return ops }
+func (s *stubOpService) ParseQuery(q string) domain.OperationFilter {
+	return domain.OperationFilter{Terms: strings.Fields(q)}
+}
+func (s *stubOpService) SearchOperations(ops []domain.Operation, _ domain.OperationFilter) []domain.Operation {
+	return ops
+}
+
+// keyMsg builds the key press k names, such as "enter" or "ctrl+s"; any
+// other k is typed as runes.
+func keyMsg(k string) tea.KeyMsg {
+	switch k {
+	case "enter":
+		return tea.KeyMsg{Type: tea.KeyEnter}
+	case "esc":
+		return tea.KeyMsg{Type: tea.KeyEsc}
+	case "down":
+		return tea.KeyMsg{Type: tea.KeyDown}
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "ctrl+o":
+		return tea.KeyMsg{Type: tea.KeyCtrlO}
+	case "ctrl+r":
+		return tea.KeyMsg{Type: tea.KeyCtrlR}
+	case "ctrl+s":
+		return tea.KeyMsg{Type: tea.KeyCtrlS}
+	case "ctrl+u":
+		return tea.KeyMsg{Type: tea.KeyCtrlU}
+	case " ":
+		return tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	default:
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+	}
+}
+
+// typeRunes types text one key at a time and returns the command from the
+// final keystroke.
+func typeRunes(s *screens.OperationsScreen, text string) tea.Cmd {
+	var lastCmd tea.Cmd
+	for _, r := range text {
+		_, lastCmd = s.Update(keyMsg(string(r)))
+	}
+	return lastCmd
+}
 
 // typeFilter enters filter mode and types the given text. It drains the
 // command from the final keystroke so that bubbles/list's async filtering
 // takes effect. Only the last command is drained — the filter captures the
 // model state at creation, so intermediate filter commands are superseded.
 func typeFilter(s *screens.OperationsScreen, text string) {
-	s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'/'}})
-	var lastCmd tea.Cmd
-	for _, r := range text {
-		_, lastCmd = s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
-	}
-	drainCmd(s, lastCmd)
+	s.Update(keyMsg("/"))
+	drainCmd(s, typeRunes(s, text))
 }
 
 // drainCmd executes a command and feeds the result back into the model.
@@ -61,17 +102,72 @@ func drainCmd(s *screens.OperationsScreen, cmd tea.Cmd) {
 	s.Update(result)
 }
 
+// testSpec is the petstore the screen tests share. Its operations are
+// listed in the order OperationService sorts them, so the stub and the
+// real service show the same list: listPets first, getPet third.
 func testSpec() *domain.Spec {
+	pets, billing := []string{"pets"}, []string{"billing"}
 	return &domain.Spec{
-		Info: domain.SpecInfo{Title: "Petstore API"},
+		Info:    domain.SpecInfo{Title: "Petstore API"},
+		Servers: []domain.Server{{URL: "https://petstore.example.com"}},
 		Operations: []domain.Operation{
-			{ID: "listPets", Path: "/pets", Method: domain.GET, Summary: "List all pets"},
-			{ID: "createPet", Path: "/pets", Method: domain.POST, Summary: "Create a pet"},
-			{ID: "deletePet", Path: "/pets/{id}", Method: domain.DELETE, Summary: "Delete a pet"},
+			{ID: "listPets", Path: "/pets", Method: domain.GET, Summary: "List all pets", Tags: pets},
+			{
+				ID: "createPet", Path: "/pets", Method: domain.POST, Summary: "Create a pet", Tags: pets,
+				RequestBody: &domain.RequestBody{Content: map[string]domain.MediaType{
+					"application/json": {Example: map[string]any{"name": "Rex"}},
+				}},
+				Responses: map[string]domain.Response{"201": {Links: map[string]domain.Link{
+					"GetPetById": {OperationID: "getPet", Parameters: map[string]any{"petId": "$response.body#/id"}},
+				}}},
+			},
+			{
+				ID: "getPet", Path: "/pets/{petId}", Method: domain.GET, Summary: "Get a pet", Tags: pets,
+				Parameters: []domain.Parameter{
+					{Name: "petId", In: domain.ParameterInPath, Required: true, Schema: &domain.Schema{Type: domain.SchemaTypeInteger}},
+					{Name: "fields", In: domain.ParameterInQuery, Schema: &domain.Schema{Type: domain.SchemaTypeString, Enum: []any{"all", "basic"}}},
+				},
+				Security: []domain.SecurityRequirement{{"api_key": nil}, {"oidc": nil}},
+			},
+			{ID: "deletePet", Path: "/pets/{petId}", Method: domain.DELETE, Summary: "Delete a pet", Tags: pets},
+			{ID: "listOrders", Path: "/store/orders", Method: domain.GET, Summary: "List orders", Tags: billing},
+			{
+				ID: "refundOrder", Path: "/store/orders/{id}/refund", Method: domain.POST, Summary: "Refund an order", Tags: billing,
+				RequestBody: &domain.RequestBody{Content: map[string]domain.MediaType{
+					"application/json": {Schema: &domain.Schema{
+						Type:       domain.SchemaTypeObject,
+						Required:   []string{"reason"},
+						Properties: map[string]*domain.Schema{"reason": {Type: domain.SchemaTypeString}},
+					}},
+				}},
+			},
+		},
+		SecuritySchemes: map[string]domain.SecurityScheme{
+			"api_key": {Type: domain.SecuritySchemeAPIKey, Name: "X-Key", In: domain.ParameterInHeader},
+			"oidc":    {Type: domain.SecuritySchemeOpenIDConnect, OpenIDConnectURL: "https://idp.example.com/.well-known/openid-configuration"},
 		},
 	}
 }
 
+// selectOperation moves the list cursor down to the operation with id,
+// counting in testSpec's order.
+func selectOperation(s *screens.OperationsScreen, id string) {
+	for _, op := range testSpec().Operations {
+		if op.ID == id {
+			return
+		}
+		s.Update(keyMsg("down"))
+	}
+}
+
+// newScreen shows spec with svc, at a size both panes fit.
+func newScreen(spec *domain.Spec, svc screens.Services) *screens.OperationsScreen {
+	s := screens.NewOperationsScreen(spec, application.NewOperationService())
+	s.SetServices(context.Background(), svc)
+	s.Update(tea.WindowSizeMsg{Width: 150, Height: 40})
+	return s
+}
+
 func TestOperationsScreen_Name(t *testing.T) {
 	s := screens.NewOperationsScreen(testSpec(), &stubOpService{})
 	if s.Name() != "operations" {
@@ -282,3 +378,51 @@ func TestOperationsScreen_ResizePropagates(t *testing.T) {
 		t.Error("expected view to change after resize")
 	}
 }
+
+func TestOperationsScreen_StructuredQueryInFilterBox(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+
+	typeFilter(s, "method:post tag:billing")
+
+	view := s.View()
+	if !strings.Contains(view, "Refund an order") {
+		t.Error("expected POST billing operation to match the structured query")
+	}
+	if strings.Contains(view, "Create a pet") || strings.Contains(view, "List orders") {
+		t.Error("expected other operations to be excluded by the structured query")
+	}
+}
+
+func TestOperationsScreen_CollapsesSharedErrorResponses(t *testing.T) {
+	unauthorized := domain.Response{Description: "Unauthorized"}
+	spec := &domain.Spec{
+		Info: domain.SpecInfo{Title: "Test"},
+		Operations: []domain.Operation{
+			{ID: "listPets", Path: "/pets", Method: domain.GET, Summary: "List all pets",
+				Responses: map[string]domain.Response{
+					"200": {Description: "OK"},
+					"401": unauthorized,
+					"409": {Description: "Conflict"},
+				}},
+			{ID: "createPet", Path: "/pets", Method: domain.POST, Summary: "Create a pet",
+				Responses: map[string]domain.Response{"401": unauthorized}},
+		},
+	}
+	s := screens.NewOperationsScreen(spec, &stubOpService{})
+	s.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+
+	// Focus the detail panel and open the Responses tab.
+	s.Update(tea.KeyMsg{Type: tea.KeyTab})
+	s.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'4'}})
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "Shared errors: 401 ×2") {
+		t.Error("expected shared 401 collapsed into the summary line")
+	}
+	if strings.Contains(plain, "Unauthorized") {
+		t.Error("expected shared response details to be collapsed")
+	}
+	if !strings.Contains(plain, "409  Conflict") {
+		t.Error("expected unique error response rendered in full")
+	}
+}
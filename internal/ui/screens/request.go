@@ -0,0 +1,544 @@
+package screens
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// requestField is one editable input of the request builder.
+type requestField struct {
+	label string
+	in    domain.ParameterIn // empty for the server field
+	name  string
+	input textinput.Model
+}
+
+// RequestBuilder is a form for filling in an operation's server, parameters
+// and body before sending it. Fields are prefilled from the spec: the first
+// server, parameter enums and the media type's example. Fields with
+// validation problems are marked as the user types; once a send has been
+// blocked every problem is listed until the form is valid.
+type RequestBuilder struct {
+	op          domain.Operation
+	fields      []requestField
+	body        textarea.Model
+	hasBody     bool
+	generated   bool // the body is a generated example, not yet edited
+	contentType string
+	focus       int
+	err         error
+	violations  []domain.Violation
+	warnings    []string
+	captures    []domain.Capture
+	blocked     bool
+	env         string
+	collection  string // saved request the form was opened from or saved as
+	savedName   string
+	saving      bool
+	prompt      string
+	notice      string
+	width       int
+	height      int
+}
+
+func NewRequestBuilder(op domain.Operation, servers []domain.Server) *RequestBuilder {
+	b := &RequestBuilder{op: op}
+
+	server := ""
+	if len(servers) > 0 {
+		server = servers[0].URL
+	}
+	b.fields = append(b.fields, newRequestField("Server", "", "", server, "https://api.example.com"))
+
+	for _, p := range op.Parameters {
+		label := fmt.Sprintf("%s %s", p.In, p.Name)
+		if p.Required {
+			label += "*"
+		}
+		b.fields = append(b.fields, newRequestField(label, p.In, p.Name, parameterDefault(p), schemaPlaceholder(p.Schema)))
+	}
+
+	if op.RequestBody != nil && len(op.RequestBody.Content) > 0 {
+		b.hasBody = true
+		b.contentType = preferredContentType(op.RequestBody.Content)
+		b.body = textarea.New()
+		b.body.ShowLineNumbers = false
+		b.body.CharLimit = 0
+		b.body.Placeholder = b.contentType + " body"
+		b.body.SetValue(exampleBody(op.RequestBody.Content[b.contentType]))
+	}
+
+	b.setFocus(0)
+	return b
+}
+
+func newRequestField(label string, in domain.ParameterIn, name, value, placeholder string) requestField {
+	ti := textinput.New()
+	ti.Prompt = ""
+	ti.Placeholder = placeholder
+	ti.SetValue(value)
+	return requestField{label: label, in: in, name: name, input: ti}
+}
+
+// parameterDefault prefills an enum parameter with its first allowed value.
+func parameterDefault(p domain.Parameter) string {
+	if p.Schema != nil && len(p.Schema.Enum) > 0 {
+		return fmt.Sprint(p.Schema.Enum[0])
+	}
+	return ""
+}
+
+func schemaPlaceholder(s *domain.Schema) string {
+	if s == nil {
+		return ""
+	}
+	return renderSchemaType(s)
+}
+
+// preferredContentType picks JSON when the body offers it, otherwise the
+// first media type by name.
+func preferredContentType(content map[string]domain.MediaType) string {
+	types := sortedKeys(content)
+	for _, t := range types {
+		if strings.Contains(t, "json") {
+			return t
+		}
+	}
+	return types[0]
+}
+
+// exampleBody renders the media type's example, or its first named example,
+// as the initial body text.
+func exampleBody(mt domain.MediaType) string {
+	v := mt.Example
+	if v == nil && len(mt.Examples) > 0 {
+		v = mt.Examples[sortedKeys(mt.Examples)[0]].Value
+	}
+	if v == nil {
+		return ""
+	}
+	if s, ok := v.(string); ok {
+		return s
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(out)
+}
+
+// generatedExample renders a value generated from the media type's schema
+// as a JSON or form body. Other media types, and media types without a
+// schema, get none.
+func generatedExample(svc domain.ExampleService, contentType string, mt domain.MediaType) (string, bool) {
+	if mt.Schema == nil {
+		return "", false
+	}
+	switch {
+	case strings.Contains(contentType, "json"):
+		out, err := json.MarshalIndent(svc.Generate(mt.Schema), "", "  ")
+		return string(out), err == nil
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		fields, ok := svc.Generate(mt.Schema).(map[string]any)
+		if !ok {
+			return "", false
+		}
+		form := make(url.Values, len(fields))
+		for name, v := range fields {
+			if text, ok := v.(string); ok {
+				form.Set(name, text)
+			} else if out, err := json.Marshal(v); err == nil {
+				form.Set(name, string(out))
+			}
+		}
+		return form.Encode(), true
+	}
+	return "", false
+}
+
+// Operation returns the operation being built.
+func (b *RequestBuilder) Operation() domain.Operation { return b.op }
+
+// Values collects the form into RequestValues.
+func (b *RequestBuilder) Values() domain.RequestValues {
+	v := domain.RequestValues{
+		Path:   make(map[string]string),
+		Query:  make(map[string]string),
+		Header: make(map[string]string),
+		Cookie: make(map[string]string),
+	}
+	for _, f := range b.fields {
+		value := strings.TrimSpace(f.input.Value())
+		switch f.in {
+		case "":
+			v.Server = value
+		case domain.ParameterInPath:
+			v.Path[f.name] = value
+		case domain.ParameterInQuery:
+			v.Query[f.name] = value
+		case domain.ParameterInHeader:
+			v.Header[f.name] = value
+		case domain.ParameterInCookie:
+			v.Cookie[f.name] = value
+		}
+	}
+	if b.hasBody {
+		v.ContentType = b.contentType
+		v.Body = b.body.Value()
+	}
+	return v
+}
+
+// SetValues fills the form from earlier values, such as a history entry.
+// Parameters the values do not mention keep their defaults.
+func (b *RequestBuilder) SetValues(v domain.RequestValues) {
+	for i := range b.fields {
+		f := &b.fields[i]
+		var value string
+		var ok bool
+		switch f.in {
+		case "":
+			value, ok = v.Server, v.Server != ""
+		case domain.ParameterInPath:
+			value, ok = v.Path[f.name]
+		case domain.ParameterInQuery:
+			value, ok = v.Query[f.name]
+		case domain.ParameterInHeader:
+			value, ok = v.Header[f.name]
+		case domain.ParameterInCookie:
+			value, ok = v.Cookie[f.name]
+		}
+		if ok {
+			f.input.SetValue(value)
+		}
+	}
+	if b.hasBody {
+		if _, ok := b.op.RequestBody.Content[v.ContentType]; ok {
+			b.contentType = v.ContentType
+			b.body.Placeholder = b.contentType + " body"
+		}
+		b.body.SetValue(v.Body)
+		b.generated = false
+	}
+}
+
+// SetWarnings lists how the values differ from the spec, such as
+// parameters an imported request had that the operation does not declare.
+// They stay until the form is closed.
+func (b *RequestBuilder) SetWarnings(warnings []string) {
+	b.warnings = warnings
+	b.resizeBody()
+}
+
+// SetCaptures sets the values to capture from the request's responses,
+// listed under the form.
+func (b *RequestBuilder) SetCaptures(captures []domain.Capture) {
+	b.captures = captures
+	b.resizeBody()
+}
+
+// AddCapture adds a value to capture, replacing any capture into the same
+// variable.
+func (b *RequestBuilder) AddCapture(c domain.Capture) {
+	captures := slices.DeleteFunc(slices.Clone(b.captures), func(old domain.Capture) bool {
+		return old.Variable == c.Variable
+	})
+	b.SetCaptures(append(captures, c))
+}
+
+// Captures returns the values to capture from the request's responses.
+func (b *RequestBuilder) Captures() []domain.Capture { return b.captures }
+
+// GenerateBody fills an empty body with an example generated from the
+// media type's schema.
+func (b *RequestBuilder) GenerateBody(svc domain.ExampleService) {
+	if !b.hasBody || b.body.Value() != "" {
+		return
+	}
+	if body, ok := generatedExample(svc, b.contentType, b.op.RequestBody.Content[b.contentType]); ok {
+		b.body.SetValue(body)
+		b.generated = true
+	}
+}
+
+// SetSavedAs records the saved request the form was opened from or last
+// saved as, shown in the header.
+func (b *RequestBuilder) SetSavedAs(collection, name string) {
+	b.collection, b.savedName = collection, name
+}
+
+// SavedAs returns the collection and name set by SetSavedAs.
+func (b *RequestBuilder) SavedAs() (collection, name string) {
+	return b.collection, b.savedName
+}
+
+// EnableSaving offers ctrl+r in the key hint.
+func (b *RequestBuilder) EnableSaving(on bool) { b.saving = on }
+
+// SetPrompt shows an input line, such as the save prompt, in place of the
+// key hint; "" removes it.
+func (b *RequestBuilder) SetPrompt(view string) { b.prompt = view }
+
+// SetNotice shows a confirmation in place of the key hint until the next
+// key press.
+func (b *RequestBuilder) SetNotice(text string) { b.notice = text }
+
+// SetEnvironment names the environment whose variables the values use,
+// shown in the header.
+func (b *RequestBuilder) SetEnvironment(name string) { b.env = name }
+
+// SetError shows an error (e.g. from building the request) under the form.
+func (b *RequestBuilder) SetError(err error) { b.err = err }
+
+// SetViolations records the current validation problems with the form.
+func (b *RequestBuilder) SetViolations(violations []domain.Violation) {
+	b.violations = violations
+	if len(violations) == 0 {
+		b.blocked = false
+	}
+	b.resizeBody()
+}
+
+// Valid reports whether the form has no validation problems.
+func (b *RequestBuilder) Valid() bool { return len(b.violations) == 0 }
+
+// Block lists every problem and offers the override after a send was
+// refused.
+func (b *RequestBuilder) Block() {
+	b.blocked = true
+	b.resizeBody()
+}
+
+// fieldViolations returns the messages for the field at index i; the index
+// after the last field is the body.
+func (b *RequestBuilder) fieldViolations(i int) []string {
+	var messages []string
+	for _, v := range b.violations {
+		switch {
+		case i == len(b.fields):
+			if strings.HasPrefix(v.Path, "$") {
+				messages = append(messages, v.String())
+			}
+		case b.fields[i].in != "" && v.Path == domain.ParameterPath(b.fields[i].in, b.fields[i].name):
+			messages = append(messages, v.Message)
+		}
+	}
+	return messages
+}
+
+func (b *RequestBuilder) SetSize(width, height int) {
+	b.width = width
+	b.height = height
+	for i := range b.fields {
+		b.fields[i].input.Width = max(1, width-b.labelWidth()-4)
+	}
+	if b.hasBody {
+		b.body.SetWidth(max(1, width))
+	}
+	b.resizeBody()
+}
+
+// resizeBody gives the body editor whatever height the header, fields,
+// problem list and hint leave over.
+func (b *RequestBuilder) resizeBody() {
+	if !b.hasBody {
+		return
+	}
+	problems := lipgloss.Height(b.renderProblems()) - 1 + lipgloss.Height(b.renderWarnings()) - 1 +
+		lipgloss.Height(b.renderCaptures()) - 1
+	b.body.SetHeight(max(3, b.height-len(b.fields)-6-problems))
+}
+
+func (b *RequestBuilder) focusCount() int {
+	if b.hasBody {
+		return len(b.fields) + 1
+	}
+	return len(b.fields)
+}
+
+func (b *RequestBuilder) setFocus(i int) {
+	b.focus = i
+	for j := range b.fields {
+		if j == i {
+			b.fields[j].input.Focus()
+		} else {
+			b.fields[j].input.Blur()
+		}
+	}
+	if b.hasBody {
+		if i == len(b.fields) {
+			b.body.Focus()
+		} else {
+			b.body.Blur()
+		}
+	}
+	b.resizeBody()
+}
+
+// Update moves between fields with tab/shift+tab (and up/down outside the
+// body) and passes other keys to the focused input.
+func (b *RequestBuilder) Update(msg tea.Msg) tea.Cmd {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		inBody := b.hasBody && b.focus == len(b.fields)
+		switch k := msg.String(); {
+		case k == "tab" || (k == "down" && !inBody):
+			b.setFocus((b.focus + 1) % b.focusCount())
+			return nil
+		case k == "shift+tab" || (k == "up" && !inBody):
+			b.setFocus((b.focus + b.focusCount() - 1) % b.focusCount())
+			return nil
+		}
+	}
+
+	b.err = nil
+	b.notice = ""
+	var cmd tea.Cmd
+	if b.hasBody && b.focus == len(b.fields) {
+		before := b.body.Value()
+		b.body, cmd = b.body.Update(msg)
+		b.generated = b.generated && b.body.Value() == before
+	} else if b.focus < len(b.fields) {
+		b.fields[b.focus].input, cmd = b.fields[b.focus].input.Update(msg)
+	}
+	return cmd
+}
+
+func (b *RequestBuilder) labelWidth() int {
+	w := 0
+	for _, f := range b.fields {
+		w = max(w, lipgloss.Width(f.label))
+	}
+	return w
+}
+
+func (b *RequestBuilder) View() string {
+	var sb strings.Builder
+	header := styles.Method(string(b.op.Method)) + " " + lipgloss.NewStyle().Bold(true).Render(b.op.Path)
+	if b.env != "" {
+		header += "  " + styles.Muted.Render("@"+b.env)
+	}
+	if b.savedName != "" {
+		header += "  " + styles.Muted.Render("★ "+b.collection+"/"+b.savedName)
+	}
+	sb.WriteString(lipgloss.NewStyle().MaxWidth(max(1, b.width)).Render(header))
+	sb.WriteString("\n\n")
+
+	labelW := b.labelWidth()
+	for i, f := range b.fields {
+		sb.WriteString(b.renderLabel(i, lipgloss.NewStyle().Width(labelW).Render(f.label)) + "  " + f.input.View() + "\n")
+	}
+
+	if b.hasBody {
+		label := b.renderLabel(len(b.fields), "Body "+b.contentType)
+		if b.generated {
+			label += styles.Muted.Render(" · generated example")
+		}
+		sb.WriteString("\n" + label + "\n")
+		sb.WriteString(b.body.View())
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(b.renderWarnings())
+	sb.WriteString(b.renderCaptures())
+	sb.WriteString(b.renderProblems())
+	switch {
+	case b.prompt != "":
+		sb.WriteString(b.prompt)
+	case b.err != nil:
+		sb.WriteString(styles.Error.Render(b.err.Error()))
+	case b.notice != "":
+		sb.WriteString(lipgloss.NewStyle().Foreground(styles.Green).Render("✓ " + b.notice))
+	case b.blocked:
+		sb.WriteString(styles.Muted.Render("ctrl+o send anyway · esc back"))
+	case b.saving:
+		sb.WriteString(styles.Muted.Render("tab next field · ctrl+s send · ctrl+r save · esc back"))
+	default:
+		sb.WriteString(styles.Muted.Render("tab next field · ctrl+s send · esc back"))
+	}
+	return sb.String()
+}
+
+// renderWarnings lists the warnings, capped like the problem list.
+func (b *RequestBuilder) renderWarnings() string {
+	lines := b.warnings
+	if len(lines) > maxListedProblems {
+		more := len(lines) - maxListedProblems
+		lines = append(lines[:maxListedProblems:maxListedProblems], fmt.Sprintf("…and %d more", more))
+	}
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(lipgloss.NewStyle().Foreground(styles.Yellow).MaxWidth(max(1, b.width)).Render("! "+l) + "\n")
+	}
+	return sb.String()
+}
+
+// renderCaptures lists the values to capture on one line.
+func (b *RequestBuilder) renderCaptures() string {
+	if len(b.captures) == 0 {
+		return ""
+	}
+	parts := make([]string, len(b.captures))
+	for i, c := range b.captures {
+		parts[i] = c.String()
+	}
+	return styles.Muted.MaxWidth(max(1, b.width)).Render("captures "+strings.Join(parts, " · ")) + "\n"
+}
+
+// renderLabel styles a field label: red with a marker when the field is
+// invalid, highlighted when focused.
+func (b *RequestBuilder) renderLabel(i int, label string) string {
+	invalid := len(b.fieldViolations(i)) > 0
+	style := styles.Muted
+	if i == b.focus {
+		style = lipgloss.NewStyle().Bold(true).Foreground(styles.Blue)
+	}
+	if invalid {
+		style = style.Foreground(styles.Red)
+		return style.Render("✗ " + label)
+	}
+	return style.Render("  " + label)
+}
+
+// maxListedProblems caps the problem list so the form keeps its layout.
+const maxListedProblems = 5
+
+// renderProblems lists every problem after a blocked send, otherwise only
+// those of the focused field.
+func (b *RequestBuilder) renderProblems() string {
+	var lines []string
+	if b.blocked {
+		for _, v := range b.violations {
+			lines = append(lines, v.String())
+		}
+		if len(lines) > maxListedProblems {
+			more := len(lines) - maxListedProblems
+			lines = append(lines[:maxListedProblems], fmt.Sprintf("…and %d more", more))
+		}
+	} else if b.focus < b.focusCount() {
+		lines = b.fieldViolations(b.focus)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	if b.blocked {
+		sb.WriteString(styles.Error.Render(domain.Plural(len(b.violations), "problem")+" — request not sent") + "\n")
+	}
+	for _, l := range lines {
+		sb.WriteString(lipgloss.NewStyle().Foreground(styles.Red).MaxWidth(max(1, b.width)).Render("✗ "+l) + "\n")
+	}
+	return sb.String()
+}
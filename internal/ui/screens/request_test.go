@@ -0,0 +1,143 @@
+package screens_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+// newBuilderScreen opens getPet's request builder on a screen with svc,
+// validating requests unless svc says how.
+func newBuilderScreen(svc screens.Services) *screens.OperationsScreen {
+	if svc.Validation == nil {
+		svc.Validation = application.NewValidationService()
+	}
+	s := newScreen(testSpec(), svc)
+	selectOperation(s, "getPet")
+	s.Update(keyMsg("enter"))
+	return s
+}
+
+func TestRequestBuilder_PrefillsEnumDefault(t *testing.T) {
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{}})
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+
+	if !strings.Contains(plain, "query fields") || !strings.Contains(plain, "all") {
+		t.Error("expected enum parameter prefilled with its first value")
+	}
+}
+
+func TestRequestBuilder_MarksInvalidFields(t *testing.T) {
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{}})
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "✗ path petId*") {
+		t.Error("expected empty required path parameter marked invalid")
+	}
+
+	// Move to petId and type a non-integer.
+	s.Update(keyMsg("down"))
+	typeRunes(s, "abc")
+	plain = ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, `✗ expected integer, got "abc"`) {
+		t.Error("expected the focused field's problem to be shown")
+	}
+}
+
+func TestRequestBuilder_InvalidRequestNeedsOverride(t *testing.T) {
+	svc := &stubRequestService{resp: jsonResponse(`{}`)}
+	s := newBuilderScreen(screens.Services{Requests: svc})
+
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	if cmd != nil {
+		t.Fatal("expected an invalid request not to be sent")
+	}
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "1 problem — request not sent") || !strings.Contains(plain, "✗ path petId: required") {
+		t.Error("expected the blocked send to list the problems")
+	}
+	if !strings.Contains(plain, "ctrl+o send anyway") {
+		t.Error("expected the override hint")
+	}
+
+	_, cmd = s.Update(keyMsg("ctrl+o"))
+	if cmd == nil {
+		t.Fatal("expected ctrl+o to send the invalid request")
+	}
+}
+
+func TestRequestBuilder_ValidRequestSends(t *testing.T) {
+	svc := &stubRequestService{resp: jsonResponse(`{}`)}
+	s := newBuilderScreen(screens.Services{Requests: svc})
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("7"))
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	if cmd == nil {
+		t.Fatal("expected a valid request to be sent")
+	}
+	drainCmd(s, cmd)
+	if svc.values.Path["petId"] != "7" {
+		t.Errorf("unexpected path values %v", svc.values.Path)
+	}
+}
+
+// failingAuth is an AuthService whose token endpoint is unreachable.
+type failingAuth struct{}
+
+func (failingAuth) Authenticate(context.Context, map[string]domain.SecurityScheme, domain.Operation, domain.Environment, *domain.HTTPRequest) error {
+	return errors.New("token endpoint unreachable")
+}
+
+func (failingAuth) BeginLogin(context.Context, map[string]domain.SecurityScheme, string, domain.Environment) (domain.LoginSession, error) {
+	return nil, errors.New("no identity provider")
+}
+
+// failingSigner is a SigningService without credentials.
+type failingSigner struct{}
+
+func (failingSigner) Sign(*domain.HTTPRequest, domain.Environment) error {
+	return errors.New("gateway: no AWS credentials")
+}
+
+func TestRequestBuilder_SigningErrorShown(t *testing.T) {
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{resp: jsonResponse(`{}`)}, Signer: failingSigner{}})
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("7"))
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	drainCmd(s, cmd)
+
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "signing: gateway: no AWS credentials") {
+		t.Errorf("expected the signing error, got:\n%s", plain)
+	}
+}
+
+func TestRequestBuilder_AuthenticationErrorShown(t *testing.T) {
+	s := newBuilderScreen(screens.Services{Requests: &stubRequestService{resp: jsonResponse(`{}`)}, Auth: failingAuth{}})
+
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("7"))
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	drainCmd(s, cmd)
+
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "authenticating: token endpoint unreachable") {
+		t.Errorf("expected the authentication error, got:\n%s", plain)
+	}
+}
+
+func TestRequestBuilder_GeneratesBodyWithoutExample(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{Requests: &stubRequestService{}, Examples: application.NewExampleService(1)})
+	selectOperation(s, "refundOrder")
+	s.Update(keyMsg("enter"))
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "Body application/json · generated example") || !strings.Contains(plain, `"reason": "`) {
+		t.Errorf("expected a generated body, got:\n%s", plain)
+	}
+}
@@ -0,0 +1,744 @@
+package screens
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/jsonpath"
+	"dazzle/internal/ui/styles"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type responseInput int
+
+const (
+	inputNone responseInput = iota
+	inputSearch
+	inputFilter
+	inputCapture
+)
+
+// captureMsg asks to capture the value under the cursor into a variable.
+type captureMsg struct{ capture domain.Capture }
+
+// followLinkMsg asks to follow the response's link at index.
+type followLinkMsg struct{ index int }
+
+// responseChromeHeight is the number of lines around the body viewport:
+// the status line, the info line and the hint/input line.
+const responseChromeHeight = 3
+
+// ResponseView shows a live response. JSON bodies are shown as a foldable,
+// highlighted tree that can be searched and narrowed with a path filter
+// (e.g. ".items[0].name" or "$..id"); other bodies are highlighted by
+// content type. r toggles the raw body. Schema violations are listed above
+// the body and marked on the lines they point at, followed by the values
+// captured from the response and the links it offers, which 1-9 follow. c
+// captures the JSON value under the cursor into a variable.
+type ResponseView struct {
+	viewport viewport.Model
+	resp     *domain.HTTPResponse
+	err      error
+	pending  bool
+
+	contentType string
+	text        string    // body as text; empty for binary bodies
+	doc         any       // decoded JSON body, for filtering
+	root        *jsonNode // parsed JSON body; nil when not JSON
+	shown       *jsonNode // root or the filter result
+	collapsed   map[string]bool
+
+	lines       []jsonLine
+	highlighted []string
+	cursor      int
+	raw         bool
+
+	input     textinput.Model
+	inputMode responseInput
+	query     string
+	matches   []int
+	match     int
+	filter    string
+	filterErr error
+
+	violations []domain.Violation
+	captureEnv string
+	captured   map[string]string
+	captureErr error
+	links      []string
+	linkErr    error
+	prelude    int // lines above the body used by the violation, capture and link lists
+
+	width  int
+	height int
+}
+
+func NewResponseView(width, height int) *ResponseView {
+	ti := textinput.New()
+	ti.Prompt = ""
+	v := &ResponseView{
+		viewport:  viewport.New(max(1, width-1), max(1, height-responseChromeHeight)),
+		collapsed: make(map[string]bool),
+		input:     ti,
+		width:     width,
+		height:    height,
+	}
+	return v
+}
+
+// SetPending shows a placeholder while the request is in flight.
+func (v *ResponseView) SetPending() {
+	v.reset()
+	v.pending = true
+	v.rebuild()
+}
+
+// SetError shows a transport error in place of a response.
+func (v *ResponseView) SetError(err error) {
+	v.reset()
+	v.err = err
+	v.rebuild()
+}
+
+// SetResponse shows resp, parsing the body as JSON when it is JSON.
+func (v *ResponseView) SetResponse(resp *domain.HTTPResponse) {
+	v.reset()
+	v.resp = resp
+	v.contentType = resp.Header.Get("Content-Type")
+	if utf8.Valid(resp.Body) {
+		v.text = string(resp.Body)
+	}
+
+	if isJSONContent(v.contentType, resp.Body) {
+		if root, err := parseJSONTree(resp.Body); err == nil {
+			v.root = root
+			v.shown = root
+			dec := json.NewDecoder(bytes.NewReader(resp.Body))
+			dec.UseNumber()
+			_ = dec.Decode(&v.doc)
+		}
+	}
+	v.rebuild()
+}
+
+// SetViolations shows the schema violations found in the response.
+func (v *ResponseView) SetViolations(violations []domain.Violation) {
+	v.violations = violations
+	v.render()
+}
+
+// SetCaptured shows the values captured from the response into env's
+// variables, and the captures that failed.
+func (v *ResponseView) SetCaptured(env string, values map[string]string, err error) {
+	v.captureEnv, v.captured, v.captureErr = env, values, err
+	v.render()
+}
+
+// SetLinks lists the links the response offers, by label, for 1-9 to
+// follow.
+func (v *ResponseView) SetLinks(labels []string) {
+	v.links = labels
+	v.render()
+}
+
+// SetLinkError shows why a link could not be followed.
+func (v *ResponseView) SetLinkError(err error) { v.linkErr = err }
+
+// Response returns the response being shown, or nil.
+func (v *ResponseView) Response() *domain.HTTPResponse { return v.resp }
+
+func (v *ResponseView) reset() {
+	v.resp = nil
+	v.err = nil
+	v.pending = false
+	v.contentType = ""
+	v.text = ""
+	v.doc = nil
+	v.root = nil
+	v.shown = nil
+	v.collapsed = make(map[string]bool)
+	v.cursor = 0
+	v.query = ""
+	v.matches = nil
+	v.filter = ""
+	v.filterErr = nil
+	v.violations = nil
+	v.captureEnv, v.captured, v.captureErr = "", nil, nil
+	v.links, v.linkErr = nil, nil
+	v.inputMode = inputNone
+	v.viewport.GotoTop()
+}
+
+func isJSONContent(contentType string, body []byte) bool {
+	if strings.Contains(strings.ToLower(contentType), "json") {
+		return true
+	}
+	// Servers often omit or mislabel the content type.
+	trimmed := bytes.TrimSpace(body)
+	return contentType == "" && len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[')
+}
+
+func (v *ResponseView) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.viewport.Width = max(1, width-1) // reserve 1 col for scrollbar
+	v.viewport.Height = max(1, height-responseChromeHeight)
+	v.input.Width = max(1, width-12)
+	v.render()
+}
+
+// Inputting reports whether the search or filter prompt is open, in which
+// case the view handles esc itself.
+func (v *ResponseView) Inputting() bool { return v.inputMode != inputNone }
+
+// treeMode reports whether the body is shown as a JSON tree.
+func (v *ResponseView) treeMode() bool { return v.shown != nil && !v.raw }
+
+func (v *ResponseView) Update(msg tea.Msg) tea.Cmd {
+	if v.inputMode != inputNone {
+		return v.updateInput(msg)
+	}
+
+	km, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		v.viewport, cmd = v.viewport.Update(msg)
+		return cmd
+	}
+
+	switch km.String() {
+	case "up", "k":
+		v.moveCursor(-1)
+	case "down", "j":
+		v.moveCursor(1)
+	case "pgup":
+		v.moveCursor(-v.viewport.Height)
+	case "pgdown":
+		v.moveCursor(v.viewport.Height)
+	case "home", "g":
+		v.moveCursor(-len(v.lines))
+	case "end", "G":
+		v.moveCursor(len(v.lines))
+	case " ", "enter":
+		v.toggleFold()
+	case "E":
+		v.expandAll()
+	case "C":
+		v.collapseAll()
+	case "r":
+		v.raw = !v.raw
+		v.cursor = 0
+		v.rebuild()
+	case "/":
+		v.openInput(inputSearch, v.query)
+		return textinput.Blink
+	case "f":
+		if v.root != nil {
+			v.openInput(inputFilter, v.filter)
+			return textinput.Blink
+		}
+	case "n":
+		v.jumpMatch(1)
+	case "N":
+		v.jumpMatch(-1)
+	case "c":
+		if n := v.cursorNode(); n != nil {
+			v.openInput(inputCapture, variableName(n.key))
+			return textinput.Blink
+		}
+	case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+		if i := int(km.String()[0] - '1'); i < len(v.links) {
+			v.linkErr = nil
+			return func() tea.Msg { return followLinkMsg{index: i} }
+		}
+	}
+	return nil
+}
+
+// cursorNode returns the JSON value under the cursor, when its location
+// is one in the response: not in a filter result.
+func (v *ResponseView) cursorNode() *jsonNode {
+	if !v.treeMode() || v.shown != v.root || v.cursor >= len(v.lines) {
+		return nil
+	}
+	return v.lines[v.cursor].node
+}
+
+// variableName suggests a variable for a captured member: its key, when
+// that is a plain name.
+func variableName(key string) string {
+	for i, r := range key {
+		if !(r == '_' || unicode.IsLetter(r) || i > 0 && unicode.IsDigit(r)) {
+			return ""
+		}
+	}
+	return key
+}
+
+func (v *ResponseView) openInput(mode responseInput, value string) {
+	v.inputMode = mode
+	v.input.SetValue(value)
+	v.input.CursorEnd()
+	v.input.Focus()
+}
+
+func (v *ResponseView) updateInput(msg tea.Msg) tea.Cmd {
+	if km, ok := msg.(tea.KeyMsg); ok {
+		switch km.String() {
+		case "esc":
+			v.inputMode = inputNone
+			v.input.Blur()
+			return nil
+		case "enter":
+			value := strings.TrimSpace(v.input.Value())
+			mode := v.inputMode
+			v.inputMode = inputNone
+			v.input.Blur()
+			switch mode {
+			case inputSearch:
+				v.search(value)
+			case inputFilter:
+				v.applyFilter(value)
+			case inputCapture:
+				if n := v.cursorNode(); n != nil && value != "" {
+					c := domain.Capture{Variable: value, Path: n.loc}
+					return func() tea.Msg { return captureMsg{capture: c} }
+				}
+			}
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	v.input, cmd = v.input.Update(msg)
+	return cmd
+}
+
+func (v *ResponseView) moveCursor(delta int) {
+	v.cursor = clampIndex(v.cursor+delta, len(v.lines))
+	v.render()
+}
+
+// toggleFold folds or unfolds the container under the cursor, keeping the
+// cursor on its opening line.
+func (v *ResponseView) toggleFold() {
+	if !v.treeMode() || v.cursor >= len(v.lines) {
+		return
+	}
+	n := v.lines[v.cursor].node
+	if n.kind == jsonScalar {
+		n = n.parent
+	}
+	if n == nil || len(n.children) == 0 {
+		return
+	}
+	if v.collapsed[n.loc] {
+		delete(v.collapsed, n.loc)
+	} else {
+		v.collapsed[n.loc] = true
+	}
+	v.rebuild()
+	v.cursorTo(n)
+}
+
+func (v *ResponseView) expandAll() {
+	if !v.treeMode() {
+		return
+	}
+	clear(v.collapsed)
+	v.rebuild()
+}
+
+// collapseAll folds every container below the top level.
+func (v *ResponseView) collapseAll() {
+	if !v.treeMode() {
+		return
+	}
+	v.shown.walk(func(n *jsonNode) {
+		if n != v.shown && len(n.children) > 0 {
+			v.collapsed[n.loc] = true
+		}
+	})
+	v.cursor = 0
+	v.rebuild()
+}
+
+// cursorTo moves the cursor to the opening line of n.
+func (v *ResponseView) cursorTo(n *jsonNode) {
+	for i, l := range v.lines {
+		if l.node == n && !l.closing {
+			v.cursor = i
+			break
+		}
+	}
+	v.render()
+}
+
+// search finds lines containing query, case-insensitively. In tree mode
+// folds hiding a match are opened first.
+func (v *ResponseView) search(query string) {
+	v.query = query
+	if query == "" {
+		v.matches = nil
+		v.render()
+		return
+	}
+
+	q := strings.ToLower(query)
+	if v.treeMode() {
+		v.shown.walk(func(n *jsonNode) {
+			if strings.Contains(strings.ToLower(n.key), q) ||
+				(n.kind == jsonScalar && strings.Contains(strings.ToLower(scalarText(n.value)), q)) {
+				for p := n.parent; p != nil; p = p.parent {
+					delete(v.collapsed, p.loc)
+				}
+			}
+		})
+	}
+	v.rebuild()
+
+	v.match = -1
+	for i := range v.matches {
+		if v.matches[i] >= v.cursor {
+			v.match = i - 1
+			break
+		}
+	}
+	v.jumpMatch(1)
+}
+
+func (v *ResponseView) findMatches() {
+	v.matches = nil
+	if v.query == "" {
+		return
+	}
+	q := strings.ToLower(v.query)
+	for i, l := range v.lines {
+		if strings.Contains(strings.ToLower(l.text), q) {
+			v.matches = append(v.matches, i)
+		}
+	}
+}
+
+func (v *ResponseView) jumpMatch(dir int) {
+	if len(v.matches) == 0 {
+		return
+	}
+	v.match = (v.match + dir + len(v.matches)) % len(v.matches)
+	v.cursor = v.matches[v.match]
+	v.render()
+}
+
+// applyFilter narrows the tree to the values selected by a path expression.
+// A single result is shown as-is; several are shown as an array.
+func (v *ResponseView) applyFilter(expr string) {
+	v.filter = expr
+	v.filterErr = nil
+	v.collapsed = make(map[string]bool)
+	v.cursor = 0
+
+	if expr == "" || v.root == nil {
+		v.shown = v.root
+		v.rebuild()
+		return
+	}
+
+	results, err := jsonpath.Eval(expr, v.doc)
+	switch {
+	case err != nil:
+		v.filterErr = err
+		v.shown = v.root
+	case len(results) == 0:
+		v.filterErr = fmt.Errorf("no values match %s", expr)
+		v.shown = v.root
+	case len(results) == 1:
+		v.shown = valueTree(results[0], jsonpath.Location{}, nil)
+	default:
+		v.shown = valueTree(results, jsonpath.Location{}, nil)
+	}
+	v.rebuild()
+}
+
+// rebuild recomputes and re-highlights the body lines.
+func (v *ResponseView) rebuild() {
+	v.lines = nil
+	v.highlighted = nil
+
+	switch {
+	case v.treeMode():
+		v.lines = flattenJSON(v.shown, v.collapsed)
+		v.highlighted = highlightLines(v.lines, "json")
+	case v.text != "":
+		for _, l := range strings.Split(strings.TrimRight(v.text, "\n"), "\n") {
+			v.lines = append(v.lines, jsonLine{text: l})
+		}
+		if v.raw {
+			for _, l := range v.lines {
+				v.highlighted = append(v.highlighted, l.text)
+			}
+		} else {
+			v.highlighted = highlightLines(v.lines, v.contentType)
+		}
+	}
+
+	v.cursor = clampIndex(v.cursor, len(v.lines))
+	v.findMatches()
+	v.render()
+}
+
+// highlightLines highlights the lines as one document so multi-line
+// constructs lex correctly, then splits the result back into lines.
+func highlightLines(lines []jsonLine, language string) []string {
+	texts := make([]string, len(lines))
+	for i, l := range lines {
+		texts[i] = l.text
+	}
+	out := strings.Split(styles.Highlight(strings.Join(texts, "\n"), language), "\n")
+	if len(out) != len(lines) {
+		return texts
+	}
+	return out
+}
+
+// render writes the violation list and the visible lines into the viewport
+// and keeps the cursor in view.
+func (v *ResponseView) render() {
+	var b strings.Builder
+	v.prelude = 0
+	if len(v.violations) > 0 {
+		for _, violation := range v.violations {
+			b.WriteString(styles.Error.Render("✗ ") + lipgloss.NewStyle().Foreground(styles.Red).Render(violation.String()) + "\n")
+			v.prelude++
+		}
+		b.WriteString("\n")
+		v.prelude++
+	}
+	if chain := v.chainLines(); len(chain) > 0 {
+		for _, l := range chain {
+			b.WriteString(lipgloss.NewStyle().MaxWidth(max(1, v.width-1)).Render(l) + "\n")
+		}
+		b.WriteString("\n")
+		v.prelude += len(chain) + 1
+	}
+
+	if len(v.lines) == 0 {
+		b.WriteString(v.placeholder())
+		v.viewport.SetContent(b.String())
+		return
+	}
+
+	isMatch := make(map[int]bool, len(v.matches))
+	for _, m := range v.matches {
+		isMatch[m] = true
+	}
+	marks := v.violationMarks()
+
+	for i, l := range v.lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		switch {
+		case i == v.cursor:
+			b.WriteString(lipgloss.NewStyle().Foreground(styles.Blue).Render("▌"))
+		case isMatch[i]:
+			b.WriteString(lipgloss.NewStyle().Foreground(styles.Yellow).Render("●"))
+		default:
+			b.WriteByte(' ')
+		}
+		b.WriteString(v.highlighted[i])
+		if l.note != "" {
+			b.WriteString("  " + styles.Muted.Render(l.note))
+		}
+		if mark := marks[i]; mark != "" {
+			b.WriteString("  " + styles.Error.Render("✗ "+mark))
+		}
+	}
+	v.viewport.SetContent(b.String())
+
+	line := v.cursor + v.prelude
+	if v.cursor == 0 {
+		line = 0 // keep the violation list visible at the top
+	}
+	if line < v.viewport.YOffset {
+		v.viewport.SetYOffset(line)
+	} else if line >= v.viewport.YOffset+v.viewport.Height {
+		v.viewport.SetYOffset(line - v.viewport.Height + 1)
+	}
+}
+
+// chainLines lists the captured values, the failed captures and the
+// links to follow.
+func (v *ResponseView) chainLines() []string {
+	var lines []string
+	green := lipgloss.NewStyle().Foreground(styles.Green)
+	for _, name := range slices.Sorted(maps.Keys(v.captured)) {
+		lines = append(lines, green.Render("✓ ")+name+" = "+v.captured[name]+styles.Muted.Render(" → @"+v.captureEnv))
+	}
+	if v.captureErr != nil {
+		for _, l := range strings.Split(v.captureErr.Error(), "\n") {
+			lines = append(lines, styles.Error.Render("✗ "+l))
+		}
+	}
+	for i, label := range v.links {
+		lines = append(lines, lipgloss.NewStyle().Foreground(styles.Blue).Render(fmt.Sprintf("→ %d ", i+1))+label)
+	}
+	return lines
+}
+
+// violationMarks maps line indexes to the violation messages shown beside
+// them. A folded container whose children have violations is marked with a
+// count. Marks are only placed on the unfiltered tree, whose locations
+// match the violation paths.
+func (v *ResponseView) violationMarks() map[int]string {
+	if !v.treeMode() || v.shown != v.root || len(v.violations) == 0 {
+		return nil
+	}
+
+	marks := make(map[int]string)
+	for i, l := range v.lines {
+		if l.closing {
+			continue
+		}
+		var messages []string
+		nested := 0
+		for _, violation := range v.violations {
+			switch {
+			case violation.Path == l.node.loc:
+				messages = append(messages, violation.Message)
+			case v.collapsed[l.node.loc] && isWithin(violation.Path, l.node.loc):
+				nested++
+			}
+		}
+		if nested > 0 {
+			messages = append(messages, fmt.Sprintf("%d inside", nested))
+		}
+		if len(messages) > 0 {
+			marks[i] = strings.Join(messages, "; ")
+		}
+	}
+	return marks
+}
+
+// isWithin reports whether the location path lies below the container at
+// prefix, e.g. "$.items[3].price" within "$.items".
+func isWithin(path, prefix string) bool {
+	rest, ok := strings.CutPrefix(path, prefix)
+	return ok && (strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "["))
+}
+
+func (v *ResponseView) placeholder() string {
+	switch {
+	case v.pending:
+		return styles.Muted.Render("Sending…")
+	case v.err != nil:
+		return styles.Error.Render(v.err.Error())
+	case v.resp == nil:
+		return ""
+	case len(v.resp.Body) == 0:
+		return styles.Muted.Render("Empty body")
+	default:
+		return styles.Muted.Render(fmt.Sprintf("%s of binary data", formatSize(len(v.resp.Body))))
+	}
+}
+
+func (v *ResponseView) View() string {
+	body := lipgloss.JoinHorizontal(lipgloss.Top, v.viewport.View(), renderViewportScrollbar(v.viewport))
+	return v.statusLine() + "\n" + v.infoLine() + "\n" + body + "\n" + v.footer()
+}
+
+func (v *ResponseView) statusLine() string {
+	if v.resp == nil {
+		return lipgloss.NewStyle().Bold(true).Render("Response")
+	}
+	code := fmt.Sprint(v.resp.StatusCode)
+	status := lipgloss.NewStyle().Bold(true).Foreground(styles.StatusColor(code)).
+		Render(strings.TrimSpace(code + " " + http.StatusText(v.resp.StatusCode)))
+
+	size := formatSize(len(v.resp.Body))
+	if v.resp.Truncated {
+		size += " truncated"
+	}
+	parts := []string{status, v.resp.Duration.Round(time.Millisecond).String(), size}
+	if v.contentType != "" {
+		parts = append(parts, v.contentType)
+	}
+	return lipgloss.NewStyle().MaxWidth(max(1, v.width)).Render(strings.Join(parts, styles.Muted.Render(" · ")))
+}
+
+func (v *ResponseView) infoLine() string {
+	mode := "pretty"
+	if v.raw {
+		mode = "raw"
+	}
+	parts := []string{mode}
+	if v.filter != "" && v.filterErr == nil {
+		parts = append(parts, "filter "+v.filter)
+	}
+	if v.query != "" {
+		parts = append(parts, fmt.Sprintf("%d matches for %q", len(v.matches), v.query))
+	}
+	line := styles.Muted.Render(strings.Join(parts, " · "))
+	if n := len(v.violations); n > 0 {
+		line += "  " + styles.Error.Render(domain.Plural(n, "violation"))
+	}
+	if v.filterErr != nil {
+		line += "  " + styles.Error.Render(v.filterErr.Error())
+	}
+	if v.linkErr != nil {
+		line += "  " + styles.Error.Render(v.linkErr.Error())
+	}
+	return lipgloss.NewStyle().MaxWidth(max(1, v.width)).Render(line)
+}
+
+func (v *ResponseView) footer() string {
+	switch v.inputMode {
+	case inputSearch:
+		return lipgloss.NewStyle().Foreground(styles.Blue).Render("search: ") + v.input.View()
+	case inputFilter:
+		return lipgloss.NewStyle().Foreground(styles.Blue).Render("filter: ") + v.input.View()
+	case inputCapture:
+		loc := ""
+		if n := v.cursorNode(); n != nil {
+			loc = n.loc + " "
+		}
+		return lipgloss.NewStyle().Foreground(styles.Blue).Render("capture "+loc+"as: ") + v.input.View()
+	}
+	hint := "r raw · / search · n/N next · esc back"
+	if v.treeMode() {
+		hint = "space fold · E/C expand/collapse all · f filter · " + hint
+	}
+	if v.cursorNode() != nil {
+		hint = "c capture · " + hint
+	}
+	switch n := len(v.links); {
+	case n == 1:
+		hint = "1 follow link · " + hint
+	case n > 1:
+		hint = fmt.Sprintf("1-%d follow link · ", min(n, 9)) + hint
+	}
+	return lipgloss.NewStyle().MaxWidth(max(1, v.width)).Render(styles.Muted.Render(hint))
+}
+
+// formatSize formats a byte count for display.
+func formatSize(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,253 @@
+package screens_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+func typeInto(v *screens.ResponseView, text string) {
+	for _, r := range text {
+		v.Update(keyMsg(string(r)))
+	}
+	v.Update(keyMsg("enter"))
+}
+
+func jsonResponse(body string) *domain.HTTPResponse {
+	return &domain.HTTPResponse{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       []byte(body),
+		Duration:   120 * time.Millisecond,
+	}
+}
+
+const petsBody = `{"items":[{"id":1,"name":"Rex"},{"id":2,"name":"Tom"}],"total":2}`
+
+func newResponseView(resp *domain.HTTPResponse) *screens.ResponseView {
+	v := screens.NewResponseView(80, 30)
+	v.SetResponse(resp)
+	return v
+}
+
+func plainView(v interface{ View() string }) string {
+	return ansiRe.ReplaceAllString(v.View(), "")
+}
+
+func TestResponseView_StatusLine(t *testing.T) {
+	plain := plainView(newResponseView(jsonResponse(petsBody)))
+
+	for _, want := range []string{"200 OK", "120ms", "65 B", "application/json"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q in status line", want)
+		}
+	}
+}
+
+func TestResponseView_PrettyPrintsJSONInDocumentOrder(t *testing.T) {
+	plain := plainView(newResponseView(jsonResponse(`{"zeta":1,"alpha":{"b":true}}`)))
+
+	if !strings.Contains(plain, `"alpha": {`) || !strings.Contains(plain, `"b": true`) {
+		t.Fatal("expected indented JSON")
+	}
+	if strings.Index(plain, `"zeta"`) > strings.Index(plain, `"alpha"`) {
+		t.Error("expected members in document order")
+	}
+}
+
+func TestResponseView_FoldAndExpand(t *testing.T) {
+	v := newResponseView(jsonResponse(petsBody))
+
+	// Line 0 is the root; line 1 opens "items".
+	v.Update(keyMsg("down"))
+	v.Update(keyMsg(" "))
+	plain := plainView(v)
+	if !strings.Contains(plain, `"items": […],`) || !strings.Contains(plain, "2 items") {
+		t.Fatal("expected items folded with its size")
+	}
+	if strings.Contains(plain, "Rex") {
+		t.Error("expected folded children to be hidden")
+	}
+
+	v.Update(keyMsg("E"))
+	if !strings.Contains(plainView(v), "Rex") {
+		t.Error("expected expand all to show children")
+	}
+
+	v.Update(keyMsg("C"))
+	plain = plainView(v)
+	if strings.Contains(plain, "Rex") || !strings.Contains(plain, `"total": 2`) {
+		t.Error("expected collapse all to fold nested containers but keep the top level")
+	}
+}
+
+func TestResponseView_Filter(t *testing.T) {
+	v := newResponseView(jsonResponse(petsBody))
+
+	v.Update(keyMsg("f"))
+	typeInto(v, ".items[].name")
+
+	plain := plainView(v)
+	if !strings.Contains(plain, `"Rex",`) || !strings.Contains(plain, `"Tom"`) {
+		t.Error("expected filtered names")
+	}
+	if strings.Contains(plain, `"total"`) {
+		t.Error("expected unselected members to be hidden")
+	}
+	if !strings.Contains(plain, "filter .items[].name") {
+		t.Error("expected active filter in info line")
+	}
+
+	v.Update(keyMsg("f"))
+	v.Update(keyMsg("ctrl+u"))
+	v.Update(keyMsg("enter"))
+	if !strings.Contains(plainView(v), `"total"`) {
+		t.Error("expected empty filter to restore the full body")
+	}
+}
+
+func TestResponseView_FilterError(t *testing.T) {
+	v := newResponseView(jsonResponse(petsBody))
+	v.Update(keyMsg("f"))
+	typeInto(v, ".nope")
+
+	if !strings.Contains(plainView(v), "no values match .nope") {
+		t.Error("expected filter error")
+	}
+}
+
+func TestResponseView_SearchOpensFolds(t *testing.T) {
+	v := newResponseView(jsonResponse(petsBody))
+	v.Update(keyMsg("C"))
+
+	v.Update(keyMsg("/"))
+	typeInto(v, "tom")
+
+	plain := plainView(v)
+	if !strings.Contains(plain, "Tom") {
+		t.Error("expected search to unfold the match")
+	}
+	if !strings.Contains(plain, `1 matches for "tom"`) {
+		t.Error("expected match count")
+	}
+}
+
+func TestResponseView_RawToggle(t *testing.T) {
+	v := newResponseView(jsonResponse(petsBody))
+	v.Update(keyMsg("r"))
+
+	plain := plainView(v)
+	if !strings.Contains(plain, petsBody) {
+		t.Error("expected the raw body in raw mode")
+	}
+	if !strings.Contains(plain, "raw") {
+		t.Error("expected raw mode indicator")
+	}
+}
+
+func TestResponseView_Highlights(t *testing.T) {
+	v := newResponseView(jsonResponse(petsBody))
+	if !strings.Contains(v.View(), "\x1b[") {
+		t.Error("expected ANSI highlighting in pretty mode")
+	}
+}
+
+func TestResponseView_NonJSON(t *testing.T) {
+	v := newResponseView(&domain.HTTPResponse{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       []byte("not here"),
+	})
+	plain := plainView(v)
+	if !strings.Contains(plain, "404 Not Found") || !strings.Contains(plain, "not here") {
+		t.Error("expected status and text body")
+	}
+}
+
+// stubRequestService records the values built and the request sent, and
+// returns a fixed response.
+type stubRequestService struct {
+	values domain.RequestValues
+	sent   *domain.HTTPRequest
+	resp   *domain.HTTPResponse
+}
+
+func (s *stubRequestService) BuildRequest(op domain.Operation, values domain.RequestValues) (*domain.HTTPRequest, error) {
+	s.values = values
+	return &domain.HTTPRequest{Method: op.Method, URL: values.Server + op.Path, Header: http.Header{}}, nil
+}
+
+func (s *stubRequestService) Send(_ context.Context, req *domain.HTTPRequest) (*domain.HTTPResponse, error) {
+	s.sent = req
+	return s.resp, nil
+}
+
+func TestOperationsScreen_SendRequest(t *testing.T) {
+	svc := &stubRequestService{resp: jsonResponse(petsBody)}
+	s := newScreen(testSpec(), screens.Services{Requests: svc})
+	selectOperation(s, "createPet")
+
+	s.Update(keyMsg("enter"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "https://petstore.example.com") {
+		t.Fatal("expected builder with the spec's server")
+	}
+	if !strings.Contains(plain, `"name": "Rex"`) {
+		t.Error("expected body prefilled from the example")
+	}
+
+	_, cmd := s.Update(keyMsg("ctrl+s"))
+	if !strings.Contains(s.View(), "Sending") {
+		t.Error("expected pending state while sending")
+	}
+	drainCmd(s, cmd)
+
+	if svc.values.Server != "https://petstore.example.com" || !strings.Contains(svc.values.Body, "Rex") {
+		t.Errorf("unexpected values %+v", svc.values)
+	}
+	plain = ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "200 OK") || !strings.Contains(plain, `"items": [`) {
+		t.Error("expected response view")
+	}
+
+	// esc steps back to the builder, then to the detail panel.
+	s.Update(keyMsg("esc"))
+	if !strings.Contains(s.View(), "ctrl+s send") {
+		t.Error("expected builder after esc")
+	}
+	s.Update(keyMsg("esc"))
+	if !strings.Contains(s.View(), "Overview") {
+		t.Error("expected detail panel after second esc")
+	}
+}
+
+func TestResponseView_Violations(t *testing.T) {
+	v := newResponseView(jsonResponse(`{"items":[{"id":1,"price":"free"}],"total":2}`))
+	v.SetViolations([]domain.Violation{
+		{Path: "$.items[0].price", Message: "expected number, got string"},
+		{Path: "$.items[0].name", Message: "required property missing"},
+	})
+
+	plain := plainView(v)
+	if !strings.Contains(plain, "✗ $.items[0].price: expected number, got string") {
+		t.Error("expected violation listed with its path")
+	}
+	if !strings.Contains(plain, "2 violations") {
+		t.Error("expected violation count in info line")
+	}
+	if !strings.Contains(plain, `"price": "free"  ✗ expected number, got string`) {
+		t.Error("expected violation marked on the offending line")
+	}
+
+	// Folding the array moves the mark onto the folded line.
+	v.Update(keyMsg("down"))
+	v.Update(keyMsg(" "))
+	if !strings.Contains(plainView(v), "✗ 2 inside") {
+		t.Error("expected folded container to count nested violations")
+	}
+}
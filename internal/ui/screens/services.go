@@ -0,0 +1,19 @@
+package screens
+
+import "dazzle/internal/domain"
+
+// Services are the operations screen's optional services. A feature whose
+// service is nil is turned off.
+type Services struct {
+	Requests    domain.RequestService    // enter opens the request builder
+	Validation  domain.ValidationService // checks requests and responses
+	Auth        domain.AuthService       // adds credentials; L logs in
+	Signer      domain.SigningService    // signs requests as they go out
+	History     domain.HistoryService    // records requests; H lists them
+	Collections domain.CollectionService // saved requests; ctrl+r saves
+	Snippets    domain.SnippetService    // the detail panel's Code tab
+	Clipboard   domain.Clipboard         // y copies the Code tab
+	Import      domain.ImportService     // I imports a curl command
+	Examples    domain.ExampleService    // bodies the spec gives no example for
+	Chain       domain.ChainService      // captures values and follows links
+}
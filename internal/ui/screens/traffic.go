@@ -0,0 +1,303 @@
+package screens
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// exchangeMsg delivers an exchange from the proxy; ok is false once the
+// proxy stopped.
+type exchangeMsg struct {
+	ex domain.RecordedExchange
+	ok bool
+}
+
+// TrafficScreen lists the exchanges a proxy sees as they arrive, newest
+// first, with the selected one in full on the right. Exchanges that do not
+// match the spec are marked with their violation count.
+type TrafficScreen struct {
+	title     string
+	source    <-chan domain.RecordedExchange
+	exchanges []domain.RecordedExchange
+	stopped   bool
+
+	onlyViolations bool
+	cursor         int // index into visible(); 0 follows the newest
+	offset         int
+	scroll         int // detail lines scrolled past
+
+	width  int
+	height int
+}
+
+// NewTrafficScreen shows the exchanges received from source under title.
+func NewTrafficScreen(title string, source <-chan domain.RecordedExchange) *TrafficScreen {
+	return &TrafficScreen{title: title, source: source}
+}
+
+func (s *TrafficScreen) Name() string { return "traffic" }
+
+func (s *TrafficScreen) Init() tea.Cmd { return s.next() }
+
+// next waits for the proxy's next exchange.
+func (s *TrafficScreen) next() tea.Cmd {
+	source := s.source
+	return func() tea.Msg {
+		ex, ok := <-source
+		return exchangeMsg{ex: ex, ok: ok}
+	}
+}
+
+func (s *TrafficScreen) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		s.width, s.height = msg.Width, msg.Height
+		s.scrollToCursor()
+		return s, nil
+
+	case exchangeMsg:
+		if !msg.ok {
+			s.stopped = true
+			return s, nil
+		}
+		s.exchanges = append([]domain.RecordedExchange{msg.ex}, s.exchanges...)
+		// Keep the selection on the same exchange unless following the
+		// newest.
+		if s.cursor > 0 && s.shows(msg.ex) {
+			s.cursor++
+			s.scrollToCursor()
+		}
+		return s, s.next()
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return s, tea.Quit
+		case "up", "k":
+			s.move(-1)
+		case "down", "j":
+			s.move(1)
+		case "home", "g":
+			s.move(-len(s.exchanges))
+		case "v":
+			selected, ok := s.selected()
+			s.onlyViolations = !s.onlyViolations
+			s.cursor, s.offset = 0, 0
+			for i, ex := range s.visible() {
+				if ok && ex.ID == selected.ID {
+					s.cursor = i
+				}
+			}
+			s.scrollToCursor()
+		case "c":
+			s.exchanges, s.cursor, s.offset, s.scroll = nil, 0, 0, 0
+		case "ctrl+d", "pgdown":
+			s.scroll += s.contentHeight() / 2
+		case "ctrl+u", "pgup":
+			s.scroll = max(0, s.scroll-s.contentHeight()/2)
+		}
+	}
+	return s, nil
+}
+
+func (s *TrafficScreen) shows(ex domain.RecordedExchange) bool {
+	return !s.onlyViolations || ex.Violations() > 0
+}
+
+func (s *TrafficScreen) visible() []domain.RecordedExchange {
+	if !s.onlyViolations {
+		return s.exchanges
+	}
+	var out []domain.RecordedExchange
+	for _, ex := range s.exchanges {
+		if ex.Violations() > 0 {
+			out = append(out, ex)
+		}
+	}
+	return out
+}
+
+func (s *TrafficScreen) selected() (domain.RecordedExchange, bool) {
+	visible := s.visible()
+	if s.cursor >= len(visible) {
+		return domain.RecordedExchange{}, false
+	}
+	return visible[s.cursor], true
+}
+
+func (s *TrafficScreen) move(delta int) {
+	s.cursor = max(0, min(len(s.visible())-1, s.cursor+delta))
+	s.scroll = 0
+	s.scrollToCursor()
+}
+
+func (s *TrafficScreen) contentHeight() int { return max(1, s.height-2) }
+
+// listRows excludes the title, summary and hint lines.
+func (s *TrafficScreen) listRows() int { return max(1, s.contentHeight()-4) }
+
+func (s *TrafficScreen) scrollToCursor() {
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if rows := s.listRows(); s.cursor >= s.offset+rows {
+		s.offset = s.cursor - rows + 1
+	}
+}
+
+func (s *TrafficScreen) View() string {
+	if s.width == 0 {
+		return ""
+	}
+	listWidth := s.width * 2 / 5
+	border := lipgloss.NewStyle().Height(s.contentHeight()).Border(lipgloss.RoundedBorder())
+	list := border.BorderForeground(styles.Blue).Width(max(1, listWidth-2)).Render(s.listView(max(1, listWidth-2)))
+	detailWidth := max(1, s.width-listWidth-4)
+	detail := border.BorderForeground(styles.Surface1).Width(detailWidth + 2).PaddingLeft(1).PaddingRight(1).
+		Render(s.detailView(detailWidth))
+	return lipgloss.JoinHorizontal(lipgloss.Top, list, detail)
+}
+
+func (s *TrafficScreen) listView(width int) string {
+	var b strings.Builder
+	row := lipgloss.NewStyle().MaxWidth(width)
+	b.WriteString(row.Inherit(styles.Title).Render(s.title) + "\n")
+
+	failing := 0
+	for _, ex := range s.exchanges {
+		if ex.Violations() > 0 {
+			failing++
+		}
+	}
+	summary := domain.Plural(len(s.exchanges), "exchange")
+	if failing > 0 {
+		summary += " · " + styles.Error.Render(fmt.Sprintf("%d with violations", failing))
+	}
+	if s.onlyViolations {
+		summary += " · only violations"
+	}
+	if s.stopped {
+		summary += " · stopped"
+	}
+	b.WriteString(row.Inherit(styles.Muted).Render(summary) + "\n\n")
+
+	visible := s.visible()
+	if len(visible) == 0 {
+		b.WriteString(styles.Muted.Render("Waiting for requests…") + "\n")
+	}
+	end := min(len(visible), s.offset+s.listRows())
+	for i := s.offset; i < end; i++ {
+		line := exchangeRow(visible[i])
+		if i == s.cursor {
+			line = lipgloss.NewStyle().Bold(true).Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		b.WriteString(row.Render(line) + "\n")
+	}
+	b.WriteString(styles.Muted.Render("v violations only · c clear · ctrl+d/u scroll · q quit"))
+	return b.String()
+}
+
+// exchangeRow summarises an exchange in one line, e.g.
+// "15:04:05 200 GET /pets/1 ✗ 2".
+func exchangeRow(ex domain.RecordedExchange) string {
+	status := styles.Error.Render("ERR")
+	if ex.Error == "" {
+		code := fmt.Sprint(ex.Status)
+		status = lipgloss.NewStyle().Foreground(styles.StatusColor(code)).Render(code)
+	}
+	mark := lipgloss.NewStyle().Foreground(styles.Green).Render("✓")
+	if n := ex.Violations(); n > 0 {
+		mark = styles.Error.Render(fmt.Sprintf("✗ %d", n))
+	}
+	return fmt.Sprintf("%s %s %s %s %s %s", ex.Time.Local().Format("15:04:05"), mark, status, styles.Method(string(ex.Method)), ex.URL,
+		styles.Muted.Render(ex.Duration.Round(time.Millisecond).String()))
+}
+
+func (s *TrafficScreen) detailView(width int) string {
+	ex, ok := s.selected()
+	if !ok {
+		return styles.Muted.Render("Send requests through the proxy to see them here.")
+	}
+
+	var b strings.Builder
+	op := ex.OperationID
+	if op == "" {
+		op = styles.Error.Render("no operation")
+	}
+	b.WriteString(styles.Title.Render(string(ex.Method)+" ") + op + "\n")
+	b.WriteString(styles.Muted.Render(ex.Time.Local().Format(time.DateTime)+" · "+ex.Duration.Round(time.Millisecond).String()) + "\n\n")
+
+	if ex.Violations() == 0 {
+		b.WriteString(lipgloss.NewStyle().Foreground(styles.Green).Render("✓ Matches the spec") + "\n\n")
+	} else {
+		for _, group := range []struct {
+			label      string
+			violations []domain.Violation
+		}{{"request", ex.RequestViolations}, {"response", ex.ResponseViolations}} {
+			for _, v := range group.violations {
+				b.WriteString(styles.Error.Render("✗ ") + lipgloss.NewStyle().Foreground(styles.Red).Render(group.label+" "+v.String()) + "\n")
+			}
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(string(ex.Method)+" "+ex.URL) + "\n")
+	writeHeaders(&b, ex.RequestHeader)
+	if ex.RequestBody != "" {
+		b.WriteString("\n" + indentedBody(ex.RequestBody))
+		if ex.RequestTruncated {
+			b.WriteString(styles.Muted.Render("…"))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	if ex.Error != "" {
+		b.WriteString(styles.Error.Render(ex.Error) + "\n")
+	} else {
+		code := fmt.Sprint(ex.Status)
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(styles.StatusColor(code)).
+			Render(strings.TrimSpace(code+" "+http.StatusText(ex.Status))) + "\n")
+		writeHeaders(&b, ex.ResponseHeader)
+		if ex.ResponseBody != "" {
+			b.WriteString("\n" + indentedBody(ex.ResponseBody))
+			if ex.ResponseTruncated {
+				b.WriteString(styles.Muted.Render("…"))
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	lines := strings.Split(strings.TrimRight(b.String(), "\n"), "\n")
+	s.scroll = min(s.scroll, max(0, len(lines)-1))
+	lines = lines[s.scroll:]
+	if h := s.contentHeight(); len(lines) > h {
+		lines = lines[:h]
+	}
+	clip := lipgloss.NewStyle().MaxWidth(width)
+	for i, l := range lines {
+		lines[i] = clip.Render(l)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentedBody indents a JSON body, leaving anything else as it is.
+func indentedBody(body string) string {
+	var out bytes.Buffer
+	if json.Indent(&out, []byte(body), "", "  ") != nil {
+		return body
+	}
+	return out.String()
+}
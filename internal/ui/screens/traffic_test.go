@@ -0,0 +1,59 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/screens"
+)
+
+// receive feeds n exchanges from the screen's source through Update.
+func receive(s *screens.TrafficScreen, cmd tea.Cmd, n int) {
+	for range n {
+		_, cmd = s.Update(cmd())
+	}
+}
+
+func TestTrafficScreen_ListsExchangesAndViolations(t *testing.T) {
+	source := make(chan domain.RecordedExchange, 2)
+	source <- domain.RecordedExchange{ID: 1, Time: time.Now(), Method: domain.GET, URL: "/v1/pets/7", Status: 200,
+		OperationID: "getPet", ResponseBody: `{"id":7}`}
+	source <- domain.RecordedExchange{ID: 2, Time: time.Now(), Method: domain.POST, URL: "/v1/pets", Status: 201,
+		OperationID: "createPet", RequestBody: `{}`,
+		RequestViolations: []domain.Violation{{Path: "$.name", Message: "required property missing"}}}
+	s := screens.NewTrafficScreen("Proxy", source)
+	s.Update(tea.WindowSizeMsg{Width: 140, Height: 30})
+	receive(s, s.Init(), 2)
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	for _, want := range []string{"2 exchanges · 1 with violations", "✗ 1 201 POST /v1/pets", "✓ 200 GET /v1/pets/7",
+		"POST createPet", "✗ request $.name: required property missing"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("expected %q, got:\n%s", want, plain)
+		}
+	}
+
+	s.Update(keyMsg("down"))
+	if plain := ansiRe.ReplaceAllString(s.View(), ""); !strings.Contains(plain, "✓ Matches the spec") {
+		t.Errorf("expected the older exchange to be selected, got:\n%s", plain)
+	}
+	s.Update(keyMsg("v"))
+	plain = ansiRe.ReplaceAllString(s.View(), "")
+	if strings.Contains(plain, "GET /v1/pets/7") || !strings.Contains(plain, "only violations") {
+		t.Errorf("expected only the failing exchange, got:\n%s", plain)
+	}
+
+	close(source)
+	_, cmd := s.Update(keyMsg("c"))
+	if cmd != nil {
+		t.Fatalf("expected no command from clearing")
+	}
+	plain = ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "Waiting for requests…") {
+		t.Errorf("expected a cleared list, got:\n%s", plain)
+	}
+}
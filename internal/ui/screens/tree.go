@@ -0,0 +1,381 @@
+package screens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"dazzle/internal/domain"
+	"dazzle/internal/ui/styles"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// treeNode is one path segment in the operation tree. Operations whose path
+// ends at this node hang off it as leaves; deeper paths are children.
+type treeNode struct {
+	key      string // full path prefix, e.g. "/v1/customers"
+	segment  string // last segment, e.g. "/customers"
+	children []*treeNode
+	ops      []domain.Operation
+	count    int // operations in this subtree
+}
+
+// treeRow is a visible line in the tree: a segment node, an operation
+// leaf, or a saved request under its operation (op and saved both set).
+type treeRow struct {
+	node  *treeNode
+	op    *domain.Operation
+	saved *domain.SavedRequest
+	depth int
+}
+
+// operationTree groups operations by path segment with expand/collapse and
+// keyboard navigation.
+type operationTree struct {
+	root     *treeNode
+	saved    map[string][]domain.SavedRequest
+	expanded map[string]bool
+	rows     []treeRow
+	cursor   int
+	offset   int
+	width    int
+	height   int
+}
+
+func newOperationTree() *operationTree {
+	return &operationTree{expanded: make(map[string]bool)}
+}
+
+// setOperations rebuilds the tree, keeping expansion state and the selected
+// operation where they still exist. On first build, chains of single-child
+// segments (e.g. "/api" → "/v1") are expanded so the tree opens at the first
+// meaningful branch.
+func (t *operationTree) setOperations(ops []domain.Operation) {
+	selectedID := ""
+	if op := t.selectedOperation(); op != nil {
+		selectedID = op.ID
+	}
+
+	first := t.root == nil
+	t.root = buildTree(ops)
+	if first {
+		for n := t.root; len(n.children) == 1 && len(n.ops) == 0; n = n.children[0] {
+			t.expanded[n.children[0].key] = true
+		}
+	}
+	t.refresh()
+
+	if selectedID != "" {
+		t.selectOperation(selectedID)
+	}
+}
+
+func buildTree(ops []domain.Operation) *treeNode {
+	root := &treeNode{}
+	index := map[string]*treeNode{"": root}
+
+	for _, op := range ops {
+		node := root
+		key := ""
+		for _, seg := range strings.Split(strings.Trim(op.Path, "/"), "/") {
+			if seg == "" {
+				continue
+			}
+			key += "/" + seg
+			child, ok := index[key]
+			if !ok {
+				child = &treeNode{key: key, segment: "/" + seg}
+				index[key] = child
+				node.children = append(node.children, child)
+			}
+			node = child
+		}
+		node.ops = append(node.ops, op)
+	}
+
+	countTree(root)
+	sortTree(root)
+	return root
+}
+
+func countTree(n *treeNode) int {
+	n.count = len(n.ops)
+	for _, c := range n.children {
+		n.count += countTree(c)
+	}
+	return n.count
+}
+
+func sortTree(n *treeNode) {
+	sort.Slice(n.children, func(i, j int) bool {
+		return n.children[i].segment < n.children[j].segment
+	})
+	for _, c := range n.children {
+		sortTree(c)
+	}
+}
+
+// refresh recomputes the visible rows from the expansion state.
+func (t *operationTree) refresh() {
+	t.rows = t.rows[:0]
+	if t.root != nil {
+		// Operations on "/" itself appear at the top level.
+		for i := range t.root.ops {
+			t.appendOperation(&t.root.ops[i], 0)
+		}
+		for _, c := range t.root.children {
+			t.appendRows(c, 0)
+		}
+	}
+	t.cursor = clampIndex(t.cursor, len(t.rows))
+	t.scrollToCursor()
+}
+
+func (t *operationTree) appendRows(n *treeNode, depth int) {
+	t.rows = append(t.rows, treeRow{node: n, depth: depth})
+	if !t.expanded[n.key] {
+		return
+	}
+	for i := range n.ops {
+		t.appendOperation(&n.ops[i], depth+1)
+	}
+	for _, c := range n.children {
+		t.appendRows(c, depth+1)
+	}
+}
+
+// appendOperation adds an operation's row followed by its saved requests.
+func (t *operationTree) appendOperation(op *domain.Operation, depth int) {
+	t.rows = append(t.rows, treeRow{op: op, depth: depth})
+	saved := t.saved[op.ID]
+	for i := range saved {
+		t.rows = append(t.rows, treeRow{op: op, saved: &saved[i], depth: depth + 1})
+	}
+}
+
+// setSaved lists saved requests under their operations.
+func (t *operationTree) setSaved(saved map[string][]domain.SavedRequest) {
+	t.saved = saved
+	t.refresh()
+}
+
+func (t *operationTree) setSize(width, height int) {
+	t.width = width
+	t.height = height
+	t.scrollToCursor()
+}
+
+// selectedOperation returns the operation under the cursor, or nil when the
+// cursor is on a segment node.
+func (t *operationTree) selectedOperation() *domain.Operation {
+	if t.cursor < 0 || t.cursor >= len(t.rows) {
+		return nil
+	}
+	return t.rows[t.cursor].op
+}
+
+// selectedSaved returns the saved request under the cursor, if any.
+func (t *operationTree) selectedSaved() *domain.SavedRequest {
+	if t.cursor < 0 || t.cursor >= len(t.rows) {
+		return nil
+	}
+	return t.rows[t.cursor].saved
+}
+
+// selectOperation expands the path to the given operation and moves the
+// cursor onto it.
+func (t *operationTree) selectOperation(id string) {
+	var path []*treeNode
+	var find func(n *treeNode) bool
+	find = func(n *treeNode) bool {
+		for _, op := range n.ops {
+			if op.ID == id {
+				return true
+			}
+		}
+		for _, c := range n.children {
+			path = append(path, c)
+			if find(c) {
+				return true
+			}
+			path = path[:len(path)-1]
+		}
+		return false
+	}
+	if t.root == nil || !find(t.root) {
+		return
+	}
+	for _, n := range path {
+		t.expanded[n.key] = true
+	}
+	t.refresh()
+	for i, r := range t.rows {
+		if r.op != nil && r.op.ID == id {
+			t.cursor = i
+			break
+		}
+	}
+	t.scrollToCursor()
+}
+
+func (t *operationTree) update(msg tea.Msg) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			t.cursor = max(0, t.cursor-1)
+		case "down", "j":
+			t.cursor = clampIndex(t.cursor+1, len(t.rows))
+		case "pgup":
+			t.cursor = max(0, t.cursor-t.visibleRows())
+		case "pgdown":
+			t.cursor = clampIndex(t.cursor+t.visibleRows(), len(t.rows))
+		case "home", "g":
+			t.cursor = 0
+		case "end", "G":
+			t.cursor = clampIndex(len(t.rows)-1, len(t.rows))
+		case "right", "l":
+			t.expand()
+		case "left", "h":
+			t.collapse()
+		case "enter", " ":
+			t.toggle()
+		}
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			t.cursor = max(0, t.cursor-1)
+		case tea.MouseButtonWheelDown:
+			t.cursor = clampIndex(t.cursor+1, len(t.rows))
+		}
+	}
+	t.scrollToCursor()
+}
+
+// expand opens a collapsed node, or steps into an already open one.
+func (t *operationTree) expand() {
+	if t.cursor >= len(t.rows) {
+		return
+	}
+	n := t.rows[t.cursor].node
+	if n == nil {
+		return
+	}
+	if !t.expanded[n.key] {
+		t.expanded[n.key] = true
+		t.refresh()
+		return
+	}
+	t.cursor = clampIndex(t.cursor+1, len(t.rows))
+}
+
+// collapse closes an open node, or moves to the parent of a leaf or closed
+// node.
+func (t *operationTree) collapse() {
+	if t.cursor >= len(t.rows) {
+		return
+	}
+	row := t.rows[t.cursor]
+	if row.node != nil && t.expanded[row.node.key] {
+		delete(t.expanded, row.node.key)
+		t.refresh()
+		return
+	}
+	for i := t.cursor - 1; i >= 0; i-- {
+		if t.rows[i].node != nil && t.rows[i].depth < row.depth {
+			t.cursor = i
+			return
+		}
+	}
+}
+
+func (t *operationTree) toggle() {
+	if t.cursor >= len(t.rows) {
+		return
+	}
+	n := t.rows[t.cursor].node
+	if n == nil {
+		return
+	}
+	if t.expanded[n.key] {
+		delete(t.expanded, n.key)
+	} else {
+		t.expanded[n.key] = true
+	}
+	t.refresh()
+}
+
+// visibleRows is the number of tree rows that fit below the title and
+// above the status line.
+func (t *operationTree) visibleRows() int {
+	return max(1, t.height-4)
+}
+
+func (t *operationTree) scrollToCursor() {
+	visible := t.visibleRows()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+visible {
+		t.offset = t.cursor - visible + 1
+	}
+	t.offset = max(0, min(t.offset, len(t.rows)-visible))
+}
+
+func (t *operationTree) view(title string) string {
+	var b strings.Builder
+	b.WriteString(styles.Title.Render(title))
+	b.WriteString("\n\n")
+
+	if len(t.rows) == 0 {
+		b.WriteString(styles.Muted.Render("No operations"))
+		return b.String()
+	}
+
+	end := min(len(t.rows), t.offset+t.visibleRows())
+	for i := t.offset; i < end; i++ {
+		b.WriteString(t.renderRow(t.rows[i], i == t.cursor))
+		b.WriteString("\n")
+	}
+
+	count := 0
+	if t.root != nil {
+		count = t.root.count
+	}
+	b.WriteString("\n")
+	b.WriteString(styles.Muted.Render(fmt.Sprintf("%d endpoints · ←/→ collapse/expand · v list", count)))
+	return b.String()
+}
+
+func (t *operationTree) renderRow(r treeRow, selected bool) string {
+	pad := strings.Repeat("  ", r.depth)
+
+	var line string
+	if r.node != nil {
+		arrow := "▸"
+		if t.expanded[r.node.key] {
+			arrow = "▾"
+		}
+		line = fmt.Sprintf("%s%s %s %s", pad, arrow, r.node.segment,
+			styles.Muted.Render(fmt.Sprintf("(%d)", r.node.count)))
+	} else if r.saved != nil {
+		line = fmt.Sprintf("%s  ↳ %s %s", pad, r.saved.Name, styles.Muted.Render(r.saved.Collection))
+	} else {
+		line = fmt.Sprintf("%s  %s %s", pad, styles.Method(string(r.op.Method)),
+			lipgloss.NewStyle().Foreground(styles.Overlay1).Render(r.op.Summary))
+	}
+
+	if t.width > 0 {
+		line = lipgloss.NewStyle().MaxWidth(max(1, t.width-2)).Render(line)
+	}
+	if selected {
+		return lipgloss.NewStyle().Bold(true).Render("> " + line)
+	}
+	return "  " + line
+}
+
+func clampIndex(i, n int) int {
+	return max(0, min(i, n-1))
+}
@@ -0,0 +1,103 @@
+package screens_test
+
+import (
+	"strings"
+	"testing"
+
+	"dazzle/internal/ui/screens"
+)
+
+func TestTreeView_GroupsBySegmentWithCounts(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.Update(keyMsg("v"))
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+
+	// The path to the operation selected in the list is expanded.
+	if !strings.Contains(plain, "▾ /pets (4)") {
+		t.Error("expected expanded /pets node with 4 operations")
+	}
+	if !strings.Contains(plain, "▸ /{petId} (2)") {
+		t.Error("expected collapsed /{petId} node with 2 operations")
+	}
+	if !strings.Contains(plain, "▸ /store (2)") {
+		t.Error("expected collapsed /store node with 2 operations")
+	}
+}
+
+func TestTreeView_ExpandAndSelectLeaf(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.Update(keyMsg("v"))
+
+	// Cursor starts on GET /pets, carried over from the list.
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("l")) // expand /{petId}
+	s.Update(keyMsg("down"))
+	s.Update(keyMsg("down")) // DELETE /pets/{petId}
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "▾ /{petId} (2)") {
+		t.Error("expected expanded /{petId} node")
+	}
+	if !strings.Contains(plain, "DELETE Delete a pet") {
+		t.Error("expected DELETE leaf shown in tree")
+	}
+	if !strings.Contains(plain, "DELETE /pets/{petId}") {
+		t.Error("expected detail panel to show the selected leaf operation")
+	}
+}
+
+func TestTreeView_CollapseHidesChildren(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.Update(keyMsg("v"))
+
+	// From the GET /pets leaf: step to /pets and collapse it.
+	s.Update(keyMsg("h"))
+	s.Update(keyMsg("h"))
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "▸ /pets (4)") {
+		t.Error("expected /pets collapsed")
+	}
+	if strings.Contains(plain, "/{petId} (2)") {
+		t.Error("expected children hidden after collapse")
+	}
+}
+
+func TestTreeView_FollowsFacets(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.Update(keyMsg("v"))
+
+	// 3 = DELETE, the third method present in this spec.
+	s.Update(keyMsg("3"))
+
+	plain := ansiRe.ReplaceAllString(s.View(), "")
+	if !strings.Contains(plain, "/pets (1)") {
+		t.Error("expected tree rebuilt from facet-filtered operations")
+	}
+	if strings.Contains(plain, "/store") {
+		t.Error("expected /store hidden by the DELETE facet")
+	}
+}
+
+func TestTreeView_ToggleBackKeepsSelection(t *testing.T) {
+	s := newScreen(testSpec(), screens.Services{})
+	s.Update(keyMsg("v"))
+	for range 3 {
+		s.Update(keyMsg("down")) // POST /pets, /{petId}, /store
+	}
+	s.Update(keyMsg("l"))
+	s.Update(keyMsg("down")) // /orders
+	s.Update(keyMsg("l"))
+	s.Update(keyMsg("down")) // GET /store/orders
+	s.Update(keyMsg("v"))
+
+	view := s.View()
+	if !strings.Contains(view, "List orders") {
+		t.Fatal("expected list view after toggling back")
+	}
+	plain := ansiRe.ReplaceAllString(view, "")
+	if !strings.Contains(plain, "> GET /store/orders") {
+		t.Error("expected list selection to follow the tree selection")
+	}
+}
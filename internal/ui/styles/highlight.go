@@ -0,0 +1,54 @@
+package styles
+
+import (
+	"strings"
+
+	"github.com/alecthomas/chroma/v2"
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/lexers"
+	chromastyles "github.com/alecthomas/chroma/v2/styles"
+)
+
+// Highlight syntax-highlights source for a 256-colour terminal using the
+// Catppuccin Mocha chroma style. language may be a lexer name or alias
+// ("json", "yaml") or a MIME type ("application/xml"); source is returned
+// unchanged when no lexer matches.
+func Highlight(source, language string) string {
+	lexer := lookupLexer(language)
+	if lexer == nil {
+		return source
+	}
+
+	iter, err := chroma.Coalesce(lexer).Tokenise(nil, source)
+	if err != nil {
+		return source
+	}
+
+	var b strings.Builder
+	if err := formatters.TTY256.Format(&b, chromastyles.Get("catppuccin-mocha"), iter); err != nil {
+		return source
+	}
+	return b.String()
+}
+
+func lookupLexer(language string) chroma.Lexer {
+	if language == "" {
+		return nil
+	}
+	// Strip parameters such as "; charset=utf-8".
+	mime, _, _ := strings.Cut(language, ";")
+	mime = strings.TrimSpace(strings.ToLower(mime))
+	if l := lexers.MatchMimeType(mime); l != nil {
+		return l
+	}
+	// Structured syntax suffixes: application/problem+json, image/svg+xml.
+	if _, suffix, ok := strings.Cut(mime, "+"); ok {
+		if l := lexers.Get(suffix); l != nil {
+			return l
+		}
+	}
+	if _, sub, ok := strings.Cut(mime, "/"); ok {
+		mime = sub
+	}
+	return lexers.Get(mime)
+}
@@ -37,3 +37,26 @@ func MethodColor(method string) lipgloss.AdaptiveColor {
 		return Text
 	}
 }
+
+// StatusColor returns the color for a response status code by class:
+// 2xx green, 3xx blue, 4xx orange, 5xx red and "default" purple.
+func StatusColor(code string) lipgloss.AdaptiveColor {
+	if code == "default" {
+		return Purple
+	}
+	if code == "" {
+		return Text
+	}
+	switch code[0] {
+	case '2':
+		return Green
+	case '3':
+		return Blue
+	case '4':
+		return Orange
+	case '5':
+		return Red
+	default:
+		return Subtext0
+	}
+}
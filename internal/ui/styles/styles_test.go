@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"dazzle/internal/ui/styles"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 func TestMethodColor_KnownMethods(t *testing.T) {
@@ -30,3 +32,24 @@ func TestMethod_ReturnsNonEmpty(t *testing.T) {
 		t.Error("expected non-empty styled string")
 	}
 }
+
+func TestStatusColor_Classes(t *testing.T) {
+	tests := []struct {
+		code string
+		want lipgloss.AdaptiveColor
+	}{
+		{"200", styles.Green},
+		{"2XX", styles.Green},
+		{"304", styles.Blue},
+		{"404", styles.Orange},
+		{"503", styles.Red},
+		{"default", styles.Purple},
+		{"101", styles.Subtext0},
+	}
+
+	for _, tt := range tests {
+		if got := styles.StatusColor(tt.code); got != tt.want {
+			t.Errorf("StatusColor(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -2,32 +2,77 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/browser"
+	"dazzle/internal/infrastructure/clipboard"
+	"dazzle/internal/infrastructure/config"
+	"dazzle/internal/infrastructure/httpclient"
 	"dazzle/internal/infrastructure/openapi"
 	"dazzle/internal/ui"
+	"dazzle/internal/ui/screens"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	if err := run(); err != nil {
+	if err := run(os.Args[1:]); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func run() error {
-	if len(os.Args) != 2 {
-		fmt.Println("dazzle — spec-aware API explorer")
-		fmt.Println()
-		fmt.Println("Usage: dazzle <spec-file-or-url>")
+func run(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "search":
+			return runSearch(context.Background(), os.Stdout, args[1:])
+		case "mock":
+			return runMock(context.Background(), os.Stdout, args[1:])
+		case "proxy":
+			return runProxy(context.Background(), args[1:])
+		case "infer":
+			return runInfer(context.Background(), os.Stdout, args[1:])
+		case "test":
+			return runTest(context.Background(), os.Stdout, args[1:])
+		case "fuzz":
+			return runFuzz(context.Background(), os.Stdout, args[1:])
+		}
+	}
+
+	flags := flag.NewFlagSet("dazzle", flag.ContinueOnError)
+	flags.Usage = printUsage
+	envName := flags.String("env", "", "environment to start with")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		printUsage()
 		return fmt.Errorf("expected exactly one argument")
 	}
 
-	source := os.Args[1]
+	source := flags.Arg(0)
+
+	envSvc := application.NewEnvironmentService(config.NewEnvironmentStore(config.DefaultEnvironmentPaths()...))
+	envs, err := envSvc.LoadEnvironments()
+	if err != nil {
+		return err
+	}
+	active, err := chooseEnvironment(envs, *envName)
+	if err != nil {
+		return err
+	}
 
 	if f := os.Getenv("DAZZLE_DEBUG"); f != "" {
 		logFile, err := tea.LogToFile(f, "dazzle")
@@ -40,10 +85,72 @@ func run() error {
 	repo := openapi.NewRepository()
 	specSvc := application.NewSpecService(repo)
 	opSvc := application.NewOperationService()
+	client := httpclient.NewClient(30 * time.Second)
+	authSvc, signSvc := newAuthorization(envSvc, client)
+	authSvc.SetBrowser(browser.Open)
+
+	services := screens.Services{
+		Requests:    application.NewRequestService(client),
+		Validation:  application.NewValidationService(),
+		Auth:        authSvc,
+		Signer:      signSvc,
+		Collections: application.NewCollectionService(config.NewCollectionStore(config.DefaultCollectionDir())),
+		Snippets:    application.NewSnippetService(),
+		Clipboard:   clipboard.NewOSC52(os.Stdout),
+		Import:      application.NewImportService(),
+		Examples:    application.NewExampleService(1),
+		Chain:       application.NewChainService(),
+	}
+	if path, err := config.DefaultHistoryPath(); err == nil {
+		services.History = application.NewHistoryService(config.NewHistoryStore(path, config.DefaultHistoryLimit))
+	}
 
 	app := ui.NewAppModel(context.Background(), specSvc, opSvc, source)
+	app.SetEnvironments(envSvc, envs, active)
+	app.SetServices(services)
 
 	p := tea.NewProgram(app, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	_, err := p.Run()
+	_, err = p.Run()
 	return err
 }
+
+// newAuthorization wires the security schemes' credentials, with tokens
+// cached on disk, and request signing, for every command that sends
+// requests.
+func newAuthorization(envSvc *application.EnvironmentService, client *httpclient.Client) (*application.AuthService, *application.SigningService) {
+	authSvc := application.NewAuthService(config.NewCredentialStore(config.DefaultCredentialPaths()...), envSvc, client)
+	if dir, err := config.DefaultTokenDir(); err == nil {
+		authSvc.SetTokenStore(config.NewTokenStore(dir))
+	}
+	signSvc := application.NewSigningService(config.NewSignerStore(config.DefaultSigningPaths()...), config.NewAWSCredentialStore(), envSvc)
+	return authSvc, signSvc
+}
+
+// chooseEnvironment returns the environment named on the command line, or
+// the configured default when none was named.
+func chooseEnvironment(envs *domain.EnvironmentSet, name string) (string, error) {
+	if name == "" {
+		return envs.Default, nil
+	}
+	if _, ok := envs.Get(name); !ok {
+		have := "none configured"
+		if names := envs.Names(); len(names) > 0 {
+			have = "have: " + strings.Join(names, ", ")
+		}
+		return "", fmt.Errorf("unknown environment %q (%s)", name, have)
+	}
+	return name, nil
+}
+
+func printUsage() {
+	fmt.Println("dazzle — spec-aware API explorer")
+	fmt.Println()
+	fmt.Println("Usage:")
+	fmt.Println("  dazzle [--env NAME] <spec-file-or-url>")
+	fmt.Println("  dazzle search <spec-file-or-url> <query>")
+	fmt.Println("  dazzle mock [--host ADDR] [--port N] [--seed N] [--stateful] [--data FILE] [--faults FILE] <spec-file-or-url>")
+	fmt.Println("  dazzle proxy --upstream URL [--host ADDR] [--port N] [--record FILE] <spec-file-or-url>")
+	fmt.Println("  dazzle infer [--overlay FILE] <spec-file-or-url> <recording-or-har>...")
+	fmt.Println("  dazzle test [--env NAME] [--parallel N] [--format text|junit|json|tap] [--output FILE] <spec-file-or-url> [collection...]")
+	fmt.Println("  dazzle fuzz [--env NAME] [--server URL] [--seed N] [--cases N] <spec-file-or-url> [operationId...]")
+}
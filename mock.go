@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/infrastructure/mockserver"
+	"dazzle/internal/infrastructure/openapi"
+)
+
+// runMock implements `dazzle mock [--port N] [--stateful] <spec>`: it
+// serves every operation of the spec on a local port until interrupted,
+// logging each request to out.
+func runMock(ctx context.Context, out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("dazzle mock", flag.ContinueOnError)
+	flags.Usage = printUsage
+	host := flags.String("host", "127.0.0.1", "address to listen on")
+	port := flags.Int("port", 4010, "port to listen on")
+	seed := flags.Uint64("seed", 1, "seed for generated responses")
+	stateful := flags.Bool("stateful", false, "keep created resources for the session")
+	dataFile := flags.String("data", "", "JSON file of resources to start with (implies --stateful)")
+	faultsFile := flags.String("faults", "", "YAML file of latencies, errors and other faults to inject")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 1 {
+		printUsage()
+		return fmt.Errorf("mock expects a spec")
+	}
+
+	spec, err := application.NewSpecService(openapi.NewRepository()).LoadSpec(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	svc := application.NewMockService(spec, application.NewValidationService(), application.NewExampleService(*seed))
+	if *stateful || *dataFile != "" {
+		var data map[string][]any
+		if *dataFile != "" {
+			if data, err = mockserver.LoadData(*dataFile); err != nil {
+				return err
+			}
+		}
+		if err := svc.EnableStore(data); err != nil {
+			return err
+		}
+	}
+	if *faultsFile != "" {
+		faults, err := mockserver.LoadFaults(*faultsFile)
+		if err != nil {
+			return err
+		}
+		if err := svc.SetFaults(faults, *seed); err != nil {
+			return err
+		}
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		return err
+	}
+	srv := &http.Server{Handler: mockserver.NewHandler(svc, out), ReadHeaderTimeout: 10 * time.Second}
+	fmt.Fprintf(out, "Mocking %s (%d operations) on http://%s\n", spec.Info.Title, len(spec.Operations), ln.Addr())
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+	if err := srv.Serve(ln); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/openapi"
+	"dazzle/internal/infrastructure/proxy"
+	"dazzle/internal/ui/screens"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// runProxy implements `dazzle proxy --upstream URL <spec>`: a reverse
+// proxy whose traffic is checked against the spec and shown as it passes,
+// and optionally recorded to a file.
+func runProxy(ctx context.Context, args []string) error {
+	flags := flag.NewFlagSet("dazzle proxy", flag.ContinueOnError)
+	flags.Usage = printUsage
+	host := flags.String("host", "127.0.0.1", "address to listen on")
+	port := flags.Int("port", 4020, "port to listen on")
+	upstreamURL := flags.String("upstream", "", "URL of the service to proxy")
+	recordFile := flags.String("record", "", "append exchanges to this file as JSON lines")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() != 1 || *upstreamURL == "" {
+		printUsage()
+		return fmt.Errorf("proxy expects --upstream and a spec")
+	}
+	upstream, err := url.Parse(*upstreamURL)
+	if err != nil || upstream.Scheme == "" || upstream.Host == "" {
+		return fmt.Errorf("invalid upstream %q: want a URL such as http://localhost:8080", *upstreamURL)
+	}
+
+	spec, err := application.NewSpecService(openapi.NewRepository()).LoadSpec(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	traffic := application.NewTrafficService(spec, application.NewValidationService())
+
+	var recorder *proxy.Recorder
+	if *recordFile != "" {
+		f, err := os.OpenFile(*recordFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("opening recording: %w", err)
+		}
+		defer f.Close()
+		recorder = proxy.NewRecorder(f)
+	}
+
+	ln, err := net.Listen("tcp", net.JoinHostPort(*host, strconv.Itoa(*port)))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	exchanges := make(chan domain.RecordedExchange, 256)
+	handler := proxy.New(upstream, func(ex domain.RecordedExchange) {
+		ex = traffic.Check(ex)
+		if recorder != nil {
+			_ = recorder.Record(traffic.Mask(ex)) // reported when the proxy stops
+		}
+		select {
+		case exchanges <- ex:
+		case <-ctx.Done():
+		}
+	})
+	srv := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = srv.Serve(ln) }()
+
+	title := fmt.Sprintf("Proxy http://%s → %s", ln.Addr(), upstream)
+	_, err = tea.NewProgram(screens.NewTrafficScreen(title, exchanges), tea.WithAltScreen()).Run()
+	cancel()
+
+	shutdown, stop := context.WithTimeout(context.Background(), 5*time.Second)
+	defer stop()
+	_ = srv.Shutdown(shutdown)
+	if recorder != nil {
+		err = errors.Join(err, recorder.Err())
+	}
+	return err
+}
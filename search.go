@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"dazzle/internal/application"
+	"dazzle/internal/infrastructure/openapi"
+)
+
+// runSearch implements `dazzle search <spec> <query>`: it loads the spec and
+// prints the operations matching the structured query, most relevant first.
+func runSearch(ctx context.Context, out io.Writer, args []string) error {
+	if len(args) < 2 {
+		printUsage()
+		return fmt.Errorf("search expects a spec and a query")
+	}
+
+	specSvc := application.NewSpecService(openapi.NewRepository())
+	opSvc := application.NewOperationService()
+
+	spec, err := specSvc.LoadSpec(ctx, args[0])
+	if err != nil {
+		return err
+	}
+
+	filter := opSvc.ParseQuery(strings.Join(args[1:], " "))
+	ops := opSvc.SearchOperations(opSvc.SortOperations(opSvc.ListOperations(spec)), filter)
+	if len(ops) == 0 {
+		return fmt.Errorf("no operations match %q", strings.Join(args[1:], " "))
+	}
+
+	w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+	for _, op := range ops {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", op.Method, op.Path, op.ID, op.Summary)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"dazzle/internal/application"
+	"dazzle/internal/domain"
+	"dazzle/internal/infrastructure/config"
+	"dazzle/internal/infrastructure/httpclient"
+	"dazzle/internal/infrastructure/openapi"
+	"dazzle/internal/infrastructure/testreport"
+)
+
+// runTest implements `dazzle test <spec> [collection...]`: it runs the
+// saved requests of the named collections, or of all of them, as contract
+// tests and reports the results. Any failure makes it return an error, so
+// CI jobs fail.
+func runTest(ctx context.Context, out io.Writer, args []string) error {
+	flags := flag.NewFlagSet("dazzle test", flag.ContinueOnError)
+	flags.Usage = printUsage
+	envName := flags.String("env", "", "environment to run against")
+	parallel := flags.Int("parallel", 1, "requests to run at once")
+	format := flags.String("format", "text", "report format: "+strings.Join(testreport.Formats, ", "))
+	outputFile := flags.String("output", "", "write the report to this file, printing a summary")
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil
+		}
+		return err
+	}
+	if flags.NArg() < 1 {
+		printUsage()
+		return fmt.Errorf("test expects a spec")
+	}
+	if !slices.Contains(testreport.Formats, *format) {
+		return fmt.Errorf("unknown report format %q (have: %s)", *format, strings.Join(testreport.Formats, ", "))
+	}
+
+	envSvc := application.NewEnvironmentService(config.NewEnvironmentStore(config.DefaultEnvironmentPaths()...))
+	envs, err := envSvc.LoadEnvironments()
+	if err != nil {
+		return err
+	}
+	active, err := chooseEnvironment(envs, *envName)
+	if err != nil {
+		return err
+	}
+	env, _ := envs.Get(active)
+
+	spec, err := application.NewSpecService(openapi.NewRepository()).LoadSpec(ctx, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	collections, err := application.NewCollectionService(config.NewCollectionStore(config.DefaultCollectionDir())).LoadCollections()
+	if err != nil {
+		return err
+	}
+	requests, err := suite(collections, flags.Args()[1:])
+	if err != nil {
+		return err
+	}
+
+	client := httpclient.NewClient(30 * time.Second)
+	authSvc, signSvc := newAuthorization(envSvc, client)
+	runner := application.NewTestService(application.NewRequestService(client), envSvc, application.NewValidationService(),
+		domain.Authorizer{Auth: authSvc, Signer: signSvc})
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	run := runner.Run(ctx, spec, requests, env, *parallel)
+
+	if *outputFile != "" {
+		f, err := os.Create(*outputFile)
+		if err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		err = testreport.Write(f, *format, run)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		*format = "text"
+	}
+	if err := testreport.Write(out, *format, run); err != nil {
+		return err
+	}
+
+	if _, failed, _ := run.Counts(); failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, len(run.Results))
+	}
+	return nil
+}
+
+// suite lists the saved requests of the named collections, in the order
+// named, or of every collection when none is.
+func suite(collections []domain.Collection, names []string) ([]domain.SavedRequest, error) {
+	var requests []domain.SavedRequest
+	if len(names) == 0 {
+		for _, c := range collections {
+			requests = append(requests, c.Requests...)
+		}
+		if len(requests) == 0 {
+			return nil, fmt.Errorf("no saved requests in %s", config.DefaultCollectionDir())
+		}
+		return requests, nil
+	}
+	for _, name := range names {
+		i := slices.IndexFunc(collections, func(c domain.Collection) bool { return c.Name == name })
+		if i < 0 {
+			have := make([]string, len(collections))
+			for j, c := range collections {
+				have[j] = c.Name
+			}
+			return nil, fmt.Errorf("unknown collection %q (have: %s)", name, strings.Join(have, ", "))
+		}
+		requests = append(requests, collections[i].Requests...)
+	}
+	return requests, nil
+}